@@ -0,0 +1,83 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ---- Activity timeline ----
+//
+// get_issue can return comments interleaved with the changes that shaped
+// the discussion (status, assignee, priority, resolution), in the order
+// Jira's activity tab shows them, so the model reads "moved to Blocked"
+// right before the comment explaining why.
+
+// pivotalFields are the changelog fields an activity timeline shows by
+// default.
+var pivotalFields = []string{"status", "assignee", "priority", "resolution"}
+
+type activityEvent struct {
+	At        string   `json:"at"`
+	Kind      string   `json:"kind"` // comment or change
+	Author    string   `json:"author,omitempty"`
+	CommentID string   `json:"comment_id,omitempty"`
+	Body      string   `json:"body,omitempty"`
+	Changes   []string `json:"changes,omitempty"` // e.g. "status: To Do -> In Progress"
+}
+
+// AllComments returns every comment on key, oldest first.
+func (c *JiraClient) AllComments(ctx context.Context, key string) ([]JiraComment, error) {
+	var out []JiraComment
+	for {
+		page, err := c.GetComments(ctx, key, len(out), 100, "created")
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, page.Comments...)
+		if len(page.Comments) == 0 || len(out) >= page.Total {
+			return out, nil
+		}
+	}
+}
+
+// activityTimeline merges comments and histories (already filtered to the
+// fields of interest) chronologically.
+func activityTimeline(comments []JiraComment, histories []JiraChangeHistory, since time.Time) []activityEvent {
+	type timed struct {
+		at time.Time
+		ev activityEvent
+	}
+	var all []timed
+	for _, cm := range comments {
+		t, _ := parseJiraTime(cm.Created)
+		if !since.IsZero() && t.Before(since) {
+			continue
+		}
+		v := viewComment(cm)
+		all = append(all, timed{t, activityEvent{At: cm.Created, Kind: "comment", Author: v.Author, CommentID: v.ID, Body: v.Body}})
+	}
+	for _, h := range histories {
+		t, _ := parseJiraTime(h.Created)
+		ev := activityEvent{At: h.Created, Kind: "change", Author: fieldString(h.Author, "displayName")}
+		for _, it := range h.Items {
+			from, to := it.FromString, it.ToString
+			if from == "" {
+				from = "none"
+			}
+			if to == "" {
+				to = "none"
+			}
+			ev.Changes = append(ev.Changes, fmt.Sprintf("%s: %s -> %s", strings.ToLower(it.Field), from, to))
+		}
+		all = append(all, timed{t, ev})
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].at.Before(all[j].at) })
+	out := make([]activityEvent, len(all))
+	for i, e := range all {
+		out[i] = e.ev
+	}
+	return out
+}
@@ -0,0 +1,880 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Atlassian Document Format (ADF) <-> Markdown ----
+//
+// Jira Cloud's v3 API takes and returns rich text as ADF. Agents speak
+// Markdown, so write paths convert Markdown to ADF and read paths render ADF
+// back to Markdown. Mentions use the form @[Display Name](accountId), which
+// renders back the same way so text can round-trip.
+
+type adfNode struct {
+	Type    string         `json:"type"`
+	Version int            `json:"version,omitempty"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+	Content []*adfNode     `json:"content,omitempty"`
+	Text    string         `json:"text,omitempty"`
+	Marks   []adfMark      `json:"marks,omitempty"`
+}
+
+type adfMark struct {
+	Type  string         `json:"type"`
+	Attrs map[string]any `json:"attrs,omitempty"`
+}
+
+func adfText(s string, marks ...adfMark) *adfNode {
+	return &adfNode{Type: "text", Text: s, Marks: marks}
+}
+
+func adfParagraph(content ...*adfNode) *adfNode {
+	return &adfNode{Type: "paragraph", Content: content}
+}
+
+func adfDoc(content ...*adfNode) *adfNode {
+	if len(content) == 0 {
+		content = []*adfNode{adfParagraph()}
+	}
+	return &adfNode{Type: "doc", Version: 1, Content: content}
+}
+
+// markdownToADF converts Markdown to an ADF document. Warnings describe
+// constructs Jira cannot represent and how they were degraded.
+func markdownToADF(md string) (*adfNode, []string) {
+	p := &mdParser{}
+	md = strings.ReplaceAll(md, "\r\n", "\n")
+	doc := adfDoc(p.parseBlocks(strings.Split(md, "\n"), "doc")...)
+	return doc, p.warningList()
+}
+
+// ---- Markdown block parsing ----
+
+var (
+	mdFenceRe     = regexp.MustCompile("^\\s*(```+|~~~+)\\s*([\\w+#.-]*)")
+	mdHeadingRe   = regexp.MustCompile(`^\s{0,3}(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdRuleRe      = regexp.MustCompile(`^\s{0,3}([-*_])(\s*[-*_]){2,}\s*$`)
+	mdQuoteRe     = regexp.MustCompile(`^\s{0,3}>\s?(.*)$`)
+	mdListItemRe  = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])\s+(.*)$`)
+	mdTableSepRe  = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	mdTaskRe      = regexp.MustCompile(`^\[([ xX])\]\s+`)
+	mdFootnoteRe  = regexp.MustCompile(`^\s{0,3}\[\^[^\]]+\]:`)
+	mdHTMLBlockRe = regexp.MustCompile(`^\s{0,3}</?[a-zA-Z][a-zA-Z0-9-]*(\s[^>]*)?/?>`)
+)
+
+type mdParser struct {
+	warnings map[string]bool
+}
+
+func (p *mdParser) warn(format string, args ...any) {
+	if p.warnings == nil {
+		p.warnings = map[string]bool{}
+	}
+	p.warnings[fmt.Sprintf(format, args...)] = true
+}
+
+func (p *mdParser) warningList() []string {
+	out := make([]string, 0, len(p.warnings))
+	for w := range p.warnings {
+		out = append(out, w)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func leadingSpaces(s string) int {
+	n := 0
+	for _, r := range s {
+		switch r {
+		case ' ':
+			n++
+		case '\t':
+			n += 4
+		default:
+			return n
+		}
+	}
+	return n
+}
+
+func dedent(s string, n int) string {
+	for n > 0 && len(s) > 0 && (s[0] == ' ' || s[0] == '\t') {
+		if s[0] == '\t' {
+			n -= 4
+		} else {
+			n--
+		}
+		s = s[1:]
+	}
+	return s
+}
+
+func isTableRow(lines []string, i int) bool {
+	return strings.Contains(lines[i], "|") && i+1 < len(lines) && mdTableSepRe.MatchString(lines[i+1]) && strings.Contains(lines[i+1], "-")
+}
+
+func (p *mdParser) startsBlock(lines []string, i int) bool {
+	l := lines[i]
+	return mdFenceRe.MatchString(l) || mdHeadingRe.MatchString(l) || mdRuleRe.MatchString(l) ||
+		mdQuoteRe.MatchString(l) || mdListItemRe.MatchString(l) || isTableRow(lines, i)
+}
+
+// parseBlocks parses lines into block nodes valid inside container (doc,
+// listItem, blockquote or tableCell).
+func (p *mdParser) parseBlocks(lines []string, container string) []*adfNode {
+	var out []*adfNode
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+		case mdFenceRe.MatchString(line):
+			m := mdFenceRe.FindStringSubmatch(line)
+			fence := m[1]
+			var code []string
+			i++
+			for i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+				code = append(code, lines[i])
+				i++
+			}
+			if i < len(lines) {
+				i++ // closing fence
+			}
+			n := &adfNode{Type: "codeBlock"}
+			if m[2] != "" {
+				n.Attrs = map[string]any{"language": m[2]}
+			}
+			if text := strings.Join(code, "\n"); text != "" {
+				n.Content = []*adfNode{adfText(text)}
+			}
+			out = append(out, n)
+		case mdHeadingRe.MatchString(line):
+			m := mdHeadingRe.FindStringSubmatch(line)
+			out = append(out, &adfNode{
+				Type:    "heading",
+				Attrs:   map[string]any{"level": len(m[1])},
+				Content: p.parseInline(m[2]),
+			})
+			i++
+		case mdRuleRe.MatchString(line):
+			out = append(out, &adfNode{Type: "rule"})
+			i++
+		case mdQuoteRe.MatchString(line):
+			var inner []string
+			for i < len(lines) && mdQuoteRe.MatchString(lines[i]) {
+				inner = append(inner, mdQuoteRe.FindStringSubmatch(lines[i])[1])
+				i++
+			}
+			out = append(out, &adfNode{Type: "blockquote", Content: p.parseBlocks(inner, "blockquote")})
+		case isTableRow(lines, i):
+			var n *adfNode
+			n, i = p.parseTable(lines, i)
+			out = append(out, n)
+		case mdListItemRe.MatchString(line):
+			var n *adfNode
+			n, i = p.parseList(lines, i)
+			out = append(out, n)
+		default:
+			if mdFootnoteRe.MatchString(line) {
+				p.warn("footnotes are not supported; kept as plain text")
+			}
+			if mdHTMLBlockRe.MatchString(line) {
+				p.warn("raw HTML is not supported; kept as plain text")
+			}
+			var para []string
+			for i < len(lines) && strings.TrimSpace(lines[i]) != "" && (len(para) == 0 || !p.startsBlock(lines, i)) {
+				para = append(para, strings.TrimSpace(lines[i]))
+				i++
+			}
+			var content []*adfNode
+			for j, l := range para {
+				if j > 0 {
+					content = append(content, &adfNode{Type: "hardBreak"})
+				}
+				content = append(content, p.parseInline(strings.TrimSuffix(l, "\\"))...)
+			}
+			out = append(out, adfParagraph(content...))
+		}
+	}
+	return p.fitContainer(out, container)
+}
+
+// adfAllowed lists the block types each container accepts.
+var adfAllowed = map[string]map[string]bool{
+	"doc":        {"paragraph": true, "heading": true, "bulletList": true, "orderedList": true, "codeBlock": true, "blockquote": true, "rule": true, "table": true},
+	"listItem":   {"paragraph": true, "bulletList": true, "orderedList": true, "codeBlock": true},
+	"blockquote": {"paragraph": true, "bulletList": true, "orderedList": true, "codeBlock": true},
+	"tableCell":  {"paragraph": true, "heading": true, "bulletList": true, "orderedList": true, "codeBlock": true, "blockquote": true, "rule": true},
+}
+
+// fitContainer degrades blocks that Jira would reject inside container.
+func (p *mdParser) fitContainer(nodes []*adfNode, container string) []*adfNode {
+	allowed := adfAllowed[container]
+	var out []*adfNode
+	for _, n := range nodes {
+		if allowed[n.Type] {
+			out = append(out, n)
+			continue
+		}
+		where := map[string]string{"listItem": "list items", "blockquote": "quotes", "tableCell": "table cells"}[container]
+		switch n.Type {
+		case "heading":
+			p.warn("headings inside %s are not supported; rendered as bold text", where)
+			for _, c := range n.Content {
+				if c.Type == "text" {
+					c.Marks = append(c.Marks, adfMark{Type: "strong"})
+				}
+			}
+			out = append(out, adfParagraph(n.Content...))
+		case "blockquote":
+			p.warn("quotes inside %s are not supported; flattened", where)
+			out = append(out, p.fitContainer(n.Content, container)...)
+		case "rule":
+			p.warn("horizontal rules inside %s are not supported; dropped", where)
+		case "table":
+			p.warn("tables inside %s are not supported; rendered as text rows", where)
+			for _, row := range n.Content {
+				var cells []*adfNode
+				for j, cell := range row.Content {
+					if j > 0 {
+						cells = append(cells, adfText(" | "))
+					}
+					for _, b := range cell.Content {
+						cells = append(cells, b.Content...)
+					}
+				}
+				out = append(out, adfParagraph(cells...))
+			}
+		default:
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, "\\|") {
+		line = line[:len(line)-1]
+	}
+	var cells []string
+	var cur strings.Builder
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' && i+1 < len(line) && line[i+1] == '|' {
+			cur.WriteByte('|')
+			i++
+			continue
+		}
+		if line[i] == '|' {
+			cells = append(cells, strings.TrimSpace(cur.String()))
+			cur.Reset()
+			continue
+		}
+		cur.WriteByte(line[i])
+	}
+	return append(cells, strings.TrimSpace(cur.String()))
+}
+
+func (p *mdParser) parseTable(lines []string, i int) (*adfNode, int) {
+	header := splitTableRow(lines[i])
+	i += 2 // header + separator
+	table := &adfNode{Type: "table", Attrs: map[string]any{"isNumberColumnEnabled": false, "layout": "default"}}
+	row := func(cells []string, cellType string) *adfNode {
+		r := &adfNode{Type: "tableRow"}
+		for j := range header {
+			text := ""
+			if j < len(cells) {
+				text = cells[j]
+			}
+			r.Content = append(r.Content, &adfNode{Type: cellType, Content: []*adfNode{adfParagraph(p.parseInline(text)...)}})
+		}
+		return r
+	}
+	table.Content = append(table.Content, row(header, "tableHeader"))
+	for i < len(lines) && strings.Contains(lines[i], "|") && strings.TrimSpace(lines[i]) != "" {
+		cells := splitTableRow(lines[i])
+		if len(cells) > len(header) {
+			p.warn("table rows with more cells than the header row are truncated")
+		}
+		table.Content = append(table.Content, row(cells, "tableCell"))
+		i++
+	}
+	return table, i
+}
+
+func isOrderedMarker(m string) bool {
+	return m[0] >= '0' && m[0] <= '9'
+}
+
+func (p *mdParser) parseList(lines []string, i int) (*adfNode, int) {
+	first := mdListItemRe.FindStringSubmatch(lines[i])
+	indent := leadingSpaces(first[1])
+	ordered := isOrderedMarker(first[2])
+	list := &adfNode{Type: "bulletList"}
+	if ordered {
+		list.Type = "orderedList"
+		if n, _ := strconv.Atoi(strings.TrimRight(first[2], ".)")); n > 1 {
+			list.Attrs = map[string]any{"order": n}
+		}
+	}
+	for i < len(lines) {
+		m := mdListItemRe.FindStringSubmatch(lines[i])
+		if m == nil || leadingSpaces(m[1]) != indent || isOrderedMarker(m[2]) != ordered {
+			break
+		}
+		contentIndent := indent + len(m[2]) + 1
+		text := m[3]
+		if mdTaskRe.MatchString(text) {
+			p.warn("task lists are not supported; rendered as plain list items")
+		}
+		itemLines := []string{text}
+		i++
+		for i < len(lines) {
+			l := lines[i]
+			if strings.TrimSpace(l) == "" {
+				j := i + 1
+				for j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+					j++
+				}
+				if j < len(lines) && leadingSpaces(lines[j]) > indent {
+					itemLines = append(itemLines, "")
+					i++
+					continue
+				}
+				break
+			}
+			if ls := leadingSpaces(l); ls > indent {
+				itemLines = append(itemLines, dedent(l, min(ls, contentIndent)))
+				i++
+				continue
+			}
+			if p.startsBlock(lines, i) {
+				break
+			}
+			// Lazy continuation of the item's paragraph.
+			itemLines = append(itemLines, l)
+			i++
+		}
+		item := &adfNode{Type: "listItem", Content: p.parseBlocks(itemLines, "listItem")}
+		if len(item.Content) == 0 || item.Content[0].Type != "paragraph" {
+			item.Content = append([]*adfNode{adfParagraph()}, item.Content...)
+		}
+		list.Content = append(list.Content, item)
+	}
+	return list, i
+}
+
+// ---- Markdown inline parsing ----
+
+var (
+	mdMentionRe    = regexp.MustCompile(`^@\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdJiraMention  = regexp.MustCompile(`^\[~accountid:([^\]]+)\]`)
+	mdLinkRe       = regexp.MustCompile(`^\[((?:[^\[\]]|\[[^\]]*\])*)\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+	mdImageRe      = regexp.MustCompile(`^!\[([^\]]*)\]\(([^)\s]+)(?:\s+"[^"]*")?\)`)
+	mdAutolinkRe   = regexp.MustCompile(`^<(https?://[^>\s]+)>`)
+	mdBareURLRe    = regexp.MustCompile(`^https?://[^\s<>()\[\]]+[^\s<>()\[\].,;:!?'"]`)
+	mdInlineHTMLRe = regexp.MustCompile(`^</?[a-zA-Z][a-zA-Z0-9-]*(\s[^>]*)?/?>`)
+	mdFootnoteRef  = regexp.MustCompile(`^\[\^[^\]]+\]`)
+)
+
+func isWordByte(b byte) bool {
+	return b == '_' || (b >= '0' && b <= '9') || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || b >= 0x80
+}
+
+func withMark(marks []adfMark, m adfMark) []adfMark {
+	out := make([]adfMark, 0, len(marks)+1)
+	out = append(out, marks...)
+	return append(out, m)
+}
+
+func (p *mdParser) parseInline(s string) []*adfNode {
+	return p.inline(s, nil)
+}
+
+func (p *mdParser) inline(s string, marks []adfMark) []*adfNode {
+	var out []*adfNode
+	var buf strings.Builder
+	flush := func() {
+		if buf.Len() > 0 {
+			out = append(out, adfText(buf.String(), marks...))
+			buf.Reset()
+		}
+	}
+	for i := 0; i < len(s); {
+		rest := s[i:]
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte("\\`*_{}[]()#+-.!|~<>@", s[i+1]) >= 0:
+			buf.WriteByte(s[i+1])
+			i += 2
+			continue
+		case c == '`':
+			n := len(rest) - len(strings.TrimLeft(rest, "`"))
+			ticks := rest[:n]
+			if end := strings.Index(rest[n:], ticks); end >= 0 {
+				flush()
+				code := strings.TrimSpace(rest[n : n+end])
+				if code != "" {
+					// ADF only allows link alongside code.
+					var codeMarks []adfMark
+					for _, m := range marks {
+						if m.Type == "link" {
+							codeMarks = append(codeMarks, m)
+						}
+					}
+					out = append(out, adfText(code, withMark(codeMarks, adfMark{Type: "code"})...))
+				}
+				i += n + end + n
+				continue
+			}
+		case c == '@' && mdMentionRe.MatchString(rest):
+			m := mdMentionRe.FindStringSubmatch(rest)
+			flush()
+			out = append(out, &adfNode{Type: "mention", Attrs: map[string]any{"id": m[2], "text": "@" + m[1]}})
+			i += len(m[0])
+			continue
+		case c == '[' && mdJiraMention.MatchString(rest):
+			m := mdJiraMention.FindStringSubmatch(rest)
+			flush()
+			out = append(out, &adfNode{Type: "mention", Attrs: map[string]any{"id": m[1]}})
+			i += len(m[0])
+			continue
+		case c == '!' && mdImageRe.MatchString(rest):
+			m := mdImageRe.FindStringSubmatch(rest)
+			p.warn("images must be uploaded as attachments; rendered as links")
+			flush()
+			label := m[1]
+			if label == "" {
+				label = m[2]
+			}
+			out = append(out, p.inline(label, withMark(marks, adfMark{Type: "link", Attrs: map[string]any{"href": m[2]}}))...)
+			i += len(m[0])
+			continue
+		case c == '[' && mdFootnoteRef.MatchString(rest):
+			p.warn("footnotes are not supported; kept as plain text")
+		case c == '[' && mdLinkRe.MatchString(rest):
+			m := mdLinkRe.FindStringSubmatch(rest)
+			flush()
+			out = append(out, p.inline(m[1], withMark(marks, adfMark{Type: "link", Attrs: map[string]any{"href": m[2]}}))...)
+			i += len(m[0])
+			continue
+		case c == '<' && mdAutolinkRe.MatchString(rest):
+			m := mdAutolinkRe.FindStringSubmatch(rest)
+			flush()
+			out = append(out, adfText(m[1], withMark(marks, adfMark{Type: "link", Attrs: map[string]any{"href": m[1]}})...))
+			i += len(m[0])
+			continue
+		case c == '<' && mdInlineHTMLRe.MatchString(rest):
+			p.warn("raw HTML is not supported; kept as plain text")
+		case c == 'h' && (i == 0 || !isWordByte(s[i-1])) && mdBareURLRe.MatchString(rest) && !hasMark(marks, "link"):
+			u := mdBareURLRe.FindString(rest)
+			flush()
+			out = append(out, adfText(u, withMark(marks, adfMark{Type: "link", Attrs: map[string]any{"href": u}})...))
+			i += len(u)
+			continue
+		case strings.HasPrefix(rest, "~~"):
+			if end := strings.Index(rest[2:], "~~"); end > 0 {
+				flush()
+				out = append(out, p.inline(rest[2:2+end], withMark(marks, adfMark{Type: "strike"}))...)
+				i += end + 4
+				continue
+			}
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			delim := rest[:2]
+			if end := strings.Index(rest[2:], delim); end > 0 && (delim == "**" || closesUnderscore(s, i+2+end+2)) {
+				flush()
+				out = append(out, p.inline(rest[2:2+end], withMark(marks, adfMark{Type: "strong"}))...)
+				i += end + 4
+				continue
+			}
+		case (c == '*' || c == '_') && i+1 < len(s) && s[i+1] != ' ' && (c == '*' || i == 0 || !isWordByte(s[i-1])):
+			if end := findEmphasisClose(rest[1:], c); end > 0 && (c == '*' || closesUnderscore(s, i+1+end+1)) {
+				flush()
+				out = append(out, p.inline(rest[1:1+end], withMark(marks, adfMark{Type: "em"}))...)
+				i += end + 2
+				continue
+			}
+		}
+		buf.WriteByte(c)
+		i++
+	}
+	flush()
+	return out
+}
+
+func hasMark(marks []adfMark, t string) bool {
+	for _, m := range marks {
+		if m.Type == t {
+			return true
+		}
+	}
+	return false
+}
+
+// closesUnderscore reports whether an underscore delimiter ending just before
+// pos is at a word boundary (snake_case identifiers stay literal).
+func closesUnderscore(s string, pos int) bool {
+	return pos >= len(s) || !isWordByte(s[pos])
+}
+
+func findEmphasisClose(s string, delim byte) int {
+	for j := 0; j < len(s); j++ {
+		switch {
+		case s[j] == '\\':
+			j++
+		case s[j] == delim && j > 0 && s[j-1] != ' ':
+			if j+1 < len(s) && s[j+1] == delim {
+				j++ // part of a strong delimiter
+				continue
+			}
+			return j
+		}
+	}
+	return -1
+}
+
+// ---- ADF -> Markdown rendering ----
+
+// decodeADF accepts a raw ADF value (as decoded from Jira JSON) and returns
+// the node tree, or nil if v is not ADF.
+func decodeADF(v any) *adfNode {
+	switch t := v.(type) {
+	case *adfNode:
+		return t
+	case map[string]any:
+		if _, ok := t["type"].(string); !ok {
+			return nil
+		}
+		b, err := json.Marshal(t)
+		if err != nil {
+			return nil
+		}
+		var n adfNode
+		if json.Unmarshal(b, &n) != nil {
+			return nil
+		}
+		return &n
+	}
+	return nil
+}
+
+// adfToMarkdown renders rich text to Markdown. Plain strings (Server/DC, or
+// fields that are not ADF) are returned unchanged.
+func adfToMarkdown(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	n := decodeADF(v)
+	if n == nil {
+		return ""
+	}
+	return strings.TrimRight(renderBlocks(n.Content), "\n")
+}
+
+func renderBlocks(nodes []*adfNode) string {
+	var parts []string
+	for _, n := range nodes {
+		if s := renderBlock(n); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// indentLines prefixes every line but the first (which follows a list marker).
+func indentLines(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if i == 0 || l == "" {
+			continue
+		}
+		lines[i] = prefix + l
+	}
+	return strings.Join(lines, "\n")
+}
+
+func quoteLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight("> "+l, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func attrString(n *adfNode, key string) string {
+	switch v := n.Attrs[key].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	}
+	return ""
+}
+
+func renderBlock(n *adfNode) string {
+	switch n.Type {
+	case "paragraph":
+		return renderInline(n.Content)
+	case "heading":
+		level, _ := strconv.Atoi(attrString(n, "level"))
+		level = max(1, min(level, 6))
+		return strings.Repeat("#", level) + " " + renderInline(n.Content)
+	case "bulletList", "orderedList":
+		start := 1
+		if o, err := strconv.Atoi(attrString(n, "order")); err == nil && o > 0 {
+			start = o
+		}
+		var items []string
+		for i, item := range n.Content {
+			marker := "- "
+			if n.Type == "orderedList" {
+				marker = fmt.Sprintf("%d. ", start+i)
+			}
+			body := renderListItem(item)
+			items = append(items, marker+indentLines(body, strings.Repeat(" ", len(marker))))
+		}
+		return strings.Join(items, "\n")
+	case "taskList", "decisionList":
+		var items []string
+		for _, item := range n.Content {
+			box := "[ ] "
+			if attrString(item, "state") == "DONE" || attrString(item, "state") == "DECIDED" {
+				box = "[x] "
+			}
+			items = append(items, "- "+box+indentLines(renderInline(item.Content), "  "))
+		}
+		return strings.Join(items, "\n")
+	case "codeBlock":
+		var text strings.Builder
+		for _, c := range n.Content {
+			text.WriteString(c.Text)
+		}
+		return "```" + attrString(n, "language") + "\n" + text.String() + "\n```"
+	case "blockquote":
+		return quoteLines(renderBlocks(n.Content))
+	case "panel":
+		label := strings.ToUpper(attrString(n, "panelType"))
+		if label == "" {
+			label = "NOTE"
+		}
+		return quoteLines("**" + label + ":** " + renderBlocks(n.Content))
+	case "expand", "nestedExpand":
+		title := attrString(n, "title")
+		body := renderBlocks(n.Content)
+		if title == "" {
+			return body
+		}
+		return "**" + title + "**\n\n" + body
+	case "rule":
+		return "---"
+	case "table":
+		return renderTable(n)
+	case "mediaSingle", "mediaGroup":
+		var parts []string
+		for _, m := range n.Content {
+			parts = append(parts, renderMedia(m))
+		}
+		return strings.Join(parts, "\n")
+	case "media":
+		return renderMedia(n)
+	case "blockCard", "embedCard":
+		return "<" + attrString(n, "url") + ">"
+	}
+	if len(n.Content) > 0 {
+		return renderBlocks(n.Content)
+	}
+	return renderInline([]*adfNode{n})
+}
+
+func renderListItem(item *adfNode) string {
+	var parts []string
+	for _, c := range item.Content {
+		if s := renderBlock(c); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func renderMedia(n *adfNode) string {
+	name := attrString(n, "alt")
+	if name == "" {
+		name = attrString(n, "id")
+	}
+	return "[attachment: " + name + "]"
+}
+
+func renderTable(n *adfNode) string {
+	var rows []string
+	for i, row := range n.Content {
+		var cells []string
+		for _, cell := range row.Content {
+			text := renderBlocks(cell.Content)
+			text = strings.ReplaceAll(strings.ReplaceAll(text, "|", "\\|"), "\n", " ")
+			cells = append(cells, text)
+		}
+		rows = append(rows, "| "+strings.Join(cells, " | ")+" |")
+		if i == 0 {
+			seps := make([]string, len(cells))
+			for j := range seps {
+				seps[j] = "---"
+			}
+			rows = append(rows, "| "+strings.Join(seps, " | ")+" |")
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+var mdEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "`", "\\`", "~~", `\~\~`)
+
+func renderInline(nodes []*adfNode) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		switch n.Type {
+		case "text":
+			b.WriteString(renderMarks(n))
+		case "hardBreak":
+			b.WriteString("\n")
+		case "mention":
+			name := strings.TrimPrefix(attrString(n, "text"), "@")
+			if name == "" {
+				name = attrString(n, "id")
+			}
+			b.WriteString("@[" + name + "](" + attrString(n, "id") + ")")
+		case "emoji":
+			if t := attrString(n, "text"); t != "" {
+				b.WriteString(t)
+			} else {
+				b.WriteString(attrString(n, "shortName"))
+			}
+		case "inlineCard":
+			b.WriteString("<" + attrString(n, "url") + ">")
+		case "date":
+			if ms, err := strconv.ParseInt(attrString(n, "timestamp"), 10, 64); err == nil {
+				b.WriteString(time.UnixMilli(ms).UTC().Format("2006-01-02"))
+			}
+		case "status":
+			b.WriteString("[" + strings.ToUpper(attrString(n, "text")) + "]")
+		default:
+			b.WriteString(renderInline(n.Content))
+		}
+	}
+	return b.String()
+}
+
+func renderMarks(n *adfNode) string {
+	text := n.Text
+	if hasMark(n.Marks, "code") {
+		ticks := "`"
+		for strings.Contains(text, ticks) {
+			ticks += "`"
+		}
+		text = ticks + text + ticks
+	} else {
+		text = mdEscaper.Replace(text)
+	}
+	var href string
+	for _, m := range n.Marks {
+		switch m.Type {
+		case "strong":
+			text = "**" + text + "**"
+		case "em":
+			text = "*" + text + "*"
+		case "strike":
+			text = "~~" + text + "~~"
+		case "link":
+			href, _ = m.Attrs["href"].(string)
+		}
+	}
+	if href != "" && href != n.Text {
+		text = "[" + text + "](" + href + ")"
+	} else if href != "" {
+		text = "<" + href + ">"
+	}
+	return text
+}
+
+// renderADFValues replaces every ADF document found in v (at any depth)
+// with its Markdown rendering, so issue payloads stay small and readable.
+func renderADFValues(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		if t["type"] == "doc" {
+			if _, ok := t["content"]; ok {
+				return adfToMarkdown(t)
+			}
+		}
+		for k, e := range t {
+			t[k] = renderADFValues(e)
+		}
+	case []any:
+		for i, e := range t {
+			t[i] = renderADFValues(e)
+		}
+	}
+	return v
+}
+
+// renderIssueText renders the rich-text fields of issues as Markdown.
+func renderIssueText(issues ...*JiraIssue) {
+	for _, iss := range issues {
+		if iss == nil {
+			continue
+		}
+		renderADFValues(iss.Fields)
+		renderADFValues(iss.Aliased)
+	}
+}
+
+type richTextPreview struct {
+	ADF            *adfNode `json:"adf"`
+	Markdown       string   `json:"markdown"`
+	RoundTripExact bool     `json:"round_trip_exact"`
+	Warnings       []string `json:"warnings,omitempty"`
+}
+
+func registerRichTextTools(server *mcp.Server) {
+	// preview_rich_text(markdown)
+	type previewArgs struct {
+		Markdown string `json:"markdown" jsonschema:"Markdown text as it would be sent in a description or comment"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "preview_rich_text",
+		Title:       "Preview Rich Text",
+		Description: "Convert Markdown to the Atlassian Document Format that would be sent to Jira, render it back to Markdown, and warn about unsupported constructs",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args previewArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=preview_rich_text args={len:%d}", len(args.Markdown))
+		doc, warnings := markdownToADF(args.Markdown)
+		md := adfToMarkdown(doc)
+		return &mcp.CallToolResult{StructuredContent: richTextPreview{
+			ADF:            doc,
+			Markdown:       md,
+			RoundTripExact: strings.TrimSpace(md) == strings.TrimSpace(strings.ReplaceAll(args.Markdown, "\r\n", "\n")),
+			Warnings:       warnings,
+		}}, nil, nil
+	})
+}
+
+// richText converts Markdown to the body format Jira expects for rich text
+// fields (descriptions, comments): ADF, or wiki markup on the v2 API.
+func (c *JiraClient) richText(ctx context.Context, md string) any {
+	doc, _ := markdownToADF(md)
+	if c.useAPIV2(ctx) {
+		return adfToWiki(doc)
+	}
+	return doc
+}
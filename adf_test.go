@@ -0,0 +1,65 @@
+package jira
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestADFRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		md   string
+		want string // "" when the Markdown survives unchanged
+	}{
+		{"paragraphs", "First line\n\nSecond line", ""},
+		{"heading", "## Steps", ""},
+		{"emphasis", "**bold**, *italic*, ~~gone~~ and `code`", ""},
+		{"link", "See [the docs](https://example.com/docs).", ""},
+		{"mention", "Ping @[Ada Lovelace](5b10ac8d82e05b22cc7d4ef5) please", ""},
+		{"bullet list", "- one\n- two\n- three", ""},
+		{"nested list", "- one\n  - one.a\n  - one.b\n- two", ""},
+		{"ordered list", "1. first\n2. second", ""},
+		{"code block", "```go\nfmt.Println(\"hi\")\n```", ""},
+		{"quote", "> quoted text", ""},
+		{"rule", "above\n\n---\n\nbelow", ""},
+		{"table", "| Key | Status |\n| --- | --- |\n| PROJ-1 | Done |", ""},
+		{"star bullets", "* one\n* two", "- one\n- two"},
+		{"crlf", "one\r\n\r\ntwo", "one\n\ntwo"},
+	}
+	for _, tt := range tests {
+		doc, _ := markdownToADF(tt.md)
+		// Render from decoded JSON, as values read back from Jira are.
+		b, err := json.Marshal(doc)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		var raw map[string]any
+		if err := json.Unmarshal(b, &raw); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		want := tt.want
+		if want == "" {
+			want = tt.md
+		}
+		if got := adfToMarkdown(raw); got != want {
+			t.Errorf("%s: round trip of %q = %q, want %q", tt.name, tt.md, got, want)
+		}
+	}
+}
+
+func TestMarkdownToADFWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		md       string
+		wantWarn bool
+	}{
+		{"plain", "Just text with **bold**", false},
+		{"footnote", "Text[^1]\n\n[^1]: note", true},
+		{"html block", "<div>raw</div>", true},
+	}
+	for _, tt := range tests {
+		if _, warnings := markdownToADF(tt.md); (len(warnings) > 0) != tt.wantWarn {
+			t.Errorf("%s: warnings %q, want any %t", tt.name, warnings, tt.wantWarn)
+		}
+	}
+}
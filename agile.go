@@ -0,0 +1,169 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ---- Jira Software (Agile) API ----
+
+type agileIssuePage struct {
+	StartAt    int         `json:"startAt"`
+	MaxResults int         `json:"maxResults"`
+	Total      int         `json:"total"`
+	Issues     []JiraIssue `json:"issues"`
+}
+
+// BoardBacklog returns issues in a board's backlog in rank order. Boards
+// without a backlog (kanban) fall back to all issues on the board.
+func (c *JiraClient) BoardBacklog(ctx context.Context, boardID int, fields []string, limit int) ([]JiraIssue, error) {
+	base := fmt.Sprintf("/rest/agile/1.0/board/%d/backlog", boardID)
+	issues, err := c.agileIssues(ctx, base, fields, limit)
+	if err != nil {
+		var je *JiraError
+		if errors.As(err, &je) && je.StatusCode == http.StatusBadRequest {
+			return c.agileIssues(ctx, fmt.Sprintf("/rest/agile/1.0/board/%d/issue", boardID), fields, limit)
+		}
+		return nil, err
+	}
+	return issues, nil
+}
+
+func (c *JiraClient) agileIssues(ctx context.Context, path string, fields []string, limit int) ([]JiraIssue, error) {
+	var issues []JiraIssue
+	for startAt := 0; limit <= 0 || len(issues) < limit; {
+		q := url.Values{}
+		q.Set("startAt", fmt.Sprintf("%d", startAt))
+		q.Set("maxResults", "50")
+		if len(fields) > 0 {
+			q.Set("fields", strings.Join(fields, ","))
+		}
+		var page agileIssuePage
+		if err := c.doJSON(ctx, http.MethodGet, path+"?"+q.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		issues = append(issues, page.Issues...)
+		startAt += len(page.Issues)
+		if len(page.Issues) == 0 || startAt >= page.Total {
+			break
+		}
+	}
+	if limit > 0 && len(issues) > limit {
+		issues = issues[:limit]
+	}
+	return issues, nil
+}
+
+// RankResult reports per-issue failures from a rank request (HTTP 207).
+type RankResult struct {
+	Entries []struct {
+		IssueID  int      `json:"issueId"`
+		IssueKey string   `json:"issueKey"`
+		Status   int      `json:"status"`
+		Errors   []string `json:"errors,omitempty"`
+	} `json:"entries,omitempty"`
+}
+
+// maxRankBatch is the most issues the rank endpoint accepts per request.
+const maxRankBatch = 50
+
+// RankIssues moves issues before or after a target issue. Exactly one of
+// before/after must be set. Larger batches are split and chained so the
+// relative order of keys is preserved.
+func (c *JiraClient) RankIssues(ctx context.Context, keys []string, before, after string) (*RankResult, error) {
+	if (before == "") == (after == "") {
+		return nil, errors.New("exactly one of rank_before or rank_after is required")
+	}
+	all := &RankResult{}
+	for len(keys) > 0 {
+		n := min(len(keys), maxRankBatch)
+		batch := keys[:n]
+		keys = keys[n:]
+		body := map[string]any{"issues": batch}
+		if before != "" {
+			body["rankBeforeIssue"] = before
+		} else {
+			body["rankAfterIssue"] = after
+		}
+		var res RankResult
+		if err := c.doJSON(ctx, http.MethodPut, "/rest/agile/1.0/issue/rank", body, &res); err != nil {
+			return nil, err
+		}
+		all.Entries = append(all.Entries, res.Entries...)
+		// Subsequent batches follow the last issue we just placed.
+		before, after = "", batch[len(batch)-1]
+	}
+	return all, nil
+}
+
+// maxSprintMoveBatch is the most issues the sprint and backlog move
+// endpoints accept per request.
+const maxSprintMoveBatch = 50
+
+// MoveToSprint moves issues into a sprint.
+func (c *JiraClient) MoveToSprint(ctx context.Context, sprintID int, keys []string) error {
+	return c.moveIssues(ctx, fmt.Sprintf("/rest/agile/1.0/sprint/%d/issue", sprintID), keys)
+}
+
+// MoveToBacklog removes issues from any sprint and puts them in the backlog.
+func (c *JiraClient) MoveToBacklog(ctx context.Context, keys []string) error {
+	return c.moveIssues(ctx, "/rest/agile/1.0/backlog/issue", keys)
+}
+
+func (c *JiraClient) moveIssues(ctx context.Context, path string, keys []string) error {
+	for len(keys) > 0 {
+		n := min(len(keys), maxSprintMoveBatch)
+		if err := c.doJSON(ctx, http.MethodPost, path, map[string]any{"issues": keys[:n]}, nil); err != nil {
+			return err
+		}
+		keys = keys[n:]
+	}
+	return nil
+}
+
+type JiraSprint struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	State         string `json:"state"` // future, active, or closed
+	Goal          string `json:"goal,omitempty"`
+	StartDate     string `json:"startDate,omitempty"`
+	EndDate       string `json:"endDate,omitempty"`
+	CompleteDate  string `json:"completeDate,omitempty"`
+	OriginBoardID int    `json:"originBoardId,omitempty"`
+}
+
+// CreateSprint creates a future sprint on a board with s's name, goal, and
+// planned dates.
+func (c *JiraClient) CreateSprint(ctx context.Context, boardID int, s JiraSprint) (*JiraSprint, error) {
+	body := map[string]any{"name": s.Name, "originBoardId": boardID}
+	if s.Goal != "" {
+		body["goal"] = s.Goal
+	}
+	if s.StartDate != "" {
+		body["startDate"] = s.StartDate
+	}
+	if s.EndDate != "" {
+		body["endDate"] = s.EndDate
+	}
+	var out JiraSprint
+	if err := c.doJSON(ctx, http.MethodPost, "/rest/agile/1.0/sprint", body, &out); err != nil {
+		return nil, err
+	}
+	noteCreated(ctx, "sprint", strconv.Itoa(out.ID))
+	return &out, nil
+}
+
+// UpdateSprint changes only the given sprint properties, e.g. state,
+// startDate, endDate, or goal.
+func (c *JiraClient) UpdateSprint(ctx context.Context, id int, changes map[string]any) (*JiraSprint, error) {
+	var out JiraSprint
+	if err := c.doJSON(ctx, http.MethodPost, fmt.Sprintf("/rest/agile/1.0/sprint/%d", id), changes, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
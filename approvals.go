@@ -0,0 +1,160 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- JSM approvals ----
+
+type JiraApproval struct {
+	ID            string         `json:"id"`
+	Name          string         `json:"name"`
+	FinalDecision string         `json:"finalDecision"` // pending, approved, or declined
+	CanAnswer     bool           `json:"canAnswerApprovalDecision"`
+	CreatedDate   map[string]any `json:"createdDate"`
+	CompletedDate map[string]any `json:"completedDate,omitempty"`
+	Approvers     []struct {
+		Approver map[string]any `json:"approver"`
+		Decision string         `json:"approverDecision"`
+	} `json:"approvers"`
+}
+
+func (c *JiraClient) Approvals(ctx context.Context, key string) ([]JiraApproval, error) {
+	return sdPage[JiraApproval](ctx, c, "/rest/servicedeskapi/request/"+url.PathEscape(key)+"/approval", nil, 0)
+}
+
+// AnswerApproval approves or declines an approval as the authenticated user.
+func (c *JiraClient) AnswerApproval(ctx context.Context, key, id, decision string) (*JiraApproval, error) {
+	var out JiraApproval
+	path := "/rest/servicedeskapi/request/" + url.PathEscape(key) + "/approval/" + url.PathEscape(id)
+	if err := c.doJSON(ctx, http.MethodPost, path, map[string]any{"decision": decision}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+type approvalView struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Decision  string            `json:"decision"`
+	CanAnswer bool              `json:"can_answer"`
+	Created   string            `json:"created,omitempty"`
+	Completed string            `json:"completed,omitempty"`
+	Approvers map[string]string `json:"approvers"` // display name to decision
+}
+
+func viewApproval(a *JiraApproval) approvalView {
+	v := approvalView{
+		ID: a.ID, Name: a.Name, Decision: a.FinalDecision, CanAnswer: a.CanAnswer,
+		Created: fieldString(a.CreatedDate, "iso8601"), Completed: fieldString(a.CompletedDate, "iso8601"),
+		Approvers: map[string]string{},
+	}
+	for _, ap := range a.Approvers {
+		v.Approvers[orNone(fieldString(ap.Approver, "displayName"))] = ap.Decision
+	}
+	return v
+}
+
+func registerApprovalTools(server *mcp.Server, jc *JiraClient) {
+	// list_approvals(key, pending_only?)
+	type listApprovalsArgs struct {
+		Key         string `json:"key" jsonschema:"Request issue key, e.g. HELP-12"`
+		PendingOnly bool   `json:"pending_only,omitempty" jsonschema:"Only approvals still waiting for a decision"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_approvals",
+		Title:       "List Approvals",
+		Description: "List a request's approvals with each approver's decision and whether the authenticated user can answer",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listApprovalsArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_approvals args={key:%q,pending_only:%t}", args.Key, args.PendingOnly)
+		approvals, err := jc.Approvals(ctx, args.Key)
+		if err != nil {
+			debugf("tool=list_approvals error=%v", err)
+			return nil, nil, err
+		}
+		views := []approvalView{}
+		for i := range approvals {
+			if args.PendingOnly && approvals[i].FinalDecision != "pending" {
+				continue
+			}
+			views = append(views, viewApproval(&approvals[i]))
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"key": args.Key, "approvals": views}}, nil, nil
+	})
+
+	// answer_approval(key, approval_id?, decision, confirm?)
+	type answerApprovalArgs struct {
+		Key        string `json:"key" jsonschema:"Request issue key, e.g. HELP-12"`
+		ApprovalID string `json:"approval_id,omitempty" jsonschema:"Approval to answer (see list_approvals); may be omitted when only one is pending for you"`
+		Decision   string `json:"decision" jsonschema:"approve or decline"`
+		Confirm    bool   `json:"confirm,omitempty" jsonschema:"Only for clients without elicitation: set once the user has approved answering"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "answer_approval",
+		Title:       "Answer Approval",
+		Description: "Approve or decline a pending approval on a request as the authenticated approver. Asks the user to confirm; clients without elicitation pass confirm=true once the user has approved",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: ptr(true)},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args answerApprovalArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=answer_approval args={key:%q,id:%q,decision:%q,confirm:%t}", args.Key, args.ApprovalID, args.Decision, args.Confirm)
+		decision := strings.ToLower(args.Decision)
+		if decision != "approve" && decision != "decline" {
+			return nil, nil, fmt.Errorf("decision must be approve or decline, not %q", args.Decision)
+		}
+		approvals, err := jc.Approvals(ctx, args.Key)
+		if err != nil {
+			debugf("tool=answer_approval error=%v", err)
+			return nil, nil, err
+		}
+		var target *JiraApproval
+		var answerable []string
+		for i, a := range approvals {
+			if a.FinalDecision == "pending" && a.CanAnswer {
+				answerable = append(answerable, a.ID)
+				if args.ApprovalID == "" {
+					target = &approvals[i]
+				}
+			}
+			if a.ID == args.ApprovalID {
+				target = &approvals[i]
+			}
+		}
+		if args.ApprovalID == "" {
+			switch len(answerable) {
+			case 0:
+				return nil, nil, fmt.Errorf("%s has no pending approval you can answer", args.Key)
+			case 1:
+			default:
+				return nil, nil, fmt.Errorf("%s has several pending approvals (%s); give approval_id", args.Key, strings.Join(answerable, ", "))
+			}
+		}
+		switch {
+		case target == nil:
+			return nil, nil, fmt.Errorf("%s has no approval %s", args.Key, args.ApprovalID)
+		case target.FinalDecision != "pending":
+			return nil, nil, fmt.Errorf("approval %s is already %s", target.ID, target.FinalDecision)
+		case !target.CanAnswer:
+			return nil, nil, fmt.Errorf("you are not an approver on approval %s", target.ID)
+		}
+		ok, err := approveAction(ctx, req.Session, args.Confirm, fmt.Sprintf("%s the approval %q on %s?", strings.ToUpper(decision[:1])+decision[1:], target.Name, args.Key))
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			return nil, nil, errors.New("answering the approval was declined by the user")
+		}
+		a, err := jc.AnswerApproval(ctx, args.Key, target.ID, decision)
+		if err != nil {
+			debugf("tool=answer_approval error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"key": args.Key, "approval": viewApproval(a)}}, nil, nil
+	})
+}
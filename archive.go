@@ -0,0 +1,342 @@
+package jira
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Issue snapshot archive ----
+//
+// snapshot_issue preserves an issue's complete current state (all fields,
+// comments, an attachment manifest with SHA-256 hashes, and the changelog)
+// as one JSON document for legal holds. Documents are written once to an
+// ArchiveStore and never modified; they are readable as
+// jira://issue-snapshot/{id}. With JIRA_ARCHIVE_DIR set they are files in
+// that directory, otherwise they last for the process. Embedders can supply
+// their own store (Options.Archive), e.g. object storage with retention
+// locks.
+
+// ArchiveStore keeps immutable documents by name.
+type ArchiveStore interface {
+	// Put stores data under name, failing with ErrArchiveExists if the
+	// name is taken.
+	Put(ctx context.Context, name string, data []byte) error
+	// Get returns the document stored under name, or an error wrapping
+	// os.ErrNotExist.
+	Get(ctx context.Context, name string) ([]byte, error)
+	// List returns the stored names with the given prefix, sorted.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+var ErrArchiveExists = errors.New("archive document already exists")
+
+var archiveNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+type memArchive struct {
+	mu   sync.Mutex
+	docs map[string][]byte
+}
+
+func (m *memArchive) Put(ctx context.Context, name string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.docs[name]; ok {
+		return ErrArchiveExists
+	}
+	if m.docs == nil {
+		m.docs = map[string][]byte{}
+	}
+	m.docs[name] = append([]byte(nil), data...)
+	return nil
+}
+
+func (m *memArchive) Get(ctx context.Context, name string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b, ok := m.docs[name]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", name, os.ErrNotExist)
+	}
+	return b, nil
+}
+
+func (m *memArchive) List(ctx context.Context, prefix string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []string
+	for name := range m.docs {
+		if strings.HasPrefix(name, prefix) {
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// dirArchive stores each document as a read-only file in a directory.
+type dirArchive struct {
+	dir string
+}
+
+func (d dirArchive) path(name string) (string, error) {
+	if !archiveNameRe.MatchString(name) {
+		return "", fmt.Errorf("invalid archive name %q", name)
+	}
+	return filepath.Join(d.dir, name+".json"), nil
+}
+
+func (d dirArchive) Put(ctx context.Context, name string, data []byte) error {
+	path, err := d.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(d.dir, 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o400)
+	if errors.Is(err, os.ErrExist) {
+		return ErrArchiveExists
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
+
+func (d dirArchive) Get(ctx context.Context, name string) ([]byte, error) {
+	path, err := d.path(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", os.ErrNotExist, err)
+	}
+	return os.ReadFile(path)
+}
+
+func (d dirArchive) List(ctx context.Context, prefix string) ([]string, error) {
+	entries, err := os.ReadDir(d.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if ok && !e.IsDir() && strings.HasPrefix(name, prefix) {
+			out = append(out, name)
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// archiveFromEnv returns the store JIRA_ARCHIVE_DIR selects.
+func archiveFromEnv() ArchiveStore {
+	if dir := os.Getenv("JIRA_ARCHIVE_DIR"); dir != "" {
+		return dirArchive{dir: dir}
+	}
+	return &memArchive{}
+}
+
+const issueSnapshotVersion = 1
+
+type archivedAttachment struct {
+	JiraAttachment
+	SHA256    string `json:"sha256,omitempty"`
+	HashError string `json:"hash_error,omitempty"`
+}
+
+type issueSnapshot struct {
+	V           int                  `json:"v"`
+	ID          string               `json:"id"`
+	Key         string               `json:"key"`
+	Site        string               `json:"site"`
+	CapturedAt  string               `json:"captured_at"`
+	CapturedBy  string               `json:"captured_by,omitempty"` // accountId of the Jira user
+	Reason      string               `json:"reason,omitempty"`
+	Issue       *JiraIssue           `json:"issue"`
+	Comments    []JiraComment        `json:"comments"`
+	Attachments []archivedAttachment `json:"attachments"`
+	Changelog   []JiraChangeHistory  `json:"changelog"`
+}
+
+// attachmentSHA256 streams an attachment's content through SHA-256.
+func (c *JiraClient) attachmentSHA256(ctx context.Context, id string) (string, error) {
+	path := "/rest/api/3/attachment/content/" + url.PathEscape(id)
+	resp, err := c.send(ctx, http.MethodGet, path, nil, "")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", &JiraError{Method: http.MethodGet, Path: path, Status: resp.Status, StatusCode: resp.StatusCode, Body: string(b)}
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, resp.Body); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// SnapshotIssue captures key's complete current state and writes it to the
+// archive. It returns the snapshot and the SHA-256 of the stored document.
+func (c *JiraClient) SnapshotIssue(ctx context.Context, key, reason string) (*issueSnapshot, string, error) {
+	iss, err := c.GetIssueFields(ctx, key, []string{"*all"})
+	if err != nil {
+		return nil, "", err
+	}
+	comments, err := c.AllComments(ctx, iss.Key)
+	if err != nil {
+		return nil, "", fmt.Errorf("comments: %w", err)
+	}
+	changelog, err := c.IssueChangelog(ctx, iss.Key)
+	if err != nil {
+		return nil, "", fmt.Errorf("changelog: %w", err)
+	}
+	now := time.Now().UTC()
+	snap := &issueSnapshot{
+		V: issueSnapshotVersion, ID: iss.Key + "_" + now.Format("20060102T150405.000Z"),
+		Key: iss.Key, Site: c.forSite(ctx).BaseURL, CapturedAt: now.Format(time.RFC3339Nano), Reason: reason,
+		Issue: iss, Comments: comments, Changelog: changelog, Attachments: []archivedAttachment{},
+	}
+	if comments == nil {
+		snap.Comments = []JiraComment{}
+	}
+	if changelog == nil {
+		snap.Changelog = []JiraChangeHistory{}
+	}
+	if me, err := c.Myself(ctx); err == nil {
+		snap.CapturedBy = me.AccountID
+	}
+	raw, _ := json.Marshal(iss.Fields["attachment"])
+	var atts []JiraAttachment
+	_ = json.Unmarshal(raw, &atts)
+	for _, a := range atts {
+		aa := archivedAttachment{JiraAttachment: a}
+		// A file that cannot be read is recorded as such rather than
+		// failing the hold.
+		if aa.SHA256, err = c.attachmentSHA256(ctx, a.ID); err != nil {
+			aa.HashError = err.Error()
+		}
+		snap.Attachments = append(snap.Attachments, aa)
+	}
+	b, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return nil, "", err
+	}
+	if err := c.Archive.Put(ctx, snap.ID, b); err != nil {
+		return nil, "", fmt.Errorf("archive: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return snap, hex.EncodeToString(sum[:]), nil
+}
+
+func registerArchiveTools(server *mcp.Server, jc *JiraClient) {
+	// snapshot_issue(key, reason?)
+	type snapshotIssueArgs struct {
+		Key    string `json:"key" jsonschema:"Issue to preserve"`
+		Reason string `json:"reason,omitempty" jsonschema:"Why it is preserved, e.g. the legal hold reference; stored in the snapshot"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "snapshot_issue",
+		Title:       "Snapshot Issue",
+		Description: "Preserve an issue's complete current state (fields, comments, attachment hashes, changelog) as an immutable archived document for compliance holds",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: ptr(false)},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args snapshotIssueArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=snapshot_issue args={key:%q}", args.Key)
+		if args.Key == "" {
+			return nil, nil, errors.New("key is required")
+		}
+		snap, sum, err := jc.SnapshotIssue(ctx, args.Key, args.Reason)
+		if err != nil {
+			debugf("tool=snapshot_issue error=%v", err)
+			return nil, nil, err
+		}
+		unhashed := 0
+		for _, a := range snap.Attachments {
+			if a.HashError != "" {
+				unhashed++
+			}
+		}
+		res := map[string]any{
+			"id": snap.ID, "key": snap.Key, "captured_at": snap.CapturedAt, "sha256": sum,
+			"uri": "jira://issue-snapshot/" + snap.ID, "comments": len(snap.Comments),
+			"attachments": len(snap.Attachments), "changelog_entries": len(snap.Changelog),
+		}
+		if unhashed > 0 {
+			res["attachments_not_hashed"] = unhashed
+		}
+		return &mcp.CallToolResult{StructuredContent: res}, nil, nil
+	})
+
+	// list_issue_snapshots(key)
+	type listIssueSnapshotsArgs struct {
+		Key string `json:"key" jsonschema:"Issue key"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_issue_snapshots",
+		Title:       "List Issue Snapshots",
+		Description: "List the archived snapshots of an issue, oldest first",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listIssueSnapshotsArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_issue_snapshots args={key:%q}", args.Key)
+		names, err := jc.Archive.List(ctx, strings.ToUpper(args.Key)+"_")
+		if err != nil {
+			debugf("tool=list_issue_snapshots error=%v", err)
+			return nil, nil, err
+		}
+		uris := make([]string, len(names))
+		for i, n := range names {
+			uris[i] = "jira://issue-snapshot/" + n
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"key": args.Key, "snapshots": uris}}, nil, nil
+	})
+
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: "jira://issue-snapshot/{id}",
+		Name:        "issue-snapshot",
+		Title:       "Issue Snapshot",
+		Description: "An archived issue snapshot, byte for byte as stored",
+		MIMEType:    "application/json",
+	}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		id := strings.TrimPrefix(req.Params.URI, "jira://issue-snapshot/")
+		debugf("resource=jira://issue-snapshot id=%q", id)
+		b, err := jc.Archive.Get(ctx, id)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, mcp.ResourceNotFoundError(req.Params.URI)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{
+			URI: req.Params.URI, MIMEType: "application/json", Text: string(b),
+		}}}, nil
+	})
+}
@@ -0,0 +1,215 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Attachments ----
+//
+// list_attachments describes an issue's attachments; their content is served
+// on demand through the jira://attachment/{id} resource (and
+// jira://attachment/{id}/thumbnail for images). JIRA_ATTACHMENT_MAX_BYTES
+// caps how much the server will download (default 10 MiB).
+
+const defaultAttachmentMaxBytes = 10 << 20
+
+func attachmentMaxBytes() int64 {
+	if v, err := strconv.ParseInt(os.Getenv("JIRA_ATTACHMENT_MAX_BYTES"), 10, 64); err == nil && v > 0 {
+		return v
+	}
+	return defaultAttachmentMaxBytes
+}
+
+type JiraAttachment struct {
+	ID        string         `json:"id"`
+	Filename  string         `json:"filename"`
+	MimeType  string         `json:"mimeType"`
+	Size      int64          `json:"size"`
+	Created   string         `json:"created,omitempty"`
+	Author    map[string]any `json:"author,omitempty"`
+	Content   string         `json:"content,omitempty"`
+	Thumbnail string         `json:"thumbnail,omitempty"`
+}
+
+func (c *JiraClient) ListAttachments(ctx context.Context, key string) ([]JiraAttachment, error) {
+	var out struct {
+		Fields struct {
+			Attachment []JiraAttachment `json:"attachment"`
+		} `json:"fields"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/issue/"+url.PathEscape(key)+"?fields=attachment", nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Fields.Attachment, nil
+}
+
+func (c *JiraClient) GetAttachment(ctx context.Context, id string) (*JiraAttachment, error) {
+	var out JiraAttachment
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/attachment/"+url.PathEscape(id), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// download fetches a binary endpoint, refusing bodies larger than limit.
+func (c *JiraClient) download(ctx context.Context, path string, limit int64) ([]byte, string, error) {
+	resp, err := c.send(ctx, http.MethodGet, path, nil, "")
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, "", &JiraError{Method: http.MethodGet, Path: path, Status: resp.Status, StatusCode: resp.StatusCode, Body: string(b)}
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if int64(len(b)) > limit {
+		return nil, "", fmt.Errorf("attachment exceeds the %d byte limit", limit)
+	}
+	return b, resp.Header.Get("Content-Type"), nil
+}
+
+// AttachmentContent downloads an attachment, or its thumbnail.
+func (c *JiraClient) AttachmentContent(ctx context.Context, id string, thumbnail bool) ([]byte, string, error) {
+	if thumbnail {
+		return c.download(ctx, "/rest/api/3/attachment/thumbnail/"+url.PathEscape(id), attachmentMaxBytes())
+	}
+	return c.download(ctx, "/rest/api/3/attachment/content/"+url.PathEscape(id), attachmentMaxBytes())
+}
+
+func isTextMime(mime string) bool {
+	mime = strings.ToLower(strings.TrimSpace(strings.Split(mime, ";")[0]))
+	switch {
+	case strings.HasPrefix(mime, "text/"):
+		return true
+	case mime == "application/json", mime == "application/xml", mime == "application/x-yaml", mime == "application/yaml":
+		return true
+	}
+	return false
+}
+
+type attachmentView struct {
+	ID           string `json:"id"`
+	Filename     string `json:"filename"`
+	MimeType     string `json:"mime_type"`
+	Size         int64  `json:"size"`
+	Created      string `json:"created,omitempty"`
+	Author       string `json:"author,omitempty"`
+	URI          string `json:"uri"`
+	ThumbnailURI string `json:"thumbnail_uri,omitempty"`
+	TooLarge     bool   `json:"too_large,omitempty"`
+}
+
+func registerAttachmentTools(server *mcp.Server, jc *JiraClient) {
+	// list_attachments(key, include_thumbnails?)
+	type listAttachmentsArgs struct {
+		Key               string `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+		IncludeThumbnails bool   `json:"include_thumbnails,omitempty" jsonschema:"Also return image thumbnails inline"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_attachments",
+		Title:       "List Attachments",
+		Description: "List an issue's attachments with mime type and size. Read the content through each attachment's jira://attachment/{id} resource",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listAttachmentsArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_attachments args={key:%q,thumbnails:%t}", args.Key, args.IncludeThumbnails)
+		atts, err := jc.ListAttachments(ctx, args.Key)
+		if err != nil {
+			debugf("tool=list_attachments error=%v", err)
+			return nil, nil, err
+		}
+		limit := attachmentMaxBytes()
+		site := jc.siteQuery(ctx)
+		res := &mcp.CallToolResult{}
+		views := make([]attachmentView, len(atts))
+		for i, a := range atts {
+			v := attachmentView{
+				ID:       a.ID,
+				Filename: a.Filename,
+				MimeType: a.MimeType,
+				Size:     a.Size,
+				Created:  a.Created,
+				Author:   fieldString(a.Author, "displayName"),
+				URI:      "jira://attachment/" + a.ID + site,
+				TooLarge: a.Size > limit,
+			}
+			if a.Thumbnail != "" {
+				v.ThumbnailURI = "jira://attachment/" + a.ID + "/thumbnail" + site
+				if args.IncludeThumbnails {
+					b, mime, err := jc.AttachmentContent(ctx, a.ID, true)
+					if err != nil {
+						debugf("tool=list_attachments thumbnail %s: %v", a.ID, err)
+					} else {
+						res.Content = append(res.Content, &mcp.ImageContent{Data: b, MIMEType: mime})
+					}
+				}
+			}
+			views[i] = v
+		}
+		res.StructuredContent = map[string]any{"key": args.Key, "max_bytes": limit, "attachments": views}
+		if len(res.Content) > 0 {
+			res.Content = append([]mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("%d attachment(s) on %s; thumbnails follow", len(views), args.Key)}}, res.Content...)
+		}
+		return res, nil, nil
+	})
+
+	read := func(thumbnail bool) mcp.ResourceHandler {
+		return func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			u, err := url.Parse(req.Params.URI)
+			if err != nil {
+				return nil, err
+			}
+			id := strings.TrimSuffix(strings.TrimPrefix(u.Path, "/"), "/thumbnail")
+			site := u.Query().Get("site")
+			debugf("resource=jira://attachment id=%q thumbnail=%t site=%q", id, thumbnail, site)
+			if ctx, err = jc.resourceSite(ctx, site); err != nil {
+				return nil, err
+			}
+			meta, err := jc.GetAttachment(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if !thumbnail && meta.Size > attachmentMaxBytes() {
+				return nil, fmt.Errorf("attachment %s is %d bytes, over the %d byte limit", id, meta.Size, attachmentMaxBytes())
+			}
+			b, mime, err := jc.AttachmentContent(ctx, id, thumbnail)
+			if err != nil {
+				return nil, err
+			}
+			if !thumbnail && meta.MimeType != "" {
+				mime = meta.MimeType
+			}
+			rc := &mcp.ResourceContents{URI: req.Params.URI, MIMEType: mime}
+			if isTextMime(mime) {
+				rc.Text = string(b)
+			} else {
+				rc.Blob = b
+			}
+			return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{rc}}, nil
+		}
+	}
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: "jira://attachment/{id}{?site}",
+		Name:        "attachment",
+		Title:       "Attachment",
+		Description: "Content of a Jira attachment (size-limited); site names a site other than the default",
+	}, read(false))
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: "jira://attachment/{id}/thumbnail{?site}",
+		Name:        "attachment-thumbnail",
+		Title:       "Attachment Thumbnail",
+		Description: "Thumbnail of an image attachment; site names a site other than the default",
+	}, read(true))
+}
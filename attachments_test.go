@@ -0,0 +1,106 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// authLog records the Authorization header of each request to a fake site.
+type authLog struct {
+	mu   sync.Mutex
+	seen []string
+}
+
+func (l *authLog) add(auth string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.seen = append(l.seen, auth)
+}
+
+func (l *authLog) all() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Clone(l.seen)
+}
+
+// fakeSite is a Jira site whose attachment 1 contains the site's name. Its
+// own credential is "Basic <name>"; the log has what each request carried.
+func fakeSite(t *testing.T, name string) (*JiraClient, *authLog) {
+	t.Helper()
+	auth := &authLog{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth.add(r.Header.Get("Authorization"))
+		switch r.URL.Path {
+		case "/rest/api/3/attachment/1":
+			w.Write([]byte(`{"id": "1", "filename": "site.txt", "mimeType": "text/plain", "size": 16}`))
+		case "/rest/api/3/attachment/content/1":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte(name))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	jc := &JiraClient{BaseURL: srv.URL, Auth: "Basic " + name, Client: srv.Client(), site: name, Instance: instanceConfig{Writes: "allow"}}
+	jc.api.resolved = true
+	return jc, auth
+}
+
+// connect returns a session of client, or of a plain client if nil, on
+// server over in-memory transports.
+func connect(t *testing.T, server *mcp.Server, client *mcp.Client) *mcp.ClientSession {
+	t.Helper()
+	ctx := context.Background()
+	st, ct := mcp.NewInMemoryTransports()
+	ss, err := server.Connect(ctx, st, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ss.Close() })
+	if client == nil {
+		client = mcp.NewClient(&mcp.Implementation{Name: "test"}, nil)
+	}
+	cs, err := client.Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cs.Close() })
+	return cs
+}
+
+func TestAttachmentResourceSite(t *testing.T) {
+	jc, _ := fakeSite(t, "default")
+	dc, _ := fakeSite(t, "dc")
+	jc.sites = []*JiraClient{jc, dc}
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	registerAttachmentTools(server, jc)
+	cs := connect(t, server, nil)
+
+	tests := []struct {
+		uri     string
+		want    string
+		wantErr bool
+	}{
+		{"jira://attachment/1", "default", false},
+		{"jira://attachment/1?site=dc", "dc", false},
+		{"jira://attachment/1?site=DC", "dc", false},
+		{"jira://attachment/1?site=cloud", "", true},
+	}
+	for _, tt := range tests {
+		res, err := cs.ReadResource(context.Background(), &mcp.ReadResourceParams{URI: tt.uri})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, want error %t", tt.uri, err, tt.wantErr)
+			continue
+		}
+		if err == nil && strings.TrimSpace(res.Contents[0].Text) != tt.want {
+			t.Errorf("%s: read %q, want %q", tt.uri, res.Contents[0].Text, tt.want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package jira
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ---- Credential routing per endpoint class ----
+//
+// Some Jira endpoints reject some auth modes (e.g. admin APIs with a scoped
+// token, JSM APIs with a classic token). JIRA_CREDENTIALS configures extra
+// credentials per endpoint class; anything unrouted uses the default
+// JIRA_USER_EMAIL/JIRA_API_TOKEN credential. Example:
+//
+//	{"admin": {"type": "bearer", "token_env": "JIRA_ADMIN_TOKEN",
+//	           "base_url": "https://api.atlassian.com/ex/jira/<cloudId>"},
+//	 "servicedesk": {"type": "basic", "email": "bot@example.com", "token_env": "JSM_TOKEN"}}
+
+type endpointClass string
+
+const (
+	classCore        endpointClass = "core"
+	classAgile       endpointClass = "agile"
+	classServiceDesk endpointClass = "servicedesk"
+	classAdmin       endpointClass = "admin"
+)
+
+var adminPathRe = regexp.MustCompile(`^/rest/api/[23]/(group|groups|role|permissionscheme|workflowscheme|screens|auditing|project/[^/]+/role)(/|\?|$)`)
+
+func classifyPath(path string) endpointClass {
+	switch {
+	case strings.HasPrefix(path, "/rest/agile/"):
+		return classAgile
+	case strings.HasPrefix(path, "/rest/servicedeskapi/"):
+		return classServiceDesk
+	case adminPathRe.MatchString(path):
+		return classAdmin
+	}
+	return classCore
+}
+
+// credential is an Authorization header value plus the base URL it is valid
+// for (scoped OAuth tokens go through api.atlassian.com).
+type credential struct {
+	Name    string
+	BaseURL string
+	Auth    string
+}
+
+type credentialConfig struct {
+	Type     string `json:"type"` // basic or bearer
+	Email    string `json:"email,omitempty"`
+	Token    string `json:"token,omitempty"`
+	TokenEnv string `json:"token_env,omitempty"`
+	BaseURL  string `json:"base_url,omitempty"`
+}
+
+func (cc credentialConfig) build(name, defaultBaseURL string) (credential, error) {
+	token := cc.Token
+	if cc.TokenEnv != "" {
+		token = os.Getenv(cc.TokenEnv)
+	}
+	if token == "" {
+		return credential{}, fmt.Errorf("credential %q: token or token_env must be set", name)
+	}
+	cred := credential{Name: name, BaseURL: strings.TrimRight(cc.BaseURL, "/")}
+	if cred.BaseURL == "" {
+		cred.BaseURL = defaultBaseURL
+	}
+	switch strings.ToLower(cc.Type) {
+	case "", "basic":
+		if cc.Email == "" {
+			return credential{}, fmt.Errorf("credential %q: basic auth needs email", name)
+		}
+		cred.Auth = "Basic " + base64.StdEncoding.EncodeToString([]byte(cc.Email+":"+token))
+	case "bearer", "oauth":
+		cred.Auth = "Bearer " + token
+	default:
+		return credential{}, fmt.Errorf("credential %q: unknown type %q", name, cc.Type)
+	}
+	return cred, nil
+}
+
+// loadCredentialRoutes reads JIRA_CREDENTIALS into per-class credentials.
+func loadCredentialRoutes(defaultBaseURL string) (map[endpointClass]credential, error) {
+	var cfg map[string]credentialConfig
+	if _, err := loadJSONSetting("JIRA_CREDENTIALS", &cfg); err != nil {
+		return nil, err
+	}
+	routes := map[endpointClass]credential{}
+	for name, cc := range cfg {
+		class := endpointClass(strings.ToLower(name))
+		switch class {
+		case classCore, classAgile, classServiceDesk, classAdmin:
+		default:
+			return nil, fmt.Errorf("JIRA_CREDENTIALS: unknown endpoint class %q (valid: core, agile, servicedesk, admin)", name)
+		}
+		cred, err := cc.build(name, defaultBaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("JIRA_CREDENTIALS: %w", err)
+		}
+		routes[class] = cred
+		debugf("credential route: class=%s type=%s base=%s", class, cc.Type, cred.BaseURL)
+	}
+	return routes, nil
+}
+
+// authRouter remembers which credential last worked for each class, so a
+// class whose routed credential is rejected switches to the fallback once
+// rather than failing on every call.
+type authRouter struct {
+	mu       sync.Mutex
+	switched map[endpointClass]bool
+}
+
+// credentialsFor returns the credentials to try for path, in order.
+func (c *JiraClient) credentialsFor(path string) []credential {
+	def := credential{Name: "default", BaseURL: c.BaseURL, Auth: c.authHeader()}
+	if c.OAuth != nil {
+		def.BaseURL = c.OAuth.apiBaseURL()
+	}
+	class := classifyPath(path)
+	routed, ok := c.AuthRoutes[class]
+	if !ok {
+		return []credential{def}
+	}
+	c.authRouter.mu.Lock()
+	switched := c.authRouter.switched[class]
+	c.authRouter.mu.Unlock()
+	if switched {
+		return []credential{def, routed}
+	}
+	return []credential{routed, def}
+}
+
+// noteAuthSwitch records that class now prefers the credential that
+// succeeded after the first was rejected.
+func (c *JiraClient) noteAuthSwitch(path string, used credential) {
+	class := classifyPath(path)
+	c.authRouter.mu.Lock()
+	defer c.authRouter.mu.Unlock()
+	if c.authRouter.switched == nil {
+		c.authRouter.switched = map[endpointClass]bool{}
+	}
+	c.authRouter.switched[class] = used.Name == "default"
+	debugf("auth autoswitch: class=%s now prefers credential=%s", class, used.Name)
+}
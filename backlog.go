@@ -0,0 +1,305 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Weighted backlog prioritization ----
+
+var defaultPriorityWeights = map[string]float64{
+	"age":      1,
+	"priority": 3,
+	"votes":    2,
+	"blockers": 2,
+	"customer": 2,
+}
+
+// priorityLevels maps Jira's default priority names onto [0,1].
+var priorityLevels = map[string]float64{
+	"highest": 1,
+	"high":    0.75,
+	"medium":  0.5,
+	"low":     0.25,
+	"lowest":  0,
+}
+
+var prioritizeFields = []string{"summary", "created", "priority", "votes", "issuelinks", "labels"}
+
+type backlogScore struct {
+	Key       string             `json:"key"`
+	Summary   string             `json:"summary,omitempty"`
+	Score     float64            `json:"score"`
+	Breakdown map[string]float64 `json:"breakdown"`
+}
+
+type prioritizeResult struct {
+	Source  string             `json:"source"`
+	Weights map[string]float64 `json:"weights"`
+	Ranked  []backlogScore     `json:"ranked"`
+	Applied bool               `json:"applied"`
+	Note    string             `json:"note,omitempty"`
+}
+
+// scoreBacklog scores issues on normalized signals (each in [0,1]) multiplied
+// by their weights, and returns them best first.
+func scoreBacklog(issues []JiraIssue, weights map[string]float64, customerLabels []string, now time.Time) []backlogScore {
+	type raw struct{ age, priority, votes, blockers, customer float64 }
+	raws := make([]raw, len(issues))
+	var maxAge, maxVotes, maxBlockers float64
+	for i, iss := range issues {
+		r := &raws[i]
+		if t, ok := parseJiraTime(fieldString(iss.Fields, "created")); ok {
+			r.age = now.Sub(t).Hours() / 24
+		}
+		r.priority = 0.5
+		if lvl, ok := priorityLevels[strings.ToLower(fieldString(iss.Fields, "priority", "name"))]; ok {
+			r.priority = lvl
+		}
+		r.votes = fieldNumber(iss.Fields, "votes", "votes")
+		for _, l := range fieldList(iss.Fields, "issuelinks") {
+			lm, _ := l.(map[string]any)
+			if strings.EqualFold(fieldString(lm, "type", "name"), "Blocks") && fieldPath(lm, "outwardIssue") != nil {
+				r.blockers++
+			}
+		}
+		for _, lbl := range fieldStrings(iss.Fields, "labels") {
+			if containsFold(customerLabels, lbl) {
+				r.customer = 1
+				break
+			}
+		}
+		maxAge = math.Max(maxAge, r.age)
+		maxVotes = math.Max(maxVotes, r.votes)
+		maxBlockers = math.Max(maxBlockers, r.blockers)
+	}
+	norm := func(v, max float64) float64 {
+		if max <= 0 {
+			return 0
+		}
+		return v / max
+	}
+	round := func(v float64) float64 { return math.Round(v*1000) / 1000 }
+
+	out := make([]backlogScore, len(issues))
+	for i, iss := range issues {
+		r := raws[i]
+		signals := map[string]float64{
+			"age":      norm(r.age, maxAge),
+			"priority": r.priority,
+			"votes":    norm(r.votes, maxVotes),
+			"blockers": norm(r.blockers, maxBlockers),
+			"customer": r.customer,
+		}
+		bs := backlogScore{Key: iss.Key, Summary: fieldString(iss.Fields, "summary"), Breakdown: map[string]float64{}}
+		for name, w := range weights {
+			v := round(w * signals[name])
+			bs.Breakdown[name] = v
+			bs.Score += v
+		}
+		bs.Score = round(bs.Score)
+		out[i] = bs
+	}
+	// Stable sort keeps the current rank order for ties.
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	return out
+}
+
+var orderByRe = regexp.MustCompile(`(?i)^order\s+by\b`)
+
+// rankOrderJQL replaces any ORDER BY clause of jql with rank order, so the
+// first issue found is the top-ranked one. Quoted strings are skipped.
+func rankOrderJQL(jql string) string {
+	for i := 0; i < len(jql); i++ {
+		if ch := jql[i]; ch == '"' || ch == '\'' {
+			for i++; i < len(jql) && jql[i] != ch; i++ {
+				if jql[i] == '\\' {
+					i++
+				}
+			}
+			continue
+		}
+		if (i == 0 || !isWordByte(jql[i-1])) && orderByRe.MatchString(jql[i:]) {
+			jql = jql[:i]
+			break
+		}
+	}
+	return strings.TrimSpace(strings.TrimSpace(jql) + " ORDER BY Rank ASC")
+}
+
+// failures returns the error for each issue the rank endpoint did not move,
+// by key.
+func (r *RankResult) failures() map[string]string {
+	failed := map[string]string{}
+	for _, e := range r.Entries {
+		if e.Status >= 300 || len(e.Errors) > 0 {
+			failed[e.IssueKey] = strings.Join(e.Errors, "; ")
+		}
+	}
+	return failed
+}
+
+// rankIssues is RankIssues, failing if any issue was not moved.
+func (c *JiraClient) rankIssues(ctx context.Context, keys []string, before, after string) error {
+	res, err := c.RankIssues(ctx, keys, before, after)
+	if err != nil {
+		return err
+	}
+	failed := res.failures()
+	if len(failed) == 0 {
+		return nil
+	}
+	msgs := make([]string, 0, len(failed))
+	for key, msg := range failed {
+		msgs = append(msgs, key+": "+msg)
+	}
+	sort.Strings(msgs)
+	return fmt.Errorf("ranking failed for %s", strings.Join(msgs, "; "))
+}
+
+// applyRanking reorders ranked so that it occupies the slot of the current
+// top issue, in the given order.
+func (c *JiraClient) applyRanking(ctx context.Context, ranked []backlogScore, currentTop string) error {
+	if len(ranked) < 2 {
+		return nil
+	}
+	keys := make([]string, len(ranked))
+	for i, r := range ranked {
+		keys[i] = r.Key
+	}
+	if keys[0] != currentTop {
+		if err := c.rankIssues(ctx, keys[:1], currentTop, ""); err != nil {
+			return err
+		}
+	}
+	return c.rankIssues(ctx, keys[1:], "", keys[0])
+}
+
+func registerBacklogTools(server *mcp.Server, jc *JiraClient) {
+	// prioritize_backlog(board_or_jql, weights?, apply?, confirm?)
+	type prioritizeArgs struct {
+		BoardOrJQL     string             `json:"board_or_jql" jsonschema:"Board id (numeric) to rank its backlog, or a JQL query (its ORDER BY is replaced by rank order)"`
+		Weights        map[string]float64 `json:"weights,omitempty" jsonschema:"Signal weights; keys: age, priority, votes, blockers, customer"`
+		CustomerLabels []string           `json:"customer_labels,omitempty" jsonschema:"Labels that mark customer-reported issues (default: customer)"`
+		MaxIssues      int                `json:"max_issues,omitempty" jsonschema:"Maximum issues to score (default 100, max 500)"`
+		Apply          bool               `json:"apply,omitempty" jsonschema:"Apply the ranking via the rank API after user confirmation"`
+		Confirm        bool               `json:"confirm,omitempty" jsonschema:"Only for clients without elicitation: set once the user has approved applying the ranking"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "prioritize_backlog",
+		Title:       "Prioritize Backlog",
+		Description: "Score issues on weighted signals (age, priority, votes, blockers, customer label) and return a ranked list with score breakdowns; optionally re-rank the backlog",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args prioritizeArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=prioritize_backlog args={source:%q,weights:%v,apply:%t}", args.BoardOrJQL, args.Weights, args.Apply)
+		weights := args.Weights
+		if len(weights) == 0 {
+			weights = defaultPriorityWeights
+		}
+		for name := range weights {
+			if _, ok := defaultPriorityWeights[name]; !ok {
+				return nil, nil, fmt.Errorf("unknown weight %q (valid: age, priority, votes, blockers, customer)", name)
+			}
+		}
+		customer := args.CustomerLabels
+		if len(customer) == 0 {
+			customer = []string{"customer"}
+		}
+		limit := args.MaxIssues
+		if limit <= 0 {
+			limit = 100
+		}
+		limit = min(limit, 500)
+
+		var (
+			issues []JiraIssue
+			err    error
+		)
+		if boardID, convErr := strconv.Atoi(strings.TrimSpace(args.BoardOrJQL)); convErr == nil {
+			issues, err = jc.BoardBacklog(ctx, boardID, prioritizeFields, limit)
+		} else {
+			// In rank order, so issues[0] is the slot the ranking takes over.
+			issues, err = jc.SearchAll(ctx, rankOrderJQL(args.BoardOrJQL), prioritizeFields, limit)
+		}
+		if err != nil {
+			debugf("tool=prioritize_backlog error=%v", err)
+			return nil, nil, err
+		}
+
+		res := prioritizeResult{
+			Source:  args.BoardOrJQL,
+			Weights: weights,
+			Ranked:  scoreBacklog(issues, weights, customer, time.Now()),
+		}
+		if args.Apply && len(issues) > 1 {
+			approved, err := approveAction(ctx, req.Session, args.Confirm, fmt.Sprintf("Re-rank %d issues from %s by priority score?", len(issues), args.BoardOrJQL))
+			if errors.Is(err, errConfirmationUnavailable) {
+				res.Note = err.Error()
+			} else if err != nil {
+				return nil, nil, err
+			} else if !approved {
+				res.Note = "ranking not applied: the user declined"
+			}
+			if approved {
+				if err := jc.applyRanking(ctx, res.Ranked, issues[0].Key); err != nil {
+					debugf("tool=prioritize_backlog rank error=%v", err)
+					return nil, nil, err
+				}
+				res.Applied = true
+			}
+		}
+		return &mcp.CallToolResult{StructuredContent: res}, nil, nil
+	})
+
+	// rank_issues(keys, rank_before?, rank_after?)
+	type rankArgs struct {
+		Keys       []string `json:"keys" jsonschema:"Issues to move, in the order they should end up"`
+		RankBefore string   `json:"rank_before,omitempty" jsonschema:"Put the issues directly above this issue"`
+		RankAfter  string   `json:"rank_after,omitempty" jsonschema:"Put the issues directly below this issue"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "rank_issues",
+		Title:       "Rank Issues",
+		Description: "Reorder the backlog: move issues directly above (rank_before) or below (rank_after) another issue, keeping their relative order, e.g. put PROJ-42 above PROJ-17",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args rankArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=rank_issues args={keys:%v,before:%q,after:%q}", args.Keys, args.RankBefore, args.RankAfter)
+		if len(args.Keys) == 0 {
+			return nil, nil, errors.New("keys is required")
+		}
+		if (args.RankBefore == "") == (args.RankAfter == "") {
+			return nil, nil, errors.New("set exactly one of rank_before or rank_after")
+		}
+		out := map[string]any{}
+		target := args.RankBefore
+		if target != "" {
+			out["rank_before"] = target
+		} else {
+			target = args.RankAfter
+			out["rank_after"] = target
+		}
+		if containsFold(args.Keys, target) {
+			return nil, nil, fmt.Errorf("%s cannot be ranked relative to itself", target)
+		}
+		res, err := jc.RankIssues(ctx, args.Keys, args.RankBefore, args.RankAfter)
+		if err != nil {
+			debugf("tool=rank_issues error=%v", err)
+			return nil, nil, err
+		}
+		failed := res.failures()
+		out["ranked"] = len(args.Keys) - len(failed)
+		if len(failed) > 0 {
+			out["failed"] = failed
+			return &mcp.CallToolResult{IsError: true, StructuredContent: out}, nil, nil
+		}
+		return &mcp.CallToolResult{StructuredContent: out}, nil, nil
+	})
+}
@@ -0,0 +1,20 @@
+package jira
+
+import "testing"
+
+func TestRankOrderJQL(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"project = PROJ", "project = PROJ ORDER BY Rank ASC"},
+		{"project = PROJ ORDER BY created DESC", "project = PROJ ORDER BY Rank ASC"},
+		{"project = PROJ order  by priority, key", "project = PROJ ORDER BY Rank ASC"},
+		{`summary ~ "order by" AND project = PROJ`, `summary ~ "order by" AND project = PROJ ORDER BY Rank ASC`},
+		{`summary ~ 'it\'s order by' ORDER BY key`, `summary ~ 'it\'s order by' ORDER BY Rank ASC`},
+		{"reorder = x", "reorder = x ORDER BY Rank ASC"},
+		{"", "ORDER BY Rank ASC"},
+	}
+	for _, tt := range tests {
+		if got := rankOrderJQL(tt.in); got != tt.want {
+			t.Errorf("rankOrderJQL(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Agile boards ----
+
+type JiraBoard struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"` // scrum, kanban, or simple
+	Location *struct {
+		ProjectKey  string `json:"projectKey,omitempty"`
+		ProjectName string `json:"projectName,omitempty"`
+		DisplayName string `json:"displayName,omitempty"`
+	} `json:"location,omitempty"`
+}
+
+// ListBoards returns boards, optionally only those of a project, of a type
+// (scrum, kanban, simple), or whose name contains name.
+func (c *JiraClient) ListBoards(ctx context.Context, project, boardType, name string, max int) ([]JiraBoard, error) {
+	var out []JiraBoard
+	for startAt := 0; len(out) < max; {
+		q := url.Values{}
+		q.Set("startAt", fmt.Sprintf("%d", startAt))
+		q.Set("maxResults", fmt.Sprintf("%d", min(max-len(out), 50)))
+		if project != "" {
+			q.Set("projectKeyOrId", project)
+		}
+		if boardType != "" {
+			q.Set("type", boardType)
+		}
+		if name != "" {
+			q.Set("name", name)
+		}
+		var page struct {
+			IsLast bool        `json:"isLast"`
+			Values []JiraBoard `json:"values"`
+		}
+		if err := c.doJSON(ctx, http.MethodGet, "/rest/agile/1.0/board?"+q.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		out = append(out, page.Values...)
+		startAt += len(page.Values)
+		if page.IsLast || len(page.Values) == 0 {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (c *JiraClient) GetBoard(ctx context.Context, id int) (*JiraBoard, error) {
+	var out JiraBoard
+	if err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/rest/agile/1.0/board/%d", id), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+type JiraStatus struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	StatusCategory struct {
+		Key  string `json:"key"`
+		Name string `json:"name"`
+	} `json:"statusCategory"`
+}
+
+// Statuses returns every workflow status on the site.
+func (c *JiraClient) Statuses(ctx context.Context) ([]JiraStatus, error) {
+	var out []JiraStatus
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/status", nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type boardColumn struct {
+	Name     string   `json:"name"`
+	Statuses []string `json:"statuses"` // status names
+	Min      *int     `json:"min,omitempty"`
+	Max      *int     `json:"max,omitempty"` // WIP limit
+}
+
+type boardConfiguration struct {
+	ID             int           `json:"id"`
+	Name           string        `json:"name"`
+	Type           string        `json:"type"`
+	FilterID       string        `json:"filter_id,omitempty"`
+	FilterJQL      string        `json:"filter_jql,omitempty"`
+	Columns        []boardColumn `json:"columns"`
+	WIPConstraint  string        `json:"wip_constraint,omitempty"` // none, issueCount, issueCountExclSubs
+	Estimation     string        `json:"estimation,omitempty"`     // e.g. Story Points, Original Time Estimate
+	EstimationType string        `json:"estimation_type,omitempty"`
+	EstimationID   string        `json:"estimation_field_id,omitempty"`
+	RankFieldID    int           `json:"rank_field_id,omitempty"`
+}
+
+// BoardConfiguration returns a board's columns with their statuses by
+// name, WIP limits, estimation statistic, and the filter that defines it.
+func (c *JiraClient) BoardConfiguration(ctx context.Context, id int) (*boardConfiguration, error) {
+	var raw struct {
+		ID     int    `json:"id"`
+		Name   string `json:"name"`
+		Type   string `json:"type"`
+		Filter struct {
+			ID string `json:"id"`
+		} `json:"filter"`
+		ColumnConfig struct {
+			Columns []struct {
+				Name     string `json:"name"`
+				Statuses []struct {
+					ID string `json:"id"`
+				} `json:"statuses"`
+				Min *int `json:"min"`
+				Max *int `json:"max"`
+			} `json:"columns"`
+			ConstraintType string `json:"constraintType"`
+		} `json:"columnConfig"`
+		Estimation struct {
+			Type  string `json:"type"`
+			Field struct {
+				FieldID     string `json:"fieldId"`
+				DisplayName string `json:"displayName"`
+			} `json:"field"`
+		} `json:"estimation"`
+		Ranking struct {
+			RankCustomFieldID int `json:"rankCustomFieldId"`
+		} `json:"ranking"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/rest/agile/1.0/board/%d/configuration", id), nil, &raw); err != nil {
+		return nil, err
+	}
+	names := map[string]string{}
+	if statuses, err := c.Statuses(ctx); err == nil {
+		for _, s := range statuses {
+			names[s.ID] = s.Name
+		}
+	} else {
+		debugf("board %d: status names: %v", id, err)
+	}
+	cfg := &boardConfiguration{
+		ID: raw.ID, Name: raw.Name, Type: raw.Type, FilterID: raw.Filter.ID,
+		WIPConstraint:  raw.ColumnConfig.ConstraintType,
+		Estimation:     raw.Estimation.Field.DisplayName,
+		EstimationType: raw.Estimation.Type,
+		EstimationID:   raw.Estimation.Field.FieldID,
+		RankFieldID:    raw.Ranking.RankCustomFieldID,
+		Columns:        []boardColumn{},
+	}
+	for _, col := range raw.ColumnConfig.Columns {
+		bc := boardColumn{Name: col.Name, Statuses: []string{}, Min: col.Min, Max: col.Max}
+		for _, s := range col.Statuses {
+			name := names[s.ID]
+			if name == "" {
+				name = "status " + s.ID
+			}
+			bc.Statuses = append(bc.Statuses, name)
+		}
+		cfg.Columns = append(cfg.Columns, bc)
+	}
+	if cfg.FilterID != "" {
+		if f, err := c.GetFilter(ctx, cfg.FilterID); err == nil {
+			cfg.FilterJQL = f.JQL
+		} else {
+			debugf("board %d: filter %s: %v", id, cfg.FilterID, err)
+		}
+	}
+	return cfg, nil
+}
+
+func registerBoardTools(server *mcp.Server, jc *JiraClient) {
+	// list_boards(project?, type?, name?, max_results?)
+	type listBoardsArgs struct {
+		Project    string `json:"project,omitempty" jsonschema:"Only boards of this project (key or id)"`
+		Type       string `json:"type,omitempty" jsonschema:"scrum, kanban, or simple"`
+		Name       string `json:"name,omitempty" jsonschema:"Only boards whose name contains this"`
+		MaxResults int    `json:"max_results,omitempty" jsonschema:"Maximum boards (default 50)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_boards",
+		Title:       "List Boards",
+		Description: "List Jira Software boards, optionally by project, type (scrum/kanban), or name",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listBoardsArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_boards args={project:%q,type:%q,name:%q}", args.Project, args.Type, args.Name)
+		switch strings.ToLower(args.Type) {
+		case "", "scrum", "kanban", "simple":
+		default:
+			return nil, nil, fmt.Errorf("unknown board type %q (valid: scrum, kanban, simple)", args.Type)
+		}
+		max := args.MaxResults
+		if max <= 0 {
+			max = 50
+		}
+		project := args.Project
+		if f := jc.focusFor(req.Session); project == "" && f != nil {
+			project = f.Project
+		}
+		boards, err := jc.ListBoards(ctx, project, strings.ToLower(args.Type), args.Name, max)
+		if err != nil {
+			debugf("tool=list_boards error=%v", err)
+			return nil, nil, err
+		}
+		if boards == nil {
+			boards = []JiraBoard{}
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"boards": boards}}, nil, nil
+	})
+
+	// get_board(board_id)
+	type boardArgs struct {
+		BoardID int `json:"board_id" jsonschema:"Board id (see list_boards)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_board",
+		Title:       "Get Board",
+		Description: "Get a board's name, type, and project",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args boardArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=get_board args={board:%d}", args.BoardID)
+		b, err := jc.GetBoard(ctx, args.BoardID)
+		if err != nil {
+			debugf("tool=get_board error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: b}, nil, nil
+	})
+
+	// get_board_configuration(board_id)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_board_configuration",
+		Title:       "Get Board Configuration",
+		Description: "Get a board's columns with the statuses mapped to each and WIP limits, its estimation statistic (e.g. Story Points), and the filter JQL that selects its issues",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args boardArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=get_board_configuration args={board:%d}", args.BoardID)
+		cfg, err := jc.BoardConfiguration(ctx, args.BoardID)
+		if err != nil {
+			debugf("tool=get_board_configuration error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: cfg}, nil, nil
+	})
+}
@@ -0,0 +1,178 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Branch names ----
+//
+// suggest_branch_name turns an issue into a branch name following a
+// template, so every agent starting work from a ticket names its branch
+// the same way. JIRA_BRANCH_CONVENTIONS adds or overrides templates and
+// maps issue types to branch prefixes, e.g.
+//
+//	{"default": "gitflow",
+//	 "conventions": {"gitflow": "{type}/{key}-{slugified-summary}", "plain": "{key-lower}-{slugified-summary}"},
+//	 "types": {"bug": "bugfix", "story": "feature"},
+//	 "max_length": 60}
+//
+// Placeholders: {key}, {key-lower}, {project}, {type} (the mapped issue
+// type), {slugified-summary} (or {summary}), and {assignee} (slugified
+// display name).
+
+type branchConventions struct {
+	Default     string            `json:"default,omitempty"`
+	Conventions map[string]string `json:"conventions,omitempty"`
+	Types       map[string]string `json:"types,omitempty"`
+	MaxLength   int               `json:"max_length,omitempty"`
+}
+
+var defaultBranchConventions = branchConventions{
+	Default: "default",
+	Conventions: map[string]string{
+		"default": "{type}/{key}-{slugified-summary}",
+		"plain":   "{key}-{slugified-summary}",
+		"key":     "{key}",
+	},
+	Types: map[string]string{
+		"bug": "bugfix", "story": "feature", "task": "task", "sub-task": "task",
+		"subtask": "task", "epic": "epic", "improvement": "improvement", "spike": "spike",
+	},
+	MaxLength: 60,
+}
+
+func loadBranchConventions() branchConventions {
+	cfg := defaultBranchConventions
+	cfg.Conventions = map[string]string{}
+	cfg.Types = map[string]string{}
+	for k, v := range defaultBranchConventions.Conventions {
+		cfg.Conventions[k] = v
+	}
+	for k, v := range defaultBranchConventions.Types {
+		cfg.Types[k] = v
+	}
+	var custom branchConventions
+	if _, err := loadJSONSetting("JIRA_BRANCH_CONVENTIONS", &custom); err != nil {
+		logger.Printf("ignoring JIRA_BRANCH_CONVENTIONS: %v", err)
+		return cfg
+	}
+	for k, v := range custom.Conventions {
+		cfg.Conventions[strings.ToLower(k)] = v
+	}
+	for k, v := range custom.Types {
+		cfg.Types[strings.ToLower(k)] = v
+	}
+	if custom.Default != "" {
+		cfg.Default = strings.ToLower(custom.Default)
+	}
+	if custom.MaxLength > 0 {
+		cfg.MaxLength = custom.MaxLength
+	}
+	if _, ok := cfg.Conventions[cfg.Default]; !ok {
+		logger.Printf("JIRA_BRANCH_CONVENTIONS: unknown default convention %q; using %q", cfg.Default, defaultBranchConventions.Default)
+		cfg.Default = defaultBranchConventions.Default
+	}
+	return cfg
+}
+
+var slugSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+
+// slugify lower-cases s and joins its ASCII words with hyphens; anything
+// git or a shell might object to is dropped.
+func slugify(s string) string {
+	return strings.Trim(slugSeparators.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
+
+// truncateSlug cuts a slug to at most n bytes at a word boundary.
+func truncateSlug(slug string, n int) string {
+	if len(slug) <= n {
+		return slug
+	}
+	if n <= 0 {
+		return ""
+	}
+	cut := slug[:n]
+	if i := strings.LastIndexByte(cut, '-'); i > 0 && slug[n] != '-' {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, "-")
+}
+
+// branchName fills template for iss. The summary slug is shortened so the
+// whole name fits in MaxLength.
+func (cfg branchConventions) branchName(template string, iss *JiraIssue) string {
+	typ := fieldString(iss.Fields, "issuetype", "name")
+	if mapped, ok := cfg.Types[strings.ToLower(typ)]; ok {
+		typ = mapped
+	}
+	project, _, _ := strings.Cut(iss.Key, "-")
+	r := strings.NewReplacer(
+		"{key}", iss.Key,
+		"{key-lower}", strings.ToLower(iss.Key),
+		"{project}", strings.ToLower(project),
+		"{type}", slugify(typ),
+		"{assignee}", slugify(fieldString(iss.Fields, "assignee", "displayName")),
+	)
+	name := r.Replace(template)
+	summary := slugify(fieldString(iss.Fields, "summary"))
+	for _, ph := range []string{"{slugified-summary}", "{summary}"} {
+		if !strings.Contains(name, ph) {
+			continue
+		}
+		room := cfg.MaxLength - (len(name) - len(ph))
+		name = strings.Replace(name, ph, truncateSlug(summary, room), 1)
+	}
+	// Empty placeholders leave dangling separators behind.
+	name = strings.NewReplacer("--", "-", "/-", "/", "-/", "/").Replace(name)
+	return strings.Trim(name, "-/")
+}
+
+func registerBranchTools(server *mcp.Server, jc *JiraClient) {
+	cfg := loadBranchConventions()
+	names := make([]string, 0, len(cfg.Conventions))
+	for n := range cfg.Conventions {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	// suggest_branch_name(key, convention?)
+	type branchNameArgs struct {
+		Key        string `json:"key" jsonschema:"Issue key"`
+		Convention string `json:"convention,omitempty" jsonschema:"Convention name or a literal template such as {type}/{key}-{slugified-summary}"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "suggest_branch_name",
+		Title:       "Suggest Branch Name",
+		Description: fmt.Sprintf("Suggest a git branch name for an issue from the team's naming convention (default %q; available: %s)", cfg.Default, strings.Join(names, ", ")),
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args branchNameArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=suggest_branch_name args={key:%q,convention:%q}", args.Key, args.Convention)
+		convention := strings.ToLower(args.Convention)
+		if convention == "" {
+			convention = cfg.Default
+		}
+		template, ok := cfg.Conventions[convention]
+		if !ok {
+			if !strings.Contains(args.Convention, "{") {
+				return nil, nil, fmt.Errorf("unknown convention %q (available: %s)", args.Convention, strings.Join(names, ", "))
+			}
+			template, convention = args.Convention, ""
+		}
+		iss, err := jc.issueFields(ctx, args.Key, "summary,issuetype,assignee")
+		if err != nil {
+			debugf("tool=suggest_branch_name error=%v", err)
+			return nil, nil, err
+		}
+		res := map[string]any{"key": iss.Key, "branch": cfg.branchName(template, iss), "template": template}
+		if convention != "" {
+			res["convention"] = convention
+		}
+		return &mcp.CallToolResult{StructuredContent: res}, nil, nil
+	})
+}
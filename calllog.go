@@ -0,0 +1,181 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Tool call log for offline evaluation ----
+//
+// JIRA_CALL_LOG names a file that every tool call is appended to as one
+// line of canonical JSON (sorted keys, no HTML escaping, UTC timestamps),
+// so evaluation pipelines can replay real sessions against changed tools.
+// Records look like:
+//
+//	{"arguments":{...},"duration_ms":12,"result":{"content":["..."],"is_error":false,"structured":{...}},
+//	 "seq":1,"session":"default","site":"default","tool":"get_issue","ts":"2026-01-02T03:04:05.6Z","v":1}
+//
+// site is the site the call ran against (see sites.go). Credentials are
+// blanked, email addresses masked, and account ids replaced by stable
+// pseudonyms, so the same user reads the same across a log. Page tokens are
+// kept, so paginated calls can be replayed.
+
+const callLogVersion = 1
+
+var (
+	// secretKeyRe matches the names of credential keys, but not keys that
+	// merely contain one of the words, such as nextPageToken.
+	secretKeyRe = regexp.MustCompile(`(?i)^((api|access|refresh|auth|bearer|id|session)[_-]?)?token$|^(client[_-]?)?secret$|^(password|passwd|authorization|cookie|credentials?)$|^(api|private)[_-]?key$`)
+	emailRe     = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+)
+
+type callLog struct {
+	mu  sync.Mutex
+	f   *os.File
+	seq int
+}
+
+// openCallLog opens JIRA_CALL_LOG for appending, or returns nil when it is
+// not set.
+func openCallLog() (*callLog, error) {
+	path := os.Getenv("JIRA_CALL_LOG")
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("JIRA_CALL_LOG: %w", err)
+	}
+	return &callLog{f: f}, nil
+}
+
+// pseudonym maps an account id to a stable opaque stand-in.
+func pseudonym(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return "acct-" + hex.EncodeToString(sum[:6])
+}
+
+// redactValue returns a copy of a decoded JSON value with credentials,
+// emails, and account ids scrubbed.
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, val := range v {
+			switch s, isStr := val.(string); {
+			case secretKeyRe.MatchString(k) && val != nil:
+				out[k] = "[REDACTED]"
+			case isStr && (strings.EqualFold(k, "accountId") || strings.EqualFold(k, "account_id")):
+				out[k] = pseudonym(s)
+			default:
+				out[k] = redactValue(val)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, val := range v {
+			out[i] = redactValue(val)
+		}
+		return out
+	case string:
+		return emailRe.ReplaceAllStringFunc(v, maskEmail)
+	}
+	return v
+}
+
+// canonical round-trips v through JSON so that it is redacted and its keys
+// are sorted on output.
+func canonical(v any) any {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("unencodable: %v", err)
+	}
+	var out any
+	if json.Unmarshal(b, &out) != nil {
+		return nil
+	}
+	return redactValue(out)
+}
+
+func (l *callLog) write(rec map[string]any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.seq++
+	rec["seq"] = l.seq
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(rec); err != nil {
+		debugf("call log: %v", err)
+		return
+	}
+	if _, err := l.f.Write(buf.Bytes()); err != nil {
+		debugf("call log: %v", err)
+	}
+}
+
+func (l *callLog) close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.f.Close()
+}
+
+// callLogMiddleware records tool calls and their results. It sits inside
+// instanceMiddleware, so results are logged before instance tagging, and
+// inside siteMiddleware, which has already taken the site argument off the
+// call; the site it selected is logged instead.
+func callLogMiddleware(l *callLog, jc *JiraClient) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			ctreq, ok := req.(*mcp.CallToolRequest)
+			if method != "tools/call" || !ok {
+				return next(ctx, method, req)
+			}
+			start := time.Now()
+			res, err := next(ctx, method, req)
+			var args any
+			if len(ctreq.Params.Arguments) > 0 {
+				args = canonical(ctreq.Params.Arguments)
+			}
+			rec := map[string]any{
+				"v":           callLogVersion,
+				"ts":          start.UTC().Format(time.RFC3339Nano),
+				"session":     sessionKey(ctreq.Session),
+				"site":        jc.forSite(ctx).site,
+				"tool":        ctreq.Params.Name,
+				"arguments":   args,
+				"duration_ms": time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				rec["error"] = redactValue(err.Error())
+			}
+			if ctres, ok := res.(*mcp.CallToolResult); ok {
+				text := []any{}
+				for _, c := range ctres.Content {
+					if tc, ok := c.(*mcp.TextContent); ok {
+						text = append(text, redactValue(tc.Text))
+					}
+				}
+				result := map[string]any{"is_error": ctres.IsError, "content": text}
+				if ctres.StructuredContent != nil {
+					result["structured"] = canonical(ctres.StructuredContent)
+				}
+				rec["result"] = result
+			}
+			l.write(rec)
+			return res, err
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package jira
+
+import "testing"
+
+func TestSecretKeyRe(t *testing.T) {
+	for _, k := range []string{"token", "api_token", "apiToken", "access_token", "refreshToken", "client_secret", "password", "Authorization", "cookie", "api_key"} {
+		if !secretKeyRe.MatchString(k) {
+			t.Errorf("%q should be redacted", k)
+		}
+	}
+	for _, k := range []string{"page_token", "nextPageToken", "tokens_used", "key", "summary", "secretary"} {
+		if secretKeyRe.MatchString(k) {
+			t.Errorf("%q should be kept", k)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Site capabilities in tool descriptions ----
+//
+// Tool descriptions are written for Jira in general. The first tools/list
+// probes what this site actually has (Jira Software, Jira Service
+// Management, which story point field) and appends caveats to the affected
+// tools' descriptions, so the model's picture of the tools matches the site.
+// Probes that fail for reasons other than "not there" are retried on the
+// next listing rather than cached.
+
+// softwareTools need Jira Software (boards, sprints, ranking).
+var softwareTools = []string{
+	"list_boards", "get_board", "get_board_configuration", "prioritize_backlog",
+	"list_sprints", "get_sprint", "get_sprint_issues", "move_to_sprint", "move_to_backlog",
+	"create_sprint", "start_sprint", "complete_sprint", "rank_issues",
+}
+
+// serviceDeskTools need Jira Service Management.
+var serviceDeskTools = []string{
+	"list_request_types", "create_request", "list_requests", "get_request",
+	"list_queues", "get_queue_issues", "get_request_sla", "sla_breach_report",
+	"list_approvals", "answer_approval", "create_customer", "list_customers", "update_service_desk_customers",
+	"list_organizations", "create_organization", "update_organization", "delete_organization",
+}
+
+// estimateTools read or write story points.
+var estimateTools = []string{"create_issue", "update_issue", "lint_issue", "lint_search", "get_sprint_issues"}
+
+// storyPointFieldNames are the names Jira gives its estimate fields:
+// company-managed and team-managed projects respectively.
+var storyPointFieldNames = []string{"Story Points", "Story point estimate"}
+
+type siteCapabilities struct {
+	mu          sync.Mutex
+	probed      bool
+	software    bool
+	serviceDesk bool
+	storyPoints []JiraField
+}
+
+// present probes path; a 404 means the product is not on the site.
+func (c *JiraClient) present(ctx context.Context, path string) (bool, error) {
+	err := c.doJSON(ctx, http.MethodGet, path, nil, nil)
+	var je *JiraError
+	if errors.As(err, &je) && je.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (c *JiraClient) probeCapabilities(ctx context.Context) {
+	caps := &c.caps
+	caps.mu.Lock()
+	defer caps.mu.Unlock()
+	if caps.probed {
+		return
+	}
+	ctx, cancel := context.WithTimeout(withSubsystem(ctx, "capabilities"), 10*time.Second)
+	defer cancel()
+	software, err := c.present(ctx, "/rest/agile/1.0/board?maxResults=1")
+	if err != nil {
+		debugf("capabilities: jira software: %v", err)
+		return
+	}
+	serviceDesk, err := c.present(ctx, "/rest/servicedeskapi/servicedesk?limit=1")
+	if err != nil {
+		debugf("capabilities: service management: %v", err)
+		return
+	}
+	fields, err := c.Fields(ctx)
+	if err != nil {
+		debugf("capabilities: fields: %v", err)
+		return
+	}
+	caps.storyPoints = nil
+	for _, f := range fields {
+		if f.Custom && containsFold(storyPointFieldNames, f.Name) {
+			caps.storyPoints = append(caps.storyPoints, f)
+		}
+	}
+	caps.software, caps.serviceDesk, caps.probed = software, serviceDesk, true
+	debugf("capabilities: software=%t service_desk=%t story_points=%d", software, serviceDesk, len(caps.storyPoints))
+}
+
+// toolCaveats returns the site-specific notes for each affected tool, or
+// nil before the site has been probed.
+func (c *JiraClient) toolCaveats() map[string][]string {
+	caps := &c.caps
+	caps.mu.Lock()
+	defer caps.mu.Unlock()
+	if !caps.probed {
+		return nil
+	}
+	out := map[string][]string{}
+	add := func(tools []string, note string) {
+		for _, t := range tools {
+			out[t] = append(out[t], note)
+		}
+	}
+	if !caps.software {
+		add(softwareTools, "Unavailable on this site: Jira Software (boards and sprints) is not enabled.")
+	}
+	if !caps.serviceDesk {
+		add(serviceDeskTools, "Unavailable on this site: it has no Jira Service Management.")
+	}
+	switch len(caps.storyPoints) {
+	case 0:
+		add(estimateTools, "This site has no story points field.")
+	default:
+		var names []string
+		for _, f := range caps.storyPoints {
+			names = append(names, fmt.Sprintf("'%s' (%s)", f.Name, f.ID))
+		}
+		add(estimateTools, "Story points on this site: "+strings.Join(names, " or ")+".")
+	}
+	return out
+}
+
+// capabilityMiddleware appends site caveats to tool descriptions in
+// tools/list results. The registered tools are left untouched.
+func capabilityMiddleware(jc *JiraClient) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			res, err := next(ctx, method, req)
+			ltres, ok := res.(*mcp.ListToolsResult)
+			if method != "tools/list" || !ok || err != nil {
+				return res, err
+			}
+			jc.probeCapabilities(ctx)
+			caveats := jc.toolCaveats()
+			if len(caveats) == 0 {
+				return res, nil
+			}
+			out := *ltres
+			out.Tools = make([]*mcp.Tool, len(ltres.Tools))
+			for i, t := range ltres.Tools {
+				out.Tools[i] = t
+				if notes := caveats[t.Name]; len(notes) > 0 {
+					cp := *t
+					cp.Description = strings.TrimRight(cp.Description, ". ") + ". " + strings.Join(notes, " ")
+					out.Tools[i] = &cp
+				}
+			}
+			return &out, nil
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Issue type, status, priority, and resolution catalogs ----
+
+// apiPage returns every value of a paged platform collection (startAt,
+// maxResults, isLast).
+func apiPage[T any](ctx context.Context, c *JiraClient, path string, q url.Values) ([]T, error) {
+	if q == nil {
+		q = url.Values{}
+	}
+	var out []T
+	for {
+		q.Set("startAt", strconv.Itoa(len(out)))
+		q.Set("maxResults", "100")
+		var page struct {
+			IsLast bool `json:"isLast"`
+			Values []T  `json:"values"`
+		}
+		if err := c.doJSON(ctx, http.MethodGet, path+"?"+q.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		out = append(out, page.Values...)
+		if page.IsLast || len(page.Values) == 0 {
+			return out, nil
+		}
+	}
+}
+
+// IssueTypes returns the issue types of a project, or every issue type on
+// the site when project is empty.
+func (c *JiraClient) IssueTypes(ctx context.Context, project string) ([]JiraIssueType, error) {
+	path := "/rest/api/3/issuetype"
+	if project != "" {
+		p, err := c.GetProject(ctx, project)
+		if err != nil {
+			return nil, err
+		}
+		path += "/project?projectId=" + url.QueryEscape(p.ID)
+	}
+	var out []JiraIssueType
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// issueTypeStatuses is the set of statuses one issue type's workflow uses.
+type issueTypeStatuses struct {
+	Name     string       `json:"name"`
+	Subtask  bool         `json:"subtask"`
+	Statuses []JiraStatus `json:"statuses"`
+}
+
+// ProjectStatuses returns the statuses each of a project's issue types can
+// be in.
+func (c *JiraClient) ProjectStatuses(ctx context.Context, project string) ([]issueTypeStatuses, error) {
+	var out []issueTypeStatuses
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/project/"+url.PathEscape(project)+"/statuses", nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type JiraPriority struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	IsDefault   bool   `json:"isDefault,omitempty"`
+}
+
+// Priorities returns the priorities available in a project's priority
+// scheme, or every priority when project is empty.
+func (c *JiraClient) Priorities(ctx context.Context, project string) ([]JiraPriority, error) {
+	q := url.Values{}
+	if project != "" {
+		p, err := c.GetProject(ctx, project)
+		if err != nil {
+			return nil, err
+		}
+		q.Set("projectId", p.ID)
+	}
+	return apiPage[JiraPriority](ctx, c, "/rest/api/3/priority/search", q)
+}
+
+type JiraResolution struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	IsDefault   bool   `json:"isDefault,omitempty"`
+}
+
+// Resolutions returns the site's resolutions. Jira has no per-project
+// resolution list; workflows may restrict them per transition.
+func (c *JiraClient) Resolutions(ctx context.Context) ([]JiraResolution, error) {
+	return apiPage[JiraResolution](ctx, c, "/rest/api/3/resolution/search", nil)
+}
+
+type statusView struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Category   string   `json:"category"` // To Do, In Progress, or Done
+	IssueTypes []string `json:"issue_types,omitempty"`
+}
+
+func registerCatalogTools(server *mcp.Server, jc *JiraClient) {
+	// list_issue_types(project?)
+	type catalogArgs struct {
+		Project string `json:"project,omitempty" jsonschema:"Project key (default the focus project); omit with no focus to list the whole site"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_issue_types",
+		Title:       "List Issue Types",
+		Description: "List issue types with id, whether each is a subtask type, and hierarchy level; scoped to a project when one is given or focused",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args catalogArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_issue_types args={project:%q}", args.Project)
+		project := args.Project
+		if f := jc.focusFor(req.Session); project == "" && f != nil {
+			project = f.Project
+		}
+		types, err := jc.IssueTypes(ctx, project)
+		if err != nil {
+			debugf("tool=list_issue_types error=%v", err)
+			return nil, nil, err
+		}
+		if types == nil {
+			types = []JiraIssueType{}
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"project": project, "issue_types": types}}, nil, nil
+	})
+
+	// list_statuses(project?)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_statuses",
+		Title:       "List Statuses",
+		Description: "List workflow statuses with their category (To Do, In Progress, Done). With a project, lists only the statuses its workflows use and which issue types reach each",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args catalogArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_statuses args={project:%q}", args.Project)
+		project := args.Project
+		if f := jc.focusFor(req.Session); project == "" && f != nil {
+			project = f.Project
+		}
+		views := []statusView{}
+		if project == "" {
+			statuses, err := jc.Statuses(ctx)
+			if err != nil {
+				debugf("tool=list_statuses error=%v", err)
+				return nil, nil, err
+			}
+			for _, s := range statuses {
+				views = append(views, statusView{ID: s.ID, Name: s.Name, Category: s.StatusCategory.Name})
+			}
+		} else {
+			byType, err := jc.ProjectStatuses(ctx, project)
+			if err != nil {
+				debugf("tool=list_statuses error=%v", err)
+				return nil, nil, err
+			}
+			index := map[string]int{}
+			for _, it := range byType {
+				for _, s := range it.Statuses {
+					i, ok := index[s.ID]
+					if !ok {
+						i = len(views)
+						index[s.ID] = i
+						views = append(views, statusView{ID: s.ID, Name: s.Name, Category: s.StatusCategory.Name})
+					}
+					views[i].IssueTypes = append(views[i].IssueTypes, it.Name)
+				}
+			}
+		}
+		sort.SliceStable(views, func(i, j int) bool { return views[i].Name < views[j].Name })
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"project": project, "statuses": views}}, nil, nil
+	})
+
+	// list_priorities(project?)
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_priorities",
+		Title:       "List Priorities",
+		Description: "List priorities with id and which is the default; with a project, only those in its priority scheme",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args catalogArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_priorities args={project:%q}", args.Project)
+		project := args.Project
+		if f := jc.focusFor(req.Session); project == "" && f != nil {
+			project = f.Project
+		}
+		priorities, err := jc.Priorities(ctx, project)
+		if err != nil {
+			debugf("tool=list_priorities error=%v", err)
+			return nil, nil, err
+		}
+		if priorities == nil {
+			priorities = []JiraPriority{}
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"project": project, "priorities": priorities}}, nil, nil
+	})
+
+	// list_resolutions()
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_resolutions",
+		Title:       "List Resolutions",
+		Description: "List the site's resolutions with id and which is the default. Resolutions are site-wide; a transition screen may offer fewer",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args struct{}) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_resolutions")
+		resolutions, err := jc.Resolutions(ctx)
+		if err != nil {
+			debugf("tool=list_resolutions error=%v", err)
+			return nil, nil, err
+		}
+		if resolutions == nil {
+			resolutions = []JiraResolution{}
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"resolutions": resolutions}}, nil, nil
+	})
+}
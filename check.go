@@ -0,0 +1,44 @@
+package jira
+
+import (
+	"context"
+	"time"
+)
+
+// ---- Connectivity check ----
+
+// SiteCheck is the outcome of Check for one site.
+type SiteCheck struct {
+	Site       string `json:"site"`
+	URL        string `json:"url"`
+	Label      string `json:"label"`
+	Production bool   `json:"production"`
+	User       string `json:"user,omitempty"` // who the credentials belong to
+	Email      string `json:"email,omitempty"`
+	APIVersion string `json:"api_version,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Check confirms that every configured site can be reached and accepts its
+// credentials, by fetching the authenticated user from each.
+func (c *JiraClient) Check(ctx context.Context) []SiteCheck {
+	sites := c.sites
+	if sites == nil {
+		sites = []*JiraClient{c}
+	}
+	out := make([]SiteCheck, len(sites))
+	for i, s := range sites {
+		r := SiteCheck{Site: s.site, URL: s.BaseURL, Label: s.Instance.Label, Production: s.Instance.Production}
+		cctx, cancel := context.WithTimeout(withSite(ctx, s), 30*time.Second)
+		me, err := c.CurrentUser(cctx)
+		cancel()
+		if err != nil {
+			r.Error = err.Error()
+		} else {
+			r.User, r.Email = me.DisplayName, me.EmailAddress
+			r.APIVersion = s.siteView().APIVersion
+		}
+		out[i] = r
+	}
+	return out
+}
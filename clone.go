@@ -0,0 +1,195 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Cloning issues ----
+
+const cloneFields = "summary,description,labels,components,issuetype,priority,project,subtasks,issuelinks"
+
+type cloneOptions struct {
+	TargetProject   string
+	Overrides       map[string]any // already resolved to field ids
+	IncludeSubtasks bool
+	IncludeLinks    bool
+}
+
+type cloneResult struct {
+	Key        string   `json:"key"`
+	Source     string   `json:"source"`
+	Subtasks   []string `json:"subtasks,omitempty"`
+	Links      int      `json:"links,omitempty"`
+	Warnings   []string `json:"warnings,omitempty"`
+	Provenance string   `json:"provenance_run,omitempty"`
+}
+
+func (c *JiraClient) issueFields(ctx context.Context, key, fields string) (*JiraIssue, error) {
+	var out JiraIssue
+	path := "/rest/api/3/issue/" + url.PathEscape(key) + "?fields=" + url.QueryEscape(fields)
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// copyableFields builds create fields for a copy of src in project. When
+// the project differs, components are matched by name and unknown ones are
+// dropped with a warning.
+func (c *JiraClient) copyableFields(ctx context.Context, src *JiraIssue, project, issueType string) (map[string]any, []string, error) {
+	var warnings []string
+	fields := map[string]any{
+		"project":   map[string]any{"key": project},
+		"issuetype": map[string]any{"name": issueType},
+		"summary":   fieldString(src.Fields, "summary"),
+	}
+	if d := fieldPath(src.Fields, "description"); d != nil {
+		fields["description"] = d
+	}
+	if labels := fieldStrings(src.Fields, "labels"); len(labels) > 0 {
+		fields["labels"] = labels
+	}
+	if p := fieldString(src.Fields, "priority", "name"); p != "" {
+		fields["priority"] = map[string]any{"name": p}
+	}
+	comps := fieldList(src.Fields, "components")
+	if len(comps) > 0 {
+		sameProject := strings.EqualFold(fieldString(src.Fields, "project", "key"), project)
+		var target map[string]string
+		if !sameProject {
+			var err error
+			if target, err = c.ProjectComponents(ctx, project); err != nil {
+				return nil, nil, err
+			}
+		}
+		var out []any
+		for _, comp := range comps {
+			cm, _ := comp.(map[string]any)
+			name := fieldString(cm, "name")
+			if sameProject {
+				out = append(out, map[string]any{"id": fieldString(cm, "id")})
+			} else if id, ok := target[strings.ToLower(name)]; ok {
+				out = append(out, map[string]any{"id": id})
+			} else {
+				warnings = append(warnings, fmt.Sprintf("component %q does not exist in %s; dropped", name, project))
+			}
+		}
+		if len(out) > 0 {
+			fields["components"] = out
+		}
+	}
+	return fields, warnings, nil
+}
+
+// CloneIssue copies key's summary, description, labels, components, and
+// priority into a new issue, optionally with its subtasks and links.
+func (c *JiraClient) CloneIssue(ctx context.Context, key string, opts cloneOptions) (*cloneResult, error) {
+	src, err := c.issueFields(ctx, key, cloneFields)
+	if err != nil {
+		return nil, err
+	}
+	project := opts.TargetProject
+	if project == "" {
+		project = fieldString(src.Fields, "project", "key")
+	}
+	fields, warnings, err := c.copyableFields(ctx, src, project, fieldString(src.Fields, "issuetype", "name"))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range opts.Overrides {
+		fields[k] = v
+	}
+	created, err := c.CreateIssueFields(ctx, fields)
+	if err != nil {
+		return nil, err
+	}
+	res := &cloneResult{Key: created.Key, Source: key, Warnings: warnings}
+
+	if opts.IncludeSubtasks && len(fieldList(src.Fields, "subtasks")) > 0 {
+		st, err := c.SubtaskType(ctx, project)
+		if err != nil {
+			res.Warnings = append(res.Warnings, "subtasks not copied: "+err.Error())
+		} else {
+			for _, s := range fieldList(src.Fields, "subtasks") {
+				sm, _ := s.(map[string]any)
+				subKey := fieldString(sm, "key")
+				sub, err := c.issueFields(ctx, subKey, cloneFields)
+				if err != nil {
+					res.Warnings = append(res.Warnings, fmt.Sprintf("subtask %s not copied: %v", subKey, err))
+					continue
+				}
+				sf, w, err := c.copyableFields(ctx, sub, project, st.Name)
+				res.Warnings = append(res.Warnings, w...)
+				if err != nil {
+					res.Warnings = append(res.Warnings, fmt.Sprintf("subtask %s not copied: %v", subKey, err))
+					continue
+				}
+				sf["parent"] = map[string]any{"key": created.Key}
+				newSub, err := c.CreateIssueFields(ctx, sf)
+				if err != nil {
+					res.Warnings = append(res.Warnings, fmt.Sprintf("subtask %s not copied: %v", subKey, err))
+					continue
+				}
+				res.Subtasks = append(res.Subtasks, newSub.Key)
+			}
+		}
+	}
+
+	if opts.IncludeLinks {
+		for _, l := range fieldList(src.Fields, "issuelinks") {
+			lm, _ := l.(map[string]any)
+			linkType := fieldString(lm, "type", "name")
+			inward, outward := created.Key, fieldString(lm, "outwardIssue", "key")
+			if outward == "" {
+				inward, outward = fieldString(lm, "inwardIssue", "key"), created.Key
+			}
+			if _, err := c.LinkIssues(ctx, linkType, inward, outward); err != nil {
+				res.Warnings = append(res.Warnings, fmt.Sprintf("link %s %s -> %s not copied: %v", linkType, inward, outward, err))
+				continue
+			}
+			res.Links++
+		}
+	}
+	return res, nil
+}
+
+func registerCloneTools(server *mcp.Server, jc *JiraClient) {
+	// clone_issue(key, target_project?, include_subtasks?, include_links?, fields?)
+	type cloneArgs struct {
+		Key             string         `json:"key" jsonschema:"Issue to clone"`
+		TargetProject   string         `json:"target_project,omitempty" jsonschema:"Project key for the copy (default: the source's project)"`
+		IncludeSubtasks bool           `json:"include_subtasks,omitempty" jsonschema:"Also copy subtasks under the new issue"`
+		IncludeLinks    bool           `json:"include_links,omitempty" jsonschema:"Also recreate the source's issue links on the copy"`
+		Fields          map[string]any `json:"fields,omitempty" jsonschema:"Field overrides for the copy, keyed by field id, name, or alias"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "clone_issue",
+		Title:       "Clone Issue",
+		Description: "Copy an issue's summary, description, labels, components, and priority into a new issue (same or another project), optionally with subtasks and links, applying field overrides",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args cloneArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=clone_issue args={key:%q,target:%q,subtasks:%t,links:%t}", args.Key, args.TargetProject, args.IncludeSubtasks, args.IncludeLinks)
+		overrides, err := jc.resolveFieldKeys(ctx, args.Fields)
+		if err != nil {
+			return nil, nil, err
+		}
+		ctx, run := jc.beginProvenance(ctx)
+		res, err := jc.CloneIssue(ctx, args.Key, cloneOptions{
+			TargetProject:   args.TargetProject,
+			Overrides:       overrides,
+			IncludeSubtasks: args.IncludeSubtasks,
+			IncludeLinks:    args.IncludeLinks,
+		})
+		if err != nil {
+			debugf("tool=clone_issue error=%v", err)
+			return nil, nil, err
+		}
+		res.Provenance = jc.finishProvenance(ctx, run)
+		return &mcp.CallToolResult{StructuredContent: res}, nil, nil
+	})
+}
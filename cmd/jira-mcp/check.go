@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+func check(ctx context.Context, args []string) error {
+	fs, settings := newFlags("check")
+	asJSON := fs.Bool("json", false, "print the results as JSON")
+	fs.Parse(args)
+
+	jc, err := setup(settings)
+	if err != nil {
+		return err
+	}
+	results := jc.Check(ctx)
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return err
+		}
+	} else {
+		for _, r := range results {
+			site := fmt.Sprintf("%s %s (%s)", r.Site, r.URL, r.Label)
+			if r.Error != "" {
+				fmt.Printf("FAIL  %s: %s\n", site, r.Error)
+				continue
+			}
+			fmt.Printf("ok    %s: authenticated as %s", site, r.User)
+			if r.Email != "" {
+				fmt.Printf(" <%s>", r.Email)
+			}
+			fmt.Printf(", REST API v%s\n", r.APIVersion)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("check failed for %d of %d sites", failed, len(results))
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Command jira serves the Jira MCP tools.
+//
+//	jira-mcp [serve] [--transport stdio|http] [--listen :8080]
+//	jira-mcp check   validate credentials and connectivity
+//	jira-mcp tools   print the tool list (--json for schemas)
+//
+// Every subcommand takes --config (default ~/.config/jira-mcp/config.yaml)
+// and --env-file (default .env in the working directory).
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/gomcpserver/jira"
+)
+
+const usage = `Usage: jira-mcp <command> [flags]
+
+Commands:
+  serve   serve the MCP tools (default)
+  check   validate credentials and connectivity for each site
+  tools   print the tools and, with --json, their schemas
+
+Run jira-mcp <command> -h for the command's flags.
+`
+
+func main() {
+	// Timestamp + microseconds + short file:line for easier troubleshooting
+	log.SetFlags(log.LstdFlags | log.Lmicroseconds | log.Lshortfile)
+
+	cmd, args := "serve", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		cmd, args = args[0], args[1:]
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var err error
+	switch cmd {
+	case "serve":
+		err = serve(ctx, args)
+	case "check":
+		err = check(ctx, args)
+	case "tools":
+		err = tools(ctx, args)
+	case "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", cmd, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// settings are the flags every subcommand takes.
+type settings struct {
+	config  string
+	envFile string
+}
+
+// newFlags returns the flag set for a subcommand, with --config and
+// --env-file.
+func newFlags(name string) (*flag.FlagSet, *settings) {
+	fs := flag.NewFlagSet("jira-mcp "+name, flag.ExitOnError)
+	s := &settings{}
+	fs.StringVar(&s.config, "config", "", "config file (default ~/.config/jira-mcp/config.yaml)")
+	fs.StringVar(&s.envFile, "env-file", "", "file of NAME=value settings (default .env)")
+	return fs, s
+}
+
+// load applies the .env file, then the config file beneath it.
+func (s *settings) load() error {
+	if err := jira.LoadEnvFile(s.envFile); err != nil {
+		return fmt.Errorf("init error: %w", err)
+	}
+	if err := jira.LoadConfig(s.config); err != nil {
+		return fmt.Errorf("init error: %w", err)
+	}
+	return nil
+}
+
+// setup loads the settings and builds the Jira client.
+func setup(s *settings) (*jira.JiraClient, error) {
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	jc, err := jira.NewJiraClientFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("init error: %w", err)
+	}
+	return jc, nil
+}
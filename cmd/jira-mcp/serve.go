@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gomcpserver/jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func serve(ctx context.Context, args []string) error {
+	fs, settings := newFlags("serve")
+	transport := fs.String("transport", "stdio", "stdio, or http for the streamable HTTP transport")
+	listen := fs.String("listen", ":8080", "address to listen on with --transport http")
+	drain := fs.Duration("shutdown-timeout", 30*time.Second, "how long shutdown waits for tool calls in progress (http)")
+	fs.Parse(args)
+
+	jc, err := setup(settings)
+	if err != nil {
+		return err
+	}
+	server, err := jira.NewServer(jc, nil)
+	if err != nil {
+		return fmt.Errorf("init error: %w", err)
+	}
+	switch *transport {
+	case "stdio":
+		// Run over stdio (for IDE/hosts)
+		if err := server.Run(ctx, &mcp.StdioTransport{}); err != nil && ctx.Err() == nil {
+			return fmt.Errorf("server failed: %w", err)
+		}
+		return nil
+	case "http":
+		// Clients authenticate with JIRA_MCP_TOKEN; see jira.HTTPOptions.
+		if err := server.RunHTTP(ctx, *listen, &jira.HTTPOptions{ShutdownTimeout: *drain}); err != nil {
+			return fmt.Errorf("server failed: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown transport %q (valid: stdio, http)", *transport)
+}
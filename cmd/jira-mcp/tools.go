@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/gomcpserver/jira"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func tools(ctx context.Context, args []string) error {
+	fs, settings := newFlags("tools")
+	asJSON := fs.Bool("json", false, "print each tool in full, with its input schema")
+	fs.Parse(args)
+
+	if err := settings.load(); err != nil {
+		return err
+	}
+	// Listing tools does not need credentials; without them, tools whose
+	// descriptions depend on the site are shown without site caveats.
+	log.SetOutput(io.Discard)
+	opts := &jira.Options{}
+	jc, err := jira.NewJiraClientFromEnv()
+	if err != nil {
+		jc, opts.HTTPClient = &jira.JiraClient{}, &http.Client{}
+	}
+	server, err := jira.NewServer(jc, opts)
+	if err != nil {
+		return fmt.Errorf("init error: %w", err)
+	}
+	st, ct := mcp.NewInMemoryTransports()
+	if _, err := server.MCP.Connect(ctx, st, nil); err != nil {
+		return err
+	}
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "jira-mcp-tools", Version: "0.1.0"}, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		return err
+	}
+	defer cs.Close()
+	var list []*mcp.Tool
+	for t, err := range cs.Tools(ctx, nil) {
+		if err != nil {
+			return err
+		}
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(list)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, t := range list {
+		fmt.Fprintf(w, "%s\t%s\n", t.Name, t.Title)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,280 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Comments ----
+
+type JiraCommentPage struct {
+	StartAt    int           `json:"startAt"`
+	MaxResults int           `json:"maxResults"`
+	Total      int           `json:"total"`
+	Comments   []JiraComment `json:"comments"`
+}
+
+// GetComments returns one page of comments on key. order is "created"
+// (oldest first) or "-created" (newest first).
+func (c *JiraClient) GetComments(ctx context.Context, key string, startAt, max int, order string) (*JiraCommentPage, error) {
+	if max <= 0 || max > 100 {
+		max = 50
+	}
+	q := url.Values{}
+	q.Set("startAt", fmt.Sprintf("%d", startAt))
+	q.Set("maxResults", fmt.Sprintf("%d", max))
+	if order != "" {
+		q.Set("orderBy", order)
+	}
+	var out JiraCommentPage
+	path := "/rest/api/3/issue/" + url.PathEscape(key) + "/comment?" + q.Encode()
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *JiraClient) GetComment(ctx context.Context, key, id string) (*JiraComment, error) {
+	var out JiraComment
+	path := "/rest/api/3/issue/" + url.PathEscape(key) + "/comment/" + url.PathEscape(id)
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PostComment adds a comment whose body is already in Jira's format.
+func (c *JiraClient) PostComment(ctx context.Context, key string, body any) (*JiraComment, error) {
+	var out JiraComment
+	if err := c.doJSON(ctx, http.MethodPost, "/rest/api/3/issue/"+url.PathEscape(key)+"/comment", map[string]any{"body": body}, &out); err != nil {
+		return nil, err
+	}
+	c.recordAction(ctx, actionComment, key, out.ID)
+	noteCreated(ctx, "comment", out.ID, key)
+	return &out, nil
+}
+
+// UpdateComment replaces the body of comment id on key.
+func (c *JiraClient) UpdateComment(ctx context.Context, key, id, body string) (*JiraComment, error) {
+	var out JiraComment
+	path := "/rest/api/3/issue/" + url.PathEscape(key) + "/comment/" + url.PathEscape(id)
+	if err := c.doJSON(ctx, http.MethodPut, path, map[string]any{"body": c.richText(ctx, body)}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// deniedResult turns a Jira permission failure (401/403, or 404 for
+// comments the caller cannot see) into a structured tool error, so agents
+// can tell "not allowed" apart from transport failures.
+func deniedResult(err error, target map[string]any) (*mcp.CallToolResult, bool) {
+	var je *JiraError
+	if !errors.As(err, &je) {
+		return nil, false
+	}
+	var reason string
+	switch je.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		reason = "permission_denied"
+	case http.StatusNotFound:
+		reason = "not_found_or_not_visible"
+	default:
+		return nil, false
+	}
+	res := map[string]any{"error": reason, "status": je.StatusCode, "messages": je.Messages()}
+	for k, v := range target {
+		res[k] = v
+	}
+	return &mcp.CallToolResult{
+		IsError:           true,
+		StructuredContent: res,
+		Content:           []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("%s: %s", reason, err)}},
+	}, true
+}
+
+// commentView is a comment flattened for agents: author and timestamps next
+// to a Markdown body.
+type commentView struct {
+	ID       string `json:"id"`
+	Author   string `json:"author,omitempty"`
+	AuthorID string `json:"author_account_id,omitempty"`
+	Created  string `json:"created,omitempty"`
+	Updated  string `json:"updated,omitempty"`
+	Body     string `json:"body"`
+	BodyADF  any    `json:"body_adf,omitempty"`
+}
+
+func viewComment(cm JiraComment) commentView {
+	v := commentView{
+		ID:       cm.ID,
+		Author:   fieldString(cm.Author, "displayName"),
+		AuthorID: fieldString(cm.Author, "accountId"),
+		Created:  cm.Created,
+		Body:     adfToMarkdown(cm.Body),
+	}
+	if cm.Updated != cm.Created {
+		v.Updated = cm.Updated
+	}
+	return v
+}
+
+// replyQuoteLimit caps how much of the original comment a reply quotes.
+const replyQuoteLimit = 300
+
+// replyDoc builds a reply to orig: a mention of its author, a truncated
+// blockquote of its text, then the Markdown reply.
+func replyDoc(orig JiraComment, reply string) *adfNode {
+	name := fieldString(orig.Author, "displayName")
+	if name == "" {
+		name = "Someone"
+	}
+	header := adfParagraph(adfText(name + " wrote:"))
+	if id := fieldString(orig.Author, "accountId"); id != "" {
+		header = adfParagraph(
+			&adfNode{Type: "mention", Attrs: map[string]any{"id": id, "text": "@" + name}},
+			adfText(" wrote:"),
+		)
+	}
+	quoted := strings.TrimSpace(adfToMarkdown(orig.Body))
+	if r := []rune(quoted); len(r) > replyQuoteLimit {
+		quoted = strings.TrimSpace(string(r[:replyQuoteLimit])) + "…"
+	}
+	content := []*adfNode{header}
+	if quoted != "" {
+		quoteDoc, _ := markdownToADF(quoteLines(quoted))
+		content = append(content, quoteDoc.Content...)
+	}
+	replyBody, _ := markdownToADF(reply)
+	return adfDoc(append(content, replyBody.Content...)...)
+}
+
+func registerCommentTools(server *mcp.Server, jc *JiraClient) {
+	// get_comments(key, start_at?, max_results?, order?, raw_adf?)
+	type getCommentsArgs struct {
+		Key        string `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+		StartAt    int    `json:"start_at,omitempty" jsonschema:"Index of the first comment to return"`
+		MaxResults int    `json:"max_results,omitempty" jsonschema:"Page size (default 50, max 100)"`
+		Order      string `json:"order,omitempty" jsonschema:"oldest (default) or newest first"`
+		RawADF     bool   `json:"raw_adf,omitempty" jsonschema:"Also return each body as raw ADF, for round-tripping"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_comments",
+		Title:       "Get Comments",
+		Description: "Read the comments on a Jira issue, with author and timestamps, one page at a time",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args getCommentsArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=get_comments args={key:%q,start:%d,max:%d,order:%q}", args.Key, args.StartAt, args.MaxResults, args.Order)
+		var order string
+		switch args.Order {
+		case "", "oldest":
+			order = "created"
+		case "newest":
+			order = "-created"
+		default:
+			return nil, nil, fmt.Errorf("unknown order %q (valid: oldest, newest)", args.Order)
+		}
+		page, err := jc.GetComments(ctx, args.Key, args.StartAt, args.MaxResults, order)
+		if err != nil {
+			debugf("tool=get_comments error=%v", err)
+			return nil, nil, err
+		}
+		views := make([]commentView, len(page.Comments))
+		for i, cm := range page.Comments {
+			views[i] = viewComment(cm)
+			if args.RawADF {
+				views[i].BodyADF = cm.Body
+			}
+		}
+		res := map[string]any{
+			"key":      args.Key,
+			"start_at": page.StartAt,
+			"total":    page.Total,
+			"comments": views,
+		}
+		if next := page.StartAt + len(page.Comments); next < page.Total {
+			res["next_start_at"] = next
+		}
+		return &mcp.CallToolResult{StructuredContent: res}, nil, nil
+	})
+
+	// edit_comment(key, comment_id, body)
+	type editCommentArgs struct {
+		Key       string `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+		CommentID string `json:"comment_id" jsonschema:"Id of the comment to edit"`
+		Body      string `json:"body" jsonschema:"New comment body (Markdown)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "edit_comment",
+		Title:       "Edit Comment",
+		Description: "Replace the body of an existing comment. Permission failures come back as a structured error",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args editCommentArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=edit_comment args={key:%q,id:%q,body-len:%d}", args.Key, args.CommentID, len(args.Body))
+		cm, err := jc.UpdateComment(ctx, args.Key, args.CommentID, args.Body)
+		if err != nil {
+			debugf("tool=edit_comment error=%v", err)
+			if res, ok := deniedResult(err, map[string]any{"key": args.Key, "comment_id": args.CommentID}); ok {
+				return res, nil, nil
+			}
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"key": args.Key, "comment": viewComment(*cm)}}, nil, nil
+	})
+
+	// delete_comment(key, comment_id)
+	type deleteCommentArgs struct {
+		Key       string `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+		CommentID string `json:"comment_id" jsonschema:"Id of the comment to delete"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "delete_comment",
+		Title:       "Delete Comment",
+		Description: "Delete a comment. Permission failures come back as a structured error",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: ptr(true)},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args deleteCommentArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=delete_comment args={key:%q,id:%q}", args.Key, args.CommentID)
+		if err := jc.DeleteComment(ctx, args.Key, args.CommentID); err != nil {
+			debugf("tool=delete_comment error=%v", err)
+			if res, ok := deniedResult(err, map[string]any{"key": args.Key, "comment_id": args.CommentID}); ok {
+				return res, nil, nil
+			}
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"key": args.Key, "comment_id": args.CommentID, "deleted": true}}, nil, nil
+	})
+
+	// reply_to_comment(key, comment_id, body)
+	type replyArgs struct {
+		Key       string `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+		CommentID string `json:"comment_id" jsonschema:"Id of the comment being replied to"`
+		Body      string `json:"body" jsonschema:"Reply text (Markdown)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "reply_to_comment",
+		Title:       "Reply to Comment",
+		Description: "Reply to a comment: posts a new comment that mentions the original author and quotes the original (truncated) above the reply",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args replyArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=reply_to_comment args={key:%q,id:%q,body-len:%d}", args.Key, args.CommentID, len(args.Body))
+		orig, err := jc.GetComment(ctx, args.Key, args.CommentID)
+		if err != nil {
+			debugf("tool=reply_to_comment error=%v", err)
+			return nil, nil, err
+		}
+		cm, err := jc.PostComment(ctx, args.Key, replyDoc(*orig, args.Body))
+		if err != nil {
+			debugf("tool=reply_to_comment error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{
+			"key":         args.Key,
+			"in_reply_to": args.CommentID,
+			"comment":     viewComment(*cm),
+		}}, nil, nil
+	})
+}
@@ -0,0 +1,304 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Components ----
+
+type JiraComponent struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description,omitempty"`
+	Project      string    `json:"project,omitempty"`
+	Lead         *JiraUser `json:"lead,omitempty"`
+	AssigneeType string    `json:"assigneeType,omitempty"`
+	RealAssignee *JiraUser `json:"realAssignee,omitempty"`
+}
+
+// componentAssigneeTypes maps the tool's default assignee choices to
+// Jira's assigneeType values.
+var componentAssigneeTypes = map[string]string{
+	"project_default": "PROJECT_DEFAULT",
+	"component_lead":  "COMPONENT_LEAD",
+	"project_lead":    "PROJECT_LEAD",
+	"unassigned":      "UNASSIGNED",
+}
+
+func componentAssigneeType(s string) (string, error) {
+	if t, ok := componentAssigneeTypes[strings.ToLower(s)]; ok {
+		return t, nil
+	}
+	return "", fmt.Errorf("unknown default_assignee %q (valid: project_default, component_lead, project_lead, unassigned)", s)
+}
+
+func (c *JiraClient) ListComponents(ctx context.Context, project string) ([]JiraComponent, error) {
+	var out []JiraComponent
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/project/"+url.PathEscape(project)+"/components", nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ProjectComponents maps component names (lower-cased) to ids.
+func (c *JiraClient) ProjectComponents(ctx context.Context, projectKey string) (map[string]string, error) {
+	comps, err := c.ListComponents(ctx, projectKey)
+	if err != nil {
+		return nil, err
+	}
+	m := map[string]string{}
+	for _, comp := range comps {
+		m[strings.ToLower(comp.Name)] = comp.ID
+	}
+	return m, nil
+}
+
+// componentRefs resolves component names (case-insensitive) or ids in a
+// project to the references issue fields take. Unknown names are an error
+// listing the project's components.
+func (c *JiraClient) componentRefs(ctx context.Context, project string, names []string) ([]any, error) {
+	comps, err := c.ListComponents(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	objs := make([]jiraNamedObject, len(comps))
+	for i, comp := range comps {
+		objs[i] = jiraNamedObject{ID: comp.ID, Name: comp.Name}
+	}
+	return namedRefs("component", project, names, objs)
+}
+
+// namedRefs matches names (case-insensitive) or ids against a project's
+// objects of one kind, returning {"id", "name"} references.
+func namedRefs(kind, project string, names []string, objs []jiraNamedObject) ([]any, error) {
+	out := []any{}
+	for _, n := range names {
+		var found *jiraNamedObject
+		for i := range objs {
+			if strings.EqualFold(objs[i].Name, n) || objs[i].ID == n {
+				found = &objs[i]
+				break
+			}
+		}
+		if found == nil {
+			avail := make([]string, len(objs))
+			for i, o := range objs {
+				avail[i] = o.Name
+			}
+			return nil, fmt.Errorf("project %s has no %s %q (%ss: %s)", project, kind, n, kind, strings.Join(avail, ", "))
+		}
+		out = append(out, map[string]any{"id": found.ID, "name": found.Name})
+	}
+	return out, nil
+}
+
+func (c *JiraClient) CreateComponent(ctx context.Context, body map[string]any) (*JiraComponent, error) {
+	var out JiraComponent
+	if err := c.doJSON(ctx, http.MethodPost, "/rest/api/3/component", body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *JiraClient) UpdateComponent(ctx context.Context, id string, changes map[string]any) (*JiraComponent, error) {
+	var out JiraComponent
+	if err := c.doJSON(ctx, http.MethodPut, "/rest/api/3/component/"+url.PathEscape(id), changes, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteComponent deletes a component, moving its issues to another
+// component when moveTo is set.
+func (c *JiraClient) DeleteComponent(ctx context.Context, id, moveTo string) error {
+	path := "/rest/api/3/component/" + url.PathEscape(id)
+	if moveTo != "" {
+		path += "?moveIssuesTo=" + url.QueryEscape(moveTo)
+	}
+	return c.doJSON(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// componentChanges builds a create or update body from the tool arguments
+// shared by create_component and update_component.
+func (c *JiraClient) componentChanges(ctx context.Context, name, description, lead, defaultAssignee string) (map[string]any, error) {
+	body := map[string]any{}
+	if name != "" {
+		body["name"] = name
+	}
+	if description != "" {
+		body["description"] = description
+	}
+	if lead != "" {
+		u, err := c.ResolveUser(ctx, lead)
+		if err != nil {
+			return nil, fmt.Errorf("lead: %w", err)
+		}
+		if u.AccountID != "" {
+			body["leadAccountId"] = u.AccountID
+		} else {
+			body["leadUserName"] = u.Name
+		}
+	}
+	if defaultAssignee != "" {
+		t, err := componentAssigneeType(defaultAssignee)
+		if err != nil {
+			return nil, err
+		}
+		body["assigneeType"] = t
+	}
+	return body, nil
+}
+
+type componentView struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	Description     string `json:"description,omitempty"`
+	Lead            string `json:"lead,omitempty"`
+	DefaultAssignee string `json:"default_assignee,omitempty"`
+	AssignsTo       string `json:"assigns_to,omitempty"` // who new issues actually go to
+}
+
+func viewComponent(comp *JiraComponent) componentView {
+	v := componentView{ID: comp.ID, Name: comp.Name, Description: comp.Description, DefaultAssignee: strings.ToLower(comp.AssigneeType)}
+	if comp.Lead != nil {
+		v.Lead = comp.Lead.DisplayName
+	}
+	if comp.RealAssignee != nil {
+		v.AssignsTo = comp.RealAssignee.DisplayName
+	}
+	return v
+}
+
+func registerComponentTools(server *mcp.Server, jc *JiraClient) {
+	// list_components(project?)
+	type listComponentsArgs struct {
+		Project string `json:"project,omitempty" jsonschema:"Project key (default the focus project)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_components",
+		Title:       "List Components",
+		Description: "List a project's components with lead and default assignee",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listComponentsArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_components args={project:%q}", args.Project)
+		project := args.Project
+		if f := jc.focusFor(req.Session); project == "" && f != nil {
+			project = f.Project
+		}
+		if project == "" {
+			return nil, nil, errors.New("project is required (no focus project set)")
+		}
+		comps, err := jc.ListComponents(ctx, project)
+		if err != nil {
+			debugf("tool=list_components error=%v", err)
+			return nil, nil, err
+		}
+		views := make([]componentView, len(comps))
+		for i := range comps {
+			views[i] = viewComponent(&comps[i])
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"project": project, "components": views}}, nil, nil
+	})
+
+	// create_component(project, name, description?, lead?, default_assignee?)
+	type createComponentArgs struct {
+		Project         string `json:"project,omitempty" jsonschema:"Project key (default the focus project)"`
+		Name            string `json:"name" jsonschema:"Component name"`
+		Description     string `json:"description,omitempty"`
+		Lead            string `json:"lead,omitempty" jsonschema:"Component lead: accountId, email, or display name"`
+		DefaultAssignee string `json:"default_assignee,omitempty" jsonschema:"Who new issues with this component are assigned to: project_default, component_lead, project_lead, or unassigned"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "create_component",
+		Title:       "Create Component",
+		Description: "Create a project component, optionally with a lead and default assignee",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args createComponentArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=create_component args={project:%q,name:%q}", args.Project, args.Name)
+		project := args.Project
+		if f := jc.focusFor(req.Session); project == "" && f != nil {
+			project = f.Project
+		}
+		if project == "" || strings.TrimSpace(args.Name) == "" {
+			return nil, nil, errors.New("project and name are required")
+		}
+		body, err := jc.componentChanges(ctx, args.Name, args.Description, args.Lead, args.DefaultAssignee)
+		if err != nil {
+			return nil, nil, err
+		}
+		body["project"] = project
+		comp, err := jc.CreateComponent(ctx, body)
+		if err != nil {
+			debugf("tool=create_component error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: viewComponent(comp)}, nil, nil
+	})
+
+	// update_component(id, name?, description?, lead?, default_assignee?)
+	type updateComponentArgs struct {
+		ID              string `json:"id" jsonschema:"Component id (see list_components)"`
+		Name            string `json:"name,omitempty" jsonschema:"New name"`
+		Description     string `json:"description,omitempty" jsonschema:"New description"`
+		Lead            string `json:"lead,omitempty" jsonschema:"New lead: accountId, email, or display name"`
+		DefaultAssignee string `json:"default_assignee,omitempty" jsonschema:"project_default, component_lead, project_lead, or unassigned"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "update_component",
+		Title:       "Update Component",
+		Description: "Rename a component or change its description, lead, or default assignee",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args updateComponentArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=update_component args={id:%q,name:%q}", args.ID, args.Name)
+		changes, err := jc.componentChanges(ctx, args.Name, args.Description, args.Lead, args.DefaultAssignee)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(changes) == 0 {
+			return nil, nil, errors.New("nothing to update")
+		}
+		comp, err := jc.UpdateComponent(ctx, args.ID, changes)
+		if err != nil {
+			debugf("tool=update_component error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: viewComponent(comp)}, nil, nil
+	})
+
+	// delete_component(id, move_issues_to?, confirm?)
+	type deleteComponentArgs struct {
+		ID           string `json:"id" jsonschema:"Component id (see list_components)"`
+		MoveIssuesTo string `json:"move_issues_to,omitempty" jsonschema:"Id of a component to move this one's issues to; otherwise they just lose it"`
+		Confirm      bool   `json:"confirm,omitempty" jsonschema:"Only for clients without elicitation: set once the user has approved the deletion"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "delete_component",
+		Title:       "Delete Component",
+		Description: "Delete a component, optionally moving its issues to another. Asks the user to confirm; clients without elicitation pass confirm=true once the user has approved",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: ptr(true)},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args deleteComponentArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=delete_component args={id:%q,move_to:%q,confirm:%t}", args.ID, args.MoveIssuesTo, args.Confirm)
+		ok, err := approveAction(ctx, req.Session, args.Confirm, fmt.Sprintf("Delete component %s?", args.ID))
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			return nil, nil, fmt.Errorf("deletion of component %s declined by the user", args.ID)
+		}
+		if err := jc.DeleteComponent(ctx, args.ID, args.MoveIssuesTo); err != nil {
+			debugf("tool=delete_component error=%v", err)
+			return nil, nil, err
+		}
+		res := map[string]any{"id": args.ID, "deleted": true}
+		if args.MoveIssuesTo != "" {
+			res["issues_moved_to"] = args.MoveIssuesTo
+		}
+		return &mcp.CallToolResult{StructuredContent: res}, nil, nil
+	})
+}
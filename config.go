@@ -0,0 +1,56 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ---- Structured settings ----
+
+// readSetting returns the value of the environment variable name, or, if
+// that is unset, the contents of the file named by name+"_FILE". It reports
+// whether the setting was present.
+func readSetting(name string) (string, bool, error) {
+	if raw := os.Getenv(name); raw != "" {
+		return raw, true, nil
+	}
+	path := os.Getenv(name + "_FILE")
+	if path == "" {
+		return "", false, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("%s_FILE: %w", name, err)
+	}
+	return string(b), true, nil
+}
+
+// loadJSONSetting decodes a JSON setting from the environment variable name,
+// or, if that is unset, from the file named by name+"_FILE". It reports
+// whether the setting was present.
+func loadJSONSetting(name string, dst any) (bool, error) {
+	raw, ok, err := readSetting(name)
+	if !ok || err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal([]byte(raw), dst); err != nil {
+		return false, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return true, nil
+}
+
+// loadYAMLSetting is loadJSONSetting for settings written as YAML. JSON is
+// valid YAML, so either form is accepted.
+func loadYAMLSetting(name string, dst any) (bool, error) {
+	raw, ok, err := readSetting(name)
+	if !ok || err != nil {
+		return false, err
+	}
+	if err := yaml.Unmarshal([]byte(raw), dst); err != nil {
+		return false, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,228 @@
+package jira
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ---- Config file ----
+//
+// LoadConfig reads a YAML (or JSON) file that stands in for the JIRA_*
+// environment variables, from --config or ~/.config/jira-mcp/config.yaml:
+//
+//	instance:
+//	  url: https://acme.atlassian.net
+//	  email: me@acme.com
+//	  token_keychain: jira-mcp
+//	  label: acme
+//	sites:
+//	  dc: {url: "https://jira.acme.internal", auth: pat, token_env: JIRA_DC_TOKEN}
+//	defaults:
+//	  project: PROJ
+//	  max_results: 25
+//	  fields: [summary, status, assignee]
+//	tools:
+//	  disabled: [delete_issue, seed_sandbox]
+//	safety:
+//	  production: true
+//	  writes: confirm
+//	settings:
+//	  JIRA_WRITE_QUEUE: {path: /var/lib/jira-mcp/queue.json}
+//
+// Each value is applied only where the environment does not already supply
+// that setting, directly or through its _FILE (or, for the token,
+// _KEYCHAIN) variant, so the environment always wins.
+
+type fileConfig struct {
+	Instance struct {
+		URL           string `yaml:"url"`
+		Auth          string `yaml:"auth"`
+		Email         string `yaml:"email"`
+		Token         string `yaml:"token"`
+		TokenFile     string `yaml:"token_file"`
+		TokenKeychain string `yaml:"token_keychain"`
+		APIVersion    string `yaml:"api_version"`
+		Label         string `yaml:"label"`
+		OAuth         any    `yaml:"oauth"`
+	} `yaml:"instance"`
+	Sites    map[string]any `yaml:"sites"`
+	Defaults struct {
+		Project    string   `yaml:"project"`
+		MaxResults int      `yaml:"max_results"`
+		Fields     []string `yaml:"fields"`
+	} `yaml:"defaults"`
+	Tools struct {
+		Enabled  []string `yaml:"enabled"`
+		Disabled []string `yaml:"disabled"`
+	} `yaml:"tools"`
+	Safety struct {
+		Production    bool   `yaml:"production"`
+		Writes        string `yaml:"writes"`
+		DisableDelete bool   `yaml:"disable_delete"`
+		Provenance    string `yaml:"provenance"`
+	} `yaml:"safety"`
+	Settings map[string]any `yaml:"settings"`
+}
+
+// defaultConfigPath is ~/.config/jira-mcp/config.yaml, or under
+// $XDG_CONFIG_HOME when that is set.
+func defaultConfigPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "jira-mcp", "config.yaml")
+}
+
+// LoadConfig applies the config file at path to the environment, leaving
+// variables that are already set alone. An empty path means the default
+// location, which need not exist.
+func LoadConfig(path string) error {
+	explicit := path != ""
+	if !explicit {
+		path = defaultConfigPath()
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !explicit {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	var cfg fileConfig
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil {
+		return fmt.Errorf("config %s: %w", path, err)
+	}
+	vars, err := cfg.env()
+	if err != nil {
+		return fmt.Errorf("config %s: %w", path, err)
+	}
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if env := envOverride(name); env != "" {
+			debugf("config: %s is set in the environment; ignoring the file's %s", env, name)
+			continue
+		}
+		os.Setenv(name, vars[name])
+	}
+	debugf("config: loaded %s (%d settings)", path, len(vars))
+	return nil
+}
+
+// envOverride returns the environment variable that already supplies the
+// setting name, or "". A setting can come from NAME, from the file named by
+// NAME_FILE (see readSetting), or, for the API token, from the keychain
+// entry NAME_KEYCHAIN; whichever of these the environment sets beats every
+// one of them in the file.
+func envOverride(name string) string {
+	base := strings.TrimSuffix(strings.TrimSuffix(name, "_FILE"), "_KEYCHAIN")
+	for _, v := range []string{base, base + "_FILE", base + "_KEYCHAIN"} {
+		if _, set := os.LookupEnv(v); set {
+			return v
+		}
+	}
+	return ""
+}
+
+// env translates the file into environment variables.
+func (cfg *fileConfig) env() (map[string]string, error) {
+	vars := map[string]string{}
+	set := func(name, v string) {
+		if v != "" {
+			vars[name] = v
+		}
+	}
+	in := cfg.Instance
+	set("JIRA_INSTANCE_URL", in.URL)
+	set("JIRA_AUTH", in.Auth)
+	set("JIRA_USER_EMAIL", in.Email)
+	set("JIRA_API_TOKEN", in.Token)
+	set("JIRA_API_TOKEN_FILE", expandHome(in.TokenFile))
+	set("JIRA_API_TOKEN_KEYCHAIN", in.TokenKeychain)
+	set("JIRA_API_VERSION", in.APIVersion)
+	if in.Label != "" || cfg.Safety.Production || cfg.Safety.Writes != "" {
+		b, err := json.Marshal(instanceConfig{Label: in.Label, Production: cfg.Safety.Production, Writes: cfg.Safety.Writes})
+		if err != nil {
+			return nil, err
+		}
+		set("JIRA_INSTANCE", string(b))
+	}
+	if cfg.Safety.DisableDelete {
+		set("JIRA_DISABLE_DELETE", "1")
+	}
+	set("JIRA_PROVENANCE", cfg.Safety.Provenance)
+	set("JIRA_DEFAULT_PROJECT", cfg.Defaults.Project)
+	if cfg.Defaults.MaxResults > 0 {
+		set("JIRA_MAX_RESULTS", strconv.Itoa(cfg.Defaults.MaxResults))
+	}
+	set("JIRA_DEFAULT_FIELDS", strings.Join(cfg.Defaults.Fields, ","))
+	set("JIRA_TOOLS", strings.Join(cfg.Tools.Enabled, ","))
+	set("JIRA_DISABLED_TOOLS", strings.Join(cfg.Tools.Disabled, ","))
+	for name, v := range map[string]any{"JIRA_OAUTH": in.OAuth, "JIRA_SITES": cfg.Sites} {
+		s, err := settingString(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		set(name, s)
+	}
+	for name, v := range cfg.Settings {
+		if !strings.HasPrefix(name, "JIRA_") {
+			return nil, fmt.Errorf("settings: %q is not a JIRA_ setting", name)
+		}
+		s, err := settingString(v)
+		if err != nil {
+			return nil, fmt.Errorf("settings: %s: %w", name, err)
+		}
+		set(name, s)
+	}
+	return vars, nil
+}
+
+// settingString renders a config value the way the environment variable
+// holds it: scalars as text, lists and maps as JSON.
+func settingString(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool, int, float64:
+		return fmt.Sprint(v), nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	if string(b) == "null" || string(b) == "{}" {
+		return "", nil
+	}
+	return string(b), nil
+}
+
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
@@ -0,0 +1,86 @@
+package jira
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfigEnvironmentWins(t *testing.T) {
+	vars := []string{
+		"JIRA_INSTANCE_URL", "JIRA_USER_EMAIL",
+		"JIRA_API_TOKEN", "JIRA_API_TOKEN_FILE", "JIRA_API_TOKEN_KEYCHAIN",
+		"JIRA_SITES", "JIRA_SITES_FILE", "JIRA_OAUTH", "JIRA_OAUTH_FILE",
+	}
+	tests := []struct {
+		name string
+		file string
+		env  map[string]string
+		want map[string]string // "" means unset
+	}{
+		{
+			name: "file fills in",
+			file: "instance: {url: https://file.example, token: filetok}",
+			want: map[string]string{"JIRA_INSTANCE_URL": "https://file.example", "JIRA_API_TOKEN": "filetok"},
+		},
+		{
+			name: "env value",
+			file: "instance: {url: https://file.example}",
+			env:  map[string]string{"JIRA_INSTANCE_URL": "https://env.example"},
+			want: map[string]string{"JIRA_INSTANCE_URL": "https://env.example"},
+		},
+		{
+			name: "env token file beats file token",
+			file: "instance: {token: filetok}",
+			env:  map[string]string{"JIRA_API_TOKEN_FILE": "/run/secrets/jira"},
+			want: map[string]string{"JIRA_API_TOKEN": "", "JIRA_API_TOKEN_FILE": "/run/secrets/jira"},
+		},
+		{
+			name: "env keychain beats file token",
+			file: "instance: {token: filetok}",
+			env:  map[string]string{"JIRA_API_TOKEN_KEYCHAIN": "jira-mcp"},
+			want: map[string]string{"JIRA_API_TOKEN": ""},
+		},
+		{
+			name: "env token beats file token file",
+			file: "instance: {token_file: /file/token}",
+			env:  map[string]string{"JIRA_API_TOKEN": "envtok"},
+			want: map[string]string{"JIRA_API_TOKEN": "envtok", "JIRA_API_TOKEN_FILE": ""},
+		},
+		{
+			name: "env sites file beats file sites",
+			file: "sites: {dc: {url: https://dc.example}}",
+			env:  map[string]string{"JIRA_SITES_FILE": "/etc/jira/sites.json"},
+			want: map[string]string{"JIRA_SITES": ""},
+		},
+		{
+			name: "env oauth file beats file setting",
+			file: "settings: {JIRA_OAUTH: {client_id: x}}",
+			env:  map[string]string{"JIRA_OAUTH_FILE": "/etc/jira/oauth.json"},
+			want: map[string]string{"JIRA_OAUTH": ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, v := range vars {
+				t.Setenv(v, "") // restored after the test
+				os.Unsetenv(v)
+			}
+			for k, v := range tt.env {
+				os.Setenv(k, v)
+			}
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if err := LoadConfig(path); err != nil {
+				t.Fatal(err)
+			}
+			for k, want := range tt.want {
+				if got := os.Getenv(k); got != want {
+					t.Errorf("%s = %q, want %q", k, got, want)
+				}
+			}
+		})
+	}
+}
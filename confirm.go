@@ -0,0 +1,47 @@
+package jira
+
+import (
+	"context"
+	"errors"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Human confirmation for consequential actions ----
+
+// errConfirmationUnavailable means the client cannot be asked (it does not
+// support elicitation), so the caller must pass confirm=true explicitly once
+// a human has approved the action.
+var errConfirmationUnavailable = errors.New("confirmation required: the client does not support elicitation; ask the user, then call again with confirm=true")
+
+// confirmAction asks the user, via MCP elicitation, to approve message.
+// It reports whether the user accepted.
+func confirmAction(ctx context.Context, ss *mcp.ServerSession, message string) (bool, error) {
+	if ss == nil {
+		return false, errConfirmationUnavailable
+	}
+	if p := ss.InitializeParams(); p == nil || p.Capabilities == nil || p.Capabilities.Elicitation == nil {
+		return false, errConfirmationUnavailable
+	}
+	res, err := ss.Elicit(ctx, &mcp.ElicitParams{
+		Message:         message,
+		RequestedSchema: map[string]any{"type": "object", "properties": map[string]any{}},
+	})
+	if err != nil {
+		debugf("elicitation failed: %v", err)
+		return false, errConfirmationUnavailable
+	}
+	return res.Action == "accept", nil
+}
+
+// approveAction asks the user to approve message when the client supports
+// elicitation, whatever confirm says. Only for clients that cannot elicit
+// does confirm=true stand in for the user's approval; without it they get
+// errConfirmationUnavailable.
+func approveAction(ctx context.Context, ss *mcp.ServerSession, confirm bool, message string) (bool, error) {
+	ok, err := confirmAction(ctx, ss, message)
+	if errors.Is(err, errConfirmationUnavailable) && confirm {
+		return true, nil
+	}
+	return ok, err
+}
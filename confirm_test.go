@@ -0,0 +1,61 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestApproveAction(t *testing.T) {
+	tests := []struct {
+		name    string
+		elicit  string // client's answer; "" means it cannot elicit
+		confirm bool
+		want    bool
+		wantErr error
+	}{
+		{"accepted", "accept", false, true, nil},
+		{"declined despite confirm", "decline", true, false, nil},
+		{"no elicitation, confirmed", "", true, true, nil},
+		{"no elicitation", "", false, false, errConfirmationUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			var got bool
+			var gotErr error
+			server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+			mcp.AddTool(server, &mcp.Tool{Name: "act"}, func(ctx context.Context, req *mcp.CallToolRequest, args struct {
+				Confirm bool `json:"confirm,omitempty"`
+			}) (*mcp.CallToolResult, any, error) {
+				got, gotErr = approveAction(ctx, req.Session, args.Confirm, "Proceed?")
+				return &mcp.CallToolResult{}, nil, nil
+			})
+			opts := &mcp.ClientOptions{}
+			if tt.elicit != "" {
+				opts.ElicitationHandler = func(context.Context, *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+					return &mcp.ElicitResult{Action: tt.elicit}, nil
+				}
+			}
+			st, ct := mcp.NewInMemoryTransports()
+			ss, err := server.Connect(ctx, st, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ss.Close()
+			cs, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, opts).Connect(ctx, ct, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer cs.Close()
+			if _, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: "act", Arguments: map[string]any{"confirm": tt.confirm}}); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want || !errors.Is(gotErr, tt.wantErr) {
+				t.Errorf("approveAction = %t, %v; want %t, %v", got, gotErr, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,439 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- JSM customers and organizations ----
+//
+// Customers are the portal accounts that raise requests; organizations
+// group them so that members see each other's requests. A service desk
+// only serves the customers and organizations added to it (unless it is
+// open to everyone).
+
+func (c *JiraClient) CreateCustomer(ctx context.Context, email, displayName string) (*JiraUser, error) {
+	var out JiraUser
+	body := map[string]any{"email": email, "displayName": displayName}
+	if err := c.doJSON(ctx, http.MethodPost, "/rest/servicedeskapi/customer", body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func serviceDeskPath(serviceDeskID, sub string) string {
+	return "/rest/servicedeskapi/servicedesk/" + url.PathEscape(serviceDeskID) + "/" + sub
+}
+
+// ServiceDeskCustomers lists up to limit of a service desk's customers,
+// optionally only those matching query.
+func (c *JiraClient) ServiceDeskCustomers(ctx context.Context, serviceDeskID, query string, limit int) ([]JiraUser, error) {
+	q := url.Values{}
+	if query != "" {
+		q.Set("query", query)
+	}
+	return sdPage[JiraUser](ctx, c, serviceDeskPath(serviceDeskID, "customer"), q, limit)
+}
+
+// SetServiceDeskCustomers adds (or, with remove, removes) customers by
+// accountId.
+func (c *JiraClient) SetServiceDeskCustomers(ctx context.Context, serviceDeskID string, accountIDs []string, remove bool) error {
+	method := http.MethodPost
+	if remove {
+		method = http.MethodDelete
+	}
+	return c.doJSON(ctx, method, serviceDeskPath(serviceDeskID, "customer"), map[string]any{"accountIds": accountIDs}, nil)
+}
+
+type JiraOrganization struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Organizations lists every organization, or those of one service desk.
+func (c *JiraClient) Organizations(ctx context.Context, serviceDeskID string) ([]JiraOrganization, error) {
+	path := "/rest/servicedeskapi/organization"
+	if serviceDeskID != "" {
+		path = serviceDeskPath(serviceDeskID, "organization")
+	}
+	return sdPage[JiraOrganization](ctx, c, path, nil, 0)
+}
+
+// Organization finds an organization by id or name.
+func (c *JiraClient) Organization(ctx context.Context, ref string) (*JiraOrganization, error) {
+	orgs, err := c.Organizations(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	for i, o := range orgs {
+		if o.ID == ref || strings.EqualFold(o.Name, ref) {
+			return &orgs[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no organization %q", ref)
+}
+
+func (c *JiraClient) CreateOrganization(ctx context.Context, name string) (*JiraOrganization, error) {
+	var out JiraOrganization
+	if err := c.doJSON(ctx, http.MethodPost, "/rest/servicedeskapi/organization", map[string]any{"name": name}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *JiraClient) DeleteOrganization(ctx context.Context, id string) error {
+	return c.doJSON(ctx, http.MethodDelete, "/rest/servicedeskapi/organization/"+url.PathEscape(id), nil, nil)
+}
+
+func (c *JiraClient) OrganizationMembers(ctx context.Context, id string) ([]JiraUser, error) {
+	return sdPage[JiraUser](ctx, c, "/rest/servicedeskapi/organization/"+url.PathEscape(id)+"/user", nil, 0)
+}
+
+// SetOrganizationMembers adds (or, with remove, removes) members by
+// accountId.
+func (c *JiraClient) SetOrganizationMembers(ctx context.Context, id string, accountIDs []string, remove bool) error {
+	method := http.MethodPost
+	if remove {
+		method = http.MethodDelete
+	}
+	path := "/rest/servicedeskapi/organization/" + url.PathEscape(id) + "/user"
+	return c.doJSON(ctx, method, path, map[string]any{"accountIds": accountIDs}, nil)
+}
+
+// SetServiceDeskOrganization adds (or, with remove, removes) an
+// organization to a service desk.
+func (c *JiraClient) SetServiceDeskOrganization(ctx context.Context, serviceDeskID, orgID string, remove bool) error {
+	method := http.MethodPost
+	if remove {
+		method = http.MethodDelete
+	}
+	return c.doJSON(ctx, method, serviceDeskPath(serviceDeskID, "organization"), map[string]any{"organizationId": orgID}, nil)
+}
+
+// customerAccountIDs resolves customers given by accountId, email, or
+// display name.
+func (c *JiraClient) customerAccountIDs(ctx context.Context, who []string) ([]string, error) {
+	var out []string
+	for _, w := range who {
+		u, err := c.ResolveUser(ctx, w)
+		if err != nil {
+			return nil, fmt.Errorf("customer %q: %w", w, err)
+		}
+		out = append(out, u.AccountID)
+	}
+	return out, nil
+}
+
+type customerView struct {
+	AccountID string `json:"account_id"`
+	Name      string `json:"name"`
+	Email     string `json:"email,omitempty"`
+}
+
+func viewCustomers(users []JiraUser) []customerView {
+	out := make([]customerView, len(users))
+	for i, u := range users {
+		out[i] = customerView{AccountID: u.AccountID, Name: u.DisplayName, Email: u.EmailAddress}
+	}
+	return out
+}
+
+func registerCustomerTools(server *mcp.Server, jc *JiraClient) {
+	// create_customer(email, display_name, service_desk?)
+	type createCustomerArgs struct {
+		Email       string `json:"email" jsonschema:"The customer's email address"`
+		DisplayName string `json:"display_name" jsonschema:"The customer's name"`
+		ServiceDesk string `json:"service_desk,omitempty" jsonschema:"Also add the customer to this service desk (id or project key)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "create_customer",
+		Title:       "Create Customer",
+		Description: "Create a Jira Service Management customer account (portal-only), optionally adding it to a service desk",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args createCustomerArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=create_customer args={email:%q,service_desk:%q}", args.Email, args.ServiceDesk)
+		if args.Email == "" || args.DisplayName == "" {
+			return nil, nil, errors.New("email and display_name are required")
+		}
+		var sd *JiraServiceDesk
+		var err error
+		if args.ServiceDesk != "" {
+			if sd, err = jc.ServiceDesk(ctx, args.ServiceDesk); err != nil {
+				return nil, nil, err
+			}
+		}
+		u, err := jc.CreateCustomer(ctx, args.Email, args.DisplayName)
+		if err != nil {
+			debugf("tool=create_customer error=%v", err)
+			return nil, nil, err
+		}
+		out := map[string]any{"customer": viewCustomers([]JiraUser{*u})[0]}
+		if sd != nil {
+			if err := jc.SetServiceDeskCustomers(ctx, sd.ID, []string{u.AccountID}, false); err != nil {
+				debugf("tool=create_customer error=%v", err)
+				return nil, nil, fmt.Errorf("customer %s created but not added to %s: %w", u.AccountID, sd.ProjectKey, err)
+			}
+			out["service_desk"] = sd.ProjectKey
+		}
+		return &mcp.CallToolResult{StructuredContent: out}, nil, nil
+	})
+
+	// list_customers(service_desk?, query?, max_results?)
+	type listCustomersArgs struct {
+		ServiceDesk string `json:"service_desk,omitempty" jsonschema:"Service desk id or project key (default the session focus project)"`
+		Query       string `json:"query,omitempty" jsonschema:"Only customers whose name or email matches"`
+		MaxResults  int    `json:"max_results,omitempty" jsonschema:"Maximum customers (default 50, max 500)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_customers",
+		Title:       "List Customers",
+		Description: "List the customers of a service desk",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listCustomersArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_customers args={service_desk:%q,query:%q}", args.ServiceDesk, args.Query)
+		sd, err := jc.serviceDeskFor(ctx, req.Session, args.ServiceDesk)
+		if err != nil {
+			return nil, nil, err
+		}
+		limit := args.MaxResults
+		if limit <= 0 || limit > 500 {
+			limit = 50
+		}
+		users, err := jc.ServiceDeskCustomers(ctx, sd.ID, args.Query, limit)
+		if err != nil {
+			debugf("tool=list_customers error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"service_desk": sd.ProjectKey, "customers": viewCustomers(users)}}, nil, nil
+	})
+
+	// update_service_desk_customers(service_desk?, add?, remove?)
+	type updateCustomersArgs struct {
+		ServiceDesk string   `json:"service_desk,omitempty" jsonschema:"Service desk id or project key (default the session focus project)"`
+		Add         []string `json:"add,omitempty" jsonschema:"Customers to add (accountId, email, or display name)"`
+		Remove      []string `json:"remove,omitempty" jsonschema:"Customers to remove"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "update_service_desk_customers",
+		Title:       "Update Service Desk Customers",
+		Description: "Add customers to or remove them from a service desk",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args updateCustomersArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=update_service_desk_customers args={service_desk:%q,add:%v,remove:%v}", args.ServiceDesk, args.Add, args.Remove)
+		if len(args.Add) == 0 && len(args.Remove) == 0 {
+			return nil, nil, errors.New("nothing to add or remove")
+		}
+		sd, err := jc.serviceDeskFor(ctx, req.Session, args.ServiceDesk)
+		if err != nil {
+			return nil, nil, err
+		}
+		add, err := jc.customerAccountIDs(ctx, args.Add)
+		if err != nil {
+			return nil, nil, err
+		}
+		remove, err := jc.customerAccountIDs(ctx, args.Remove)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(add) > 0 {
+			if err := jc.SetServiceDeskCustomers(ctx, sd.ID, add, false); err != nil {
+				debugf("tool=update_service_desk_customers error=%v", err)
+				return nil, nil, err
+			}
+		}
+		if len(remove) > 0 {
+			if err := jc.SetServiceDeskCustomers(ctx, sd.ID, remove, true); err != nil {
+				debugf("tool=update_service_desk_customers error=%v", err)
+				return nil, nil, err
+			}
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{
+			"service_desk": sd.ProjectKey, "added": add, "removed": remove,
+		}}, nil, nil
+	})
+
+	// list_organizations(service_desk?, include_members?)
+	type listOrgsArgs struct {
+		ServiceDesk    string `json:"service_desk,omitempty" jsonschema:"Only organizations of this service desk (id or project key); all when omitted"`
+		IncludeMembers bool   `json:"include_members,omitempty" jsonschema:"Include each organization's members"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_organizations",
+		Title:       "List Organizations",
+		Description: "List JSM customer organizations, all or those of one service desk, optionally with their members",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listOrgsArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_organizations args={service_desk:%q,members:%t}", args.ServiceDesk, args.IncludeMembers)
+		var sdID string
+		if args.ServiceDesk != "" {
+			sd, err := jc.ServiceDesk(ctx, args.ServiceDesk)
+			if err != nil {
+				return nil, nil, err
+			}
+			sdID = sd.ID
+		}
+		orgs, err := jc.Organizations(ctx, sdID)
+		if err != nil {
+			debugf("tool=list_organizations error=%v", err)
+			return nil, nil, err
+		}
+		out := make([]map[string]any, len(orgs))
+		for i, o := range orgs {
+			out[i] = map[string]any{"id": o.ID, "name": o.Name}
+			if args.IncludeMembers {
+				members, err := jc.OrganizationMembers(ctx, o.ID)
+				if err != nil {
+					debugf("tool=list_organizations error=%v", err)
+					return nil, nil, err
+				}
+				out[i]["members"] = viewCustomers(members)
+			}
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"organizations": out}}, nil, nil
+	})
+
+	// create_organization(name, service_desk?, members?)
+	type createOrgArgs struct {
+		Name        string   `json:"name" jsonschema:"Organization name"`
+		ServiceDesk string   `json:"service_desk,omitempty" jsonschema:"Also add the organization to this service desk (id or project key)"`
+		Members     []string `json:"members,omitempty" jsonschema:"Customers to add as members (accountId, email, or display name)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "create_organization",
+		Title:       "Create Organization",
+		Description: "Create a JSM customer organization, optionally adding members and linking it to a service desk",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args createOrgArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=create_organization args={name:%q,service_desk:%q,members:%d}", args.Name, args.ServiceDesk, len(args.Members))
+		if args.Name == "" {
+			return nil, nil, errors.New("name is required")
+		}
+		members, err := jc.customerAccountIDs(ctx, args.Members)
+		if err != nil {
+			return nil, nil, err
+		}
+		var sd *JiraServiceDesk
+		if args.ServiceDesk != "" {
+			if sd, err = jc.ServiceDesk(ctx, args.ServiceDesk); err != nil {
+				return nil, nil, err
+			}
+		}
+		org, err := jc.CreateOrganization(ctx, args.Name)
+		if err != nil {
+			debugf("tool=create_organization error=%v", err)
+			return nil, nil, err
+		}
+		out := map[string]any{"organization": org}
+		if len(members) > 0 {
+			if err := jc.SetOrganizationMembers(ctx, org.ID, members, false); err != nil {
+				debugf("tool=create_organization error=%v", err)
+				return nil, nil, fmt.Errorf("organization %s created but members not added: %w", org.ID, err)
+			}
+			out["members"] = members
+		}
+		if sd != nil {
+			if err := jc.SetServiceDeskOrganization(ctx, sd.ID, org.ID, false); err != nil {
+				debugf("tool=create_organization error=%v", err)
+				return nil, nil, fmt.Errorf("organization %s created but not added to %s: %w", org.ID, sd.ProjectKey, err)
+			}
+			out["service_desk"] = sd.ProjectKey
+		}
+		return &mcp.CallToolResult{StructuredContent: out}, nil, nil
+	})
+
+	// update_organization(organization, add_members?, remove_members?, add_to_service_desks?, remove_from_service_desks?)
+	type updateOrgArgs struct {
+		Organization string   `json:"organization" jsonschema:"Organization id or name"`
+		AddMembers   []string `json:"add_members,omitempty" jsonschema:"Customers to add (accountId, email, or display name)"`
+		Remove       []string `json:"remove_members,omitempty" jsonschema:"Customers to remove"`
+		AddDesks     []string `json:"add_to_service_desks,omitempty" jsonschema:"Service desks (id or project key) to add the organization to"`
+		RemoveDesks  []string `json:"remove_from_service_desks,omitempty" jsonschema:"Service desks to remove the organization from"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "update_organization",
+		Title:       "Update Organization",
+		Description: "Change a JSM organization's members and the service desks it belongs to",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args updateOrgArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=update_organization args={org:%q,add:%v,remove:%v,desks+:%v,desks-:%v}", args.Organization, args.AddMembers, args.Remove, args.AddDesks, args.RemoveDesks)
+		org, err := jc.Organization(ctx, args.Organization)
+		if err != nil {
+			return nil, nil, err
+		}
+		add, err := jc.customerAccountIDs(ctx, args.AddMembers)
+		if err != nil {
+			return nil, nil, err
+		}
+		remove, err := jc.customerAccountIDs(ctx, args.Remove)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(add) > 0 {
+			if err := jc.SetOrganizationMembers(ctx, org.ID, add, false); err != nil {
+				debugf("tool=update_organization error=%v", err)
+				return nil, nil, err
+			}
+		}
+		if len(remove) > 0 {
+			if err := jc.SetOrganizationMembers(ctx, org.ID, remove, true); err != nil {
+				debugf("tool=update_organization error=%v", err)
+				return nil, nil, err
+			}
+		}
+		for _, desks := range []struct {
+			refs   []string
+			remove bool
+		}{{args.AddDesks, false}, {args.RemoveDesks, true}} {
+			for _, ref := range desks.refs {
+				sd, err := jc.ServiceDesk(ctx, ref)
+				if err != nil {
+					return nil, nil, err
+				}
+				if err := jc.SetServiceDeskOrganization(ctx, sd.ID, org.ID, desks.remove); err != nil {
+					debugf("tool=update_organization error=%v", err)
+					return nil, nil, err
+				}
+			}
+		}
+		members, err := jc.OrganizationMembers(ctx, org.ID)
+		if err != nil {
+			debugf("tool=update_organization error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"organization": org, "members": viewCustomers(members)}}, nil, nil
+	})
+
+	// delete_organization(organization, confirm?)
+	type deleteOrgArgs struct {
+		Organization string `json:"organization" jsonschema:"Organization id or name"`
+		Confirm      bool   `json:"confirm,omitempty" jsonschema:"Only for clients without elicitation: set once the user has approved the deletion"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "delete_organization",
+		Title:       "Delete Organization",
+		Description: "Delete a JSM organization. Its customers keep their accounts but lose access to the organization's shared requests. Asks the user to confirm; clients without elicitation pass confirm=true once the user has approved",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: ptr(true)},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args deleteOrgArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=delete_organization args={org:%q,confirm:%t}", args.Organization, args.Confirm)
+		org, err := jc.Organization(ctx, args.Organization)
+		if err != nil {
+			return nil, nil, err
+		}
+		ok, err := approveAction(ctx, req.Session, args.Confirm, fmt.Sprintf("Delete organization %q (%s)?", org.Name, org.ID))
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			return nil, nil, fmt.Errorf("deletion of organization %s declined by the user", org.Name)
+		}
+		if err := jc.DeleteOrganization(ctx, org.ID); err != nil {
+			debugf("tool=delete_organization error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"organization": org, "deleted": true}}, nil, nil
+	})
+}
@@ -0,0 +1,400 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Declarative custom tools ----
+//
+// JIRA_CUSTOM_TOOLS (or JIRA_CUSTOM_TOOLS_FILE) defines site-specific tools
+// in YAML or JSON, registered at startup alongside the built-in ones:
+//
+//	tools:
+//	  - name: stale_bugs
+//	    description: Open bugs in a project untouched for a month
+//	    jql: project = {project} AND type = Bug AND resolution IS EMPTY AND updated < -30d
+//	    fields: [slim]
+//	    args:
+//	      - {name: project, description: Project key, required: true}
+//	  - name: sr_escalate
+//	    description: Run the ScriptRunner escalation endpoint for an issue
+//	    method: POST
+//	    path: /rest/scriptrunner/latest/custom/escalate?issue={key}
+//	    body: {level: "{level}", note: "Escalated: {reason}"}
+//	    args:
+//	      - {name: key, required: true}
+//	      - {name: level, type: integer, default: 1}
+//	      - {name: reason}
+//
+// A tool either runs a JQL search or calls a Jira REST path. {arg}
+// placeholders are substituted escaped for where they appear: quoted in JQL,
+// path- or query-escaped in paths, and as the typed value in a body string
+// that is only the placeholder. Query parameters whose placeholder has no
+// value are dropped. Calls go through the same write policy and request
+// budget as built-in tools. A custom tool with a built-in's name replaces it.
+
+type customToolArg struct {
+	Name        string   `yaml:"name"`
+	Type        string   `yaml:"type"` // string (default), integer, number, boolean
+	Description string   `yaml:"description"`
+	Required    bool     `yaml:"required"`
+	Default     any      `yaml:"default"`
+	Enum        []string `yaml:"enum"`
+}
+
+type customToolDef struct {
+	Name        string          `yaml:"name"`
+	Title       string          `yaml:"title"`
+	Description string          `yaml:"description"`
+	JQL         string          `yaml:"jql"`
+	Fields      []string        `yaml:"fields"` // JQL tools; default slim
+	Limit       int             `yaml:"limit"`  // JQL tools; default 100
+	Method      string          `yaml:"method"` // path tools; default GET
+	Path        string          `yaml:"path"`
+	Body        any             `yaml:"body"`
+	Args        []customToolArg `yaml:"args"`
+}
+
+const defaultCustomToolLimit = 100
+
+var (
+	customToolNameRe    = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+	customPlaceholderRe = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+)
+
+// placeholders lists the argument names referenced in s.
+func placeholders(s string) []string {
+	var out []string
+	for _, m := range customPlaceholderRe.FindAllStringSubmatch(s, -1) {
+		out = append(out, m[1])
+	}
+	return out
+}
+
+// bodyPlaceholders lists the argument names referenced in a body template.
+func bodyPlaceholders(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return placeholders(v)
+	case map[string]any:
+		var out []string
+		for _, val := range v {
+			out = append(out, bodyPlaceholders(val)...)
+		}
+		return out
+	case []any:
+		var out []string
+		for _, val := range v {
+			out = append(out, bodyPlaceholders(val)...)
+		}
+		return out
+	}
+	return nil
+}
+
+// validate checks a definition and fills in its defaults.
+func (d *customToolDef) validate() error {
+	if !customToolNameRe.MatchString(d.Name) {
+		return fmt.Errorf("name %q must be 1-64 letters, digits, _ or -", d.Name)
+	}
+	if strings.TrimSpace(d.Description) == "" {
+		return errors.New("description is required")
+	}
+	if (d.JQL == "") == (d.Path == "") {
+		return errors.New("exactly one of jql and path is required")
+	}
+	args := map[string]bool{}
+	for _, a := range d.Args {
+		if !customPlaceholderRe.MatchString("{" + a.Name + "}") {
+			return fmt.Errorf("argument name %q is invalid", a.Name)
+		}
+		if args[a.Name] {
+			return fmt.Errorf("argument %q is defined twice", a.Name)
+		}
+		args[a.Name] = true
+		switch a.Type {
+		case "", "string", "integer", "number", "boolean":
+		default:
+			return fmt.Errorf("argument %q: unknown type %q (valid: string, integer, number, boolean)", a.Name, a.Type)
+		}
+	}
+	refs := placeholders(d.JQL + d.Path)
+	if d.JQL != "" {
+		if d.Method != "" || d.Body != nil {
+			return errors.New("method and body apply only to path tools")
+		}
+		if d.Limit <= 0 {
+			d.Limit = defaultCustomToolLimit
+		}
+		if len(d.Fields) == 0 {
+			d.Fields = []string{"slim"}
+		}
+	} else {
+		d.Method = strings.ToUpper(d.Method)
+		switch d.Method {
+		case "":
+			d.Method = http.MethodGet
+		case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete:
+		default:
+			return fmt.Errorf("unknown method %q (valid: GET, POST, PUT, DELETE)", d.Method)
+		}
+		if !strings.HasPrefix(d.Path, "/") {
+			return errors.New("path must start with / (it is relative to the Jira site)")
+		}
+		if d.Body != nil && d.Method == http.MethodGet {
+			return errors.New("GET tools cannot have a body")
+		}
+		refs = append(refs, bodyPlaceholders(d.Body)...)
+	}
+	for _, r := range refs {
+		if !args[r] {
+			return fmt.Errorf("placeholder {%s} has no matching argument", r)
+		}
+	}
+	return nil
+}
+
+// inputSchema builds the tool's input schema from its arguments.
+func (d *customToolDef) inputSchema() (*jsonschema.Schema, error) {
+	s := &jsonschema.Schema{Type: "object", Properties: map[string]*jsonschema.Schema{}}
+	for _, a := range d.Args {
+		typ := a.Type
+		if typ == "" {
+			typ = "string"
+		}
+		p := &jsonschema.Schema{Type: typ, Description: a.Description}
+		for _, e := range a.Enum {
+			p.Enum = append(p.Enum, e)
+		}
+		if a.Default != nil {
+			b, err := json.Marshal(a.Default)
+			if err != nil {
+				return nil, fmt.Errorf("argument %q: %w", a.Name, err)
+			}
+			p.Default = b
+		}
+		s.Properties[a.Name] = p
+		if a.Required {
+			s.Required = append(s.Required, a.Name)
+		}
+	}
+	// Resolving catches defaults and enums that do not fit their type.
+	if _, err := s.Resolve(&jsonschema.ResolveOptions{ValidateDefaults: true}); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// argText formats an argument value for substitution into text.
+func argText(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+// expandJQL substitutes arguments into a JQL template, quoting strings.
+// Every placeholder must have a value, since a JQL clause cannot be dropped.
+func expandJQL(tmpl string, args map[string]any) (string, error) {
+	var missing []string
+	out := customPlaceholderRe.ReplaceAllStringFunc(tmpl, func(m string) string {
+		name := m[1 : len(m)-1]
+		v, ok := args[name]
+		if !ok || v == nil {
+			missing = append(missing, name)
+			return m
+		}
+		if s, ok := v.(string); ok {
+			return quoteJQL(s)
+		}
+		return argText(v)
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("the query needs %s", strings.Join(missing, ", "))
+	}
+	return out, nil
+}
+
+// expandPath substitutes arguments into a path template, escaping them for
+// the path or the query string. A query parameter whose whole value is a
+// placeholder without a value is dropped; any other missing placeholder is
+// an error.
+func expandPath(tmpl string, args map[string]any) (string, error) {
+	var missing []string
+	sub := func(s string, escape func(string) string) string {
+		return customPlaceholderRe.ReplaceAllStringFunc(s, func(m string) string {
+			v, ok := args[m[1:len(m)-1]]
+			if !ok || v == nil {
+				missing = append(missing, m[1:len(m)-1])
+				return ""
+			}
+			return escape(argText(v))
+		})
+	}
+	path, query, hasQuery := strings.Cut(tmpl, "?")
+	out := sub(path, url.PathEscape)
+	if hasQuery {
+		var params []string
+		for _, p := range strings.Split(query, "&") {
+			k, v, _ := strings.Cut(p, "=")
+			if m := customPlaceholderRe.FindStringSubmatch(v); m != nil && m[0] == v {
+				if val, ok := args[m[1]]; !ok || val == nil {
+					continue
+				}
+			}
+			params = append(params, sub(k, url.QueryEscape)+"="+sub(v, url.QueryEscape))
+		}
+		if len(params) > 0 {
+			out += "?" + strings.Join(params, "&")
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("the path needs %s", strings.Join(missing, ", "))
+	}
+	return out, nil
+}
+
+// expandBody substitutes arguments into a body template. A string that is
+// only a placeholder becomes the argument's value, keeping its type; an
+// unset one becomes null.
+func expandBody(v any, args map[string]any) any {
+	switch v := v.(type) {
+	case string:
+		if m := customPlaceholderRe.FindStringSubmatch(v); m != nil && m[0] == v {
+			return args[m[1]]
+		}
+		return customPlaceholderRe.ReplaceAllStringFunc(v, func(m string) string {
+			if val, ok := args[m[1:len(m)-1]]; ok && val != nil {
+				return argText(val)
+			}
+			return ""
+		})
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, val := range v {
+			out[k] = expandBody(val, args)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, val := range v {
+			out[i] = expandBody(val, args)
+		}
+		return out
+	}
+	return v
+}
+
+// run executes the tool with validated arguments.
+func (d *customToolDef) run(ctx context.Context, jc *JiraClient, args map[string]any) (any, error) {
+	if d.JQL != "" {
+		jql, err := expandJQL(d.JQL, args)
+		if err != nil {
+			return nil, err
+		}
+		fields, err := jc.resolveFieldSelection(ctx, d.Fields)
+		if err != nil {
+			return nil, err
+		}
+		issues, err := jc.SearchAll(ctx, jql, fields, d.Limit)
+		if err != nil {
+			return nil, err
+		}
+		for i := range issues {
+			renderIssueText(&issues[i])
+		}
+		return map[string]any{"jql": jql, "total": len(issues), "issues": issues}, nil
+	}
+	path, err := expandPath(d.Path, args)
+	if err != nil {
+		return nil, err
+	}
+	var body any
+	if d.Body != nil {
+		body = expandBody(d.Body, args)
+	}
+	var out any
+	if err := jc.doJSON(ctx, d.Method, path, body, &out); err != nil {
+		return nil, err
+	}
+	return map[string]any{"method": d.Method, "path": path, "response": out}, nil
+}
+
+// loadCustomTools reads and validates JIRA_CUSTOM_TOOLS. Invalid
+// definitions are logged and skipped rather than failing startup.
+func loadCustomTools() []customToolDef {
+	var cfg struct {
+		Tools []customToolDef `yaml:"tools"`
+	}
+	if _, err := loadYAMLSetting("JIRA_CUSTOM_TOOLS", &cfg); err != nil {
+		logger.Printf("custom tools disabled: %v", err)
+		return nil
+	}
+	seen := map[string]bool{}
+	var out []customToolDef
+	for i, d := range cfg.Tools {
+		if err := d.validate(); err != nil {
+			logger.Printf("JIRA_CUSTOM_TOOLS: skipping tool %d (%s): %v", i+1, d.Name, err)
+			continue
+		}
+		if seen[d.Name] {
+			logger.Printf("JIRA_CUSTOM_TOOLS: skipping tool %d: %s is defined twice", i+1, d.Name)
+			continue
+		}
+		seen[d.Name] = true
+		out = append(out, d)
+	}
+	return out
+}
+
+func registerCustomTools(server *mcp.Server, jc *JiraClient) {
+	defs := loadCustomTools()
+	names := make([]string, 0, len(defs))
+	for i := range defs {
+		d := &defs[i]
+		schema, err := d.inputSchema()
+		if err != nil {
+			logger.Printf("JIRA_CUSTOM_TOOLS: skipping %s: %v", d.Name, err)
+			continue
+		}
+		ann := &mcp.ToolAnnotations{ReadOnlyHint: d.JQL != "" || d.Method == http.MethodGet}
+		if d.Method == http.MethodDelete {
+			ann.DestructiveHint = ptr(true)
+		}
+		mcp.AddTool(server, &mcp.Tool{
+			Name:        d.Name,
+			Title:       d.Title,
+			Description: d.Description,
+			InputSchema: schema,
+			Annotations: ann,
+		}, func(ctx context.Context, req *mcp.CallToolRequest, args map[string]any) (*mcp.CallToolResult, any, error) {
+			debugf("tool=%s custom=true args=%v", d.Name, args)
+			res, err := d.run(ctx, jc, args)
+			if err != nil {
+				debugf("tool=%s error=%v", d.Name, err)
+				return nil, nil, err
+			}
+			return &mcp.CallToolResult{StructuredContent: res}, nil, nil
+		})
+		names = append(names, d.Name)
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		logger.Printf("registered %d custom tools: %s", len(names), strings.Join(names, ", "))
+	}
+}
@@ -0,0 +1,32 @@
+package jira
+
+import "testing"
+
+func TestExpandPath(t *testing.T) {
+	tests := []struct {
+		tmpl    string
+		args    map[string]any
+		want    string
+		wantErr bool
+	}{
+		{"/rest/api/3/issue/{key}", map[string]any{"key": "PROJ-1"}, "/rest/api/3/issue/PROJ-1", false},
+		{"/rest/api/3/project/{key}/role/{id}", map[string]any{"key": "A B", "id": float64(10002)}, "/rest/api/3/project/A%20B/role/10002", false},
+		{"/rest/api/3/user/search?query={q}", map[string]any{"q": "a&b c"}, "/rest/api/3/user/search?query=a%26b+c", false},
+		{"/rest/api/3/user/search?query={q}&maxResults={max}", map[string]any{"q": "x"}, "/rest/api/3/user/search?query=x", false},
+		{"/rest/api/3/user/search?query={q}", map[string]any{}, "/rest/api/3/user/search", false},
+		{"/rest/api/3/user/search?query=pre{q}", map[string]any{}, "", true},
+		{"/rest/api/3/issue/{key}", map[string]any{}, "", true},
+		{"/rest/api/3/issue/{key}", map[string]any{"key": nil}, "", true},
+		{"/rest/api/3/issue/{key}", map[string]any{"key": "../../admin"}, "/rest/api/3/issue/..%2F..%2Fadmin", false},
+	}
+	for _, tt := range tests {
+		got, err := expandPath(tt.tmpl, tt.args)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("expandPath(%q, %v) error = %v, want error %t", tt.tmpl, tt.args, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("expandPath(%q, %v) = %q, want %q", tt.tmpl, tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ---- Project defaults for created issues ----
+//
+// JIRA_PROJECT_DEFAULTS sets fields applied to every issue the server
+// creates (create_issue, clones, scaffolds, seeds, bulk creates), keyed by
+// project, with "*" for all projects:
+//
+//	{"*": {"labels": ["ai-created"]},
+//	 "OPS": {"components": ["Triage"], "reporter": "jira-bot@example.com"}}
+//
+// Labels are added to the caller's. Components and the reporter apply only
+// when the caller set none. A project's entry overrides "*" except for
+// labels, which combine. Where the reporter field cannot be set (it is not
+// on the create screen or the account lacks Modify Reporter), the issue is
+// created without it.
+
+type ProjectDefaults struct {
+	Reporter   string   `json:"reporter,omitempty"` // accountId, email, or display name
+	Components []string `json:"components,omitempty"`
+	Labels     []string `json:"labels,omitempty"`
+}
+
+func loadProjectDefaults() (map[string]ProjectDefaults, error) {
+	var raw map[string]ProjectDefaults
+	if _, err := loadJSONSetting("JIRA_PROJECT_DEFAULTS", &raw); err != nil {
+		return nil, err
+	}
+	out := make(map[string]ProjectDefaults, len(raw))
+	for k, d := range raw {
+		out[strings.ToUpper(k)] = d
+	}
+	return out, nil
+}
+
+// defaultsFor merges the "*" and project entries.
+func (c *JiraClient) defaultsFor(project string) ProjectDefaults {
+	d := c.ProjectDefaults["*"]
+	p, ok := c.ProjectDefaults[strings.ToUpper(project)]
+	if !ok {
+		return d
+	}
+	labels := append(slices.Clone(d.Labels), p.Labels...)
+	if p.Reporter != "" {
+		d.Reporter = p.Reporter
+	}
+	if p.Components != nil {
+		d.Components = p.Components
+	}
+	d.Labels = labels
+	return d
+}
+
+// withProjectDefaults returns a copy of create fields with the project
+// defaults applied, and whether it added the reporter.
+func (c *JiraClient) withProjectDefaults(ctx context.Context, fields map[string]any) (map[string]any, bool, error) {
+	if len(c.ProjectDefaults) == 0 {
+		return fields, false, nil
+	}
+	project, _ := fields["project"].(map[string]any)
+	key, _ := project["key"].(string)
+	d := c.defaultsFor(key)
+	out := maps.Clone(fields)
+	if len(d.Labels) > 0 {
+		labels := slices.Clone(valueStrings(out["labels"]))
+		for _, l := range d.Labels {
+			if !slices.Contains(labels, l) {
+				labels = append(labels, l)
+			}
+		}
+		out["labels"] = labels
+	}
+	if len(d.Components) > 0 && key != "" && out["components"] == nil {
+		refs, err := c.componentRefs(ctx, key, d.Components)
+		if err != nil {
+			return nil, false, fmt.Errorf("JIRA_PROJECT_DEFAULTS: %w", err)
+		}
+		out["components"] = refs
+	}
+	addedReporter := false
+	if d.Reporter != "" && out["reporter"] == nil {
+		u, err := c.ResolveUser(ctx, d.Reporter)
+		if err != nil {
+			return nil, false, fmt.Errorf("JIRA_PROJECT_DEFAULTS reporter: %w", err)
+		}
+		out["reporter"] = u.ref()
+		addedReporter = true
+	}
+	debugf("project defaults for %s: labels=%v components=%v reporter=%t", key, d.Labels, d.Components, addedReporter)
+	return out, addedReporter, nil
+}
+
+// reporterRejected reports whether a create error is Jira refusing the
+// reporter field.
+func reporterRejected(msg string) bool {
+	return strings.Contains(strings.ToLower(msg), "reporter")
+}
@@ -0,0 +1,180 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"plugin"
+	"strings"
+	"time"
+)
+
+// ---- Egress controls and request signing ----
+//
+// JIRA_EGRESS routes outbound requests through a proxy, restricts the hosts
+// they may reach, and signs each request for gateways that require it:
+//
+//	{"proxy_url": "http://egress.internal:3128",
+//	 "allowed_hosts": ["example.atlassian.net", "api.atlassian.com"],
+//	 "signer": {"command": ["/usr/local/bin/jira-sign"], "timeout_ms": 2000}}
+//
+// A signer is an external command or a Go plugin exporting
+// SignRequest(*http.Request) error. The command receives the request as
+// JSON on stdin ({"method", "url", "headers", "body_sha256", "body"}, body
+// base64) and prints {"headers": {...}} to set. Signing runs in the
+// transport, after auth headers are set.
+
+type signerConfig struct {
+	Command   []string `json:"command,omitempty"`
+	TimeoutMS int      `json:"timeout_ms,omitempty"`
+	Plugin    string   `json:"plugin,omitempty"`
+	Symbol    string   `json:"symbol,omitempty"` // default SignRequest
+}
+
+type egressConfig struct {
+	ProxyURL     string        `json:"proxy_url,omitempty"`
+	AllowedHosts []string      `json:"allowed_hosts,omitempty"`
+	Signer       *signerConfig `json:"signer,omitempty"`
+}
+
+type signFunc func(*http.Request) error
+
+// commandSigner runs an external command per request.
+func commandSigner(argv []string, timeout time.Duration) signFunc {
+	return func(req *http.Request) error {
+		var body []byte
+		if req.GetBody != nil {
+			rc, err := req.GetBody()
+			if err != nil {
+				return err
+			}
+			body, err = io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+		}
+		sum := sha256.Sum256(body)
+		headers := map[string]string{}
+		for k := range req.Header {
+			headers[k] = req.Header.Get(k)
+		}
+		in, err := json.Marshal(map[string]any{
+			"method":      req.Method,
+			"url":         req.URL.String(),
+			"headers":     headers,
+			"body_sha256": hex.EncodeToString(sum[:]),
+			"body":        base64.StdEncoding.EncodeToString(body),
+		})
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(req.Context(), timeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+		cmd.Stdin = bytes.NewReader(in)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return fmt.Errorf("request signer: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		var res struct {
+			Headers map[string]string `json:"headers"`
+		}
+		if err := json.Unmarshal(out, &res); err != nil {
+			return fmt.Errorf("request signer: bad output: %w", err)
+		}
+		for k, v := range res.Headers {
+			req.Header.Set(k, v)
+		}
+		return nil
+	}
+}
+
+func pluginSigner(path, symbol string) (signFunc, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if symbol == "" {
+		symbol = "SignRequest"
+	}
+	sym, err := p.Lookup(symbol)
+	if err != nil {
+		return nil, err
+	}
+	fn, ok := sym.(func(*http.Request) error)
+	if !ok {
+		return nil, fmt.Errorf("plugin %s: %s is %T, want func(*http.Request) error", path, symbol, sym)
+	}
+	return fn, nil
+}
+
+type egressTransport struct {
+	base    http.RoundTripper
+	allowed []string
+	sign    signFunc
+}
+
+func (t egressTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(t.allowed) > 0 && !containsFold(t.allowed, req.URL.Hostname()) {
+		return nil, fmt.Errorf("egress to %s is not allowed (JIRA_EGRESS allowed_hosts)", req.URL.Hostname())
+	}
+	if t.sign != nil {
+		// RoundTrippers must not modify the caller's request.
+		req = req.Clone(req.Context())
+		if err := t.sign(req); err != nil {
+			return nil, err
+		}
+	}
+	return t.base.RoundTrip(req)
+}
+
+// loadEgressTransport builds the transport for JIRA_EGRESS, or returns nil
+// when it is not configured.
+func loadEgressTransport() (http.RoundTripper, error) {
+	var cfg egressConfig
+	ok, err := loadJSONSetting("JIRA_EGRESS", &cfg)
+	if err != nil || !ok {
+		return nil, err
+	}
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.ProxyURL != "" {
+		u, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("JIRA_EGRESS: bad proxy_url: %w", err)
+		}
+		base.Proxy = http.ProxyURL(u)
+	}
+	t := egressTransport{base: base, allowed: cfg.AllowedHosts}
+	if s := cfg.Signer; s != nil {
+		switch {
+		case len(s.Command) > 0 && s.Plugin != "":
+			return nil, errors.New("JIRA_EGRESS: signer takes command or plugin, not both")
+		case len(s.Command) > 0:
+			timeout := 5 * time.Second
+			if s.TimeoutMS > 0 {
+				timeout = time.Duration(s.TimeoutMS) * time.Millisecond
+			}
+			t.sign = commandSigner(s.Command, timeout)
+		case s.Plugin != "":
+			if t.sign, err = pluginSigner(s.Plugin, s.Symbol); err != nil {
+				return nil, fmt.Errorf("JIRA_EGRESS: signer plugin: %w", err)
+			}
+		default:
+			return nil, errors.New("JIRA_EGRESS: signer needs command or plugin")
+		}
+	}
+	debugf("egress: proxy=%q allowed_hosts=%v signer=%t", cfg.ProxyURL, cfg.AllowedHosts, t.sign != nil)
+	return t, nil
+}
@@ -0,0 +1,104 @@
+package jira
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ---- .env files ----
+//
+// MCP hosts often start the server without a shell, so the environment is
+// hard to set. LoadEnvFile reads KEY=VALUE lines from .env in the working
+// directory (or --env-file) into the environment. Its values take
+// precedence; anything it leaves out comes from the process environment,
+// and then from the config file. Values are never logged.
+//
+//	# comments and blank lines are skipped
+//	export JIRA_INSTANCE_URL=https://acme.atlassian.net
+//	JIRA_USER_EMAIL="me@acme.com"
+//	JIRA_API_TOKEN='...'   # single quotes are literal
+
+// LoadEnvFile applies the .env file at path. An empty path means .env in
+// the working directory, which need not exist.
+func LoadEnvFile(path string) error {
+	explicit := path != ""
+	if !explicit {
+		path = ".env"
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) && !explicit {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("env file: %w", err)
+	}
+	defer f.Close()
+	vars, err := parseEnvFile(f)
+	if err != nil {
+		return fmt.Errorf("env file %s: %w", path, err)
+	}
+	names := make([]string, 0, len(vars))
+	for name, v := range vars {
+		if err := os.Setenv(name, v); err != nil {
+			return fmt.Errorf("env file %s: %s: %w", path, name, err)
+		}
+		names = append(names, name)
+	}
+	debugf("env file: loaded %s (%s)", path, strings.Join(names, ", "))
+	return nil
+}
+
+func parseEnvFile(f *os.File) (map[string]string, error) {
+	vars := map[string]string{}
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			// Don't echo the line: it may hold a secret.
+			return nil, fmt.Errorf("line %d: want NAME=value", n)
+		}
+		v, err := envValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d (%s): %w", n, name, err)
+		}
+		vars[name] = v
+	}
+	return vars, sc.Err()
+}
+
+// envValue unquotes a value: double quotes take Go escapes such as \n,
+// single quotes are literal, and an unquoted value ends at " #".
+func envValue(v string) (string, error) {
+	switch {
+	case strings.HasPrefix(v, `"`):
+		end := strings.LastIndex(v, `"`)
+		if end == 0 {
+			return "", errors.New("unterminated double quote")
+		}
+		s, err := strconv.Unquote(v[:end+1])
+		if err != nil {
+			return "", errors.New("invalid double-quoted value")
+		}
+		return s, nil
+	case strings.HasPrefix(v, "'"):
+		end := strings.LastIndex(v, "'")
+		if end == 0 {
+			return "", errors.New("unterminated single quote")
+		}
+		return v[1:end], nil
+	}
+	if i := strings.Index(v, " #"); i >= 0 {
+		v = v[:i]
+	}
+	return strings.TrimSpace(v), nil
+}
@@ -0,0 +1,296 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Epics ----
+//
+// Company-managed projects attach issues to epics with the Epic Link field
+// (set through the Agile epic endpoints); team-managed projects use the
+// parent field. These helpers pick the right mechanism per project so the
+// tools behave the same on both.
+
+// epicLinkSchema is the custom field type of the Epic Link field.
+const epicLinkSchema = "com.pyxis.greenhopper.jira:gh-epic-link"
+
+type JiraEpic struct {
+	ID      int    `json:"id,omitempty"`
+	Key     string `json:"key"`
+	Name    string `json:"name,omitempty"`
+	Summary string `json:"summary"`
+	Done    bool   `json:"done"`
+}
+
+// epicLinkField returns the Epic Link field, or nil on sites without one.
+func (c *JiraClient) epicLinkField(ctx context.Context) (*JiraField, error) {
+	fields, err := c.Fields(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range fields {
+		if custom, _ := fields[i].Schema["custom"].(string); custom == epicLinkSchema {
+			return &fields[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// teamManaged reports whether project is a team-managed (next-gen) project.
+func (c *JiraClient) teamManaged(ctx context.Context, project string) (bool, error) {
+	p, err := c.GetProject(ctx, project)
+	if err != nil {
+		return false, err
+	}
+	return p.Style == "next-gen", nil
+}
+
+// BoardEpics returns the epics of a board; done epics only when includeDone
+// is set.
+func (c *JiraClient) BoardEpics(ctx context.Context, boardID int, includeDone bool, max int) ([]JiraEpic, error) {
+	var out []JiraEpic
+	for startAt := 0; len(out) < max; {
+		q := url.Values{}
+		q.Set("startAt", fmt.Sprintf("%d", startAt))
+		q.Set("maxResults", fmt.Sprintf("%d", min(max-len(out), 50)))
+		if !includeDone {
+			q.Set("done", "false")
+		}
+		var page struct {
+			IsLast bool       `json:"isLast"`
+			Values []JiraEpic `json:"values"`
+		}
+		if err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/rest/agile/1.0/board/%d/epic?%s", boardID, q.Encode()), nil, &page); err != nil {
+			return nil, err
+		}
+		out = append(out, page.Values...)
+		startAt += len(page.Values)
+		if page.IsLast || len(page.Values) == 0 {
+			break
+		}
+	}
+	return out, nil
+}
+
+// ProjectEpics finds a project's epics with JQL, which works for both
+// project types.
+func (c *JiraClient) ProjectEpics(ctx context.Context, project string, includeDone bool, max int) ([]JiraEpic, error) {
+	jql := fmt.Sprintf("project = %q AND issuetype = Epic", project)
+	if !includeDone {
+		jql += " AND statusCategory != Done"
+	}
+	issues, err := c.SearchAll(ctx, jql+" ORDER BY rank", []string{"summary", "status"}, max)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]JiraEpic, len(issues))
+	for i, iss := range issues {
+		out[i] = JiraEpic{
+			Key: iss.Key, Summary: fieldString(iss.Fields, "summary"),
+			Done: fieldString(iss.Fields, "status", "statusCategory", "key") == "done",
+		}
+	}
+	return out, nil
+}
+
+// epicJQL selects the children of an epic however they are attached.
+func (c *JiraClient) epicJQL(ctx context.Context, epic string) (string, error) {
+	jql := fmt.Sprintf("parent = %q", epic)
+	link, err := c.epicLinkField(ctx)
+	if err != nil {
+		return "", err
+	}
+	if link != nil {
+		jql = fmt.Sprintf("(%s OR cf[%s] = %q)", jql, strings.TrimPrefix(link.ID, "customfield_"), epic)
+	}
+	return jql, nil
+}
+
+// SetEpic attaches issues to epic, or detaches them from their epic when
+// epic is "". Team-managed projects set the parent; company-managed ones
+// go through the Agile epic endpoint, which maintains Epic Link.
+func (c *JiraClient) SetEpic(ctx context.Context, epic string, keys []string) error {
+	byProject := map[string][]string{}
+	var projects []string
+	for _, k := range keys {
+		p, _, ok := strings.Cut(k, "-")
+		if !ok {
+			return fmt.Errorf("invalid issue key %q", k)
+		}
+		if _, seen := byProject[p]; !seen {
+			projects = append(projects, p)
+		}
+		byProject[p] = append(byProject[p], k)
+	}
+	for _, p := range projects {
+		team, err := c.teamManaged(ctx, p)
+		if err != nil {
+			return err
+		}
+		if !team {
+			target := epic
+			if target == "" {
+				target = "none"
+			}
+			if err := c.moveIssues(ctx, "/rest/agile/1.0/epic/"+url.PathEscape(target)+"/issue", byProject[p]); err != nil {
+				return err
+			}
+			continue
+		}
+		var parent any
+		if epic != "" {
+			parent = map[string]any{"key": epic}
+		}
+		for _, k := range byProject[p] {
+			if err := c.UpdateIssue(ctx, k, map[string]any{"parent": parent}, nil); err != nil {
+				return fmt.Errorf("%s: %w", k, err)
+			}
+		}
+	}
+	return nil
+}
+
+func registerEpicTools(server *mcp.Server, jc *JiraClient) {
+	// list_epics(board_id?, project?, include_done?, max_results?)
+	type listEpicsArgs struct {
+		BoardID     int    `json:"board_id,omitempty" jsonschema:"Board whose epics to list"`
+		Project     string `json:"project,omitempty" jsonschema:"Project whose epics to list, instead of a board (default the focus project)"`
+		IncludeDone bool   `json:"include_done,omitempty" jsonschema:"Also list done epics"`
+		MaxResults  int    `json:"max_results,omitempty" jsonschema:"Maximum epics (default 50)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_epics",
+		Title:       "List Epics",
+		Description: "List the epics of a board or project; open epics by default",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listEpicsArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_epics args={board:%d,project:%q,done:%t}", args.BoardID, args.Project, args.IncludeDone)
+		max := args.MaxResults
+		if max <= 0 {
+			max = 50
+		}
+		project := args.Project
+		if f := jc.focusFor(req.Session); project == "" && args.BoardID == 0 && f != nil {
+			project = f.Project
+		}
+		var epics []JiraEpic
+		var err error
+		switch {
+		case args.BoardID != 0:
+			epics, err = jc.BoardEpics(ctx, args.BoardID, args.IncludeDone, max)
+		case project != "":
+			epics, err = jc.ProjectEpics(ctx, project, args.IncludeDone, max)
+		default:
+			return nil, nil, errors.New("board_id or project is required")
+		}
+		if err != nil {
+			debugf("tool=list_epics error=%v", err)
+			return nil, nil, err
+		}
+		if epics == nil {
+			epics = []JiraEpic{}
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"epics": epics}}, nil, nil
+	})
+
+	// get_epic_issues(epic_key, fields?, max_results?, raw_adf?)
+	type epicIssuesArgs struct {
+		EpicKey    string   `json:"epic_key" jsonschema:"Epic issue key"`
+		Fields     []string `json:"fields,omitempty" jsonschema:"Fields to return by name, id, or alias (default slim)"`
+		MaxResults int      `json:"max_results,omitempty" jsonschema:"Maximum issues (default 200)"`
+		RawADF     bool     `json:"raw_adf,omitempty" jsonschema:"Return rich-text fields as raw ADF instead of Markdown"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_epic_issues",
+		Title:       "Get Epic Issues",
+		Description: "List the issues in an epic (by Epic Link or parent), in rank order, with counts by status and status category",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args epicIssuesArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=get_epic_issues args={epic:%q,max:%d}", args.EpicKey, args.MaxResults)
+		sel := args.Fields
+		if len(sel) == 0 {
+			sel = []string{"slim", "issuetype"}
+		}
+		fields, err := jc.resolveFieldSelection(ctx, sel)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !containsFold(fields, "status") && !containsFold(fields, "*all") && !containsFold(fields, "*navigable") {
+			fields = append(fields, "status")
+		}
+		jql, err := jc.epicJQL(ctx, args.EpicKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		limit := args.MaxResults
+		if limit <= 0 {
+			limit = 200
+		}
+		issues, err := jc.SearchAll(ctx, jql+" ORDER BY rank", fields, limit)
+		if err != nil {
+			debugf("tool=get_epic_issues error=%v", err)
+			return nil, nil, err
+		}
+		if issues == nil {
+			issues = []JiraIssue{}
+		}
+		if !args.RawADF {
+			for i := range issues {
+				renderIssueText(&issues[i])
+			}
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{
+			"epic_key": args.EpicKey, "issues": issues, "breakdown": statusBreakdown(issues),
+		}}, nil, nil
+	})
+
+	// add_issues_to_epic(epic_key, keys)
+	type addToEpicArgs struct {
+		EpicKey string   `json:"epic_key" jsonschema:"Epic issue key"`
+		Keys    []string `json:"keys" jsonschema:"Issue keys to put in the epic"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "add_issues_to_epic",
+		Title:       "Add Issues to Epic",
+		Description: "Put issues in an epic, moving them from any other epic. Works for company-managed (Epic Link) and team-managed (parent) projects",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args addToEpicArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=add_issues_to_epic args={epic:%q,keys:%v}", args.EpicKey, args.Keys)
+		if args.EpicKey == "" || len(args.Keys) == 0 {
+			return nil, nil, errors.New("epic_key and keys are required")
+		}
+		if err := jc.SetEpic(ctx, args.EpicKey, args.Keys); err != nil {
+			debugf("tool=add_issues_to_epic error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"epic_key": args.EpicKey, "added": args.Keys}}, nil, nil
+	})
+
+	// remove_issues_from_epic(keys)
+	type removeFromEpicArgs struct {
+		Keys []string `json:"keys" jsonschema:"Issue keys to take out of their epic"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "remove_issues_from_epic",
+		Title:       "Remove Issues from Epic",
+		Description: "Take issues out of whatever epic they are in",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args removeFromEpicArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=remove_issues_from_epic args={keys:%v}", args.Keys)
+		if len(args.Keys) == 0 {
+			return nil, nil, errors.New("keys is required")
+		}
+		if err := jc.SetEpic(ctx, "", args.Keys); err != nil {
+			debugf("tool=remove_issues_from_epic error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"removed": args.Keys}}, nil, nil
+	})
+}
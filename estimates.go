@@ -0,0 +1,176 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Time-tracking estimates ----
+
+// timeTrackingConfig is the site's time-tracking setup: the provider (empty
+// when time tracking is off) and how long a working day and week are.
+type timeTrackingConfig struct {
+	Provider           string  `json:"-"`
+	WorkingHoursPerDay float64 `json:"workingHoursPerDay"`
+	WorkingDaysPerWeek float64 `json:"workingDaysPerWeek"`
+	DefaultUnit        string  `json:"defaultUnit"`
+}
+
+// TimeTrackingConfig reads the site's time-tracking provider and options.
+func (c *JiraClient) TimeTrackingConfig(ctx context.Context) (*timeTrackingConfig, error) {
+	var provider struct {
+		Key string `json:"key"`
+	}
+	// 204 No Content means time tracking is disabled.
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/configuration/timetracking", nil, &provider); err != nil {
+		return nil, err
+	}
+	var cfg timeTrackingConfig
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/configuration/timetracking/options", nil, &cfg); err != nil {
+		return nil, err
+	}
+	cfg.Provider = provider.Key
+	if cfg.WorkingHoursPerDay <= 0 {
+		cfg.WorkingHoursPerDay = 8
+	}
+	if cfg.WorkingDaysPerWeek <= 0 {
+		cfg.WorkingDaysPerWeek = 5
+	}
+	return &cfg, nil
+}
+
+// estimate checks an estimate for this site and rewrites it in whole units.
+// A bare number is taken in the site's default unit, and fractional days
+// and weeks are converted with the working day and week lengths, e.g.
+// "1.5d" on an 8-hour day is "1d 4h".
+func (cfg *timeTrackingConfig) estimate(s string) (string, error) {
+	s = strings.TrimSpace(strings.ToLower(s))
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		unit := map[string]string{"minute": "m", "hour": "h", "day": "d", "week": "w"}[strings.ToLower(cfg.DefaultUnit)]
+		if unit == "" {
+			unit = "m"
+		}
+		s += unit
+	}
+	var parts []string
+	for _, f := range strings.Fields(s) {
+		m := durationPartRe.FindStringSubmatch(f)
+		if m == nil || (m[2] != "w" && m[2] != "d") {
+			parts = append(parts, f)
+			continue
+		}
+		v, _ := strconv.ParseFloat(m[1], 64)
+		whole, frac := math.Modf(v)
+		if whole > 0 {
+			parts = append(parts, fmt.Sprintf("%d%s", int(whole), m[2]))
+		}
+		if frac > 0 {
+			h := frac * cfg.WorkingHoursPerDay
+			if m[2] == "w" {
+				h *= cfg.WorkingDaysPerWeek
+			}
+			parts = append(parts, strconv.FormatFloat(h, 'f', -1, 64)+"h")
+		}
+	}
+	if len(parts) == 0 {
+		return "", fmt.Errorf("invalid estimate %q", s)
+	}
+	return normalizeDuration(strings.Join(parts, " "))
+}
+
+type estimateView struct {
+	OriginalEstimate  string  `json:"original_estimate,omitempty"`
+	OriginalHours     float64 `json:"original_hours"`
+	RemainingEstimate string  `json:"remaining_estimate,omitempty"`
+	RemainingHours    float64 `json:"remaining_hours"`
+	TimeSpent         string  `json:"time_spent,omitempty"`
+	SpentHours        float64 `json:"spent_hours"`
+}
+
+func viewEstimate(tt map[string]any) estimateView {
+	secs := func(k string) float64 {
+		v, _ := tt[k].(float64)
+		return math.Round(v/36) / 100
+	}
+	return estimateView{
+		OriginalEstimate: fieldString(tt, "originalEstimate"), OriginalHours: secs("originalEstimateSeconds"),
+		RemainingEstimate: fieldString(tt, "remainingEstimate"), RemainingHours: secs("remainingEstimateSeconds"),
+		TimeSpent: fieldString(tt, "timeSpent"), SpentHours: secs("timeSpentSeconds"),
+	}
+}
+
+func (c *JiraClient) issueEstimate(ctx context.Context, key string) (estimateView, error) {
+	iss, err := c.issueFields(ctx, key, "timetracking")
+	if err != nil {
+		return estimateView{}, err
+	}
+	tt, _ := iss.Fields["timetracking"].(map[string]any)
+	return viewEstimate(tt), nil
+}
+
+func registerEstimateTools(server *mcp.Server, jc *JiraClient) {
+	// set_estimate(key, original_estimate?, remaining_estimate?)
+	type setEstimateArgs struct {
+		Key               string `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+		OriginalEstimate  string `json:"original_estimate,omitempty" jsonschema:"New original estimate, e.g. 3d, 1.5d, 4h 30m; a bare number is in the site's default unit"`
+		RemainingEstimate string `json:"remaining_estimate,omitempty" jsonschema:"New remaining estimate, in the same form"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "set_estimate",
+		Title:       "Set Estimate",
+		Description: "Set an issue's original and/or remaining time estimate, checked against the site's time-tracking settings (working day and week length, default unit)",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args setEstimateArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=set_estimate args={key:%q,original:%q,remaining:%q}", args.Key, args.OriginalEstimate, args.RemainingEstimate)
+		if args.OriginalEstimate == "" && args.RemainingEstimate == "" {
+			return nil, nil, errors.New("give original_estimate, remaining_estimate, or both")
+		}
+		cfg, err := jc.TimeTrackingConfig(ctx)
+		if err != nil {
+			debugf("tool=set_estimate error=%v", err)
+			return nil, nil, err
+		}
+		if cfg.Provider == "" {
+			return nil, nil, errors.New("time tracking is disabled on this site")
+		}
+		if cfg.Provider != "JIRA" {
+			return nil, nil, fmt.Errorf("time tracking is provided by %s; set estimates there", cfg.Provider)
+		}
+		tt := map[string]any{}
+		if args.OriginalEstimate != "" {
+			if tt["originalEstimate"], err = cfg.estimate(args.OriginalEstimate); err != nil {
+				return nil, nil, err
+			}
+		}
+		if args.RemainingEstimate != "" {
+			if tt["remainingEstimate"], err = cfg.estimate(args.RemainingEstimate); err != nil {
+				return nil, nil, err
+			}
+		}
+		before, err := jc.issueEstimate(ctx, args.Key)
+		if err != nil {
+			debugf("tool=set_estimate error=%v", err)
+			return nil, nil, err
+		}
+		if err := jc.UpdateIssue(ctx, args.Key, map[string]any{"timetracking": tt}, nil); err != nil {
+			debugf("tool=set_estimate error=%v", err)
+			return nil, nil, err
+		}
+		after, err := jc.issueEstimate(ctx, args.Key)
+		if err != nil {
+			debugf("tool=set_estimate error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{
+			"key": args.Key, "before": before, "after": after,
+			"working_hours_per_day": cfg.WorkingHoursPerDay, "working_days_per_week": cfg.WorkingDaysPerWeek,
+		}}, nil, nil
+	})
+}
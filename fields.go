@@ -0,0 +1,482 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Field catalog and team aliases ----
+
+type JiraField struct {
+	ID          string         `json:"id"`
+	Key         string         `json:"key,omitempty"`
+	Name        string         `json:"name"`
+	Custom      bool           `json:"custom"`
+	ClauseNames []string       `json:"clauseNames,omitempty"`
+	Schema      map[string]any `json:"schema,omitempty"`
+}
+
+const fieldCatalogTTL = time.Hour
+
+type fieldCatalog struct {
+	mu      sync.Mutex
+	fields  []JiraField
+	fetched time.Time
+}
+
+// Fields returns all system and custom fields, cached for an hour.
+func (c *JiraClient) Fields(ctx context.Context) ([]JiraField, error) {
+	c = c.forSite(ctx)
+	c.fieldCache.mu.Lock()
+	defer c.fieldCache.mu.Unlock()
+	if c.fieldCache.fields != nil && time.Since(c.fieldCache.fetched) < fieldCatalogTTL {
+		return c.fieldCache.fields, nil
+	}
+	var out []JiraField
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/field", nil, &out); err != nil {
+		return nil, err
+	}
+	c.fieldCache.fields, c.fieldCache.fetched = out, time.Now()
+	return out, nil
+}
+
+// aliasTarget maps a team alias (case-insensitive) to its configured field id
+// or name; anything else is returned unchanged.
+func (c *JiraClient) aliasTarget(name string) string {
+	for alias, target := range c.FieldAliases {
+		if strings.EqualFold(alias, name) {
+			return target
+		}
+	}
+	return name
+}
+
+// ResolveField resolves an alias, field id or field name to the field.
+func (c *JiraClient) ResolveField(ctx context.Context, name string) (*JiraField, error) {
+	target := c.aliasTarget(strings.TrimSpace(name))
+	fields, err := c.Fields(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range fields {
+		if fields[i].ID == target || fields[i].Key == target {
+			return &fields[i], nil
+		}
+	}
+	var matches []*JiraField
+	for i := range fields {
+		if strings.EqualFold(fields[i].Name, target) {
+			matches = append(matches, &fields[i])
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("unknown field %q", name)
+	case 1:
+		return matches[0], nil
+	}
+	ids := make([]string, len(matches))
+	for i, m := range matches {
+		ids[i] = m.ID
+	}
+	return nil, fmt.Errorf("field name %q is ambiguous (%s); use the field id", name, strings.Join(ids, ", "))
+}
+
+// ResolveFieldID is ResolveField returning just the id.
+func (c *JiraClient) ResolveFieldID(ctx context.Context, name string) (string, error) {
+	f, err := c.ResolveField(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return f.ID, nil
+}
+
+// resolveFieldKeys rewrites the keys of a fields map from aliases or names to
+// field ids. Plain strings sent to rich-text fields are converted from
+// Markdown to ADF.
+func (c *JiraClient) resolveFieldKeys(ctx context.Context, fields map[string]any) (map[string]any, error) {
+	if len(fields) == 0 {
+		return fields, nil
+	}
+	out := make(map[string]any, len(fields))
+	for k, v := range fields {
+		f, err := c.ResolveField(ctx, k)
+		if err != nil {
+			return nil, err
+		}
+		if s, ok := v.(string); ok && isRichTextField(f) {
+			v = c.richText(ctx, s)
+		}
+		out[f.ID] = v
+	}
+	return out, nil
+}
+
+// coerceCustomFields resolves a custom_fields map keyed by field names (or
+// ids and aliases) and converts each value to the shape the field's schema
+// takes: numbers from numeric strings, {"value"} for options, {"accountId"}
+// for users, and element-wise for arrays. A single value given for an array
+// field becomes a one-element list; null clears the field.
+func (c *JiraClient) coerceCustomFields(ctx context.Context, fields map[string]any) (map[string]any, error) {
+	out := make(map[string]any, len(fields))
+	for k, v := range fields {
+		f, err := c.ResolveField(ctx, k)
+		if err != nil {
+			return nil, err
+		}
+		if v == nil {
+			out[f.ID] = nil
+			continue
+		}
+		typ := fieldString(f.Schema, "type")
+		if typ == "array" {
+			list, ok := v.([]any)
+			if !ok {
+				list = []any{v}
+			}
+			items := make([]any, len(list))
+			for i, e := range list {
+				if items[i], err = c.coerceFieldValue(ctx, f, fieldString(f.Schema, "items"), e); err != nil {
+					return nil, err
+				}
+			}
+			out[f.ID] = items
+			continue
+		}
+		if out[f.ID], err = c.coerceFieldValue(ctx, f, typ, v); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// coerceFieldValue converts one value to the schema type typ. Objects are
+// passed through for Jira to validate, so callers can still send ids.
+func (c *JiraClient) coerceFieldValue(ctx context.Context, f *JiraField, typ string, v any) (any, error) {
+	if _, ok := v.(map[string]any); ok {
+		return v, nil
+	}
+	mismatch := func(want string) error {
+		return fmt.Errorf("%s (%s) expects %s, got %s", f.Name, f.ID, want, describeValue(v))
+	}
+	s, isString := v.(string)
+	switch typ {
+	case "number":
+		switch n := v.(type) {
+		case float64:
+			return n, nil
+		case string:
+			if x, err := strconv.ParseFloat(strings.TrimSpace(n), 64); err == nil {
+				return x, nil
+			}
+		}
+		return nil, mismatch("a number")
+	case "string":
+		if !isString {
+			return nil, mismatch("text")
+		}
+		if isRichTextField(f) {
+			return c.richText(ctx, s), nil
+		}
+		return s, nil
+	case "date":
+		if !isString {
+			return nil, mismatch("a date (YYYY-MM-DD)")
+		}
+		if _, err := time.Parse("2006-01-02", s); err != nil {
+			return nil, mismatch("a date (YYYY-MM-DD)")
+		}
+		return s, nil
+	case "datetime":
+		if !isString {
+			return nil, mismatch("a date-time (e.g. 2024-05-01T09:00:00.000+0000)")
+		}
+		return s, nil
+	case "option":
+		if !isString {
+			return nil, mismatch("an option value")
+		}
+		return map[string]any{"value": s}, nil
+	case "option-with-child":
+		// Cascading selects take "Parent > Child" or just "Parent".
+		if !isString {
+			return nil, mismatch(`an option value, or "Parent > Child"`)
+		}
+		parent, child, ok := strings.Cut(s, ">")
+		out := map[string]any{"value": strings.TrimSpace(parent)}
+		if ok {
+			out["child"] = map[string]any{"value": strings.TrimSpace(child)}
+		}
+		return out, nil
+	case "user":
+		if !isString {
+			return nil, mismatch("a user (accountId, email, or display name)")
+		}
+		u, err := c.ResolveUser(ctx, s)
+		if err != nil {
+			return nil, fmt.Errorf("%s (%s): %w", f.Name, f.ID, err)
+		}
+		return u.ref(), nil
+	case "version", "component", "group", "priority":
+		if !isString {
+			return nil, mismatch("a " + typ + " name")
+		}
+		return map[string]any{"name": s}, nil
+	case "project":
+		if !isString {
+			return nil, mismatch("a project key")
+		}
+		return map[string]any{"key": s}, nil
+	}
+	return v, nil
+}
+
+// describeValue names a JSON value's type for error messages.
+func describeValue(v any) string {
+	switch t := v.(type) {
+	case string:
+		return fmt.Sprintf("text %q", t)
+	case float64:
+		return "number " + strconv.FormatFloat(t, 'f', -1, 64)
+	case bool:
+		return fmt.Sprintf("boolean %t", t)
+	case []any:
+		return fmt.Sprintf("a list of %d", len(t))
+	case map[string]any:
+		return "an object"
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// slimFields is the "slim" field selection preset. The key is always
+// returned.
+var slimFields = []string{"summary", "status", "assignee", "priority", "updated"}
+
+// resolveFieldSelection turns a fields parameter into field ids for a read.
+// Names and aliases are resolved; "slim" expands to slimFields, and Jira's
+// own selectors ("*all", "*navigable", "-field") pass through.
+func (c *JiraClient) resolveFieldSelection(ctx context.Context, sel []string) ([]string, error) {
+	var out []string
+	for _, s := range sel {
+		s = strings.TrimSpace(s)
+		switch {
+		case s == "":
+		case strings.EqualFold(s, "slim"):
+			out = append(out, slimFields...)
+		case strings.EqualFold(s, "key"), strings.HasPrefix(s, "*"):
+			if !strings.EqualFold(s, "key") {
+				out = append(out, s)
+			}
+		case strings.HasPrefix(s, "-"):
+			id, err := c.ResolveFieldID(ctx, s[1:])
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, "-"+id)
+		default:
+			id, err := c.ResolveFieldID(ctx, s)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, id)
+		}
+	}
+	return out, nil
+}
+
+// isRichTextField reports whether f takes ADF (the description, environment,
+// and multi-line text custom fields).
+func isRichTextField(f *JiraField) bool {
+	switch f.ID {
+	case "description", "environment":
+		return true
+	}
+	custom, _ := f.Schema["custom"].(string)
+	return custom == "com.atlassian.jira.plugin.system.customfieldtypes:textarea"
+}
+
+// aliasFieldIDs resolves every configured alias to its field id, once per
+// read. If the field list cannot be fetched it gives up rather than trying
+// again for each alias.
+func (c *JiraClient) aliasFieldIDs(ctx context.Context) map[string]string {
+	if len(c.FieldAliases) == 0 {
+		return nil
+	}
+	if _, err := c.Fields(ctx); err != nil {
+		debugf("field aliases: %v", err)
+		return nil
+	}
+	ids := make(map[string]string, len(c.FieldAliases))
+	for alias := range c.FieldAliases {
+		id, err := c.ResolveFieldID(ctx, alias)
+		if err != nil {
+			debugf("alias %q: %v", alias, err)
+			continue
+		}
+		ids[alias] = id
+	}
+	return ids
+}
+
+// aliasedFields returns the values of aliased fields present on an issue,
+// keyed by alias, so reads speak the team's shorthand too. ids comes from
+// aliasFieldIDs.
+func aliasedFields(ids map[string]string, iss *JiraIssue) map[string]any {
+	if len(ids) == 0 || iss == nil {
+		return nil
+	}
+	out := map[string]any{}
+	for alias, id := range ids {
+		if v, ok := iss.Fields[id]; ok {
+			out[alias] = v
+		}
+	}
+	return out
+}
+
+var (
+	jqlIdentRe     = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*`)
+	jqlOperatorRe  = regexp.MustCompile(`(?i)^\s*(=|!=|~|!~|<|>|\bin\b|\bnot\b|\bis\b|\bwas\b|\bchanged\b)`)
+	jqlOrderByTail = regexp.MustCompile(`(?i)(\border\s+by|,)\s*$`)
+	customFieldRe  = regexp.MustCompile(`^customfield_(\d+)$`)
+)
+
+// jqlClause returns how a field is referenced in JQL.
+func (c *JiraClient) jqlClause(ctx context.Context, target string) string {
+	if m := customFieldRe.FindStringSubmatch(target); m != nil {
+		return "cf[" + m[1] + "]"
+	}
+	if f, err := c.ResolveField(ctx, target); err == nil {
+		if m := customFieldRe.FindStringSubmatch(f.ID); m != nil {
+			return "cf[" + m[1] + "]"
+		}
+		if len(f.ClauseNames) > 0 {
+			return f.ClauseNames[0]
+		}
+	}
+	return `"` + strings.ReplaceAll(target, `"`, `\"`) + `"`
+}
+
+// rewriteJQLAliases replaces alias identifiers used as field references
+// (followed by an operator, or in an ORDER BY list) with JQL clause names.
+// Quoted strings are left untouched.
+func (c *JiraClient) rewriteJQLAliases(ctx context.Context, jql string) string {
+	if len(c.FieldAliases) == 0 {
+		return jql
+	}
+	var b strings.Builder
+	for i := 0; i < len(jql); {
+		ch := jql[i]
+		if ch == '"' || ch == '\'' {
+			j := i + 1
+			for j < len(jql) && jql[j] != ch {
+				if jql[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			j = min(j+1, len(jql))
+			b.WriteString(jql[i:j])
+			i = j
+			continue
+		}
+		if ident := jqlIdentRe.FindString(jql[i:]); ident != "" && (i == 0 || !isWordByte(jql[i-1])) {
+			rest := jql[i+len(ident):]
+			if target := c.aliasTarget(ident); target != ident &&
+				(jqlOperatorRe.MatchString(rest) || jqlOrderByTail.MatchString(jql[:i])) {
+				b.WriteString(c.jqlClause(ctx, target))
+			} else {
+				b.WriteString(ident)
+			}
+			i += len(ident)
+			continue
+		}
+		b.WriteByte(ch)
+		i++
+	}
+	return b.String()
+}
+
+type fieldView struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Custom      bool     `json:"custom"`
+	Type        string   `json:"type,omitempty"`  // schema type, e.g. string, number, array, option
+	Items       string   `json:"items,omitempty"` // element type of arrays
+	CustomType  string   `json:"custom_type,omitempty"`
+	ClauseNames []string `json:"clause_names,omitempty"`
+	Aliases     []string `json:"aliases,omitempty"`
+	Ambiguous   bool     `json:"ambiguous_name,omitempty"` // other fields share the name; refer to this one by id
+}
+
+func registerFieldTools(server *mcp.Server, jc *JiraClient) {
+	// list_fields(query?, custom_only?, refresh?)
+	type listFieldsArgs struct {
+		Query      string `json:"query,omitempty" jsonschema:"Only fields whose name or id contains this text"`
+		CustomOnly bool   `json:"custom_only,omitempty" jsonschema:"Only custom fields"`
+		Refresh    bool   `json:"refresh,omitempty" jsonschema:"Refetch the field list instead of using the hourly cache"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_fields",
+		Title:       "List Fields",
+		Description: "List system and custom fields with id, name, type, and schema. Tools accept a field's name (e.g. Story Points) or alias wherever they take a field id, unless the name is ambiguous",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listFieldsArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_fields args={query:%q,custom_only:%t,refresh:%t}", args.Query, args.CustomOnly, args.Refresh)
+		if args.Refresh {
+			fc := &jc.forSite(ctx).fieldCache
+			fc.mu.Lock()
+			fc.fields = nil
+			fc.mu.Unlock()
+		}
+		fields, err := jc.Fields(ctx)
+		if err != nil {
+			debugf("tool=list_fields error=%v", err)
+			return nil, nil, err
+		}
+		names := map[string]int{}
+		for _, f := range fields {
+			names[strings.ToLower(f.Name)]++
+		}
+		aliases := map[string][]string{}
+		for alias := range jc.FieldAliases {
+			if f, err := jc.ResolveField(ctx, alias); err == nil {
+				aliases[f.ID] = append(aliases[f.ID], alias)
+			}
+		}
+		q := strings.ToLower(args.Query)
+		out := []fieldView{}
+		for _, f := range fields {
+			if args.CustomOnly && !f.Custom {
+				continue
+			}
+			if q != "" && !strings.Contains(strings.ToLower(f.Name), q) && !strings.Contains(strings.ToLower(f.ID), q) {
+				continue
+			}
+			custom := fieldString(f.Schema, "custom")
+			if i := strings.LastIndex(custom, ":"); i >= 0 {
+				custom = custom[i+1:]
+			}
+			out = append(out, fieldView{
+				ID: f.ID, Name: f.Name, Custom: f.Custom, Type: fieldString(f.Schema, "type"), Items: fieldString(f.Schema, "items"),
+				CustomType: custom, ClauseNames: f.ClauseNames, Aliases: aliases[f.ID], Ambiguous: names[strings.ToLower(f.Name)] > 1,
+			})
+		}
+		sort.Slice(out, func(i, j int) bool {
+			if out[i].Custom != out[j].Custom {
+				return !out[i].Custom
+			}
+			return strings.ToLower(out[i].Name) < strings.ToLower(out[j].Name)
+		})
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"fields": out, "count": len(out)}}, nil, nil
+	})
+}
@@ -0,0 +1,109 @@
+package jira
+
+import (
+	"strings"
+	"time"
+)
+
+// ---- Helpers for reading loosely typed Jira field maps ----
+
+// jiraTimeLayout is the timestamp format used by the Jira REST API.
+const jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+func parseJiraTime(s string) (time.Time, bool) {
+	if s == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range []string{jiraTimeLayout, time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// fieldPath walks nested maps, e.g. fieldPath(fields, "priority", "name").
+func fieldPath(m map[string]any, path ...string) any {
+	var cur any = m
+	for _, p := range path {
+		mm, ok := cur.(map[string]any)
+		if !ok {
+			return nil
+		}
+		cur = mm[p]
+	}
+	return cur
+}
+
+func fieldString(m map[string]any, path ...string) string {
+	s, _ := fieldPath(m, path...).(string)
+	return s
+}
+
+func fieldNumber(m map[string]any, path ...string) float64 {
+	switch v := fieldPath(m, path...).(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	}
+	return 0
+}
+
+func fieldList(m map[string]any, path ...string) []any {
+	l, _ := fieldPath(m, path...).([]any)
+	return l
+}
+
+func fieldStrings(m map[string]any, path ...string) []string {
+	var out []string
+	for _, v := range fieldList(m, path...) {
+		if s, ok := v.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// IssueCounts is lightweight engagement metadata taken from fields the
+// issue payload already carries. A count is nil when its field was not
+// returned.
+type IssueCounts struct {
+	Watchers    *int `json:"watchers,omitempty"`
+	Votes       *int `json:"votes,omitempty"`
+	Attachments *int `json:"attachments,omitempty"`
+	Subtasks    *int `json:"subtasks,omitempty"`
+	Links       *int `json:"links,omitempty"`
+}
+
+func issueCounts(fields map[string]any) *IssueCounts {
+	var ic IssueCounts
+	present := false
+	count := func(key string, n func() int) *int {
+		if _, ok := fields[key]; !ok {
+			return nil
+		}
+		present = true
+		return ptr(n())
+	}
+	ic.Watchers = count("watches", func() int { return int(fieldNumber(fields, "watches", "watchCount")) })
+	ic.Votes = count("votes", func() int { return int(fieldNumber(fields, "votes", "votes")) })
+	ic.Attachments = count("attachment", func() int { return len(fieldList(fields, "attachment")) })
+	ic.Subtasks = count("subtasks", func() int { return len(fieldList(fields, "subtasks")) })
+	ic.Links = count("issuelinks", func() int { return len(fieldList(fields, "issuelinks")) })
+	if !present {
+		return nil
+	}
+	return &ic
+}
+
+func ptr[T any](v T) *T { return &v }
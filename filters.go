@@ -0,0 +1,262 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Saved filters ----
+
+type JiraFilter struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	JQL         string    `json:"jql,omitempty"`
+	Owner       *JiraUser `json:"owner,omitempty"`
+	Favourite   bool      `json:"favourite"`
+	ViewURL     string    `json:"viewUrl,omitempty"`
+}
+
+const filterExpand = "description,favourite,jql,owner,viewUrl"
+
+// ListFilters returns the caller's own filters (scope "my"), their
+// favourites ("favourite"), or filters visible to them whose name contains
+// name ("search").
+func (c *JiraClient) ListFilters(ctx context.Context, scope, name string, max int) ([]JiraFilter, error) {
+	switch scope {
+	case "my", "favourite":
+		var out []JiraFilter
+		if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/filter/"+scope+"?expand="+filterExpand, nil, &out); err != nil {
+			return nil, err
+		}
+		if len(out) > max {
+			out = out[:max]
+		}
+		return out, nil
+	case "search":
+	default:
+		return nil, fmt.Errorf("unknown scope %q (valid: my, favourite, search)", scope)
+	}
+	var out []JiraFilter
+	for startAt := 0; len(out) < max; {
+		q := url.Values{}
+		q.Set("expand", filterExpand)
+		q.Set("startAt", fmt.Sprintf("%d", startAt))
+		q.Set("maxResults", fmt.Sprintf("%d", min(max-len(out), 100)))
+		if name != "" {
+			q.Set("filterName", name)
+		}
+		var page struct {
+			IsLast bool         `json:"isLast"`
+			Values []JiraFilter `json:"values"`
+		}
+		if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/filter/search?"+q.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		out = append(out, page.Values...)
+		startAt += len(page.Values)
+		if page.IsLast || len(page.Values) == 0 {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (c *JiraClient) GetFilter(ctx context.Context, id string) (*JiraFilter, error) {
+	var out JiraFilter
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/filter/"+url.PathEscape(id)+"?expand="+filterExpand, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SaveFilter creates f, or updates the filter with f.ID when it is set.
+// Updates replace name, description, and JQL, so callers start from the
+// current filter.
+func (c *JiraClient) SaveFilter(ctx context.Context, f JiraFilter) (*JiraFilter, error) {
+	body := map[string]any{"name": f.Name, "jql": f.JQL, "description": f.Description}
+	method, path := http.MethodPost, "/rest/api/3/filter"
+	if f.ID != "" {
+		method, path = http.MethodPut, "/rest/api/3/filter/"+url.PathEscape(f.ID)
+	} else {
+		body["favourite"] = f.Favourite
+	}
+	var out JiraFilter
+	if err := c.doJSON(ctx, method, path+"?expand="+filterExpand, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func registerFilterTools(server *mcp.Server, jc *JiraClient) {
+	// list_filters(scope?, name?, max_results?)
+	type listFiltersArgs struct {
+		Scope      string `json:"scope,omitempty" jsonschema:"favourite (default), my (owned by me), or search (all filters I can see)"`
+		Name       string `json:"name,omitempty" jsonschema:"With scope search: only filters whose name contains this"`
+		MaxResults int    `json:"max_results,omitempty" jsonschema:"Maximum filters (default 50)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_filters",
+		Title:       "List Filters",
+		Description: "List saved Jira filters (favourites, my own, or a name search) with their JQL, to reuse the team's established queries",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listFiltersArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_filters args={scope:%q,name:%q}", args.Scope, args.Name)
+		scope := args.Scope
+		if scope == "" {
+			scope = "favourite"
+			if args.Name != "" {
+				scope = "search"
+			}
+		}
+		max := args.MaxResults
+		if max <= 0 {
+			max = 50
+		}
+		filters, err := jc.ListFilters(ctx, scope, args.Name, max)
+		if err != nil {
+			debugf("tool=list_filters error=%v", err)
+			return nil, nil, err
+		}
+		if filters == nil {
+			filters = []JiraFilter{}
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"scope": scope, "filters": filters}}, nil, nil
+	})
+
+	// get_filter(id)
+	type getFilterArgs struct {
+		ID string `json:"id" jsonschema:"Filter id"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_filter",
+		Title:       "Get Filter",
+		Description: "Get a saved filter's name, owner, and JQL",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args getFilterArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=get_filter args={id:%q}", args.ID)
+		f, err := jc.GetFilter(ctx, args.ID)
+		if err != nil {
+			debugf("tool=get_filter error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: f}, nil, nil
+	})
+
+	// run_filter(id, max_results?, page_token?, fields?, raw_adf?)
+	type runFilterArgs struct {
+		ID         string   `json:"id" jsonschema:"Filter id"`
+		MaxResults int      `json:"max_results,omitempty"`
+		PageToken  string   `json:"page_token,omitempty" jsonschema:"nextPageToken from the previous page"`
+		Fields     []string `json:"fields,omitempty" jsonschema:"Fields to return by name, id, or alias; slim for key/summary/status/assignee/priority/updated (default *navigable)"`
+		RawADF     bool     `json:"raw_adf,omitempty" jsonschema:"Return rich-text fields as raw ADF instead of Markdown"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "run_filter",
+		Title:       "Run Filter",
+		Description: "Run a saved filter's JQL, paged like search_issues. The session focus is not applied",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args runFilterArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=run_filter args={id:%q,max:%d,page:%q}", args.ID, args.MaxResults, args.PageToken)
+		f, err := jc.GetFilter(ctx, args.ID)
+		if err != nil {
+			debugf("tool=run_filter error=%v", err)
+			return nil, nil, err
+		}
+		fields, err := jc.resolveFieldSelection(ctx, args.Fields)
+		if err != nil {
+			return nil, nil, err
+		}
+		res, err := jc.Search(ctx, f.JQL, args.PageToken, args.MaxResults, fields)
+		if err != nil {
+			debugf("tool=run_filter error=%v", err)
+			return nil, nil, err
+		}
+		if !args.RawADF {
+			for i := range res.Issues {
+				renderIssueText(&res.Issues[i])
+			}
+		}
+		out := map[string]any{
+			"filter": map[string]any{"id": f.ID, "name": f.Name, "jql": f.JQL},
+			"issues": res.Issues, "isLast": res.IsLast,
+		}
+		if res.NextPageToken != "" {
+			out["nextPageToken"] = res.NextPageToken
+		}
+		return &mcp.CallToolResult{StructuredContent: out}, nil, nil
+	})
+
+	// create_filter(name, jql, description?, favourite?)
+	type createFilterArgs struct {
+		Name        string `json:"name"`
+		JQL         string `json:"jql"`
+		Description string `json:"description,omitempty"`
+		Favourite   bool   `json:"favourite,omitempty" jsonschema:"Star the filter for me"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "create_filter",
+		Title:       "Create Filter",
+		Description: "Save a JQL query as a Jira filter so it can be reused later (shared per your Jira default sharing settings)",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args createFilterArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=create_filter args={name:%q,jql:%q}", args.Name, args.JQL)
+		if args.Name == "" || args.JQL == "" {
+			return nil, nil, errors.New("name and jql are required")
+		}
+		f, err := jc.SaveFilter(ctx, JiraFilter{Name: args.Name, JQL: args.JQL, Description: args.Description, Favourite: args.Favourite})
+		if err != nil {
+			debugf("tool=create_filter error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: f}, nil, nil
+	})
+
+	// update_filter(id, name?, jql?, description?)
+	type updateFilterArgs struct {
+		ID          string  `json:"id" jsonschema:"Filter id"`
+		Name        string  `json:"name,omitempty"`
+		JQL         string  `json:"jql,omitempty"`
+		Description *string `json:"description,omitempty" jsonschema:"New description; empty string clears it"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "update_filter",
+		Title:       "Update Filter",
+		Description: "Change a saved filter's name, JQL, or description. Only filters you own can be changed",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args updateFilterArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=update_filter args={id:%q,name:%q,jql:%q}", args.ID, args.Name, args.JQL)
+		if args.Name == "" && args.JQL == "" && args.Description == nil {
+			return nil, nil, errors.New("nothing to update: give name, jql, or description")
+		}
+		cur, err := jc.GetFilter(ctx, args.ID)
+		if err != nil {
+			debugf("tool=update_filter error=%v", err)
+			return nil, nil, err
+		}
+		before := cur.JQL
+		if args.Name != "" {
+			cur.Name = args.Name
+		}
+		if args.JQL != "" {
+			cur.JQL = args.JQL
+		}
+		if args.Description != nil {
+			cur.Description = *args.Description
+		}
+		f, err := jc.SaveFilter(ctx, *cur)
+		if err != nil {
+			debugf("tool=update_filter error=%v", err)
+			return nil, nil, err
+		}
+		out := map[string]any{"filter": f}
+		if before != f.JQL {
+			out["previous_jql"] = before
+		}
+		return &mcp.CallToolResult{StructuredContent: out}, nil, nil
+	})
+}
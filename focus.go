@@ -0,0 +1,193 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Session focus ----
+//
+// set_focus records a project, sprint, and/or assignee for the current
+// session. Until it expires or is cleared, searches are narrowed to it and
+// convenience tools use it for arguments left empty.
+
+const defaultFocusTTL = 4 * time.Hour
+
+type sessionFocus struct {
+	Project   string    `json:"project,omitempty"`
+	Sprint    string    `json:"sprint,omitempty"`
+	Assignee  string    `json:"assignee,omitempty"` // as given, for display
+	Expires   time.Time `json:"expires"`
+	accountID string    // resolved assignee; empty for "me"
+	fallback  bool      // JIRA_DEFAULT_PROJECT, not set_focus
+}
+
+type focusStore struct {
+	mu       sync.Mutex
+	sessions map[string]*sessionFocus
+}
+
+// sessionKey identifies a session; stdio has a single unnamed session.
+func sessionKey(ss *mcp.ServerSession) string {
+	if ss != nil && ss.ID() != "" {
+		return ss.ID()
+	}
+	return "default"
+}
+
+func (c *JiraClient) focusFor(ss *mcp.ServerSession) *sessionFocus {
+	s := &c.focus
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := sessionKey(ss)
+	f := s.sessions[key]
+	if f != nil && time.Now().After(f.Expires) {
+		delete(s.sessions, key)
+		f = nil
+	}
+	if f == nil && c.DefaultProject != "" {
+		return &sessionFocus{Project: c.DefaultProject, fallback: true}
+	}
+	return f
+}
+
+func (c *JiraClient) setFocus(ss *mcp.ServerSession, f *sessionFocus) {
+	s := &c.focus
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sessions == nil {
+		s.sessions = map[string]*sessionFocus{}
+	}
+	if f == nil {
+		delete(s.sessions, sessionKey(ss))
+	} else {
+		s.sessions[sessionKey(ss)] = f
+	}
+}
+
+var (
+	jqlProjectRe  = regexp.MustCompile(`(?i)\bproject\b`)
+	jqlSprintRe   = regexp.MustCompile(`(?i)\bsprint\b`)
+	jqlAssigneeRe = regexp.MustCompile(`(?i)\bassignee\b`)
+	jqlOrderByRe  = regexp.MustCompile(`(?i)\border\s+by\b`)
+)
+
+// focusClauses returns the JQL clauses f adds to jql, skipping fields jql
+// already constrains.
+func (f *sessionFocus) focusClauses(jql string) []string {
+	var clauses []string
+	if f.Project != "" && !jqlProjectRe.MatchString(jql) {
+		clauses = append(clauses, "project = "+quoteJQL(f.Project))
+	}
+	if f.Sprint != "" && !jqlSprintRe.MatchString(jql) {
+		switch s := strings.ToLower(f.Sprint); {
+		case s == "current" || s == "open":
+			clauses = append(clauses, "sprint in openSprints()")
+		case s == "future":
+			clauses = append(clauses, "sprint in futureSprints()")
+		default:
+			if _, err := strconv.Atoi(f.Sprint); err == nil {
+				clauses = append(clauses, "sprint = "+f.Sprint)
+			} else {
+				clauses = append(clauses, "sprint = "+quoteJQL(f.Sprint))
+			}
+		}
+	}
+	if f.Assignee != "" && !jqlAssigneeRe.MatchString(jql) {
+		if f.accountID == "" {
+			clauses = append(clauses, "assignee = currentUser()")
+		} else {
+			clauses = append(clauses, "assignee = "+quoteJQL(f.accountID))
+		}
+	}
+	return clauses
+}
+
+// applyFocus narrows jql to the session focus. It returns jql unchanged
+// when there is no focus or nothing to add.
+func (f *sessionFocus) applyFocus(jql string) string {
+	if f == nil || f.fallback {
+		return jql
+	}
+	clauses := f.focusClauses(jql)
+	if len(clauses) == 0 {
+		return jql
+	}
+	where, order := jql, ""
+	if loc := jqlOrderByRe.FindStringIndex(jql); loc != nil {
+		where, order = jql[:loc[0]], " "+jql[loc[0]:]
+	}
+	if strings.TrimSpace(where) != "" {
+		clauses = append([]string{"(" + strings.TrimSpace(where) + ")"}, clauses...)
+	}
+	return strings.Join(clauses, " AND ") + order
+}
+
+// resolveFocus validates and resolves the arguments of set_focus.
+func (c *JiraClient) resolveFocus(ctx context.Context, project, sprint, assignee string, ttl time.Duration) (*sessionFocus, error) {
+	f := &sessionFocus{Project: strings.ToUpper(project), Sprint: sprint, Assignee: assignee, Expires: time.Now().Add(ttl)}
+	if assignee != "" && !strings.EqualFold(assignee, "me") {
+		u, err := c.ResolveUser(ctx, assignee)
+		if err != nil {
+			return nil, err
+		}
+		f.accountID = u.id()
+		if u.DisplayName != "" {
+			f.Assignee = u.DisplayName
+		}
+	}
+	return f, nil
+}
+
+func registerFocusTools(server *mcp.Server, jc *JiraClient) {
+	// set_focus(project?, sprint?, assignee?, ttl_minutes?)
+	type setFocusArgs struct {
+		Project    string `json:"project,omitempty" jsonschema:"Project key"`
+		Sprint     string `json:"sprint,omitempty" jsonschema:"Sprint id or name, or current/future"`
+		Assignee   string `json:"assignee,omitempty" jsonschema:"accountId, email, display name, or me"`
+		TTLMinutes int    `json:"ttl_minutes,omitempty" jsonschema:"How long the focus lasts (default 240)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "set_focus",
+		Title:       "Set Focus",
+		Description: "Set session defaults (project, sprint, assignee) that narrow later searches and fill in empty arguments of convenience tools, until cleared or expired",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args setFocusArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=set_focus args={project:%q,sprint:%q,assignee:%q,ttl:%d}", args.Project, args.Sprint, args.Assignee, args.TTLMinutes)
+		if args.Project == "" && args.Sprint == "" && args.Assignee == "" {
+			return nil, nil, fmt.Errorf("give at least one of project, sprint, assignee (use clear_focus to reset)")
+		}
+		ttl := defaultFocusTTL
+		if args.TTLMinutes > 0 {
+			ttl = time.Duration(args.TTLMinutes) * time.Minute
+		}
+		f, err := jc.resolveFocus(ctx, args.Project, args.Sprint, args.Assignee, ttl)
+		if err != nil {
+			return nil, nil, err
+		}
+		jc.setFocus(req.Session, f)
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"focus": f, "jql_clauses": f.focusClauses("")}}, nil, nil
+	})
+
+	// clear_focus()
+	type clearFocusArgs struct{}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "clear_focus",
+		Title:       "Clear Focus",
+		Description: "Remove the session defaults set with set_focus",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args clearFocusArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=clear_focus")
+		f := jc.focusFor(req.Session)
+		had := f != nil && !f.fallback
+		jc.setFocus(req.Session, nil)
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"cleared": had}}, nil, nil
+	})
+}
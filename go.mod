@@ -2,9 +2,10 @@ module github.com/gomcpserver/jira
 
 go 1.25
 
-require github.com/modelcontextprotocol/go-sdk v0.8.0
-
 require (
-	github.com/google/jsonschema-go v0.3.0 // indirect
-	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
+	github.com/google/jsonschema-go v0.3.0
+	github.com/modelcontextprotocol/go-sdk v0.8.0
+	gopkg.in/yaml.v3 v3.0.1
 )
+
+require github.com/yosida95/uritemplate/v3 v3.0.2
@@ -0,0 +1,397 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Groups and project roles ----
+//
+// These are admin APIs; with JIRA_CREDENTIALS they go through the "admin"
+// credential route.
+
+type JiraGroup struct {
+	Name    string `json:"name"`
+	GroupID string `json:"groupId,omitempty"`
+}
+
+var groupIDRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// groupParam sets groupId or groupname on q, depending on what ref looks
+// like.
+func groupParam(q url.Values, ref string) {
+	if groupIDRe.MatchString(ref) {
+		q.Set("groupId", ref)
+	} else {
+		q.Set("groupname", ref)
+	}
+}
+
+// Groups returns every group on the site whose name contains query.
+func (c *JiraClient) Groups(ctx context.Context, query string) ([]JiraGroup, error) {
+	groups, err := apiPage[JiraGroup](ctx, c, "/rest/api/3/group/bulk", nil)
+	if err != nil {
+		return nil, err
+	}
+	out := []JiraGroup{}
+	for _, g := range groups {
+		if query == "" || strings.Contains(strings.ToLower(g.Name), strings.ToLower(query)) {
+			out = append(out, g)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// GroupMembers returns the members of a group, given by name or id.
+func (c *JiraClient) GroupMembers(ctx context.Context, group string, inactive bool) ([]JiraUser, error) {
+	q := url.Values{}
+	groupParam(q, group)
+	q.Set("includeInactiveUsers", strconv.FormatBool(inactive))
+	return apiPage[JiraUser](ctx, c, "/rest/api/3/group/member", q)
+}
+
+// UserGroups returns the groups a user belongs to.
+func (c *JiraClient) UserGroups(ctx context.Context, accountID string) ([]JiraGroup, error) {
+	var out []JiraGroup
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/user/groups?accountId="+url.QueryEscape(accountID), nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type JiraProjectRole struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Actors      []struct {
+		DisplayName string `json:"displayName"`
+		Type        string `json:"type"` // atlassian-user-role-actor or atlassian-group-role-actor
+		ActorUser   *struct {
+			AccountID string `json:"accountId"`
+		} `json:"actorUser,omitempty"`
+		ActorGroup *JiraGroup `json:"actorGroup,omitempty"`
+	} `json:"actors,omitempty"`
+}
+
+// ProjectRoles maps a project's role names to role ids.
+func (c *JiraClient) ProjectRoles(ctx context.Context, project string) (map[string]int, error) {
+	var links map[string]string // role name to role URL
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/project/"+url.PathEscape(project)+"/role", nil, &links); err != nil {
+		return nil, err
+	}
+	out := make(map[string]int, len(links))
+	for name, u := range links {
+		id, err := strconv.Atoi(path.Base(u))
+		if err != nil {
+			return nil, fmt.Errorf("unexpected role URL %q", u)
+		}
+		out[name] = id
+	}
+	return out, nil
+}
+
+// ProjectRole returns a project role, given by name or id, with its actors.
+func (c *JiraClient) ProjectRole(ctx context.Context, project, role string) (*JiraProjectRole, error) {
+	id, err := strconv.Atoi(role)
+	if err != nil {
+		roles, err := c.ProjectRoles(ctx, project)
+		if err != nil {
+			return nil, err
+		}
+		var names []string
+		for name, rid := range roles {
+			if strings.EqualFold(name, role) {
+				id = rid
+			}
+			names = append(names, name)
+		}
+		if id == 0 {
+			sort.Strings(names)
+			return nil, fmt.Errorf("project %s has no role %q; roles: %s", project, role, strings.Join(names, ", "))
+		}
+	}
+	var out JiraProjectRole
+	if err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/rest/api/3/project/%s/role/%d", url.PathEscape(project), id), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AddRoleActors adds users (account ids) and groups (ids) to a project role.
+func (c *JiraClient) AddRoleActors(ctx context.Context, project string, role int, users, groups []string) error {
+	body := map[string]any{}
+	if len(users) > 0 {
+		body["user"] = users
+	}
+	if len(groups) > 0 {
+		body["groupId"] = groups
+	}
+	return c.doJSON(ctx, http.MethodPost, fmt.Sprintf("/rest/api/3/project/%s/role/%d", url.PathEscape(project), role), body, nil)
+}
+
+// RemoveRoleActor removes one user (param "user") or group ("groupId")
+// from a project role.
+func (c *JiraClient) RemoveRoleActor(ctx context.Context, project string, role int, param, id string) error {
+	q := url.Values{}
+	q.Set(param, id)
+	return c.doJSON(ctx, http.MethodDelete, fmt.Sprintf("/rest/api/3/project/%s/role/%d?%s", url.PathEscape(project), role, q.Encode()), nil, nil)
+}
+
+// groupIDs resolves group names or ids to group ids.
+func (c *JiraClient) groupIDs(ctx context.Context, refs []string) ([]string, error) {
+	var all []JiraGroup
+	out := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if groupIDRe.MatchString(ref) {
+			out = append(out, ref)
+			continue
+		}
+		if all == nil {
+			var err error
+			if all, err = c.Groups(ctx, ""); err != nil {
+				return nil, err
+			}
+		}
+		id := ""
+		for _, g := range all {
+			if strings.EqualFold(g.Name, ref) {
+				id = g.GroupID
+			}
+		}
+		if id == "" {
+			return nil, fmt.Errorf("no group named %q", ref)
+		}
+		out = append(out, id)
+	}
+	return out, nil
+}
+
+type roleActorView struct {
+	Type        string `json:"type"` // user or group
+	DisplayName string `json:"display_name"`
+	AccountID   string `json:"account_id,omitempty"`
+	GroupID     string `json:"group_id,omitempty"`
+}
+
+func viewRole(r *JiraProjectRole) map[string]any {
+	actors := []roleActorView{}
+	for _, a := range r.Actors {
+		v := roleActorView{DisplayName: a.DisplayName}
+		switch {
+		case a.ActorUser != nil:
+			v.Type, v.AccountID = "user", a.ActorUser.AccountID
+		case a.ActorGroup != nil:
+			v.Type, v.GroupID = "group", a.ActorGroup.GroupID
+		default:
+			v.Type = a.Type
+		}
+		actors = append(actors, v)
+	}
+	return map[string]any{"id": r.ID, "name": r.Name, "description": r.Description, "actors": actors}
+}
+
+func registerGroupTools(server *mcp.Server, jc *JiraClient) {
+	// list_groups(query?)
+	type listGroupsArgs struct {
+		Query string `json:"query,omitempty" jsonschema:"Only groups whose name contains this"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_groups",
+		Title:       "List Groups",
+		Description: "List the site's user groups with their ids, optionally filtered by name. Needs Jira admin permission",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listGroupsArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_groups args={query:%q}", args.Query)
+		groups, err := jc.Groups(ctx, args.Query)
+		if err != nil {
+			debugf("tool=list_groups error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"groups": groups}}, nil, nil
+	})
+
+	// get_group_members(group?, user?, include_inactive?)
+	type groupMembersArgs struct {
+		Group           string `json:"group,omitempty" jsonschema:"Group name or id whose members to list"`
+		User            string `json:"user,omitempty" jsonschema:"Instead, list the groups this user (accountId, email, or display name) belongs to"`
+		IncludeInactive bool   `json:"include_inactive,omitempty" jsonschema:"Include deactivated accounts in a group's members"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_group_members",
+		Title:       "Get Group Members",
+		Description: "Look up group membership: the members of a group, or the groups a user belongs to",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args groupMembersArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=get_group_members args={group:%q,user:%q}", args.Group, args.User)
+		switch {
+		case (args.Group == "") == (args.User == ""):
+			return nil, nil, errors.New("give group or user")
+		case args.User != "":
+			u, err := jc.ResolveUser(ctx, args.User)
+			if err != nil {
+				return nil, nil, err
+			}
+			groups, err := jc.UserGroups(ctx, u.AccountID)
+			if err != nil {
+				debugf("tool=get_group_members error=%v", err)
+				return nil, nil, err
+			}
+			if groups == nil {
+				groups = []JiraGroup{}
+			}
+			return &mcp.CallToolResult{StructuredContent: map[string]any{"account_id": u.AccountID, "groups": groups}}, nil, nil
+		}
+		members, err := jc.GroupMembers(ctx, args.Group, args.IncludeInactive)
+		if err != nil {
+			debugf("tool=get_group_members error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"group": args.Group, "members": activeUsers(members, args.IncludeInactive)}}, nil, nil
+	})
+
+	// list_project_roles(project?, role?)
+	type projectRolesArgs struct {
+		Project string `json:"project,omitempty" jsonschema:"Project key (default the focus project)"`
+		Role    string `json:"role,omitempty" jsonschema:"Role name or id; omit to list every role with its actors"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_project_roles",
+		Title:       "List Project Roles",
+		Description: "List a project's roles (e.g. Administrators, Developers) with the users and groups in each, for access reviews",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args projectRolesArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_project_roles args={project:%q,role:%q}", args.Project, args.Role)
+		project := args.Project
+		if f := jc.focusFor(req.Session); project == "" && f != nil {
+			project = f.Project
+		}
+		if project == "" {
+			return nil, nil, errors.New("project is required (no focus project set)")
+		}
+		refs := []string{args.Role}
+		if args.Role == "" {
+			roles, err := jc.ProjectRoles(ctx, project)
+			if err != nil {
+				debugf("tool=list_project_roles error=%v", err)
+				return nil, nil, err
+			}
+			refs = refs[:0]
+			for _, id := range roles {
+				refs = append(refs, strconv.Itoa(id))
+			}
+		}
+		views := []map[string]any{}
+		for _, ref := range refs {
+			r, err := jc.ProjectRole(ctx, project, ref)
+			if err != nil {
+				debugf("tool=list_project_roles error=%v", err)
+				return nil, nil, err
+			}
+			views = append(views, viewRole(r))
+		}
+		sort.Slice(views, func(i, j int) bool { return views[i]["name"].(string) < views[j]["name"].(string) })
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"project": project, "roles": views}}, nil, nil
+	})
+
+	// update_project_role(project?, role, add_users?, remove_users?, add_groups?, remove_groups?, confirm?)
+	type updateRoleArgs struct {
+		Project      string   `json:"project,omitempty" jsonschema:"Project key (default the focus project)"`
+		Role         string   `json:"role" jsonschema:"Role name or id"`
+		AddUsers     []string `json:"add_users,omitempty" jsonschema:"Users to add (accountId, email, or display name)"`
+		RemoveUsers  []string `json:"remove_users,omitempty" jsonschema:"Users to remove"`
+		AddGroups    []string `json:"add_groups,omitempty" jsonschema:"Groups to add (name or id)"`
+		RemoveGroups []string `json:"remove_groups,omitempty" jsonschema:"Groups to remove"`
+		Confirm      bool     `json:"confirm,omitempty" jsonschema:"Only for clients without elicitation: set once the user has approved the change"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "update_project_role",
+		Title:       "Update Project Role",
+		Description: "Add or remove users and groups in a project role, changing who has that role's permissions. Asks the user to confirm; clients without elicitation pass confirm=true once the user has approved",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: ptr(true), IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args updateRoleArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=update_project_role args={project:%q,role:%q,users+:%v,users-:%v,groups+:%v,groups-:%v,confirm:%t}",
+			args.Project, args.Role, args.AddUsers, args.RemoveUsers, args.AddGroups, args.RemoveGroups, args.Confirm)
+		project := args.Project
+		if f := jc.focusFor(req.Session); project == "" && f != nil {
+			project = f.Project
+		}
+		if project == "" {
+			return nil, nil, errors.New("project is required (no focus project set)")
+		}
+		if len(args.AddUsers)+len(args.RemoveUsers)+len(args.AddGroups)+len(args.RemoveGroups) == 0 {
+			return nil, nil, errors.New("nothing to change")
+		}
+		role, err := jc.ProjectRole(ctx, project, args.Role)
+		if err != nil {
+			debugf("tool=update_project_role error=%v", err)
+			return nil, nil, err
+		}
+		users := map[bool][]string{}
+		for remove, refs := range map[bool][]string{false: args.AddUsers, true: args.RemoveUsers} {
+			for _, ref := range refs {
+				u, err := jc.ResolveUser(ctx, ref)
+				if err != nil {
+					return nil, nil, err
+				}
+				users[remove] = append(users[remove], u.AccountID)
+			}
+		}
+		addGroups, err := jc.groupIDs(ctx, args.AddGroups)
+		if err != nil {
+			return nil, nil, err
+		}
+		removeGroups, err := jc.groupIDs(ctx, args.RemoveGroups)
+		if err != nil {
+			return nil, nil, err
+		}
+		var parts []string
+		if n := len(users[false]) + len(addGroups); n > 0 {
+			parts = append(parts, fmt.Sprintf("add %d", n))
+		}
+		if n := len(users[true]) + len(removeGroups); n > 0 {
+			parts = append(parts, fmt.Sprintf("remove %d", n))
+		}
+		ok, err := approveAction(ctx, req.Session, args.Confirm, fmt.Sprintf("In %s, %s actor(s) of the %q role?", project, strings.Join(parts, " and "), role.Name))
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			return nil, nil, fmt.Errorf("change to the %s role declined by the user", role.Name)
+		}
+		if len(users[false]) > 0 || len(addGroups) > 0 {
+			if err := jc.AddRoleActors(ctx, project, role.ID, users[false], addGroups); err != nil {
+				debugf("tool=update_project_role error=%v", err)
+				return nil, nil, err
+			}
+		}
+		for _, id := range users[true] {
+			if err := jc.RemoveRoleActor(ctx, project, role.ID, "user", id); err != nil {
+				debugf("tool=update_project_role error=%v", err)
+				return nil, nil, err
+			}
+		}
+		for _, id := range removeGroups {
+			if err := jc.RemoveRoleActor(ctx, project, role.ID, "groupId", id); err != nil {
+				debugf("tool=update_project_role error=%v", err)
+				return nil, nil, err
+			}
+		}
+		role, err = jc.ProjectRole(ctx, project, strconv.Itoa(role.ID))
+		if err != nil {
+			debugf("tool=update_project_role error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"project": project, "role": viewRole(role)}}, nil, nil
+	})
+}
@@ -0,0 +1,150 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Maintenance windows and the health resource ----
+//
+// During Atlassian maintenance Jira answers 503 with an HTML page. Rather
+// than handing that page to the model, requests back off and retry a few
+// times, then fail with a MaintenanceError, which tool results carry as a
+// structured "jira_maintenance" error. jira://health publishes the state.
+
+// maintenanceBackoff is how long to wait before each retry when Jira does
+// not send Retry-After.
+var maintenanceBackoff = []time.Duration{5 * time.Second, 15 * time.Second}
+
+const maxRetryAfter = 60 * time.Second
+
+type MaintenanceError struct {
+	Status     string
+	RetryAfter time.Duration
+}
+
+func (e *MaintenanceError) Error() string {
+	return fmt.Sprintf("Jira is under maintenance (%s); try again in about %s", e.Status, e.RetryAfter.Round(time.Second))
+}
+
+// maintenanceFrom reports whether resp is a maintenance page. Other 503s
+// are passed through with their body intact.
+func maintenanceFrom(resp *http.Response, attempt int) *MaintenanceError {
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		return nil
+	}
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+	html := strings.Contains(resp.Header.Get("Content-Type"), "html")
+	if !html && !bytes.Contains(bytes.ToLower(b), []byte("maintenance")) {
+		return nil
+	}
+	wait := maintenanceBackoff[min(attempt, len(maintenanceBackoff)-1)]
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs > 0 {
+		wait = min(time.Duration(secs)*time.Second, maxRetryAfter)
+	}
+	return &MaintenanceError{Status: resp.Status, RetryAfter: wait}
+}
+
+// sleepCtx waits for d unless ctx ends first.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+type healthState struct {
+	mu               sync.Mutex
+	maintenanceSince time.Time
+	lastSuccess      time.Time
+	lastMaintenance  *MaintenanceError
+}
+
+func (h *healthState) noteMaintenance(err *MaintenanceError) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.maintenanceSince.IsZero() {
+		h.maintenanceSince = time.Now()
+	}
+	h.lastMaintenance = err
+}
+
+func (h *healthState) noteOK() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.maintenanceSince = time.Time{}
+	h.lastMaintenance = nil
+	h.lastSuccess = time.Now()
+}
+
+type healthReport struct {
+	Instance         string     `json:"instance"`
+	Production       bool       `json:"production"`
+	Maintenance      bool       `json:"maintenance"`
+	MaintenanceSince *time.Time `json:"maintenance_since,omitempty"`
+	RetryAfterSecs   float64    `json:"retry_after_seconds,omitempty"`
+	LastSuccess      *time.Time `json:"last_success,omitempty"`
+}
+
+func (c *JiraClient) healthReport() healthReport {
+	h := &c.health
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	r := healthReport{Instance: c.Instance.Label, Production: c.Instance.Production}
+	if !h.maintenanceSince.IsZero() {
+		since := h.maintenanceSince
+		r.Maintenance, r.MaintenanceSince = true, &since
+		r.RetryAfterSecs = h.lastMaintenance.RetryAfter.Seconds()
+	}
+	if !h.lastSuccess.IsZero() {
+		last := h.lastSuccess
+		r.LastSuccess = &last
+	}
+	return r
+}
+
+// maintenanceResult turns a tool failure caused by maintenance into a
+// structured error.
+func maintenanceResult(res *mcp.CallToolResult, err *MaintenanceError) {
+	res.IsError = true
+	res.StructuredContent = map[string]any{
+		"error":               "jira_maintenance",
+		"message":             err.Error(),
+		"retry_after_seconds": err.RetryAfter.Seconds(),
+	}
+}
+
+func registerHealthResources(server *mcp.Server, jc *JiraClient) {
+	server.AddResource(&mcp.Resource{
+		URI:         "jira://health",
+		Name:        "health",
+		Title:       "Jira Health",
+		Description: "Connection health: maintenance status and last successful request",
+		MIMEType:    "application/json",
+	}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		debugf("resource=jira://health")
+		b, err := json.MarshalIndent(jc.healthReport(), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{
+			URI: req.Params.URI, MIMEType: "application/json", Text: string(b),
+		}}}, nil
+	})
+}
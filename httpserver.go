@@ -0,0 +1,156 @@
+package jira
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Streamable HTTP transport ----
+//
+// RunHTTP serves the tools to any number of clients over the streamable
+// HTTP transport, so one server can be shared by several hosts. Clients
+// authenticate with a bearer token (JIRA_MCP_TOKEN, or the file named by
+// JIRA_MCP_TOKEN_FILE), and may bring their own Jira credentials (see
+// sessionauth.go). On shutdown the server stops accepting connections,
+// lets tool calls in progress finish, and then closes the sessions, which
+// ends their event streams.
+
+const defaultShutdownTimeout = 30 * time.Second
+
+// HTTPOptions configures RunHTTP and HTTPHandler.
+type HTTPOptions struct {
+	// Token is the bearer token clients must send. Empty means
+	// JIRA_MCP_TOKEN; when that is unset too, the endpoint is open.
+	Token string
+
+	// ShutdownTimeout bounds how long shutdown waits for tool calls in
+	// progress (default 30s).
+	ShutdownTimeout time.Duration
+}
+
+func (o *HTTPOptions) token() (string, error) {
+	if o != nil && o.Token != "" {
+		return o.Token, nil
+	}
+	tok, _, err := readSetting("JIRA_MCP_TOKEN")
+	return strings.TrimSpace(tok), err
+}
+
+// HTTPHandler returns the MCP endpoint as an http.Handler, to mount in
+// your own HTTP server. Start the Server first.
+func (s *Server) HTTPHandler(opts *HTTPOptions) (http.Handler, error) {
+	token, err := opts.token()
+	if err != nil {
+		return nil, err
+	}
+	var h http.Handler = mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return s.MCP }, nil)
+	h = s.trackRequests(h)
+	if token != "" {
+		h = bearerAuth(token, h)
+	}
+	return h, nil
+}
+
+// RunHTTP starts the server and serves it on addr until ctx ends, then
+// shuts down gracefully.
+func (s *Server) RunHTTP(ctx context.Context, addr string, opts *HTTPOptions) error {
+	if opts == nil {
+		opts = &HTTPOptions{}
+	}
+	handler, err := s.HTTPHandler(opts)
+	if err != nil {
+		return err
+	}
+	if tok, _ := opts.token(); tok == "" && os.Getenv("JIRA_SESSION_AUTH") != string(sessionAuthRequire) {
+		logger.Printf("WARNING: JIRA_MCP_TOKEN is not set; anyone who can reach %s can use this server's Jira credentials", addr)
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	if err := s.Start(ctx); err != nil {
+		ln.Close()
+		return err
+	}
+	srv := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(ln) }()
+	logger.Printf("Serving MCP over HTTP on %s", ln.Addr())
+
+	var serveErr error
+	select {
+	case serveErr = <-served:
+	case <-ctx.Done():
+	}
+	timeout := opts.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+	sctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+	logger.Printf("Shutting down the HTTP server")
+	stopped := make(chan error, 1)
+	go func() { stopped <- srv.Shutdown(sctx) }()
+	s.drainRequests(sctx)
+	err = s.Shutdown(sctx)
+	if serr := <-stopped; serr != nil {
+		srv.Close()
+		if err == nil {
+			err = serr
+		}
+	}
+	if serveErr != nil && !errors.Is(serveErr, http.ErrServerClosed) {
+		return serveErr
+	}
+	return err
+}
+
+// trackRequests counts the POSTs (client messages, including tool calls)
+// being handled, so shutdown can wait for them. GETs are event streams that
+// only end when their session does.
+func (s *Server) trackRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			s.inflight.Add(1)
+			defer s.inflight.Add(-1)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// drainRequests waits until no POST is being handled, or ctx ends.
+func (s *Server) drainRequests(ctx context.Context) {
+	t := time.NewTicker(50 * time.Millisecond)
+	defer t.Stop()
+	for s.inflight.Load() > 0 {
+		select {
+		case <-ctx.Done():
+			logger.Printf("Shutdown timed out with %d requests in progress", s.inflight.Load())
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// bearerAuth rejects requests without "Authorization: Bearer <token>". It
+// then drops the header, so it is never mistaken for Jira credentials.
+func bearerAuth(token string, next http.Handler) http.Handler {
+	want := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="jira-mcp"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		r.Header.Del("Authorization")
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,80 @@
+package jira
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBearerAuth(t *testing.T) {
+	var reached bool
+	var forwarded string
+	h := bearerAuth("s3cret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached, forwarded = true, r.Header.Get("Authorization")
+	}))
+	tests := []struct {
+		name   string
+		auth   string
+		wantOK bool
+	}{
+		{"none", "", false},
+		{"wrong token", "Bearer nope", false},
+		{"token prefix", "Bearer s3cre", false},
+		{"token with suffix", "Bearer s3cret2", false},
+		{"basic scheme", "Basic s3cret", false},
+		{"lower-case scheme", "bearer s3cret", false},
+		{"right token", "Bearer s3cret", true},
+	}
+	for _, tt := range tests {
+		reached, forwarded = false, ""
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		if tt.auth != "" {
+			req.Header.Set("Authorization", tt.auth)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if reached != tt.wantOK {
+			t.Errorf("%s: reached handler = %t, want %t", tt.name, reached, tt.wantOK)
+		}
+		if !tt.wantOK && (rec.Code != http.StatusUnauthorized || !strings.HasPrefix(rec.Header().Get("WWW-Authenticate"), "Bearer ")) {
+			t.Errorf("%s: status %d, WWW-Authenticate %q; want 401 with a Bearer challenge", tt.name, rec.Code, rec.Header().Get("WWW-Authenticate"))
+		}
+		if forwarded != "" {
+			t.Errorf("%s: handler saw Authorization %q, want it dropped", tt.name, forwarded)
+		}
+	}
+}
+
+func TestHTTPHandlerToken(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       string
+		opts      *HTTPOptions
+		wantToken string // "" when the endpoint is open
+	}{
+		{"open", "", nil, ""},
+		{"from env", "env-token", nil, "env-token"},
+		{"from options", "", &HTTPOptions{Token: "opt-token"}, "opt-token"},
+		{"options win", "env-token", &HTTPOptions{Token: "opt-token"}, "opt-token"},
+	}
+	for _, tt := range tests {
+		t.Setenv("JIRA_MCP_TOKEN", tt.env)
+		h, err := newTestServer(t).HTTPHandler(tt.opts)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		for _, tok := range []string{"", "env-token", "opt-token"} {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{}"))
+			if tok != "" {
+				req.Header.Set("Authorization", "Bearer "+tok)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			wantOpen := tt.wantToken == "" || tok == tt.wantToken
+			if open := rec.Code != http.StatusUnauthorized; open != wantOpen {
+				t.Errorf("%s: request with token %q got %d, want accepted %t", tt.name, tok, rec.Code, wantOpen)
+			}
+		}
+	}
+}
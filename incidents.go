@@ -0,0 +1,217 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Incident reliability metrics (MTTA / MTTR) ----
+
+// incidentStatusMap says which statuses count as acknowledged and resolved.
+// Override with JIRA_INCIDENT_STATUS_MAP, e.g.
+// {"acknowledged": ["Investigating"], "resolved": ["Mitigated", "Closed"]}.
+type incidentStatusMap struct {
+	Acknowledged []string `json:"acknowledged"`
+	Resolved     []string `json:"resolved"`
+}
+
+var defaultIncidentStatuses = incidentStatusMap{
+	Acknowledged: []string{"Acknowledged", "Investigating", "In Progress"},
+	Resolved:     []string{"Resolved", "Done", "Closed"},
+}
+
+type durationStats struct {
+	Count         int     `json:"count"`
+	MeanMinutes   float64 `json:"mean_minutes"`
+	MedianMinutes float64 `json:"median_minutes"`
+	P90Minutes    float64 `json:"p90_minutes"`
+}
+
+type incidentGroup struct {
+	Priority   string        `json:"priority"`
+	Incidents  int           `json:"incidents"`
+	Unacked    int           `json:"unacknowledged"`
+	Unresolved int           `json:"unresolved"`
+	MTTA       durationStats `json:"mtta"`
+	MTTR       durationStats `json:"mttr"`
+}
+
+type incidentMetrics struct {
+	JQL      string            `json:"jql"`
+	Statuses incidentStatusMap `json:"status_mapping"`
+	Overall  incidentGroup     `json:"overall"`
+	ByPrio   []incidentGroup   `json:"by_priority"`
+}
+
+func summarizeDurations(mins []float64) durationStats {
+	if len(mins) == 0 {
+		return durationStats{}
+	}
+	sort.Float64s(mins)
+	var sum float64
+	for _, m := range mins {
+		sum += m
+	}
+	pct := func(p float64) float64 {
+		return mins[int(math.Ceil(p*float64(len(mins))))-1]
+	}
+	round := func(v float64) float64 { return math.Round(v*10) / 10 }
+	return durationStats{
+		Count:         len(mins),
+		MeanMinutes:   round(sum / float64(len(mins))),
+		MedianMinutes: round(pct(0.5)),
+		P90Minutes:    round(pct(0.9)),
+	}
+}
+
+// incidentTimes finds when an issue was acknowledged and resolved. The
+// resolution date wins for resolution when set.
+func incidentTimes(iss *JiraIssue, histories []JiraChangeHistory, st incidentStatusMap) (ack, resolved time.Time) {
+	sort.SliceStable(histories, func(i, j int) bool { return histories[i].Created < histories[j].Created })
+	for _, h := range histories {
+		at, ok := parseJiraTime(h.Created)
+		if !ok {
+			continue
+		}
+		for _, it := range h.Items {
+			if it.Field != "status" {
+				continue
+			}
+			if ack.IsZero() && (containsFold(st.Acknowledged, it.ToString) || containsFold(st.Resolved, it.ToString)) {
+				ack = at
+			}
+			if resolved.IsZero() && containsFold(st.Resolved, it.ToString) {
+				resolved = at
+			}
+		}
+	}
+	if t, ok := parseJiraTime(fieldString(iss.Fields, "resolutiondate")); ok {
+		resolved = t
+	}
+	return ack, resolved
+}
+
+func (c *JiraClient) IncidentMetrics(ctx context.Context, jql string, st incidentStatusMap) (*incidentMetrics, error) {
+	issues, err := c.SearchAllExpanded(ctx, jql, []string{"created", "priority", "resolutiondate"}, []string{"changelog"}, 1000)
+	if err != nil {
+		return nil, err
+	}
+	type acc struct {
+		g        incidentGroup
+		tta, ttr []float64
+	}
+	groups := map[string]*acc{}
+	overall := &acc{g: incidentGroup{Priority: "all"}}
+	for i := range issues {
+		iss := &issues[i]
+		created, ok := parseJiraTime(fieldString(iss.Fields, "created"))
+		if !ok {
+			continue
+		}
+		histories, err := c.fullChangelog(ctx, iss)
+		if err != nil {
+			return nil, err
+		}
+		ack, resolved := incidentTimes(iss, histories, st)
+		prio := fieldString(iss.Fields, "priority", "name")
+		if prio == "" {
+			prio = "None"
+		}
+		g := groups[prio]
+		if g == nil {
+			g = &acc{g: incidentGroup{Priority: prio}}
+			groups[prio] = g
+		}
+		for _, a := range []*acc{g, overall} {
+			a.g.Incidents++
+			if ack.IsZero() {
+				a.g.Unacked++
+			} else {
+				a.tta = append(a.tta, ack.Sub(created).Minutes())
+			}
+			if resolved.IsZero() {
+				a.g.Unresolved++
+			} else {
+				a.ttr = append(a.ttr, resolved.Sub(created).Minutes())
+			}
+		}
+	}
+	finish := func(a *acc) incidentGroup {
+		a.g.MTTA = summarizeDurations(a.tta)
+		a.g.MTTR = summarizeDurations(a.ttr)
+		return a.g
+	}
+	out := &incidentMetrics{JQL: jql, Statuses: st, Overall: finish(overall)}
+	for _, g := range groups {
+		out.ByPrio = append(out.ByPrio, finish(g))
+	}
+	sort.Slice(out.ByPrio, func(i, j int) bool { return out.ByPrio[i].Priority < out.ByPrio[j].Priority })
+	return out, nil
+}
+
+func registerIncidentTools(server *mcp.Server, jc *JiraClient) {
+	statuses := defaultIncidentStatuses
+	if _, err := loadJSONSetting("JIRA_INCIDENT_STATUS_MAP", &statuses); err != nil {
+		logger.Printf("ignoring JIRA_INCIDENT_STATUS_MAP: %v", err)
+		statuses = defaultIncidentStatuses
+	}
+
+	// incident_metrics(project, from, to, issue_types?, acknowledged_statuses?, resolved_statuses?)
+	type incidentArgs struct {
+		Project      string   `json:"project" jsonschema:"Project key"`
+		From         string   `json:"from" jsonschema:"Start date (YYYY-MM-DD), by issue creation"`
+		To           string   `json:"to" jsonschema:"End date (YYYY-MM-DD), inclusive"`
+		IssueTypes   []string `json:"issue_types,omitempty" jsonschema:"Restrict to these issue types, e.g. Incident"`
+		AckStatuses  []string `json:"acknowledged_statuses,omitempty" jsonschema:"Statuses that mean acknowledged (overrides the configured mapping)"`
+		DoneStatuses []string `json:"resolved_statuses,omitempty" jsonschema:"Statuses that mean resolved (overrides the configured mapping)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "incident_metrics",
+		Title:       "Incident Metrics",
+		Description: "Compute mean/median/p90 time to acknowledge (MTTA) and resolve (MTTR) for incidents created in a date range, grouped by priority",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args incidentArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=incident_metrics args={project:%q,from:%q,to:%q}", args.Project, args.From, args.To)
+		from, err := time.Parse("2006-01-02", args.From)
+		if err != nil {
+			return nil, nil, fmt.Errorf("from must be YYYY-MM-DD: %w", err)
+		}
+		to, err := time.Parse("2006-01-02", args.To)
+		if err != nil {
+			return nil, nil, fmt.Errorf("to must be YYYY-MM-DD: %w", err)
+		}
+		st := statuses
+		if len(args.AckStatuses) > 0 {
+			st.Acknowledged = args.AckStatuses
+		}
+		if len(args.DoneStatuses) > 0 {
+			st.Resolved = args.DoneStatuses
+		}
+		jql := fmt.Sprintf(`project = %s AND created >= "%s" AND created < "%s"`,
+			quoteJQL(args.Project), from.Format("2006-01-02"), to.AddDate(0, 0, 1).Format("2006-01-02"))
+		if len(args.IssueTypes) > 0 {
+			quoted := make([]string, len(args.IssueTypes))
+			for i, t := range args.IssueTypes {
+				quoted[i] = quoteJQL(t)
+			}
+			jql += " AND issuetype in (" + strings.Join(quoted, ", ") + ")"
+		}
+		res, err := jc.IncidentMetrics(ctx, jql, st)
+		if err != nil {
+			debugf("tool=incident_metrics error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: res}, nil, nil
+	})
+}
+
+// quoteJQL quotes a value for use in JQL.
+func quoteJQL(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
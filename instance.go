@@ -0,0 +1,241 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Instance labelling and production write barriers ----
+//
+// JIRA_INSTANCE describes the connected site, e.g.
+//
+//	{"label": "acme-prod", "production": true, "writes": "confirm"}
+//
+// writes is allow, confirm (ask the user once per tool call), or deny, and
+// applies whether or not the instance is production. It defaults to confirm
+// on production instances and allow elsewhere. Every
+// tool result is tagged with the label so agents working against several
+// sites cannot mix them up.
+
+type instanceConfig struct {
+	Label      string `json:"label"`
+	Production bool   `json:"production"`
+	Writes     string `json:"writes,omitempty"`
+}
+
+func loadInstanceConfig(baseURL string) (instanceConfig, error) {
+	var ic instanceConfig
+	if _, err := loadJSONSetting("JIRA_INSTANCE", &ic); err != nil {
+		return ic, err
+	}
+	if err := ic.normalize(baseURL); err != nil {
+		return ic, fmt.Errorf("JIRA_INSTANCE: %w", err)
+	}
+	return ic, nil
+}
+
+// normalize fills in the label from baseURL and the default write mode.
+func (ic *instanceConfig) normalize(baseURL string) error {
+	if ic.Label == "" {
+		if u, err := url.Parse(baseURL); err == nil {
+			ic.Label = u.Host
+		}
+	}
+	ic.Writes = strings.ToLower(ic.Writes)
+	switch ic.Writes {
+	case "":
+		ic.Writes = "allow"
+		if ic.Production {
+			ic.Writes = "confirm"
+		}
+	case "allow", "confirm", "deny":
+	default:
+		return fmt.Errorf("unknown writes mode %q (valid: allow, confirm, deny)", ic.Writes)
+	}
+	return nil
+}
+
+func (ic instanceConfig) banner() string {
+	if ic.Production {
+		return fmt.Sprintf("Connected to PRODUCTION Jira instance %q (writes: %s).", ic.Label, ic.Writes)
+	}
+	if ic.Writes != "allow" {
+		return fmt.Sprintf("Connected to non-production Jira instance %q (writes: %s).", ic.Label, ic.Writes)
+	}
+	return fmt.Sprintf("Connected to non-production Jira instance %q.", ic.Label)
+}
+
+// describe names the instance in messages about its write policy.
+func (ic instanceConfig) describe() string {
+	if ic.Production {
+		return fmt.Sprintf("production instance %q", ic.Label)
+	}
+	return fmt.Sprintf("instance %q", ic.Label)
+}
+
+// needsConfirmation reports whether writes to the site must be confirmed
+// by the user.
+func (ic instanceConfig) needsConfirmation() bool {
+	return ic.Writes == "confirm"
+}
+
+// readOnlyPOST lists endpoints that take POST without changing anything:
+// searches and queries whose input is too large for a query string. An
+// entry matches the path exactly, with a query string, or, if it ends in
+// "/", everything beneath it.
+var readOnlyPOST = append(platformPaths(
+	"search", // legacy search
+	"search/jql",
+	"search/approximate-count",
+	"jql/", // parse, match, sanitize, autocompletedata
+	"issue/bulkfetch",
+	"changelog/bulkfetch",
+	"comment/list",
+	"worklog/list",
+	"expression/eval",
+	"expression/evaluate",
+	"expression/analyse",
+	"permissions/check",
+	"permissions/project",
+), "/tempo/worklogs/search")
+
+// platformPaths returns each platform API path under both /rest/api/2/
+// and /rest/api/3/.
+func platformPaths(rel ...string) []string {
+	out := make([]string, 0, 2*len(rel))
+	for _, v := range []string{"/rest/api/2/", "/rest/api/3/"} {
+		for _, r := range rel {
+			out = append(out, v+r)
+		}
+	}
+	return out
+}
+
+func isWrite(method, path string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	case http.MethodPost:
+		for _, p := range readOnlyPOST {
+			if path == p || strings.HasPrefix(path, p+"?") || (strings.HasSuffix(p, "/") && strings.HasPrefix(path, p)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// toolCall is per-call state threaded through the context by
+// instanceMiddleware.
+type toolCall struct {
+	Name    string
+	Session *mcp.ServerSession
+
+	mu          sync.Mutex
+	confirmed   bool
+	maintenance *MaintenanceError
+}
+
+func (tc *toolCall) noteMaintenance(err *MaintenanceError) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.maintenance = err
+}
+
+type toolCallKey struct{}
+
+func currentToolCall(ctx context.Context) *toolCall {
+	tc, _ := ctx.Value(toolCallKey{}).(*toolCall)
+	return tc
+}
+
+// checkWrite enforces the instance's write policy before a mutating request.
+func (c *JiraClient) checkWrite(ctx context.Context, method, path string) error {
+	ic := c.forSite(ctx).Instance
+	if ic.Writes == "allow" || !isWrite(method, path) {
+		return nil
+	}
+	if ic.Writes == "deny" {
+		return fmt.Errorf("writes to %s are disabled (%s %s)", ic.describe(), method, path)
+	}
+	tc := currentToolCall(ctx)
+	if tc == nil {
+		return fmt.Errorf("write to %s needs confirmation outside a tool call (%s %s)", ic.describe(), method, path)
+	}
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if tc.confirmed {
+		return nil
+	}
+	target := fmt.Sprintf("Jira instance %q", ic.Label)
+	if ic.Production {
+		target = "PRODUCTION " + target
+	}
+	ok, err := confirmAction(ctx, tc.Session, fmt.Sprintf("%s will write to %s (%s %s). Proceed?", tc.Name, target, method, path))
+	if errors.Is(err, errConfirmationUnavailable) {
+		return fmt.Errorf("writes to %s need confirmation, but the client does not support elicitation", ic.describe())
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("write to %s declined by the user", ic.describe())
+	}
+	tc.confirmed = true
+	return nil
+}
+
+// instanceMiddleware records the current tool call in the context and tags
+// every tool result with the label of the site it ran against. Failures
+// caused by Jira maintenance get a structured error.
+func instanceMiddleware(jc *JiraClient) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			ctreq, ok := req.(*mcp.CallToolRequest)
+			if method != "tools/call" || !ok {
+				return next(ctx, method, req)
+			}
+			tc := &toolCall{Name: ctreq.Params.Name, Session: ctreq.Session}
+			res, err := next(context.WithValue(ctx, toolCallKey{}, tc), method, req)
+			if ctres, ok := res.(*mcp.CallToolResult); ok && err == nil {
+				tc.mu.Lock()
+				if tc.maintenance != nil && ctres.IsError {
+					maintenanceResult(ctres, tc.maintenance)
+				}
+				tc.mu.Unlock()
+				tagResult(ctres, jc.forSite(ctx).Instance)
+			}
+			return res, err
+		}
+	}
+}
+
+func tagResult(res *mcp.CallToolResult, ic instanceConfig) {
+	tag := map[string]any{"label": ic.Label, "production": ic.Production}
+	if res.Meta == nil {
+		res.Meta = mcp.Meta{}
+	}
+	res.Meta["jira/instance"] = tag
+	if res.StructuredContent != nil {
+		b, err := json.Marshal(res.StructuredContent)
+		var m map[string]any
+		if err == nil && json.Unmarshal(b, &m) == nil {
+			m["instance"] = tag
+			res.StructuredContent = m
+		}
+	}
+	for _, ct := range res.Content {
+		if tc, ok := ct.(*mcp.TextContent); ok {
+			tc.Text = "[" + ic.Label + "] " + tc.Text
+			break
+		}
+	}
+}
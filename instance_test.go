@@ -0,0 +1,68 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestIsWrite(t *testing.T) {
+	tests := []struct {
+		method, path string
+		want         bool
+	}{
+		{http.MethodGet, "/rest/api/3/issue/PROJ-1", false},
+		{http.MethodHead, "/rest/api/3/issue/PROJ-1", false},
+		{http.MethodPut, "/rest/api/3/issue/PROJ-1", true},
+		{http.MethodDelete, "/rest/api/3/issue/PROJ-1", true},
+		{http.MethodPost, "/rest/api/3/issue", true},
+		{http.MethodPost, "/rest/api/3/issue/bulk", true},
+		{http.MethodPost, "/rest/api/3/issue/bulkfetch", false},
+		{http.MethodPost, "/rest/api/3/search", false},
+		{http.MethodPost, "/rest/api/2/search", false},
+		{http.MethodPost, "/rest/api/3/search/jql", false},
+		{http.MethodPost, "/rest/api/3/search?validateQuery=strict", false},
+		{http.MethodPost, "/rest/api/3/searchable", true},
+		{http.MethodPost, "/rest/api/3/jql/parse?validation=strict", false},
+		{http.MethodPost, "/rest/api/2/jql/match", false},
+		{http.MethodPost, "/rest/api/3/worklog/list", false},
+		{http.MethodPost, "/rest/api/2/expression/eval", false},
+		{http.MethodPost, "/rest/api/3/issue/PROJ-1/comment", true},
+		{http.MethodPost, "/rest/agile/1.0/sprint", true},
+		{http.MethodPost, "/tempo/worklogs", true},
+		{http.MethodPost, "/tempo/worklogs/search", false},
+	}
+	for _, tt := range tests {
+		if got := isWrite(tt.method, tt.path); got != tt.want {
+			t.Errorf("isWrite(%s, %s) = %t, want %t", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCheckWrite(t *testing.T) {
+	tests := []struct {
+		name       string
+		production bool
+		writes     string
+		confirmed  bool
+		method     string
+		wantErr    bool
+	}{
+		{"allow", false, "allow", false, http.MethodPost, false},
+		{"production allow", true, "allow", false, http.MethodPost, false},
+		{"deny", false, "deny", false, http.MethodPost, true},
+		{"production deny", true, "deny", false, http.MethodPost, true},
+		{"deny read", false, "deny", false, http.MethodGet, false},
+		{"confirm unconfirmed", false, "confirm", false, http.MethodPut, true},
+		{"production confirm unconfirmed", true, "confirm", false, http.MethodPut, true},
+		{"confirm confirmed", false, "confirm", true, http.MethodPut, false},
+	}
+	for _, tt := range tests {
+		jc := &JiraClient{site: "default", Instance: instanceConfig{Label: "x", Production: tt.production, Writes: tt.writes}}
+		ctx := context.WithValue(context.Background(), toolCallKey{}, &toolCall{Name: "t", confirmed: tt.confirmed})
+		err := jc.checkWrite(ctx, tt.method, "/rest/api/3/issue/PROJ-1")
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: checkWrite = %v, want error %t", tt.name, err, tt.wantErr)
+		}
+	}
+}
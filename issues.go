@@ -0,0 +1,791 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Issue lifecycle, comments and links ----
+
+// errDeleteDisabled is returned by DeleteIssue when JIRA_DISABLE_DELETE=1.
+var errDeleteDisabled = errors.New("deleting issues is disabled on this server (JIRA_DISABLE_DELETE)")
+
+// deleteDisabled reports whether JIRA_DISABLE_DELETE=1 switches off issue
+// deletion, for the delete_issue tool and for anything else that deletes
+// issues, such as workflow rollback.
+func deleteDisabled() bool {
+	return os.Getenv("JIRA_DISABLE_DELETE") == "1"
+}
+
+func (c *JiraClient) DeleteIssue(ctx context.Context, key string, deleteSubtasks bool) error {
+	if deleteDisabled() {
+		return errDeleteDisabled
+	}
+	path := "/rest/api/3/issue/" + url.PathEscape(key)
+	if deleteSubtasks {
+		path += "?deleteSubtasks=true"
+	}
+	return c.doJSON(ctx, http.MethodDelete, path, nil, nil)
+}
+
+func (c *JiraClient) DeleteComment(ctx context.Context, key, id string) error {
+	if err := c.doJSON(ctx, http.MethodDelete, "/rest/api/3/issue/"+url.PathEscape(key)+"/comment/"+url.PathEscape(id), nil, nil); err != nil {
+		return err
+	}
+	c.noteCommentDeleted(key, id)
+	return nil
+}
+
+// LinkIssues creates an issue link ("inward <type.inward> outward", e.g.
+// "PROJ-2 is blocked by PROJ-1" for type Blocks with inward PROJ-2) and
+// returns the id of the new link. Jira does not return the id on creation,
+// so it is looked up on the inward issue afterwards.
+func (c *JiraClient) LinkIssues(ctx context.Context, linkType, inwardKey, outwardKey string) (string, error) {
+	body := map[string]any{
+		"type":         map[string]any{"name": linkType},
+		"inwardIssue":  map[string]any{"key": inwardKey},
+		"outwardIssue": map[string]any{"key": outwardKey},
+	}
+	if err := c.doJSON(ctx, http.MethodPost, "/rest/api/3/issueLink", body, nil); err != nil {
+		return "", err
+	}
+	var iss JiraIssue
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/issue/"+url.PathEscape(inwardKey)+"?fields=issuelinks", nil, &iss); err != nil {
+		return "", err
+	}
+	for _, l := range fieldList(iss.Fields, "issuelinks") {
+		lm, _ := l.(map[string]any)
+		if !strings.EqualFold(fieldString(lm, "type", "name"), linkType) {
+			continue
+		}
+		if strings.EqualFold(fieldString(lm, "outwardIssue", "key"), outwardKey) || strings.EqualFold(fieldString(lm, "inwardIssue", "key"), outwardKey) {
+			id := fieldString(lm, "id")
+			noteCreated(ctx, "link", id, inwardKey, outwardKey)
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("link %s %s -> %s created but not found on %s", linkType, inwardKey, outwardKey, inwardKey)
+}
+
+// maxBulkCreate is the most issues /rest/api/3/issue/bulk accepts at once.
+const maxBulkCreate = 50
+
+// CreateIssuesBulk creates issues from field maps in batches. keys[i] is
+// the key created for issues[i], or "" if it failed; failures are
+// described in errs by index.
+func (c *JiraClient) CreateIssuesBulk(ctx context.Context, issues []map[string]any) (keys []string, errs map[int]string, err error) {
+	withDefaults := make([]map[string]any, len(issues))
+	addedReporter := make([]bool, len(issues))
+	for i, f := range issues {
+		if withDefaults[i], addedReporter[i], err = c.withProjectDefaults(ctx, f); err != nil {
+			return nil, nil, err
+		}
+	}
+	keys, errs, err = c.createIssueBatches(ctx, withDefaults)
+	if err != nil {
+		return keys, errs, err
+	}
+	// Issues refused only for a defaulted reporter are retried without it.
+	var retry []int
+	var retryFields []map[string]any
+	for i, msg := range errs {
+		if addedReporter[i] && reporterRejected(msg) {
+			delete(withDefaults[i], "reporter")
+			retry = append(retry, i)
+			retryFields = append(retryFields, withDefaults[i])
+		}
+	}
+	if len(retry) == 0 {
+		return keys, errs, nil
+	}
+	logger.Printf("JIRA_PROJECT_DEFAULTS: reporter not accepted, creating %d issue(s) without it", len(retry))
+	rkeys, rerrs, err := c.createIssueBatches(ctx, retryFields)
+	for j, i := range retry {
+		keys[i] = rkeys[j]
+		if msg, ok := rerrs[j]; ok {
+			errs[i] = msg
+		} else if rkeys[j] != "" {
+			delete(errs, i)
+		}
+	}
+	return keys, errs, err
+}
+
+func (c *JiraClient) createIssueBatches(ctx context.Context, issues []map[string]any) (keys []string, errs map[int]string, err error) {
+	keys = make([]string, len(issues))
+	errs = map[int]string{}
+	for start := 0; start < len(issues); start += maxBulkCreate {
+		batch := issues[start:min(start+maxBulkCreate, len(issues))]
+		updates := make([]any, len(batch))
+		for i, f := range batch {
+			updates[i] = map[string]any{"fields": f}
+		}
+		var res struct {
+			Issues []JiraIssue `json:"issues"`
+			Errors []struct {
+				FailedElementNumber int `json:"failedElementNumber"`
+				ElementErrors       struct {
+					ErrorMessages []string          `json:"errorMessages"`
+					Errors        map[string]string `json:"errors"`
+				} `json:"elementErrors"`
+			} `json:"errors"`
+		}
+		// Partial failures come back as 201 with errors listed; a 400 means
+		// every element failed, with the same body.
+		if err := c.doJSON(ctx, http.MethodPost, "/rest/api/3/issue/bulk", map[string]any{"issueUpdates": updates}, &res); err != nil {
+			var je *JiraError
+			if !errors.As(err, &je) || je.StatusCode != http.StatusBadRequest ||
+				json.Unmarshal([]byte(je.Body), &res) != nil || len(res.Errors) == 0 {
+				return keys, errs, err
+			}
+		}
+		failed := map[int]bool{}
+		for _, e := range res.Errors {
+			failed[e.FailedElementNumber] = true
+			msgs := append([]string{}, e.ElementErrors.ErrorMessages...)
+			for f, m := range e.ElementErrors.Errors {
+				msgs = append(msgs, f+": "+m)
+			}
+			sort.Strings(msgs)
+			errs[start+e.FailedElementNumber] = strings.Join(msgs, "; ")
+		}
+		// Created issues are listed in request order, skipping failures.
+		next := 0
+		for i := range batch {
+			if failed[i] || next >= len(res.Issues) {
+				continue
+			}
+			keys[start+i] = res.Issues[next].Key
+			c.recordAction(ctx, actionCreate, res.Issues[next].Key, "")
+			noteCreated(ctx, "issue", res.Issues[next].Key)
+			next++
+		}
+	}
+	return keys, errs, nil
+}
+
+func (c *JiraClient) DeleteIssueLink(ctx context.Context, id string) error {
+	return c.doJSON(ctx, http.MethodDelete, "/rest/api/3/issueLink/"+url.PathEscape(id), nil, nil)
+}
+
+type JiraIssueType struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Description    string `json:"description,omitempty"`
+	Subtask        bool   `json:"subtask"`
+	HierarchyLevel int    `json:"hierarchyLevel,omitempty"`
+}
+
+// CreateMetaIssueTypes lists the issue types that can be created in a project.
+func (c *JiraClient) CreateMetaIssueTypes(ctx context.Context, projectKey string) ([]JiraIssueType, error) {
+	var out struct {
+		IssueTypes []JiraIssueType `json:"issueTypes"`
+		Values     []JiraIssueType `json:"values"`
+	}
+	path := "/rest/api/3/issue/createmeta/" + url.PathEscape(projectKey) + "/issuetypes?maxResults=200"
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return append(out.IssueTypes, out.Values...), nil
+}
+
+// SubtaskType returns the project's subtask issue type.
+func (c *JiraClient) SubtaskType(ctx context.Context, projectKey string) (*JiraIssueType, error) {
+	types, err := c.CreateMetaIssueTypes(ctx, projectKey)
+	if err != nil {
+		return nil, err
+	}
+	for i := range types {
+		if types[i].Subtask {
+			return &types[i], nil
+		}
+	}
+	return nil, fmt.Errorf("project %s has no subtask issue type", projectKey)
+}
+
+// CreateMetaField describes one field on the create screen of an issue type.
+type CreateMetaField struct {
+	FieldID         string         `json:"fieldId"`
+	Name            string         `json:"name"`
+	Required        bool           `json:"required"`
+	Schema          map[string]any `json:"schema,omitempty"`
+	HasDefaultValue bool           `json:"hasDefaultValue,omitempty"`
+	AllowedValues   []any          `json:"allowedValues,omitempty"`
+}
+
+// CreateMetaFields lists the fields that can be set when creating an issue
+// of issueTypeID in a project.
+func (c *JiraClient) CreateMetaFields(ctx context.Context, projectKey, issueTypeID string) ([]CreateMetaField, error) {
+	var all []CreateMetaField
+	for startAt := 0; ; {
+		var page struct {
+			Total  int               `json:"total"`
+			Fields []CreateMetaField `json:"fields"`
+			Values []CreateMetaField `json:"values"`
+		}
+		path := fmt.Sprintf("/rest/api/3/issue/createmeta/%s/issuetypes/%s?startAt=%d&maxResults=200",
+			url.PathEscape(projectKey), url.PathEscape(issueTypeID), startAt)
+		if err := c.doJSON(ctx, http.MethodGet, path, nil, &page); err != nil {
+			return nil, err
+		}
+		got := append(page.Fields, page.Values...)
+		all = append(all, got...)
+		startAt += len(got)
+		if len(got) == 0 || startAt >= page.Total {
+			return all, nil
+		}
+	}
+}
+
+// issueTypeByName finds a creatable issue type by name or id.
+func issueTypeByName(types []JiraIssueType, name string) (*JiraIssueType, error) {
+	names := make([]string, len(types))
+	for i := range types {
+		if strings.EqualFold(types[i].Name, name) || types[i].ID == name {
+			return &types[i], nil
+		}
+		names[i] = types[i].Name
+	}
+	return nil, fmt.Errorf("unknown issue type %q; available: %s", name, strings.Join(names, ", "))
+}
+
+// createMetaView is a create field as the agent needs it: id, whether it
+// must be given, its type, and the allowed values by id and name.
+type createMetaView struct {
+	ID            string              `json:"id"`
+	Name          string              `json:"name"`
+	Required      bool                `json:"required"`
+	Type          string              `json:"type,omitempty"`
+	Items         string              `json:"items,omitempty"`
+	HasDefault    bool                `json:"has_default,omitempty"`
+	AllowedValues []map[string]string `json:"allowed_values,omitempty"`
+}
+
+func viewCreateMeta(f CreateMetaField) createMetaView {
+	v := createMetaView{
+		ID: f.FieldID, Name: f.Name, Required: f.Required, HasDefault: f.HasDefaultValue,
+		Type: fieldString(f.Schema, "type"), Items: fieldString(f.Schema, "items"),
+	}
+	for _, a := range f.AllowedValues {
+		am, _ := a.(map[string]any)
+		av := map[string]string{"id": fieldString(am, "id")}
+		for _, k := range []string{"name", "value", "key"} {
+			if s := fieldString(am, k); s != "" {
+				av[k] = s
+				break
+			}
+		}
+		v.AllowedValues = append(v.AllowedValues, av)
+	}
+	return v
+}
+
+// EditMetaField describes one field in an issue's edit metadata.
+type EditMetaField struct {
+	Name          string         `json:"name"`
+	Required      bool           `json:"required"`
+	Schema        map[string]any `json:"schema,omitempty"`
+	Operations    []string       `json:"operations,omitempty"`
+	AllowedValues []any          `json:"allowedValues,omitempty"`
+}
+
+func (c *JiraClient) EditMeta(ctx context.Context, key string) (map[string]EditMetaField, error) {
+	var out struct {
+		Fields map[string]EditMetaField `json:"fields"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/issue/"+url.PathEscape(key)+"/editmeta", nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Fields, nil
+}
+
+// UpdateIssue edits an issue. fields are set outright; update holds
+// field operations such as {"labels": [{"add": "x"}]}.
+func (c *JiraClient) UpdateIssue(ctx context.Context, key string, fields, update map[string]any) error {
+	body := map[string]any{}
+	if len(fields) > 0 {
+		body["fields"] = fields
+	}
+	if len(update) > 0 {
+		body["update"] = update
+	}
+	return c.doJSON(ctx, http.MethodPut, "/rest/api/3/issue/"+url.PathEscape(key), body, nil)
+}
+
+// allowedValueNames lists the display names of a field's allowed values.
+func allowedValueNames(f EditMetaField) []string {
+	var names []string
+	for _, v := range f.AllowedValues {
+		vm, _ := v.(map[string]any)
+		for _, k := range []string{"name", "value", "key"} {
+			if s := fieldString(vm, k); s != "" {
+				names = append(names, s)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// hasAllowedValueID reports whether id is the id of one of f's allowed
+// values.
+func hasAllowedValueID(f EditMetaField, id string) bool {
+	for _, v := range f.AllowedValues {
+		vm, _ := v.(map[string]any)
+		if fieldString(vm, "id") == id {
+			return true
+		}
+	}
+	return false
+}
+
+// coerceEditValue validates value against the field's edit metadata and
+// turns bare strings into the {"name"}/{"value"} objects Jira expects for
+// fields with a fixed set of allowed values, or {"id"} for a string that
+// is one of their ids.
+func coerceEditValue(id string, f EditMetaField, value any) (any, error) {
+	allowed := allowedValueNames(f)
+	if len(allowed) == 0 {
+		return value, nil
+	}
+	check := func(v any) (any, error) {
+		s, ok := v.(string)
+		if !ok {
+			return v, nil // already an object; let Jira validate ids
+		}
+		if !containsFold(allowed, s) {
+			if hasAllowedValueID(f, s) {
+				return map[string]any{"id": s}, nil
+			}
+			return nil, fmt.Errorf("invalid value %q for %s (%s); allowed: %s (or their ids)", s, f.Name, id, strings.Join(allowed, ", "))
+		}
+		if fieldString(f.Schema, "type") == "option" || fieldString(f.Schema, "items") == "option" {
+			return map[string]any{"value": s}, nil
+		}
+		return map[string]any{"name": s}, nil
+	}
+	if list, ok := value.([]any); ok {
+		out := make([]any, len(list))
+		for i, v := range list {
+			cv, err := check(v)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = cv
+		}
+		return out, nil
+	}
+	return check(value)
+}
+
+// validateEdit checks fields against editmeta, returning the coerced fields
+// or an error that lists what can be edited.
+func validateEdit(key string, meta map[string]EditMetaField, fields map[string]any) (map[string]any, error) {
+	out := make(map[string]any, len(fields))
+	for id, v := range fields {
+		f, ok := meta[id]
+		if !ok {
+			editable := make([]string, 0, len(meta))
+			for mid, mf := range meta {
+				editable = append(editable, fmt.Sprintf("%s (%s)", mf.Name, mid))
+			}
+			sort.Strings(editable)
+			return nil, fmt.Errorf("field %s cannot be set on %s; settable fields: %s", id, key, strings.Join(editable, ", "))
+		}
+		if v == nil && f.Required {
+			return nil, fmt.Errorf("field %s (%s) is required and cannot be cleared", f.Name, id)
+		}
+		cv, err := coerceEditValue(id, f, v)
+		if err != nil {
+			return nil, err
+		}
+		out[id] = cv
+	}
+	return out, nil
+}
+
+// fieldChange is one line of an update preview: a field's current value
+// and the value being written, both in display form.
+type fieldChange struct {
+	Field string `json:"field"`
+	Name  string `json:"name,omitempty"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// displayValue reduces a field value to what a person would see: Markdown
+// for rich text, names for objects, and lists of those.
+func displayValue(v any) any {
+	switch t := v.(type) {
+	case *adfNode:
+		return adfToMarkdown(t)
+	case map[string]any:
+		if t["type"] == "doc" {
+			return adfToMarkdown(t)
+		}
+		if s := valueStrings(t); len(s) > 0 {
+			return s[0]
+		}
+		return t
+	case []any:
+		out := make([]any, 0, len(t))
+		for _, e := range t {
+			out = append(out, displayValue(e))
+		}
+		return out
+	case []string:
+		out := make([]any, 0, len(t))
+		for _, e := range t {
+			out = append(out, e)
+		}
+		return out
+	}
+	return v
+}
+
+// diffFields compares the fields being written with the issue's current
+// values, returning the changes and the ids of fields that would not change.
+func diffFields(meta map[string]EditMetaField, current, fields map[string]any) ([]fieldChange, []string) {
+	ids := make([]string, 0, len(fields))
+	for id := range fields {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var changes []fieldChange
+	var unchanged []string
+	for _, id := range ids {
+		from, to := displayValue(current[id]), displayValue(fields[id])
+		if reflect.DeepEqual(from, to) {
+			unchanged = append(unchanged, id)
+			continue
+		}
+		changes = append(changes, fieldChange{Field: id, Name: meta[id].Name, From: from, To: to})
+	}
+	return changes, unchanged
+}
+
+// editConflict compares the updated timestamp an edit was based on with the
+// issue's current one. When they differ it returns a conflict result listing
+// the changes made since, so the caller can re-read and decide. Jira cannot
+// make the check and the write atomic, so this narrows the window for
+// clobbering a concurrent edit rather than closing it.
+func (c *JiraClient) editConflict(ctx context.Context, key, expected, actual string) (map[string]any, error) {
+	want, ok := parseJiraTime(expected)
+	if !ok {
+		return nil, fmt.Errorf("expected_updated %q is not a timestamp (use the issue's updated field as returned)", expected)
+	}
+	got, _ := parseJiraTime(actual)
+	if got.Equal(want) {
+		return nil, nil
+	}
+	conflict := map[string]any{
+		"error":            "conflict",
+		"message":          fmt.Sprintf("%s changed after %s (now updated %s); nothing was written. Re-read the issue and retry with its current updated value", key, expected, actual),
+		"key":              key,
+		"expected_updated": expected,
+		"actual_updated":   actual,
+	}
+	histories, err := c.IssueChangelog(ctx, key)
+	if err != nil {
+		debugf("conflict changelog %s: %v", key, err)
+		return conflict, nil
+	}
+	var since []JiraChangeHistory
+	for _, h := range histories {
+		if t, ok := parseJiraTime(h.Created); ok && t.After(want) {
+			since = append(since, h)
+		}
+	}
+	conflict["changes_since"] = activityTimeline(nil, since, time.Time{})
+	return conflict, nil
+}
+
+func registerIssueTools(server *mcp.Server, jc *JiraClient) {
+	// update_issue(key, summary?, description?, priority?, labels?, components?, fix_versions?, affected_versions?, due_date?, fields?, custom_fields?, expected_updated?, preview?, confidence?)
+	type updateIssueArgs struct {
+		Key         string         `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+		Summary     string         `json:"summary,omitempty"`
+		Description string         `json:"description,omitempty" jsonschema:"New description in Markdown"`
+		Priority    string         `json:"priority,omitempty" jsonschema:"Priority name, e.g. High"`
+		Labels      []string       `json:"labels,omitempty" jsonschema:"Replaces the full label set"`
+		Components  []string       `json:"components,omitempty" jsonschema:"Component names or ids; replaces the full component set"`
+		FixVersions []string       `json:"fix_versions,omitempty" jsonschema:"Fix version names or ids; replaces the full set"`
+		Affects     []string       `json:"affected_versions,omitempty" jsonschema:"Affected version names or ids; replaces the full set"`
+		DueDate     string         `json:"due_date,omitempty" jsonschema:"Due date as YYYY-MM-DD; use the fields map with null to clear"`
+		Fields      map[string]any `json:"fields,omitempty" jsonschema:"Other fields keyed by field id, field name, or configured alias; null clears a field"`
+		Custom      map[string]any `json:"custom_fields,omitempty" jsonschema:"Custom field values keyed by field name, given plainly (e.g. {\"Story Points\": 5}); values are converted to the field's type and null clears a field"`
+		Expected    string         `json:"expected_updated,omitempty" jsonschema:"The issue's updated timestamp when you read it (as returned by get_issue or a preview); the write is refused if the issue changed since"`
+		Preview     bool           `json:"preview,omitempty" jsonschema:"Only return the change preview; nothing is written"`
+		Confidence  string         `json:"confidence,omitempty" jsonschema:"How sure you are of this edit (low, medium, high); echoed in the result for reviewers"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "update_issue",
+		Title:       "Update Issue",
+		Description: "Edit fields of an existing Jira issue. Values are validated against the issue's edit metadata. The result lists each change as from/to; preview=true returns that list without writing. Pass expected_updated to refuse the edit if someone changed the issue after you read it",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args updateIssueArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=update_issue args={key:%q,fields:%d,custom:%d,expected:%q,preview:%t}", args.Key, len(args.Fields), len(args.Custom), args.Expected, args.Preview)
+		switch args.Confidence {
+		case "", "low", "medium", "high":
+		default:
+			return nil, nil, fmt.Errorf("confidence must be low, medium, or high, not %q", args.Confidence)
+		}
+		fields, err := jc.resolveFieldKeys(ctx, args.Fields)
+		if err != nil {
+			return nil, nil, err
+		}
+		if fields == nil {
+			fields = map[string]any{}
+		}
+		custom, err := jc.coerceCustomFields(ctx, args.Custom)
+		if err != nil {
+			return nil, nil, err
+		}
+		for k, v := range custom {
+			fields[k] = v
+		}
+		if args.Summary != "" {
+			fields["summary"] = args.Summary
+		}
+		if args.Description != "" {
+			fields["description"] = jc.richText(ctx, args.Description)
+		}
+		if args.Priority != "" {
+			fields["priority"] = args.Priority
+		}
+		if args.Labels != nil {
+			fields["labels"] = args.Labels
+		}
+		project, _, _ := strings.Cut(args.Key, "-")
+		if args.Components != nil {
+			refs, err := jc.componentRefs(ctx, project, args.Components)
+			if err != nil {
+				return nil, nil, err
+			}
+			fields["components"] = refs
+		}
+		for id, names := range map[string][]string{"fixVersions": args.FixVersions, "versions": args.Affects} {
+			if names == nil {
+				continue
+			}
+			refs, err := jc.versionRefs(ctx, project, names)
+			if err != nil {
+				return nil, nil, err
+			}
+			fields[id] = refs
+		}
+		if args.DueDate != "" {
+			if _, err := time.Parse("2006-01-02", args.DueDate); err != nil {
+				return nil, nil, fmt.Errorf("due_date must be YYYY-MM-DD: %w", err)
+			}
+			fields["duedate"] = args.DueDate
+		}
+		if len(fields) == 0 {
+			return nil, nil, errors.New("nothing to update")
+		}
+		meta, err := jc.EditMeta(ctx, args.Key)
+		if err != nil {
+			debugf("tool=update_issue editmeta error=%v", err)
+			return nil, nil, err
+		}
+		fields, err = validateEdit(args.Key, meta, fields)
+		if err != nil {
+			return nil, nil, err
+		}
+		ids := make([]string, 0, len(fields))
+		for id := range fields {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		cur, err := jc.issueFields(ctx, args.Key, strings.Join(append(ids, "updated"), ","))
+		if err != nil {
+			debugf("tool=update_issue error=%v", err)
+			return nil, nil, err
+		}
+		updated := fieldString(cur.Fields, "updated")
+		if args.Expected != "" {
+			if conflict, err := jc.editConflict(ctx, args.Key, args.Expected, updated); err != nil {
+				return nil, nil, err
+			} else if conflict != nil {
+				return &mcp.CallToolResult{IsError: true, StructuredContent: conflict}, nil, nil
+			}
+		}
+		changes, unchanged := diffFields(meta, cur.Fields, fields)
+		out := map[string]any{"key": args.Key, "changes": changes}
+		if len(unchanged) > 0 {
+			out["unchanged_fields"] = unchanged
+		}
+		if args.Confidence != "" {
+			out["confidence"] = args.Confidence
+		}
+		if args.Preview {
+			out["preview"] = true
+			out["updated"] = updated // pass as expected_updated to apply exactly this preview
+			return &mcp.CallToolResult{StructuredContent: out}, nil, nil
+		}
+		if err := jc.UpdateIssue(ctx, args.Key, fields, nil); err != nil {
+			debugf("tool=update_issue error=%v", err)
+			return nil, nil, err
+		}
+		out["updated_fields"] = ids
+		return &mcp.CallToolResult{StructuredContent: out}, nil, nil
+	})
+
+	// get_create_metadata(project?, issue_type?, required_only?)
+	type createMetaArgs struct {
+		Project      string `json:"project,omitempty" jsonschema:"Project key (default the focus project)"`
+		IssueType    string `json:"issue_type,omitempty" jsonschema:"Issue type name or id; omit to list the creatable issue types"`
+		RequiredOnly bool   `json:"required_only,omitempty" jsonschema:"Only return required fields"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_create_metadata",
+		Title:       "Get Create Metadata",
+		Description: "Show which fields an issue type's create screen has: ids, which are required, and allowed values. Call before create_issue to avoid missing required custom fields. Without issue_type, lists the project's creatable issue types",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args createMetaArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=get_create_metadata args={project:%q,type:%q,required:%t}", args.Project, args.IssueType, args.RequiredOnly)
+		project := args.Project
+		if f := jc.focusFor(req.Session); project == "" && f != nil {
+			project = f.Project
+		}
+		if project == "" {
+			return nil, nil, errors.New("project is required (no focus project set)")
+		}
+		types, err := jc.CreateMetaIssueTypes(ctx, project)
+		if err != nil {
+			debugf("tool=get_create_metadata error=%v", err)
+			return nil, nil, err
+		}
+		if args.IssueType == "" {
+			return &mcp.CallToolResult{StructuredContent: map[string]any{"project": project, "issue_types": types}}, nil, nil
+		}
+		it, err := issueTypeByName(types, args.IssueType)
+		if err != nil {
+			return nil, nil, err
+		}
+		meta, err := jc.CreateMetaFields(ctx, project, it.ID)
+		if err != nil {
+			debugf("tool=get_create_metadata error=%v", err)
+			return nil, nil, err
+		}
+		fields := []createMetaView{}
+		for _, f := range meta {
+			if f.Required || !args.RequiredOnly {
+				fields = append(fields, viewCreateMeta(f))
+			}
+		}
+		// Required fields first, then by name.
+		sort.SliceStable(fields, func(i, j int) bool {
+			if fields[i].Required != fields[j].Required {
+				return fields[i].Required
+			}
+			return fields[i].Name < fields[j].Name
+		})
+		return &mcp.CallToolResult{StructuredContent: map[string]any{
+			"project": project, "issue_type": it, "fields": fields,
+		}}, nil, nil
+	})
+
+	// delete_issue is destructive and can be switched off for conservative
+	// deployments with JIRA_DISABLE_DELETE=1.
+	if deleteDisabled() {
+		debugf("delete_issue disabled by JIRA_DISABLE_DELETE")
+	} else {
+		// delete_issue(key, delete_subtasks?, confirm?)
+		type deleteIssueArgs struct {
+			Key            string `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+			DeleteSubtasks bool   `json:"delete_subtasks,omitempty" jsonschema:"Also delete subtasks (required if the issue has any)"`
+			Confirm        bool   `json:"confirm,omitempty" jsonschema:"Only for clients without elicitation: set once the user has approved the deletion"`
+		}
+		mcp.AddTool(server, &mcp.Tool{
+			Name:        "delete_issue",
+			Title:       "Delete Issue",
+			Description: "Permanently delete a Jira issue (and optionally its subtasks). Asks the user to confirm; clients without elicitation pass confirm=true once the user has approved",
+			Annotations: &mcp.ToolAnnotations{DestructiveHint: ptr(true)},
+		}, func(ctx context.Context, req *mcp.CallToolRequest, args deleteIssueArgs) (*mcp.CallToolResult, any, error) {
+			debugf("tool=delete_issue args={key:%q,subtasks:%t,confirm:%t}", args.Key, args.DeleteSubtasks, args.Confirm)
+			ok, err := approveAction(ctx, req.Session, args.Confirm, fmt.Sprintf("Permanently delete %s?", args.Key))
+			if err != nil {
+				return nil, nil, err
+			}
+			if !ok {
+				return nil, nil, fmt.Errorf("deletion of %s declined by the user", args.Key)
+			}
+			if err := jc.DeleteIssue(ctx, args.Key, args.DeleteSubtasks); err != nil {
+				debugf("tool=delete_issue error=%v", err)
+				return nil, nil, err
+			}
+			return &mcp.CallToolResult{StructuredContent: map[string]any{"key": args.Key, "deleted": true}}, nil, nil
+		})
+	}
+}
+
+// IssueChangelog returns an issue's full change history, oldest first.
+func (c *JiraClient) IssueChangelog(ctx context.Context, key string) ([]JiraChangeHistory, error) {
+	var all []JiraChangeHistory
+	for startAt := 0; ; {
+		var page struct {
+			StartAt int                 `json:"startAt"`
+			Total   int                 `json:"total"`
+			IsLast  bool                `json:"isLast"`
+			Values  []JiraChangeHistory `json:"values"`
+		}
+		path := fmt.Sprintf("/rest/api/3/issue/%s/changelog?startAt=%d&maxResults=100", url.PathEscape(key), startAt)
+		if err := c.doJSON(ctx, http.MethodGet, path, nil, &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Values...)
+		startAt += len(page.Values)
+		if page.IsLast || len(page.Values) == 0 || startAt >= page.Total {
+			return all, nil
+		}
+	}
+}
+
+// fullChangelog returns iss's change history, fetching the remainder when a
+// search expansion returned only part of it.
+func (c *JiraClient) fullChangelog(ctx context.Context, iss *JiraIssue) ([]JiraChangeHistory, error) {
+	if iss.Changelog != nil && len(iss.Changelog.Histories) >= iss.Changelog.Total {
+		return iss.Changelog.Histories, nil
+	}
+	return c.IssueChangelog(ctx, iss.Key)
+}
+
+// filterChangelog keeps only changes to the given fields (by name or id,
+// case-insensitive) made at or after since. Histories left with no items are
+// dropped. Empty filters keep everything.
+func filterChangelog(histories []JiraChangeHistory, fields []string, since time.Time) []JiraChangeHistory {
+	out := make([]JiraChangeHistory, 0, len(histories))
+	for _, h := range histories {
+		if !since.IsZero() {
+			if t, ok := parseJiraTime(h.Created); ok && t.Before(since) {
+				continue
+			}
+		}
+		if len(fields) > 0 {
+			var items []JiraChangeItem
+			for _, it := range h.Items {
+				if containsFold(fields, it.Field) || containsFold(fields, it.FieldID) {
+					items = append(items, it)
+				}
+			}
+			if len(items) == 0 {
+				continue
+			}
+			h.Items = items
+		}
+		out = append(out, h)
+	}
+	return out
+}
@@ -0,0 +1,54 @@
+package jira
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCoerceEditValue(t *testing.T) {
+	priority := EditMetaField{
+		Name:   "Priority",
+		Schema: map[string]any{"type": "priority"},
+		AllowedValues: []any{
+			map[string]any{"id": "1", "name": "High"},
+			map[string]any{"id": "3", "name": "Low"},
+		},
+	}
+	colours := EditMetaField{
+		Name:   "Colours",
+		Schema: map[string]any{"type": "array", "items": "option"},
+		AllowedValues: []any{
+			map[string]any{"id": "10001", "value": "Red"},
+			map[string]any{"id": "10002", "value": "Blue"},
+		},
+	}
+	free := EditMetaField{Name: "Summary", Schema: map[string]any{"type": "string"}}
+
+	tests := []struct {
+		name    string
+		field   EditMetaField
+		value   any
+		want    any
+		wantErr bool
+	}{
+		{"name", priority, "High", map[string]any{"name": "High"}, false},
+		{"name any case", priority, "low", map[string]any{"name": "low"}, false},
+		{"id", priority, "3", map[string]any{"id": "3"}, false},
+		{"object passes through", priority, map[string]any{"id": "1"}, map[string]any{"id": "1"}, false},
+		{"unknown", priority, "Medium", nil, true},
+		{"unknown id", priority, "2", nil, true},
+		{"options", colours, []any{"Red", "10002"}, []any{map[string]any{"value": "Red"}, map[string]any{"id": "10002"}}, false},
+		{"unknown option", colours, []any{"Red", "Green"}, nil, true},
+		{"no allowed values", free, "anything", "anything", false},
+	}
+	for _, tt := range tests {
+		got, err := coerceEditValue("f", tt.field, tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, want error %t", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %#v, want %#v", tt.name, got, tt.want)
+		}
+	}
+}
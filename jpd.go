@@ -0,0 +1,225 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Jira Product Discovery ideas ----
+//
+// JPD ideas carry most of their meaning in JPD-specific custom fields
+// (impact, effort, insights, goals). These tools find those fields in the
+// catalog and present them by name rather than as customfield_* ids. They
+// are registered when JIRA_JPD=1.
+
+// jpdDeliveryLinkType is the link type JPD uses between ideas and the
+// delivery work that implements them.
+const jpdDeliveryLinkType = "Polaris work item link"
+
+// isJPDField reports whether f is one of JPD's own custom fields.
+func isJPDField(f JiraField) bool {
+	custom, _ := f.Schema["custom"].(string)
+	custom = strings.ToLower(custom)
+	return f.Custom && (strings.Contains(custom, "polaris") || strings.Contains(custom, "jpd"))
+}
+
+func (c *JiraClient) jpdFields(ctx context.Context) ([]JiraField, error) {
+	all, err := c.Fields(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []JiraField
+	for _, f := range all {
+		if isJPDField(f) {
+			out = append(out, f)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+type ideaView struct {
+	Key     string         `json:"key"`
+	Summary string         `json:"summary"`
+	Status  string         `json:"status,omitempty"`
+	Fields  map[string]any `json:"fields,omitempty"` // JPD fields by name
+}
+
+func viewIdea(iss *JiraIssue, jpd []JiraField) ideaView {
+	v := ideaView{
+		Key:     iss.Key,
+		Summary: fieldString(iss.Fields, "summary"),
+		Status:  fieldString(iss.Fields, "status", "name"),
+		Fields:  map[string]any{},
+	}
+	for _, f := range jpd {
+		if val, ok := iss.Fields[f.ID]; ok && val != nil {
+			v.Fields[f.Name] = displayValue(val)
+		}
+	}
+	return v
+}
+
+func ideaFieldIDs(jpd []JiraField) []string {
+	ids := []string{"summary", "status"}
+	for _, f := range jpd {
+		ids = append(ids, f.ID)
+	}
+	return ids
+}
+
+func registerJPDTools(server *mcp.Server, jc *JiraClient) {
+	if os.Getenv("JIRA_JPD") != "1" {
+		return
+	}
+
+	// list_ideas(project, jql?, max_results?)
+	type listIdeasArgs struct {
+		Project    string `json:"project" jsonschema:"JPD project key"`
+		JQL        string `json:"jql,omitempty" jsonschema:"Extra JQL to narrow the ideas, e.g. status = Discovery"`
+		MaxResults int    `json:"max_results,omitempty" jsonschema:"Maximum ideas (default 50, max 500)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_ideas",
+		Title:       "List Ideas",
+		Description: "List Jira Product Discovery ideas with their JPD fields (impact, effort, insights, ...) by name",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listIdeasArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_ideas args={project:%q,jql:%q}", args.Project, args.JQL)
+		jpd, err := jc.jpdFields(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		limit := args.MaxResults
+		if limit <= 0 || limit > 500 {
+			limit = 50
+		}
+		jql := "project = " + quoteJQL(args.Project) + " AND issuetype = Idea"
+		if args.JQL != "" {
+			jql += " AND (" + args.JQL + ")"
+		}
+		issues, err := jc.SearchAll(ctx, jql, ideaFieldIDs(jpd), limit)
+		if err != nil {
+			debugf("tool=list_ideas error=%v", err)
+			return nil, nil, err
+		}
+		ideas := make([]ideaView, 0, len(issues))
+		for i := range issues {
+			ideas = append(ideas, viewIdea(&issues[i], jpd))
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"project": args.Project, "ideas": ideas}}, nil, nil
+	})
+
+	// get_idea(key)
+	type getIdeaArgs struct {
+		Key string `json:"key" jsonschema:"Idea key, e.g. IDEA-12"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_idea",
+		Title:       "Get Idea",
+		Description: "Read a Jira Product Discovery idea with its JPD fields by name",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args getIdeaArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=get_idea args={key:%q}", args.Key)
+		jpd, err := jc.jpdFields(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		iss, err := jc.issueFields(ctx, args.Key, strings.Join(append(ideaFieldIDs(jpd), "description"), ","))
+		if err != nil {
+			debugf("tool=get_idea error=%v", err)
+			return nil, nil, err
+		}
+		v := viewIdea(iss, jpd)
+		return &mcp.CallToolResult{StructuredContent: map[string]any{
+			"key": v.Key, "summary": v.Summary, "status": v.Status, "fields": v.Fields,
+			"description": adfToMarkdown(iss.Fields["description"]),
+		}}, nil, nil
+	})
+
+	// update_idea(key, fields)
+	type updateIdeaArgs struct {
+		Key    string         `json:"key" jsonschema:"Idea key, e.g. IDEA-12"`
+		Fields map[string]any `json:"fields" jsonschema:"Values keyed by field name, e.g. {\"Impact\": 4, \"Effort\": 2}"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "update_idea",
+		Title:       "Update Idea",
+		Description: "Set JPD fields such as impact, effort, or goals on an idea, by field name. Returns each change as from/to",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args updateIdeaArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=update_idea args={key:%q,fields:%d}", args.Key, len(args.Fields))
+		if len(args.Fields) == 0 {
+			return nil, nil, errors.New("nothing to update")
+		}
+		fields, err := jc.resolveFieldKeys(ctx, args.Fields)
+		if err != nil {
+			return nil, nil, err
+		}
+		meta, err := jc.EditMeta(ctx, args.Key)
+		if err != nil {
+			debugf("tool=update_idea editmeta error=%v", err)
+			return nil, nil, err
+		}
+		if fields, err = validateEdit(args.Key, meta, fields); err != nil {
+			return nil, nil, err
+		}
+		ids := make([]string, 0, len(fields))
+		for id := range fields {
+			ids = append(ids, id)
+		}
+		cur, err := jc.issueFields(ctx, args.Key, strings.Join(ids, ","))
+		if err != nil {
+			return nil, nil, err
+		}
+		changes, _ := diffFields(meta, cur.Fields, fields)
+		if err := jc.UpdateIssue(ctx, args.Key, fields, nil); err != nil {
+			debugf("tool=update_idea error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"key": args.Key, "changes": changes}}, nil, nil
+	})
+
+	// link_idea_to_epic(idea, epic, link_type?)
+	type linkIdeaArgs struct {
+		Idea     string `json:"idea" jsonschema:"Idea key"`
+		Epic     string `json:"epic" jsonschema:"Delivery epic (or other issue) key"`
+		LinkType string `json:"link_type,omitempty" jsonschema:"Link type (default: JPD's delivery link, else Relates)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "link_idea_to_epic",
+		Title:       "Link Idea to Epic",
+		Description: "Connect a JPD idea to the delivery epic that implements it, so delivery progress shows on the idea",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args linkIdeaArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=link_idea_to_epic args={idea:%q,epic:%q,type:%q}", args.Idea, args.Epic, args.LinkType)
+		name := args.LinkType
+		if name == "" {
+			name = jpdDeliveryLinkType
+			if _, err := jc.ResolveLinkType(ctx, name); err != nil {
+				name = "Relates"
+			}
+		}
+		lt, err := jc.ResolveLinkType(ctx, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		// The idea is the inward side: "IDEA-1 is implemented by EPIC-2".
+		id, err := jc.LinkIssues(ctx, lt.Name, args.Idea, args.Epic)
+		if err != nil {
+			debugf("tool=link_idea_to_epic error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{
+			"link_id":     id,
+			"link_type":   lt.Name,
+			"description": fmt.Sprintf("%s %s %s", args.Idea, lt.Inward, args.Epic),
+		}}, nil, nil
+	})
+}
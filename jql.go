@@ -0,0 +1,270 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- JQL validation ----
+
+type jqlProblem struct {
+	Message   string `json:"message"`
+	Line      int    `json:"line,omitempty"`
+	Character int    `json:"character,omitempty"`
+}
+
+type jqlCheck struct {
+	Query    string       `json:"query"`
+	Sent     string       `json:"sent,omitempty"` // the query after alias rewriting, when it differs
+	Valid    bool         `json:"valid"`
+	Errors   []jqlProblem `json:"errors,omitempty"`
+	Warnings []jqlProblem `json:"warnings,omitempty"`
+}
+
+// jqlPositionRe finds the position Jira appends to parse errors, e.g.
+// "... (line 1, character 15)".
+var jqlPositionRe = regexp.MustCompile(`\(line (\d+), character (\d+)\)`)
+
+func parseJQLProblems(msgs []string) []jqlProblem {
+	var out []jqlProblem
+	for _, m := range msgs {
+		p := jqlProblem{Message: m}
+		if sm := jqlPositionRe.FindStringSubmatch(m); sm != nil {
+			p.Line, _ = strconv.Atoi(sm[1])
+			p.Character, _ = strconv.Atoi(sm[2])
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+// ParseJQL checks queries with /rest/api/3/jql/parse. validation is strict
+// (unknown fields and values are errors), warn (they are warnings), or none
+// (syntax only).
+func (c *JiraClient) ParseJQL(ctx context.Context, queries []string, validation string) ([]jqlCheck, error) {
+	sent := make([]string, len(queries))
+	for i, q := range queries {
+		sent[i] = c.rewriteJQLAliases(ctx, q)
+	}
+	var res struct {
+		Queries []struct {
+			Query    string   `json:"query"`
+			Errors   []string `json:"errors"`
+			Warnings []string `json:"warnings"`
+		} `json:"queries"`
+	}
+	if err := c.doJSON(ctx, http.MethodPost, "/rest/api/3/jql/parse?validation="+validation,
+		map[string]any{"queries": sent}, &res); err != nil {
+		return nil, err
+	}
+	out := make([]jqlCheck, len(queries))
+	for i, q := range queries {
+		out[i] = jqlCheck{Query: q}
+		if sent[i] != q {
+			out[i].Sent = sent[i]
+		}
+		if i < len(res.Queries) {
+			out[i].Errors = parseJQLProblems(res.Queries[i].Errors)
+			out[i].Warnings = parseJQLProblems(res.Queries[i].Warnings)
+		}
+		out[i].Valid = len(out[i].Errors) == 0
+	}
+	return out, nil
+}
+
+// ---- JQL autocomplete ----
+
+type jqlFieldInfo struct {
+	Value       string   `json:"value"` // what to write in JQL
+	DisplayName string   `json:"displayName"`
+	Orderable   string   `json:"orderable,omitempty"`
+	Searchable  string   `json:"searchable,omitempty"`
+	Auto        string   `json:"auto,omitempty"` // "true" when value suggestions are available
+	CFID        string   `json:"cfid,omitempty"`
+	Operators   []string `json:"operators"`
+	Types       []string `json:"types,omitempty"`
+}
+
+type jqlFunctionInfo struct {
+	Value       string   `json:"value"`
+	DisplayName string   `json:"displayName"`
+	IsList      string   `json:"isList,omitempty"`
+	Types       []string `json:"types,omitempty"`
+}
+
+type jqlAutocompleteData struct {
+	Fields        []jqlFieldInfo    `json:"visibleFieldNames"`
+	Functions     []jqlFunctionInfo `json:"visibleFunctionNames"`
+	ReservedWords []string          `json:"jqlReservedWords"`
+}
+
+type jqlAutocompleteCache struct {
+	mu      sync.Mutex
+	data    *jqlAutocompleteData
+	fetched time.Time
+}
+
+// JQLAutocompleteData returns the fields, functions, and operators usable in
+// JQL, cached like the field catalog.
+func (c *JiraClient) JQLAutocompleteData(ctx context.Context) (*jqlAutocompleteData, error) {
+	c = c.forSite(ctx)
+	c.jqlCache.mu.Lock()
+	defer c.jqlCache.mu.Unlock()
+	if c.jqlCache.data != nil && time.Since(c.jqlCache.fetched) < fieldCatalogTTL {
+		return c.jqlCache.data, nil
+	}
+	var out jqlAutocompleteData
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/jql/autocompletedata", nil, &out); err != nil {
+		return nil, err
+	}
+	c.jqlCache.data, c.jqlCache.fetched = &out, time.Now()
+	return &out, nil
+}
+
+type jqlSuggestion struct {
+	Value       string `json:"value"`
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+// Jira highlights the typed prefix in suggestion display names.
+var jqlHighlightTags = strings.NewReplacer("<b>", "", "</b>", "")
+
+// JQLSuggestions returns values for a JQL field starting with prefix, such
+// as statuses, sprints, or users. field is a JQL clause name; aliases and
+// customfield ids are accepted too.
+func (c *JiraClient) JQLSuggestions(ctx context.Context, field, prefix string) ([]jqlSuggestion, error) {
+	name := c.aliasTarget(strings.TrimSpace(field))
+	if m := customFieldRe.FindStringSubmatch(name); m != nil {
+		name = "cf[" + m[1] + "]"
+	}
+	q := url.Values{}
+	q.Set("fieldName", name)
+	q.Set("fieldValue", prefix)
+	var res struct {
+		Results []jqlSuggestion `json:"results"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/jql/autocompletedata/suggestions?"+q.Encode(), nil, &res); err != nil {
+		return nil, err
+	}
+	for i := range res.Results {
+		res.Results[i].DisplayName = jqlHighlightTags.Replace(res.Results[i].DisplayName)
+	}
+	return res.Results, nil
+}
+
+func registerJQLTools(server *mcp.Server, jc *JiraClient) {
+	// validate_jql(jql?, queries?, validation?)
+	type validateJQLArgs struct {
+		JQL        string   `json:"jql,omitempty" jsonschema:"Query to check"`
+		Queries    []string `json:"queries,omitempty" jsonschema:"Several queries to check at once"`
+		Validation string   `json:"validation,omitempty" jsonschema:"strict (default: unknown fields or values are errors), warn (they are warnings), or none (syntax only)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "validate_jql",
+		Title:       "Validate JQL",
+		Description: "Check JQL before running it. Returns syntax errors and warnings with line and character positions",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args validateJQLArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=validate_jql args={jql:%q,queries:%d,validation:%q}", args.JQL, len(args.Queries), args.Validation)
+		queries := args.Queries
+		if args.JQL != "" {
+			queries = append([]string{args.JQL}, queries...)
+		}
+		if len(queries) == 0 {
+			return nil, nil, errors.New("jql or queries is required")
+		}
+		switch args.Validation {
+		case "":
+			args.Validation = "strict"
+		case "strict", "warn", "none":
+		default:
+			return nil, nil, errors.New("validation must be strict, warn, or none")
+		}
+		checks, err := jc.ParseJQL(ctx, queries, args.Validation)
+		if err != nil {
+			debugf("tool=validate_jql error=%v", err)
+			return nil, nil, err
+		}
+		valid := true
+		for _, c := range checks {
+			valid = valid && c.Valid
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"valid": valid, "queries": checks}}, nil, nil
+	})
+
+	// list_jql_fields(query?, include_functions?)
+	type listJQLFieldsArgs struct {
+		Query            string `json:"query,omitempty" jsonschema:"Only fields (and functions) whose name contains this text"`
+		IncludeFunctions bool   `json:"include_functions,omitempty" jsonschema:"Also list JQL functions such as currentUser() and openSprints()"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_jql_fields",
+		Title:       "List JQL Fields",
+		Description: "List the fields usable in JQL with the operators each accepts, to compose valid queries. Fields with auto=true have value suggestions via suggest_jql_values",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listJQLFieldsArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_jql_fields args={query:%q,functions:%t}", args.Query, args.IncludeFunctions)
+		data, err := jc.JQLAutocompleteData(ctx)
+		if err != nil {
+			debugf("tool=list_jql_fields error=%v", err)
+			return nil, nil, err
+		}
+		q := strings.ToLower(args.Query)
+		matches := func(value, display string) bool {
+			return q == "" || strings.Contains(strings.ToLower(value), q) || strings.Contains(strings.ToLower(display), q)
+		}
+		fields := []jqlFieldInfo{}
+		for _, f := range data.Fields {
+			if matches(f.Value, f.DisplayName) {
+				fields = append(fields, f)
+			}
+		}
+		out := map[string]any{"fields": fields}
+		if args.IncludeFunctions {
+			funcs := []jqlFunctionInfo{}
+			for _, f := range data.Functions {
+				if matches(f.Value, f.DisplayName) {
+					funcs = append(funcs, f)
+				}
+			}
+			out["functions"] = funcs
+			out["reserved_words"] = data.ReservedWords
+		}
+		return &mcp.CallToolResult{StructuredContent: out}, nil, nil
+	})
+
+	// suggest_jql_values(field, prefix?)
+	type suggestArgs struct {
+		Field  string `json:"field" jsonschema:"JQL field, e.g. status, sprint, assignee, or cf[10020]"`
+		Prefix string `json:"prefix,omitempty" jsonschema:"What has been typed of the value so far"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "suggest_jql_values",
+		Title:       "Suggest JQL Values",
+		Description: "Suggest valid values for a JQL field (statuses, sprints, users, versions, ...) matching a prefix, as Jira's search box does",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args suggestArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=suggest_jql_values args={field:%q,prefix:%q}", args.Field, args.Prefix)
+		if strings.TrimSpace(args.Field) == "" {
+			return nil, nil, errors.New("field is required")
+		}
+		sugg, err := jc.JQLSuggestions(ctx, args.Field, args.Prefix)
+		if err != nil {
+			debugf("tool=suggest_jql_values error=%v", err)
+			return nil, nil, err
+		}
+		if sugg == nil {
+			sugg = []jqlSuggestion{}
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"field": args.Field, "suggestions": sugg}}, nil, nil
+	})
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package jira
+
+import "errors"
+
+func readWindowsCredential(string) (string, error) {
+	return "", errors.New("the Windows Credential Manager is only available on Windows")
+}
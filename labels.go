@@ -0,0 +1,140 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Labels ----
+
+// ListLabels pages through every label in the instance.
+func (c *JiraClient) ListLabels(ctx context.Context) ([]string, error) {
+	var labels []string
+	for startAt := 0; ; {
+		var page struct {
+			Values []string `json:"values"`
+			IsLast bool     `json:"isLast"`
+		}
+		path := fmt.Sprintf("/rest/api/3/label?startAt=%d&maxResults=1000", startAt)
+		if err := c.doJSON(ctx, http.MethodGet, path, nil, &page); err != nil {
+			return nil, err
+		}
+		labels = append(labels, page.Values...)
+		startAt += len(page.Values)
+		if page.IsLast || len(page.Values) == 0 {
+			return labels, nil
+		}
+	}
+}
+
+// labelOps builds the update.labels operations for add and remove.
+func labelOps(add, remove []string) ([]any, error) {
+	var ops []any
+	for _, l := range add {
+		if strings.ContainsAny(l, " \t") {
+			return nil, fmt.Errorf("label %q contains whitespace", l)
+		}
+		ops = append(ops, map[string]any{"add": l})
+	}
+	for _, l := range remove {
+		ops = append(ops, map[string]any{"remove": l})
+	}
+	return ops, nil
+}
+
+func registerLabelTools(server *mcp.Server, jc *JiraClient) {
+	// modify_labels(keys, add?, remove?, urgent?)
+	type modifyLabelsArgs struct {
+		Keys   []string `json:"keys" jsonschema:"Issue keys to change"`
+		Add    []string `json:"add,omitempty" jsonschema:"Labels to add (no spaces)"`
+		Remove []string `json:"remove,omitempty" jsonschema:"Labels to remove"`
+		Urgent bool     `json:"urgent,omitempty" jsonschema:"Fail rather than queue changes if Jira is unreachable (only matters with the write queue enabled)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "modify_labels",
+		Title:       "Modify Labels",
+		Description: "Add and remove labels on issues without replacing their other labels",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args modifyLabelsArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=modify_labels args={keys:%d,add:%v,remove:%v}", len(args.Keys), args.Add, args.Remove)
+		if len(args.Keys) == 0 {
+			return nil, nil, errors.New("keys is required")
+		}
+		ops, err := labelOps(args.Add, args.Remove)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(ops) == 0 {
+			return nil, nil, errors.New("nothing to change: give add or remove")
+		}
+		var updated []string
+		failed := map[string]string{}
+		queued := map[string]string{} // key -> queue id
+		for _, key := range args.Keys {
+			w := &queuedWrite{Kind: queuedLabels, Key: key, Add: args.Add, Remove: args.Remove}
+			q, err := jc.deferWrite(ctx, args.Urgent, w, func() error {
+				return jc.UpdateIssue(ctx, key, nil, map[string]any{"labels": ops})
+			})
+			switch {
+			case err != nil:
+				debugf("tool=modify_labels key=%s error=%v", key, err)
+				failed[key] = err.Error()
+			case q:
+				queued[key] = w.ID
+			default:
+				updated = append(updated, key)
+			}
+		}
+		out := map[string]any{"updated": updated, "added": args.Add, "removed": args.Remove}
+		if len(failed) > 0 {
+			out["failed"] = failed
+		}
+		if len(queued) > 0 {
+			out["queued"] = queued
+		}
+		return &mcp.CallToolResult{StructuredContent: out, IsError: len(updated) == 0 && len(queued) == 0}, nil, nil
+	})
+
+	// list_labels(query?, max_results?)
+	type listLabelsArgs struct {
+		Query      string `json:"query,omitempty" jsonschema:"Only labels containing this text (case-insensitive)"`
+		MaxResults int    `json:"max_results,omitempty" jsonschema:"Maximum labels to return (default 200)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_labels",
+		Title:       "List Labels",
+		Description: "List labels used anywhere in the instance, optionally filtered, for picking existing labels instead of inventing new ones",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listLabelsArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_labels args={query:%q}", args.Query)
+		max := args.MaxResults
+		if max <= 0 {
+			max = 200
+		}
+		all, err := jc.ListLabels(ctx)
+		if err != nil {
+			debugf("tool=list_labels error=%v", err)
+			return nil, nil, err
+		}
+		q := strings.ToLower(args.Query)
+		labels := []string{}
+		matched := 0
+		for _, l := range all {
+			if q != "" && !strings.Contains(strings.ToLower(l), q) {
+				continue
+			}
+			matched++
+			if len(labels) < max {
+				labels = append(labels, l)
+			}
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{
+			"labels": labels, "total": matched, "truncated": matched > len(labels),
+		}}, nil, nil
+	})
+}
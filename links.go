@@ -0,0 +1,114 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Issue links ----
+
+type JiraLinkType struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Inward  string `json:"inward"`
+	Outward string `json:"outward"`
+}
+
+func (c *JiraClient) ListLinkTypes(ctx context.Context) ([]JiraLinkType, error) {
+	var out struct {
+		IssueLinkTypes []JiraLinkType `json:"issueLinkTypes"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/issueLinkType", nil, &out); err != nil {
+		return nil, err
+	}
+	return out.IssueLinkTypes, nil
+}
+
+// ResolveLinkType finds a link type by id or name (case-insensitive).
+func (c *JiraClient) ResolveLinkType(ctx context.Context, name string) (*JiraLinkType, error) {
+	types, err := c.ListLinkTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	for i, t := range types {
+		if t.ID == name || strings.EqualFold(t.Name, name) {
+			return &types[i], nil
+		}
+	}
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = fmt.Sprintf("%s (%s / %s)", t.Name, t.Outward, t.Inward)
+	}
+	return nil, fmt.Errorf("unknown link type %q; available: %s", name, strings.Join(names, ", "))
+}
+
+func registerLinkTools(server *mcp.Server, jc *JiraClient) {
+	// list_issue_link_types()
+	type listLinkTypesArgs struct{}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_issue_link_types",
+		Title:       "List Issue Link Types",
+		Description: "List the issue link types (e.g. Blocks: blocks / is blocked by) configured on the site",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listLinkTypesArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_issue_link_types")
+		types, err := jc.ListLinkTypes(ctx)
+		if err != nil {
+			debugf("tool=list_issue_link_types error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"link_types": types}}, nil, nil
+	})
+
+	// link_issues(type, inward_key, outward_key)
+	type linkArgs struct {
+		Type       string `json:"type" jsonschema:"Link type name or id, case-insensitive, e.g. Blocks or Relates"`
+		InwardKey  string `json:"inward_key" jsonschema:"Issue on the inward side, e.g. the blocked issue for Blocks"`
+		OutwardKey string `json:"outward_key" jsonschema:"Issue on the outward side, e.g. the blocking issue for Blocks"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "link_issues",
+		Title:       "Link Issues",
+		Description: "Link two issues. The result reads as: inward_key <inward phrase> outward_key (e.g. PROJ-2 is blocked by PROJ-1)",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args linkArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=link_issues args={type:%q,inward:%q,outward:%q}", args.Type, args.InwardKey, args.OutwardKey)
+		lt, err := jc.ResolveLinkType(ctx, args.Type)
+		if err != nil {
+			debugf("tool=link_issues error=%v", err)
+			return nil, nil, err
+		}
+		id, err := jc.LinkIssues(ctx, lt.Name, args.InwardKey, args.OutwardKey)
+		if err != nil {
+			debugf("tool=link_issues error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{
+			"link_id":     id,
+			"type":        lt.Name,
+			"description": fmt.Sprintf("%s %s %s", args.InwardKey, lt.Inward, args.OutwardKey),
+		}}, nil, nil
+	})
+
+	// delete_link(link_id)
+	type deleteLinkArgs struct {
+		LinkID string `json:"link_id" jsonschema:"Issue link id, as returned by link_issues or found in an issue's issuelinks"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "delete_link",
+		Title:       "Delete Issue Link",
+		Description: "Delete an issue link by id",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: ptr(true), IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args deleteLinkArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=delete_link args={id:%q}", args.LinkID)
+		if err := jc.DeleteIssueLink(ctx, args.LinkID); err != nil {
+			debugf("tool=delete_link error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"link_id": args.LinkID, "deleted": true}}, nil, nil
+	})
+}
@@ -0,0 +1,235 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Issue hygiene checks ----
+//
+// A lint rule flags issues whose fields are inconsistent with each other.
+// Its "when" conditions use the triage rule syntax; an issue matching all of
+// them violates the rule. JIRA_LINT_RULES adds rules and turns off defaults:
+//
+//	{"rules": [{"name": "epic_without_owner", "when": {"type": "Epic", "assignee": "empty"},
+//	            "message": "Epics need an owner", "severity": "error"}],
+//	 "disable": ["story_without_points"]}
+//
+// Rules naming fields the site does not have (e.g. Sprint on Jira Work
+// Management) are skipped and reported as such.
+
+type lintRule struct {
+	Name     string         `json:"name"`
+	When     map[string]any `json:"when"`
+	Message  string         `json:"message"`
+	Severity string         `json:"severity,omitempty"` // warning (default) or error
+}
+
+type lintConfig struct {
+	Rules   []lintRule `json:"rules,omitempty"`
+	Disable []string   `json:"disable,omitempty"`
+}
+
+var defaultLintRules = []lintRule{
+	{Name: "resolved_without_resolution", When: map[string]any{"status_category": "Done", "resolution": "empty"},
+		Message: "In a done status but the resolution is unset", Severity: "error"},
+	{Name: "resolution_while_open", When: map[string]any{"status_category": "!Done", "resolution": "!empty"},
+		Message: "Has a resolution but is not in a done status", Severity: "error"},
+	{Name: "done_without_sprint", When: map[string]any{"status_category": "Done", "Sprint": "empty", "type": []any{"Story", "Task", "Bug"}},
+		Message: "Done but never in a sprint"},
+	{Name: "bug_without_affected_version", When: map[string]any{"type": "Bug", "versions": "empty"},
+		Message: "Bug without an affected version"},
+	{Name: "story_without_points", When: map[string]any{"type": "Story", "Story Points": "empty"},
+		Message: "Story without a story point estimate"},
+}
+
+type lintViolation struct {
+	Rule     string `json:"rule"`
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+}
+
+type lintResult struct {
+	Key        string          `json:"key"`
+	Summary    string          `json:"summary,omitempty"`
+	Status     string          `json:"status,omitempty"`
+	Violations []lintViolation `json:"violations"`
+}
+
+type linter struct {
+	rules   []lintRule
+	fields  []string          // ids the rules read
+	skipped map[string]string // rule -> why
+}
+
+func loadLintRules() []lintRule {
+	var cfg lintConfig
+	if _, err := loadJSONSetting("JIRA_LINT_RULES", &cfg); err != nil {
+		logger.Printf("ignoring JIRA_LINT_RULES: %v", err)
+		cfg = lintConfig{}
+	}
+	var rules []lintRule
+	for _, r := range append(slices.Clone(defaultLintRules), cfg.Rules...) {
+		if containsFold(cfg.Disable, r.Name) {
+			continue
+		}
+		if r.Severity == "" {
+			r.Severity = "warning"
+		}
+		if r.Message == "" {
+			r.Message = r.Name
+		}
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+// newLinter prepares rules (all, or only those named in only) for the site,
+// setting aside rules whose fields cannot be resolved.
+func (c *JiraClient) newLinter(ctx context.Context, rules []lintRule, only []string) (*linter, error) {
+	l := &linter{skipped: map[string]string{}}
+	fields := []string{"summary", "status"}
+	for _, r := range rules {
+		if len(only) > 0 && !containsFold(only, r.Name) {
+			continue
+		}
+		var ids []string
+		var err error
+		for name := range r.When {
+			var id string
+			if id, err = c.conditionField(ctx, name); err != nil {
+				break
+			}
+			ids = append(ids, id)
+		}
+		if err != nil {
+			l.skipped[r.Name] = err.Error()
+			continue
+		}
+		l.rules = append(l.rules, r)
+		for _, id := range ids {
+			if !slices.Contains(fields, id) {
+				fields = append(fields, id)
+			}
+		}
+	}
+	for _, name := range only {
+		if !slices.ContainsFunc(rules, func(r lintRule) bool { return strings.EqualFold(r.Name, name) }) {
+			return nil, fmt.Errorf("unknown lint rule %q", name)
+		}
+	}
+	l.fields = fields
+	return l, nil
+}
+
+func (c *JiraClient) lintIssue(ctx context.Context, l *linter, iss *JiraIssue) (*lintResult, error) {
+	res := &lintResult{
+		Key: iss.Key, Summary: fieldString(iss.Fields, "summary"), Status: fieldString(iss.Fields, "status", "name"),
+		Violations: []lintViolation{},
+	}
+	for _, r := range l.rules {
+		ok, err := c.conditionsMatch(ctx, r.Name, r.When, iss)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			res.Violations = append(res.Violations, lintViolation{Rule: r.Name, Message: r.Message, Severity: r.Severity})
+		}
+	}
+	return res, nil
+}
+
+func registerLintTools(server *mcp.Server, jc *JiraClient) {
+	rules := loadLintRules()
+	names := make([]string, len(rules))
+	for i, r := range rules {
+		names[i] = r.Name
+	}
+
+	// lint_issue(key, rules?)
+	type lintIssueArgs struct {
+		Key   string   `json:"key" jsonschema:"Issue key"`
+		Rules []string `json:"rules,omitempty" jsonschema:"Only these rules (default all)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "lint_issue",
+		Title:       "Lint Issue",
+		Description: "Check an issue against consistency rules (" + strings.Join(names, ", ") + ") and list the violations",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args lintIssueArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=lint_issue args={key:%q,rules:%v}", args.Key, args.Rules)
+		l, err := jc.newLinter(ctx, rules, args.Rules)
+		if err != nil {
+			return nil, nil, err
+		}
+		iss, err := jc.GetIssueFields(ctx, args.Key, l.fields)
+		if err != nil {
+			debugf("tool=lint_issue error=%v", err)
+			return nil, nil, err
+		}
+		res, err := jc.lintIssue(ctx, l, iss)
+		if err != nil {
+			return nil, nil, err
+		}
+		out := map[string]any{"issue": res}
+		if len(l.skipped) > 0 {
+			out["skipped_rules"] = l.skipped
+		}
+		return &mcp.CallToolResult{StructuredContent: out}, nil, nil
+	})
+
+	// lint_search(jql, rules?, max_issues?)
+	type lintSearchArgs struct {
+		JQL       string   `json:"jql" jsonschema:"Issues to check, e.g. project = PROJ AND updated >= -7d"`
+		Rules     []string `json:"rules,omitempty" jsonschema:"Only these rules (default all)"`
+		MaxIssues int      `json:"max_issues,omitempty" jsonschema:"Maximum issues to check (default 200, max 1000)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "lint_search",
+		Title:       "Lint Search",
+		Description: "Check every issue matching JQL against the consistency rules; returns the issues with violations and a count per rule, for hygiene sweeps",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args lintSearchArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=lint_search args={jql:%q,rules:%v,max:%d}", args.JQL, args.Rules, args.MaxIssues)
+		if strings.TrimSpace(args.JQL) == "" {
+			return nil, nil, errors.New("jql is required")
+		}
+		limit := args.MaxIssues
+		if limit <= 0 {
+			limit = 200
+		}
+		l, err := jc.newLinter(ctx, rules, args.Rules)
+		if err != nil {
+			return nil, nil, err
+		}
+		issues, err := jc.SearchAll(ctx, args.JQL, l.fields, min(limit, 1000))
+		if err != nil {
+			debugf("tool=lint_search error=%v", err)
+			return nil, nil, err
+		}
+		flagged := []*lintResult{}
+		counts := map[string]int{}
+		for i := range issues {
+			res, err := jc.lintIssue(ctx, l, &issues[i])
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, v := range res.Violations {
+				counts[v.Rule]++
+			}
+			if len(res.Violations) > 0 {
+				flagged = append(flagged, res)
+			}
+		}
+		out := map[string]any{"jql": args.JQL, "checked": len(issues), "issues": flagged, "counts": counts}
+		if len(l.skipped) > 0 {
+			out["skipped_rules"] = l.skipped
+		}
+		return &mcp.CallToolResult{StructuredContent: out}, nil, nil
+	})
+}
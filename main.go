@@ -1,6 +1,7 @@
-package main
+package jira
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
@@ -11,7 +12,11 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -21,14 +26,12 @@ import (
 
 var debug = os.Getenv("DEBUG") == "1"
 
-func init() {
-	// Timestamp + microseconds + short file:line for easier troubleshooting
-	log.SetFlags(log.LstdFlags | log.Lmicroseconds | log.Lshortfile)
-}
+// logger receives all log output; embedders redirect it with log.SetOutput.
+var logger = log.Default()
 
 func debugf(format string, args ...any) {
 	if debug {
-		log.Printf("[DEBUG] "+format, args...)
+		logger.Printf("[DEBUG] "+format, args...)
 	}
 }
 
@@ -85,14 +88,82 @@ func wrapClientForDebug(c *http.Client) *http.Client {
 
 type JiraClient struct {
 	BaseURL string
-	Auth    string // "Basic <base64(email:token)>"
+	Auth    string // "Basic <base64(email:token)>", or "Bearer <token>" with JIRA_AUTH=pat
 	Client  *http.Client
+	// OAuth supplies the default credential's token when JIRA_AUTH=oauth;
+	// Auth is unused then.
+	OAuth *oauthSource
+	// tokenAuth, if set, rebuilds Auth from a token file or keychain entry
+	// that may be rotated; see secrets.go.
+	tokenAuth *tokenAuth
+
+	// FieldAliases maps team shorthand to a field id or name, e.g.
+	// "ac" -> "customfield_10031", "sev" -> "Severity".
+	FieldAliases map[string]string
+	// ProjectDefaults are applied to created issues by project key, with
+	// "*" for all projects; see defaults.go.
+	ProjectDefaults map[string]ProjectDefaults
+	// DefaultProject fills in the project of tools called without one and
+	// without a session focus (JIRA_DEFAULT_PROJECT). Unlike a focus, it
+	// does not narrow searches.
+	DefaultProject string
+	// DefaultMaxResults and DefaultFields apply to search_issues calls that
+	// do not set them (JIRA_MAX_RESULTS, JIRA_DEFAULT_FIELDS).
+	DefaultMaxResults int
+	DefaultFields     []string
+
+	// AuthRoutes overrides the default credential per endpoint class.
+	AuthRoutes map[endpointClass]credential
+
+	// Instance labels the site and sets its write policy.
+	Instance instanceConfig
+
+	// Archive keeps issue snapshots (see snapshot_issue).
+	Archive ArchiveStore
+	// Tempo is the Tempo Timesheets API client, nil unless configured.
+	Tempo *TempoClient
+
+	fieldCache fieldCatalog
+	jqlCache   jqlAutocompleteCache
+	caps       siteCapabilities
+	budget     *rateBudget
+	quota      rateLimitTelemetry
+	health     healthState
+	status     statusMonitor
+	outcomes   outcomeTracker
+	focus      focusStore
+	uploads    uploadStore
+	queue      *writeQueue  // nil unless JIRA_WRITE_QUEUE is set
+	provenance string       // JIRA_PROVENANCE mode
+	triage     triageConfig // JIRA_TRIAGE_RULES
+
+	// legacySearch is set once the site turns out not to support the
+	// token-based search endpoint.
+	legacySearch atomic.Bool
+	authRouter   authRouter
+	api          apiVersionState
+
+	site  string        // name for the site argument; see sites.go
+	sites []*JiraClient // every configured site, this one first; default site only
+	// passthrough marks a client acting with a session's own credentials;
+	// see sessionauth.go.
+	passthrough bool
 }
 
 func NewJiraClientFromEnv() (*JiraClient, error) {
 	baseURL := strings.TrimRight(os.Getenv("JIRA_INSTANCE_URL"), "/")
 	email := os.Getenv("JIRA_USER_EMAIL")
 	token := os.Getenv("JIRA_API_TOKEN")
+	var rotating *secret
+	if token == "" {
+		rotating = tokenSecret("JIRA_API_TOKEN_FILE", os.Getenv("JIRA_API_TOKEN_FILE"), "JIRA_API_TOKEN_KEYCHAIN", os.Getenv("JIRA_API_TOKEN_KEYCHAIN"), email)
+	}
+	if rotating != nil {
+		var err error
+		if token, err = rotating.get(); err != nil {
+			return nil, err
+		}
+	}
 
 	if debug {
 		debugf("Load env: JIRA_INSTANCE_URL=%q", baseURL)
@@ -100,25 +171,141 @@ func NewJiraClientFromEnv() (*JiraClient, error) {
 		debugf("Load env: JIRA_API_TOKEN (%s)", tokenInfo(token))
 	}
 
-	if baseURL == "" || email == "" || token == "" {
-		return nil, errors.New("JIRA_INSTANCE_URL, JIRA_USER_EMAIL, JIRA_API_TOKEN must be set")
+	authMode := strings.ToLower(os.Getenv("JIRA_AUTH"))
+	switch authMode {
+	case "", "basic":
+		if baseURL == "" || email == "" || token == "" {
+			return nil, errors.New("JIRA_INSTANCE_URL, JIRA_USER_EMAIL, JIRA_API_TOKEN (or JIRA_API_TOKEN_FILE, JIRA_API_TOKEN_KEYCHAIN) must be set")
+		}
+	case "pat":
+		// Data Center personal access tokens are not tied to an email.
+		if baseURL == "" || token == "" {
+			return nil, errors.New("JIRA_INSTANCE_URL and JIRA_API_TOKEN (or JIRA_API_TOKEN_FILE, JIRA_API_TOKEN_KEYCHAIN) must be set")
+		}
+	case "oauth":
+		if baseURL == "" {
+			return nil, errors.New("JIRA_INSTANCE_URL must be set")
+		}
+	default:
+		return nil, fmt.Errorf("JIRA_AUTH: unknown value %q (valid: basic, pat, oauth)", authMode)
 	}
 	if _, err := url.ParseRequestURI(baseURL); err != nil {
 		return nil, fmt.Errorf("invalid JIRA_INSTANCE_URL: %w", err)
 	}
-	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(email+":"+token))
 	cl := &http.Client{Timeout: 30 * time.Second}
+	egress, err := loadEgressTransport()
+	if err != nil {
+		return nil, err
+	}
+	if egress != nil {
+		cl.Transport = egress
+	}
 	cl = wrapClientForDebug(cl)
 
-	return &JiraClient{
-		BaseURL: baseURL,
-		Auth:    auth,
-		Client:  cl,
-	}, nil
+	var auth string
+	var oauth *oauthSource
+	var rotatingAuth *tokenAuth
+	switch authMode {
+	case "oauth":
+		if oauth, err = loadOAuth(baseURL, cl); err != nil {
+			return nil, err
+		}
+	case "pat":
+		auth = "Bearer " + token
+		if rotating != nil {
+			rotatingAuth = &tokenAuth{token: rotating, bearer: true}
+		}
+	default:
+		auth = "Basic " + base64.StdEncoding.EncodeToString([]byte(email+":"+token))
+		if rotating != nil {
+			rotatingAuth = &tokenAuth{token: rotating, email: email}
+		}
+	}
+
+	var aliases map[string]string
+	if _, err := loadJSONSetting("JIRA_FIELD_ALIASES", &aliases); err != nil {
+		return nil, err
+	}
+	routes, err := loadCredentialRoutes(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	instance, err := loadInstanceConfig(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	budget, err := loadRateBudget()
+	if err != nil {
+		return nil, err
+	}
+	queue, err := loadWriteQueue()
+	if err != nil {
+		return nil, err
+	}
+	defaults, err := loadProjectDefaults()
+	if err != nil {
+		return nil, err
+	}
+	tempo, err := tempoFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	triage, err := loadTriageConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	maxResults := 0
+	if v := os.Getenv("JIRA_MAX_RESULTS"); v != "" {
+		if maxResults, err = strconv.Atoi(v); err != nil || maxResults <= 0 || maxResults > 1000 {
+			return nil, fmt.Errorf("JIRA_MAX_RESULTS: want a number from 1 to 1000, got %q", v)
+		}
+	}
+	var fields []string
+	for _, f := range strings.Split(os.Getenv("JIRA_DEFAULT_FIELDS"), ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+
+	jc := &JiraClient{
+		BaseURL:           baseURL,
+		Auth:              auth,
+		Client:            cl,
+		OAuth:             oauth,
+		tokenAuth:         rotatingAuth,
+		FieldAliases:      aliases,
+		AuthRoutes:        routes,
+		ProjectDefaults:   defaults,
+		DefaultProject:    strings.ToUpper(strings.TrimSpace(os.Getenv("JIRA_DEFAULT_PROJECT"))),
+		DefaultMaxResults: maxResults,
+		DefaultFields:     fields,
+		Instance:          instance,
+		budget:            budget,
+		queue:             queue,
+		provenance:        provenanceMode(),
+		Archive:           archiveFromEnv(),
+		Tempo:             tempo,
+		triage:            triage,
+	}
+	if err := jc.loadAPIVersion(); err != nil {
+		return nil, err
+	}
+	switch api := os.Getenv("JIRA_SEARCH_API"); api {
+	case "", "auto", "jql":
+	case "legacy":
+		jc.legacySearch.Store(true)
+	default:
+		return nil, fmt.Errorf("JIRA_SEARCH_API: unknown value %q (valid: auto, legacy)", api)
+	}
+	if err := jc.loadSites(); err != nil {
+		return nil, err
+	}
+	return jc, nil
 }
 
 func (c *JiraClient) doJSON(ctx context.Context, method, path string, body any, out any) error {
-	var r io.Reader
+	var payload []byte
 	if body != nil {
 		b, err := json.Marshal(body)
 		if err != nil {
@@ -128,190 +315,722 @@ func (c *JiraClient) doJSON(ctx context.Context, method, path string, body any,
 			// Body might contain user text; safe to log as JSON if needed
 			debugf("Jira request body: %s", string(b))
 		}
-		r = strings.NewReader(string(b))
-	}
-	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, r)
-	if err != nil {
-		return err
+		payload = b
 	}
-	req.Header.Set("Authorization", c.Auth)
-	req.Header.Set("Accept", "application/json")
+	contentType := ""
 	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+		contentType = "application/json"
 	}
-	resp, err := c.Client.Do(req)
+	resp, err := c.send(ctx, method, path, payload, contentType)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode >= 300 {
 		b, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("jira %s %s failed: %s - %s", method, path, resp.Status, string(b))
+		return &JiraError{Method: method, Path: path, Status: resp.Status, StatusCode: resp.StatusCode, Body: string(b)}
 	}
-	if out != nil {
+	if out != nil && resp.StatusCode != http.StatusNoContent {
 		return json.NewDecoder(resp.Body).Decode(out)
 	}
 	return nil
 }
 
+// JiraError is returned by doJSON for non-2xx responses.
+type JiraError struct {
+	Method     string
+	Path       string
+	Status     string
+	StatusCode int
+	Body       string
+}
+
+func (e *JiraError) Error() string {
+	return fmt.Sprintf("jira %s %s failed: %s - %s", e.Method, e.Path, e.Status, e.Body)
+}
+
+// Messages extracts Jira's errorMessages and field errors from the body.
+func (e *JiraError) Messages() []string {
+	var body struct {
+		ErrorMessages []string          `json:"errorMessages"`
+		Errors        map[string]string `json:"errors"`
+	}
+	if json.Unmarshal([]byte(e.Body), &body) != nil {
+		return nil
+	}
+	msgs := body.ErrorMessages
+	keys := make([]string, 0, len(body.Errors))
+	for k := range body.Errors {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		msgs = append(msgs, k+": "+body.Errors[k])
+	}
+	return msgs
+}
+
+// send performs a request with the credential routed for path. If that
+// credential is rejected (401/403) and another is configured, the request is
+// retried with it. contentType is empty for requests without a body.
+func (c *JiraClient) send(ctx context.Context, method, path string, payload []byte, contentType string) (*http.Response, error) {
+	var body *requestBody
+	if payload != nil {
+		body = &requestBody{
+			open:   func() (io.Reader, error) { return bytes.NewReader(payload), nil },
+			length: int64(len(payload)),
+		}
+	}
+	return c.sendBody(ctx, method, path, body, contentType)
+}
+
+// requestBody produces a request body afresh for each attempt, so large
+// bodies can be streamed. length is its size, or -1 if unknown, in which
+// case it is sent chunked.
+type requestBody struct {
+	open   func() (io.Reader, error)
+	length int64
+}
+
+// sendBody is send with a streamed body; body is nil for requests without
+// one.
+func (c *JiraClient) sendBody(ctx context.Context, method, path string, body *requestBody, contentType string) (*http.Response, error) {
+	c = c.forSite(ctx)
+	if err := c.checkWrite(ctx, method, path); err != nil {
+		return nil, err
+	}
+	creds := c.credentialsFor(path)
+	target := c.apiPath(ctx, path)
+	maintenanceRetries := 0
+	for i := 0; ; i++ {
+		cred := creds[i]
+		if c.budget != nil {
+			if err := c.budget.wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+		var r io.Reader
+		if body != nil {
+			var err error
+			if r, err = body.open(); err != nil {
+				return nil, err
+			}
+		}
+		req, err := http.NewRequestWithContext(ctx, method, cred.BaseURL+target, r)
+		if err != nil {
+			if rc, ok := r.(io.Closer); ok {
+				rc.Close()
+			}
+			return nil, err
+		}
+		if body != nil && body.length >= 0 {
+			req.ContentLength = body.length
+		}
+		auth := cred.Auth
+		if cred.Name == "default" && c.OAuth != nil {
+			tok, err := c.OAuth.Token(ctx)
+			if err != nil {
+				return nil, err
+			}
+			auth = "Bearer " + tok
+		}
+		req.Header.Set("Authorization", auth)
+		req.Header.Set("Accept", "application/json")
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if strings.HasPrefix(contentType, "multipart/") {
+			// Jira rejects multipart posts without this as possible XSRF.
+			req.Header.Set("X-Atlassian-Token", "no-check")
+		}
+		if classifyPath(path) == classServiceDesk {
+			// Some JSM endpoints (customer listing and removal) are still
+			// marked experimental and refuse calls without this.
+			req.Header.Set("X-ExperimentalApi", "opt-in")
+		}
+		resp, err := c.Client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if merr := maintenanceFrom(resp, maintenanceRetries); merr != nil {
+			c.health.noteMaintenance(merr)
+			c.publishStatus()
+			if maintenanceRetries < len(maintenanceBackoff) {
+				debugf("jira maintenance on %s %s; retrying in %s", method, path, merr.RetryAfter)
+				if err := sleepCtx(ctx, merr.RetryAfter); err != nil {
+					return nil, err
+				}
+				maintenanceRetries++
+				i--
+				continue
+			}
+			if tc := currentToolCall(ctx); tc != nil {
+				tc.noteMaintenance(merr)
+			}
+			return nil, merr
+		}
+		if resp.StatusCode < 500 {
+			c.health.noteOK()
+		}
+		rejected := resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden
+		c.quota.note(resp)
+		c.noteStatus(method, path, resp, rejected && i == len(creds)-1)
+		if !rejected || i == len(creds)-1 {
+			if i > 0 && !rejected {
+				c.noteAuthSwitch(path, cred)
+			}
+			return resp, nil
+		}
+		debugf("credential %s rejected for %s %s (%s); trying next", cred.Name, method, path, resp.Status)
+		resp.Body.Close()
+	}
+}
+
 type JiraIssue struct {
 	ID     string         `json:"id,omitempty"`
 	Key    string         `json:"key,omitempty"`
 	Self   string         `json:"self,omitempty"`
 	Fields map[string]any `json:"fields,omitempty"`
+
+	// Aliased holds values of configured field aliases, keyed by alias.
+	Aliased map[string]any `json:"aliased,omitempty"`
+
+	// Counts summarizes engagement (watchers, votes, attachments, ...).
+	Counts *IssueCounts `json:"counts,omitempty"`
+
+	Changelog *JiraChangelog `json:"changelog,omitempty"`
+
+	// Activity is the comment and change timeline get_issue builds on
+	// request.
+	Activity []activityEvent `json:"activity,omitempty"`
+
+	// Filled in only when requested with expand.
+	RenderedFields map[string]any    `json:"renderedFields,omitempty"`
+	Names          map[string]string `json:"names,omitempty"`
+	Transitions    []JiraTransition  `json:"transitions,omitempty"`
+}
+
+// issueExpansions are the expand values get_issue accepts.
+var issueExpansions = []string{"renderedFields", "changelog", "names", "transitions"}
+
+// normalizeExpand checks expand values against issueExpansions, fixing
+// their case.
+func normalizeExpand(expand []string) ([]string, error) {
+	var out []string
+	for _, e := range expand {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		i := slices.IndexFunc(issueExpansions, func(v string) bool { return strings.EqualFold(v, e) })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown expand %q (valid: %s)", e, strings.Join(issueExpansions, ", "))
+		}
+		out = append(out, issueExpansions[i])
+	}
+	return out, nil
+}
+
+type JiraChangelog struct {
+	StartAt    int                 `json:"startAt"`
+	MaxResults int                 `json:"maxResults"`
+	Total      int                 `json:"total"`
+	Histories  []JiraChangeHistory `json:"histories"`
+}
+
+type JiraChangeHistory struct {
+	ID      string           `json:"id"`
+	Author  map[string]any   `json:"author,omitempty"`
+	Created string           `json:"created"`
+	Items   []JiraChangeItem `json:"items"`
+}
+
+type JiraChangeItem struct {
+	Field      string `json:"field"`
+	FieldID    string `json:"fieldId,omitempty"`
+	From       string `json:"from,omitempty"`
+	FromString string `json:"fromString,omitempty"`
+	To         string `json:"to,omitempty"`
+	ToString   string `json:"toString,omitempty"`
 }
 
 type JiraSearchResult struct {
-	StartAt    int         `json:"startAt"`
-	MaxResults int         `json:"maxResults"`
-	Total      int         `json:"total"`
+	StartAt    int         `json:"startAt,omitempty"`
+	MaxResults int         `json:"maxResults,omitempty"`
+	Total      int         `json:"total,omitempty"` // legacy search only
 	Issues     []JiraIssue `json:"issues"`
+
+	NextPageToken string `json:"nextPageToken,omitempty"`
+	IsLast        bool   `json:"isLast"`
+
+	FocusedJQL string `json:"focused_jql,omitempty"` // the query actually run, when set_focus narrowed it
 }
 
 func (c *JiraClient) GetIssue(ctx context.Context, key string) (*JiraIssue, error) {
+	return c.GetIssueFields(ctx, key, nil)
+}
+
+// GetIssueFields is GetIssue returning only the given field ids (all
+// navigable fields when empty).
+func (c *JiraClient) GetIssueFields(ctx context.Context, key string, fields []string) (*JiraIssue, error) {
+	return c.GetIssueExpanded(ctx, key, fields, nil)
+}
+
+// GetIssueExpanded is GetIssueFields that also asks Jira to expand the
+// issue (see issueExpansions) in the same request.
+func (c *JiraClient) GetIssueExpanded(ctx context.Context, key string, fields, expand []string) (*JiraIssue, error) {
 	var out JiraIssue
-	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/issue/"+url.PathEscape(key), nil, &out); err != nil {
+	q := url.Values{}
+	if len(fields) > 0 {
+		q.Set("fields", strings.Join(fields, ","))
+	}
+	if len(expand) > 0 {
+		q.Set("expand", strings.Join(expand, ","))
+	}
+	path := "/rest/api/3/issue/" + url.PathEscape(key)
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, &out); err != nil {
 		return nil, err
 	}
+	out.Aliased = aliasedFields(c.aliasFieldIDs(ctx), &out)
+	out.Counts = issueCounts(out.Fields)
 	return &out, nil
 }
 
-func (c *JiraClient) Search(ctx context.Context, jql string, max int) (*JiraSearchResult, error) {
+func (c *JiraClient) Search(ctx context.Context, jql, pageToken string, max int, fields []string) (*JiraSearchResult, error) {
+	if max <= 0 && c.DefaultMaxResults > 0 {
+		max = c.DefaultMaxResults
+	}
 	if max <= 0 || max > 1000 {
 		max = 50
 	}
+	return c.searchPage(ctx, jql, pageToken, max, fields, nil)
+}
+
+// offsetTokenPrefix marks page tokens for the legacy offset-based search,
+// which has no tokens of its own.
+const offsetTokenPrefix = "offset:"
+
+// searchPage fetches one page of results. It uses the token-based
+// /rest/api/3/search/jql endpoint, falling back to the legacy offset search
+// for sites that do not have it (or when JIRA_SEARCH_API=legacy). An empty
+// pageToken starts from the beginning; NextPageToken continues.
+func (c *JiraClient) searchPage(ctx context.Context, jql, pageToken string, max int, fields, expand []string) (*JiraSearchResult, error) {
+	c = c.forSite(ctx)
+	if c.legacySearch.Load() || strings.HasPrefix(pageToken, offsetTokenPrefix) {
+		return c.legacySearchPage(ctx, jql, pageToken, max, fields, expand)
+	}
 	q := url.Values{}
-	q.Set("jql", jql)
+	q.Set("jql", c.rewriteJQLAliases(ctx, jql))
 	q.Set("maxResults", fmt.Sprintf("%d", max))
+	// Unlike the legacy search, the new endpoint returns only ids by default.
+	if len(fields) > 0 {
+		q.Set("fields", strings.Join(fields, ","))
+	} else {
+		q.Set("fields", "*navigable")
+	}
+	if len(expand) > 0 {
+		q.Set("expand", strings.Join(expand, ","))
+	}
+	if pageToken != "" {
+		q.Set("nextPageToken", pageToken)
+	}
+	var out JiraSearchResult
+	err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/search/jql?"+q.Encode(), nil, &out)
+	var je *JiraError
+	if errors.As(err, &je) && je.StatusCode == http.StatusNotFound && pageToken == "" {
+		debugf("search: /rest/api/3/search/jql unavailable; using the legacy search")
+		c.legacySearch.Store(true)
+		return c.legacySearchPage(ctx, jql, "", max, fields, expand)
+	}
+	if err != nil {
+		return nil, err
+	}
+	out.IsLast = out.IsLast || out.NextPageToken == ""
+	c.decorateResults(ctx, &out)
+	return &out, nil
+}
+
+func (c *JiraClient) legacySearchPage(ctx context.Context, jql, pageToken string, max int, fields, expand []string) (*JiraSearchResult, error) {
+	startAt := 0
+	if pageToken != "" {
+		n, err := strconv.Atoi(strings.TrimPrefix(pageToken, offsetTokenPrefix))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid page token %q", pageToken)
+		}
+		startAt = n
+	}
+	q := url.Values{}
+	q.Set("jql", c.rewriteJQLAliases(ctx, jql))
+	q.Set("startAt", fmt.Sprintf("%d", startAt))
+	q.Set("maxResults", fmt.Sprintf("%d", max))
+	if len(fields) > 0 {
+		q.Set("fields", strings.Join(fields, ","))
+	}
+	if len(expand) > 0 {
+		q.Set("expand", strings.Join(expand, ","))
+	}
 	var out JiraSearchResult
 	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/search?"+q.Encode(), nil, &out); err != nil {
 		return nil, err
 	}
+	if next := out.StartAt + len(out.Issues); len(out.Issues) > 0 && next < out.Total {
+		out.NextPageToken = offsetTokenPrefix + strconv.Itoa(next)
+	} else {
+		out.IsLast = true
+	}
+	c.decorateResults(ctx, &out)
 	return &out, nil
 }
 
-func (c *JiraClient) AddComment(ctx context.Context, key, body string) error {
-	req := map[string]string{"body": body}
-	return c.doJSON(ctx, http.MethodPost, "/rest/api/3/issue/"+url.PathEscape(key)+"/comment", req, nil)
+func (c *JiraClient) decorateResults(ctx context.Context, res *JiraSearchResult) {
+	var ids map[string]string
+	if len(res.Issues) > 0 {
+		ids = c.aliasFieldIDs(ctx)
+	}
+	for i := range res.Issues {
+		res.Issues[i].Aliased = aliasedFields(ids, &res.Issues[i])
+		res.Issues[i].Counts = issueCounts(res.Issues[i].Fields)
+	}
+}
+
+// SearchAll pages through a JQL search until limit issues have been collected
+// or the result set is exhausted.
+func (c *JiraClient) SearchAll(ctx context.Context, jql string, fields []string, limit int) ([]JiraIssue, error) {
+	return c.SearchAllExpanded(ctx, jql, fields, nil, limit)
+}
+
+// SearchAllExpanded is SearchAll with expansions such as "changelog".
+func (c *JiraClient) SearchAllExpanded(ctx context.Context, jql string, fields, expand []string, limit int) ([]JiraIssue, error) {
+	var issues []JiraIssue
+	for token := ""; limit <= 0 || len(issues) < limit; {
+		page := 100
+		if limit > 0 && limit-len(issues) < page {
+			page = limit - len(issues)
+		}
+		res, err := c.searchPage(ctx, jql, token, page, fields, expand)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, res.Issues...)
+		if len(res.Issues) == 0 || res.IsLast {
+			break
+		}
+		token = res.NextPageToken
+	}
+	return issues, nil
+}
+
+type JiraComment struct {
+	ID      string         `json:"id,omitempty"`
+	Self    string         `json:"self,omitempty"`
+	Author  map[string]any `json:"author,omitempty"`
+	Body    any            `json:"body,omitempty"`
+	Created string         `json:"created,omitempty"`
+	Updated string         `json:"updated,omitempty"`
+	// JSDPublic is set on service desk issues: false for internal notes.
+	JSDPublic *bool `json:"jsdPublic,omitempty"`
+}
+
+func (c *JiraClient) AddComment(ctx context.Context, key, body string) (*JiraComment, error) {
+	return c.PostComment(ctx, key, c.richText(ctx, body))
 }
 
 func (c *JiraClient) CreateIssue(ctx context.Context, projectKey, issueType, summary, description string) (*JiraIssue, error) {
-	payload := map[string]any{
-		"fields": map[string]any{
-			"project":     map[string]any{"key": projectKey},
-			"summary":     summary,
-			"description": description,
-			"issuetype":   map[string]any{"name": issueType},
-		},
+	return c.CreateIssueFields(ctx, map[string]any{
+		"project":     map[string]any{"key": projectKey},
+		"summary":     summary,
+		"description": c.richText(ctx, description),
+		"issuetype":   map[string]any{"name": issueType},
+	})
+}
+
+// CreateIssueFields creates an issue from a raw fields map.
+func (c *JiraClient) CreateIssueFields(ctx context.Context, fields map[string]any) (*JiraIssue, error) {
+	fields, addedReporter, err := c.withProjectDefaults(ctx, fields)
+	if err != nil {
+		return nil, err
 	}
+	out, err := c.createIssue(ctx, fields)
+	if err != nil && addedReporter && reporterRejected(err.Error()) {
+		logger.Printf("JIRA_PROJECT_DEFAULTS: reporter not accepted, creating without it: %v", err)
+		delete(fields, "reporter")
+		out, err = c.createIssue(ctx, fields)
+	}
+	return out, err
+}
+
+func (c *JiraClient) createIssue(ctx context.Context, fields map[string]any) (*JiraIssue, error) {
 	var out JiraIssue
-	if err := c.doJSON(ctx, http.MethodPost, "/rest/api/3/issue", payload, &out); err != nil {
+	if err := c.doJSON(ctx, http.MethodPost, "/rest/api/3/issue", map[string]any{"fields": fields}, &out); err != nil {
 		return nil, err
 	}
+	c.recordAction(ctx, actionCreate, out.Key, "")
+	noteCreated(ctx, "issue", out.Key)
 	return &out, nil
 }
 
 // ---- MCP server (v0.8.0 API) ----
 
-func main() {
-	ctx := context.Background()
-
-	jc, err := NewJiraClientFromEnv()
-	if err != nil {
-		log.Fatalf("init error: %v", err)
-	}
-	debugf("Starting MCP server: name=%s version=%s", "jira", "0.1.0")
-
-	server := mcp.NewServer(&mcp.Implementation{
-		Name:    "jira",
-		Version: "0.1.0",
-	}, nil)
-
-	// get_issue(key)
+// registerCoreTools adds the basic issue tools: get, search, comment, and
+// create.
+func registerCoreTools(server *mcp.Server, jc *JiraClient, snapshots *snapshotStore) {
+	// get_issue(key, fields?, expand?, include_changelog?, changelog_fields?, changelog_since?, raw_adf?)
 	type getIssueArgs struct {
-		Key string `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+		Key              string   `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+		Fields           []string `json:"fields,omitempty" jsonschema:"Fields to return by name, id, or alias; slim for key/summary/status/assignee/priority/updated (default *navigable)"`
+		Expand           []string `json:"expand,omitempty" jsonschema:"Extras to fetch in the same request: renderedFields (HTML), changelog, names (field id to name), transitions"`
+		IncludeChangelog bool     `json:"include_changelog,omitempty" jsonschema:"Include the change history"`
+		ChangelogFields  []string `json:"changelog_fields,omitempty" jsonschema:"Only keep changes to these fields, e.g. status, assignee"`
+		ChangelogSince   string   `json:"changelog_since,omitempty" jsonschema:"Only keep changes at or after this date (YYYY-MM-DD or RFC 3339)"`
+		Activity         bool     `json:"activity,omitempty" jsonschema:"Add an activity timeline: comments interleaved chronologically with status, assignee, priority, and resolution changes (or changelog_fields)"`
+		RawADF           bool     `json:"raw_adf,omitempty" jsonschema:"Return rich-text fields as raw ADF instead of Markdown"`
 	}
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "get_issue",
 		Title:       "Get Issue",
 		Description: "Get a Jira issue by key",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args getIssueArgs) (*mcp.CallToolResult, any, error) {
-		debugf("tool=get_issue args={key:%q}", args.Key)
-		iss, err := jc.GetIssue(ctx, args.Key)
+		debugf("tool=get_issue args={key:%q,expand:%v,changelog:%t}", args.Key, args.Expand, args.IncludeChangelog)
+		var since time.Time
+		if args.ChangelogSince != "" {
+			t, ok := parseJiraTime(args.ChangelogSince)
+			if !ok {
+				return nil, nil, fmt.Errorf("invalid changelog_since %q", args.ChangelogSince)
+			}
+			since = t
+		}
+		fields, err := jc.resolveFieldSelection(ctx, args.Fields)
+		if err != nil {
+			return nil, nil, err
+		}
+		expand, err := normalizeExpand(args.Expand)
+		if err != nil {
+			return nil, nil, err
+		}
+		iss, err := jc.GetIssueExpanded(ctx, args.Key, fields, expand)
 		if err != nil {
 			debugf("tool=get_issue error=%v", err)
 			return nil, nil, err
 		}
+		wantChangelog := slices.Contains(expand, "changelog") || args.IncludeChangelog || len(args.ChangelogFields) > 0 || !since.IsZero()
+		if wantChangelog || args.Activity {
+			// The expanded changelog stops at 100 entries; fetch the rest.
+			all, err := jc.fullChangelog(ctx, iss)
+			if err != nil {
+				debugf("tool=get_issue changelog error=%v", err)
+				return nil, nil, err
+			}
+			if wantChangelog {
+				histories := filterChangelog(all, args.ChangelogFields, since)
+				iss.Changelog = &JiraChangelog{MaxResults: len(histories), Total: len(histories), Histories: histories}
+			}
+			if args.Activity {
+				comments, err := jc.AllComments(ctx, iss.Key)
+				if err != nil {
+					debugf("tool=get_issue comments error=%v", err)
+					return nil, nil, err
+				}
+				pivotal := args.ChangelogFields
+				if len(pivotal) == 0 {
+					pivotal = pivotalFields
+				}
+				iss.Activity = activityTimeline(comments, filterChangelog(all, pivotal, since), since)
+			}
+		}
+		if !args.RawADF {
+			renderIssueText(iss)
+		}
 		return &mcp.CallToolResult{StructuredContent: iss}, nil, nil
 	})
 
-	// search_issues(jql, max_results?)
+	// search_issues(jql, max_results?, page_token?, fields?, raw_adf?, snapshot?, ignore_focus?)
 	type searchArgs struct {
-		JQL        string `json:"jql"`
-		MaxResults int    `json:"max_results,omitempty"`
+		JQL         string   `json:"jql"`
+		MaxResults  int      `json:"max_results,omitempty"`
+		PageToken   string   `json:"page_token,omitempty" jsonschema:"nextPageToken from the previous page, to continue the same search"`
+		Fields      []string `json:"fields,omitempty" jsonschema:"Fields to return by name, id, or alias; slim for key/summary/status/assignee/priority/updated (default *navigable)"`
+		RawADF      bool     `json:"raw_adf,omitempty" jsonschema:"Return rich-text fields as raw ADF instead of Markdown"`
+		Snapshot    bool     `json:"snapshot,omitempty" jsonschema:"Freeze the full matching key list now and return its first page; read further pages with read_search_snapshot"`
+		IgnoreFocus bool     `json:"ignore_focus,omitempty" jsonschema:"Do not narrow the query to the session focus (see set_focus)"`
 	}
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "search_issues",
 		Title:       "Search Issues",
-		Description: "Search Jira with JQL",
+		Description: "Search Jira with JQL. Results are paged: pass the returned nextPageToken as page_token for more, until isLast. While a session focus is set, the query is narrowed to it",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args searchArgs) (*mcp.CallToolResult, any, error) {
-		debugf("tool=search_issues args={jql:%q,max:%d}", args.JQL, args.MaxResults)
-		res, err := jc.Search(ctx, args.JQL, args.MaxResults)
+		debugf("tool=search_issues args={jql:%q,max:%d,page:%q,snapshot:%t}", args.JQL, args.MaxResults, args.PageToken, args.Snapshot)
+		selection := args.Fields
+		if len(selection) == 0 {
+			selection = jc.DefaultFields
+		}
+		fields, err := jc.resolveFieldSelection(ctx, selection)
+		if err != nil {
+			return nil, nil, err
+		}
+		jql := args.JQL
+		if !args.IgnoreFocus {
+			jql = jc.focusFor(req.Session).applyFocus(jql)
+		}
+		if args.Snapshot {
+			snap, err := jc.SnapshotSearch(ctx, jql)
+			if err != nil {
+				debugf("tool=search_issues error=%v", err)
+				return nil, nil, err
+			}
+			snapshots.add(sessionKey(req.Session), snap)
+			page, err := jc.SnapshotPage(ctx, snap, 0, args.MaxResults, fields)
+			if err != nil {
+				debugf("tool=search_issues error=%v", err)
+				return nil, nil, err
+			}
+			if !args.RawADF {
+				for i := range page.Issues {
+					renderIssueText(&page.Issues[i])
+				}
+			}
+			return &mcp.CallToolResult{StructuredContent: page}, nil, nil
+		}
+		res, err := jc.Search(ctx, jql, args.PageToken, args.MaxResults, fields)
 		if err != nil {
 			debugf("tool=search_issues error=%v", err)
 			return nil, nil, err
 		}
+		if jql != args.JQL {
+			res.FocusedJQL = jql
+		}
+		if !args.RawADF {
+			for i := range res.Issues {
+				renderIssueText(&res.Issues[i])
+			}
+		}
 		return &mcp.CallToolResult{StructuredContent: res}, nil, nil
 	})
 
-	// add_comment(key, body)
+	// add_comment(key, body, urgent?)
 	type addCommentArgs struct {
-		Key  string `json:"key"`
-		Body string `json:"body"`
+		Key    string `json:"key"`
+		Body   string `json:"body" jsonschema:"Comment text (Markdown)"`
+		Urgent bool   `json:"urgent,omitempty" jsonschema:"Fail rather than queue the comment if Jira is unreachable (only matters with the write queue enabled)"`
+		Public *bool  `json:"public,omitempty" jsonschema:"Service desk requests only: true for a reply the customer sees, false for an internal note agents only. Sent through the service desk API, where the body is plain text"`
 	}
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "add_comment",
 		Title:       "Add Comment",
-		Description: "Add a comment to a Jira issue",
+		Description: "Add a comment to a Jira issue, or with public set, a customer reply or internal note on a service desk request. With the write queue enabled, comments that cannot reach Jira are queued and posted later",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args addCommentArgs) (*mcp.CallToolResult, any, error) {
 		preview := args.Body
 		if len(preview) > 80 {
 			preview = preview[:80] + "..."
 		}
-		debugf("tool=add_comment args={key:%q, body-preview:%q}", args.Key, preview)
-		if err := jc.AddComment(ctx, args.Key, args.Body); err != nil {
+		debugf("tool=add_comment args={key:%q, body-preview:%q, public:%v}", args.Key, preview, args.Public)
+		w := &queuedWrite{Kind: queuedComment, Key: args.Key, Body: args.Body, Public: args.Public}
+		queued, err := jc.deferWrite(ctx, args.Urgent, w, func() error {
+			_, err := jc.addComment(ctx, args.Key, args.Body, args.Public)
+			return err
+		})
+		if err != nil {
 			debugf("tool=add_comment error=%v", err)
 			return nil, nil, err
 		}
+		if queued {
+			return &mcp.CallToolResult{StructuredContent: map[string]any{
+				"queued": true, "queue_id": w.ID, "reason": w.LastError,
+			}}, nil, nil
+		}
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{&mcp.TextContent{Text: "ok"}},
 		}, nil, nil
 	})
 
-	// create_issue(project_key, issue_type, summary, description?)
+	// create_issue(project_key, issue_type, summary, description?, parent_key?, components?, fix_versions?, affected_versions?, fields?, custom_fields?)
 	type createIssueArgs struct {
-		ProjectKey  string `json:"project_key"`
-		IssueType   string `json:"issue_type"`
-		Summary     string `json:"summary"`
-		Description string `json:"description,omitempty"`
+		ProjectKey  string         `json:"project_key,omitempty" jsonschema:"Project key; defaults to the parent's project when parent_key is set, else the session focus"`
+		IssueType   string         `json:"issue_type,omitempty" jsonschema:"Issue type name; defaults to the project's subtask type when parent_key is set"`
+		Summary     string         `json:"summary"`
+		ParentKey   string         `json:"parent_key,omitempty" jsonschema:"Parent issue key, to create a subtask (or a child of an epic)"`
+		Description string         `json:"description,omitempty" jsonschema:"Issue description (Markdown)"`
+		Components  []string       `json:"components,omitempty" jsonschema:"Component names or ids"`
+		FixVersions []string       `json:"fix_versions,omitempty" jsonschema:"Fix version names or ids"`
+		Affects     []string       `json:"affected_versions,omitempty" jsonschema:"Affected version names or ids"`
+		Fields      map[string]any `json:"fields,omitempty" jsonschema:"Additional fields keyed by field id, field name, or configured alias"`
+		Custom      map[string]any `json:"custom_fields,omitempty" jsonschema:"Custom field values keyed by field name, given plainly (e.g. {\"Story Points\": 5, \"Team\": \"Platform\"}); values are converted to the field's type"`
 	}
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "create_issue",
 		Title:       "Create Issue",
-		Description: "Create a Jira issue",
+		Description: "Create a Jira issue, or a subtask when parent_key is given",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args createIssueArgs) (*mcp.CallToolResult, any, error) {
-		debugf("tool=create_issue args={project:%q,type:%q,summary:%q,desc-len:%d}",
-			args.ProjectKey, args.IssueType, args.Summary, len(args.Description))
-		iss, err := jc.CreateIssue(ctx, args.ProjectKey, args.IssueType, args.Summary, args.Description)
+		debugf("tool=create_issue args={project:%q,type:%q,parent:%q,summary:%q,desc-len:%d}",
+			args.ProjectKey, args.IssueType, args.ParentKey, args.Summary, len(args.Description))
+		if args.ParentKey != "" {
+			if args.ProjectKey == "" {
+				args.ProjectKey, _, _ = strings.Cut(args.ParentKey, "-")
+			}
+			if args.IssueType == "" {
+				st, err := jc.SubtaskType(ctx, args.ProjectKey)
+				if err != nil {
+					debugf("tool=create_issue error=%v", err)
+					return nil, nil, err
+				}
+				args.IssueType = st.Name
+			}
+		}
+		if f := jc.focusFor(req.Session); args.ProjectKey == "" && f != nil {
+			args.ProjectKey = f.Project
+		}
+		if args.ProjectKey == "" || args.IssueType == "" {
+			return nil, nil, errors.New("project_key and issue_type are required unless parent_key is set")
+		}
+		extra, err := jc.resolveFieldKeys(ctx, args.Fields)
+		if err != nil {
+			return nil, nil, err
+		}
+		custom, err := jc.coerceCustomFields(ctx, args.Custom)
+		if err != nil {
+			return nil, nil, err
+		}
+		fields := map[string]any{
+			"project":   map[string]any{"key": args.ProjectKey},
+			"summary":   args.Summary,
+			"issuetype": map[string]any{"name": args.IssueType},
+		}
+		if args.Description != "" {
+			fields["description"] = jc.richText(ctx, args.Description)
+		}
+		if args.ParentKey != "" {
+			fields["parent"] = map[string]any{"key": args.ParentKey}
+		}
+		if len(args.Components) > 0 {
+			refs, err := jc.componentRefs(ctx, args.ProjectKey, args.Components)
+			if err != nil {
+				return nil, nil, err
+			}
+			fields["components"] = refs
+		}
+		for id, names := range map[string][]string{"fixVersions": args.FixVersions, "versions": args.Affects} {
+			if len(names) == 0 {
+				continue
+			}
+			refs, err := jc.versionRefs(ctx, args.ProjectKey, names)
+			if err != nil {
+				return nil, nil, err
+			}
+			fields[id] = refs
+		}
+		for k, v := range extra {
+			fields[k] = v
+		}
+		for k, v := range custom {
+			fields[k] = v
+		}
+		iss, err := jc.CreateIssueFields(ctx, fields)
 		if err != nil {
 			debugf("tool=create_issue error=%v", err)
 			return nil, nil, err
 		}
 		return &mcp.CallToolResult{StructuredContent: iss}, nil, nil
 	})
-
-	// Run over stdio (for IDE/hosts)
-	if err := server.Run(ctx, &mcp.StdioTransport{}); err != nil {
-		log.Fatalf("server failed: %v", err)
-	}
 }
@@ -0,0 +1,256 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Notifications ----
+//
+// Jira's notification inbox has no public API. my_notifications
+// approximates it from search: comments mentioning the user, issues
+// assigned to them, and activity by others on issues they watch, since a
+// point in time, as one list ordered by how likely each needs attention.
+
+// notificationRank orders notification kinds, most urgent first.
+var notificationRank = map[string]int{"mention": 0, "assigned": 1, "update": 2}
+
+var notificationPriority = map[string]string{"mention": "high", "assigned": "medium", "update": "low"}
+
+type notification struct {
+	Priority string `json:"priority"` // high, medium, or low
+	Kind     string `json:"kind"`     // mention, assigned, or update
+	Key      string `json:"key"`
+	Summary  string `json:"summary"`
+	At       string `json:"at"`
+	By       string `json:"by,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	URL      string `json:"url"`
+
+	at time.Time
+}
+
+// mentions reports whether a comment body mentions accountID.
+func mentions(body any, accountID string) bool {
+	if s, ok := body.(string); ok {
+		return strings.Contains(s, "[~accountid:"+accountID+"]")
+	}
+	var walk func(n *adfNode) bool
+	walk = func(n *adfNode) bool {
+		if n.Type == "mention" && attrString(n, "id") == accountID {
+			return true
+		}
+		for _, child := range n.Content {
+			if child != nil && walk(child) {
+				return true
+			}
+		}
+		return false
+	}
+	n := decodeADF(body)
+	return n != nil && walk(n)
+}
+
+// issueComments returns the comments a search returned for iss, fetching
+// the rest when the search truncated them.
+func (c *JiraClient) issueComments(ctx context.Context, iss *JiraIssue) ([]JiraComment, error) {
+	var page struct {
+		Comments []JiraComment `json:"comments"`
+		Total    int           `json:"total"`
+	}
+	if raw, ok := iss.Fields["comment"]; ok {
+		b, _ := json.Marshal(raw)
+		_ = json.Unmarshal(b, &page)
+	}
+	if len(page.Comments) >= page.Total {
+		return page.Comments, nil
+	}
+	return c.AllComments(ctx, iss.Key)
+}
+
+// Notifications collects what happened since since that concerns me.
+func (c *JiraClient) Notifications(ctx context.Context, me *JiraUser, since time.Time, maxIssues int) ([]notification, error) {
+	// JQL dates are in the user's time zone; search a day wider and filter
+	// on exact timestamps below.
+	day := since.Add(-24 * time.Hour).Format("2006-01-02")
+	who := "watcher = currentUser() OR assignee = currentUser()"
+	if me.DisplayName != "" {
+		// Mentions are indexed as the mentioned user's name.
+		who += fmt.Sprintf(" OR comment ~ %q", `"`+me.DisplayName+`"`)
+	}
+	jql := fmt.Sprintf("(%s) AND updated >= %q ORDER BY updated DESC", who, day)
+	issues, err := c.SearchAllExpanded(ctx, jql, []string{"summary", "comment", "watches"}, []string{"changelog"}, maxIssues)
+	if err != nil {
+		return nil, err
+	}
+	var out []notification
+	for i := range issues {
+		iss := &issues[i]
+		link := c.forSite(ctx).BaseURL + "/browse/" + url.PathEscape(iss.Key)
+		base := notification{Key: iss.Key, Summary: fieldString(iss.Fields, "summary")}
+		var others []string
+		var latest notification
+		note := func(n notification, t time.Time, by string) {
+			if !containsFold(others, by) {
+				others = append(others, by)
+			}
+			if t.After(latest.at) {
+				latest = n
+				latest.at = t
+			}
+		}
+
+		comments, err := c.issueComments(ctx, iss)
+		if err != nil {
+			return nil, err
+		}
+		for _, cm := range comments {
+			t, _ := parseJiraTime(cm.Created)
+			if t.Before(since) || fieldString(cm.Author, "accountId") == me.AccountID {
+				continue
+			}
+			by := fieldString(cm.Author, "displayName")
+			n := base
+			n.At, n.By, n.at = cm.Created, by, t
+			n.URL = link + "?focusedCommentId=" + url.QueryEscape(cm.ID)
+			n.Detail = truncateRunes(adfToMarkdown(cm.Body), 200)
+			if mentions(cm.Body, me.AccountID) {
+				n.Kind = "mention"
+				out = append(out, n)
+			}
+			n.Detail = "commented: " + n.Detail
+			note(n, t, by)
+		}
+
+		histories, err := c.fullChangelog(ctx, iss)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range histories {
+			t, _ := parseJiraTime(h.Created)
+			if t.Before(since) || fieldString(h.Author, "accountId") == me.AccountID {
+				continue
+			}
+			by := fieldString(h.Author, "displayName")
+			n := base
+			n.At, n.By, n.at, n.URL = h.Created, by, t, link
+			var changes []string
+			for _, it := range h.Items {
+				if it.FieldID == "assignee" || strings.EqualFold(it.Field, "assignee") {
+					if it.To == me.AccountID {
+						a := n
+						a.Kind = "assigned"
+						a.Detail = "assigned to you"
+						if it.FromString != "" {
+							a.Detail += " (was " + it.FromString + ")"
+						}
+						out = append(out, a)
+					}
+				}
+				changes = append(changes, fmt.Sprintf("%s: %s -> %s", strings.ToLower(it.Field), orNone(it.FromString), orNone(it.ToString)))
+			}
+			n.Detail = strings.Join(changes, "; ")
+			note(n, t, by)
+		}
+
+		// Activity on watched issues, unless already reported above.
+		watching, _ := fieldPath(iss.Fields, "watches", "isWatching").(bool)
+		if watching && !latest.at.IsZero() && !notifiedAbout(out, iss.Key) {
+			n := latest
+			n.Kind = "update"
+			n.By = strings.Join(others, ", ")
+			n.Detail = "latest: " + latest.Detail
+			out = append(out, n)
+		}
+	}
+	for i := range out {
+		out[i].Priority = notificationPriority[out[i].Kind]
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if ri, rj := notificationRank[out[i].Kind], notificationRank[out[j].Kind]; ri != rj {
+			return ri < rj
+		}
+		return out[i].at.After(out[j].at)
+	})
+	return out, nil
+}
+
+func notifiedAbout(list []notification, key string) bool {
+	for _, n := range list {
+		if n.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+// truncateRunes shortens s to at most n runes, marking the cut.
+func truncateRunes(s string, n int) string {
+	r := []rune(strings.Join(strings.Fields(s), " "))
+	if len(r) <= n {
+		return string(r)
+	}
+	return string(r[:n]) + "…"
+}
+
+func registerNotificationTools(server *mcp.Server, jc *JiraClient) {
+	// my_notifications(since?, max_issues?)
+	type notificationsArgs struct {
+		Since     string `json:"since,omitempty" jsonschema:"Only activity at or after this time (YYYY-MM-DD or RFC 3339; default 24 hours ago)"`
+		MaxIssues int    `json:"max_issues,omitempty" jsonschema:"Maximum issues to examine (default 100)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "my_notifications",
+		Title:       "My Notifications",
+		Description: "What needs my attention: comments mentioning me, issues assigned to me, and others' activity on issues I watch since a time, most urgent first, with links",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args notificationsArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=my_notifications args={since:%q,max:%d}", args.Since, args.MaxIssues)
+		since := time.Now().Add(-24 * time.Hour)
+		if args.Since != "" {
+			t, ok := parseJiraTime(args.Since)
+			if !ok {
+				return nil, nil, fmt.Errorf("invalid since %q", args.Since)
+			}
+			since = t
+		}
+		max := args.MaxIssues
+		if max <= 0 {
+			max = 100
+		}
+		me, err := jc.Myself(ctx)
+		if err != nil {
+			debugf("tool=my_notifications error=%v", err)
+			return nil, nil, err
+		}
+		items, err := jc.Notifications(ctx, me, since, max)
+		if err != nil {
+			debugf("tool=my_notifications error=%v", err)
+			return nil, nil, err
+		}
+		if items == nil {
+			items = []notification{}
+		}
+		counts := map[string]int{}
+		for _, n := range items {
+			counts[n.Kind]++
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{
+			"since": since.Format(time.RFC3339), "notifications": items, "counts": counts,
+		}}, nil, nil
+	})
+}
@@ -0,0 +1,335 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ---- OAuth 2.0 (3LO) ----
+//
+// JIRA_AUTH=oauth authenticates through an Atlassian OAuth 2.0 app instead
+// of an API token. JIRA_OAUTH configures it:
+//
+//	{"client_id": "...", "client_secret_env": "JIRA_OAUTH_SECRET",
+//	 "redirect_url": "http://localhost:8089/callback",
+//	 "token_path": "/var/lib/jira-mcp/oauth.json"}
+//
+// With no stored refresh token, startup logs an authorization URL and waits
+// for the browser to come back to redirect_url. Access tokens are refreshed
+// shortly before they expire, and the rotated refresh token is written back
+// to token_path. Requests go to api.atlassian.com/ex/jira/{cloudId}; the
+// cloud id is looked up from JIRA_INSTANCE_URL unless cloud_id is set.
+
+var defaultOAuthScopes = []string{"read:jira-work", "write:jira-work", "read:jira-user", "manage:jira-project", "offline_access"}
+
+// oauthAuthorizeTimeout bounds how long startup waits for the user to
+// complete the browser consent.
+const oauthAuthorizeTimeout = 5 * time.Minute
+
+type oauthConfig struct {
+	ClientID        string   `json:"client_id"`
+	ClientSecret    string   `json:"client_secret,omitempty"`
+	ClientSecretEnv string   `json:"client_secret_env,omitempty"`
+	RedirectURL     string   `json:"redirect_url,omitempty"` // default http://localhost:8089/callback
+	Scopes          []string `json:"scopes,omitempty"`
+	TokenPath       string   `json:"token_path"`
+	CloudID         string   `json:"cloud_id,omitempty"`
+	AuthURL         string   `json:"auth_url,omitempty"` // default https://auth.atlassian.com
+	APIURL          string   `json:"api_url,omitempty"`  // default https://api.atlassian.com
+}
+
+// oauthToken is what token_path holds.
+type oauthToken struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	Expiry       time.Time `json:"expiry"`
+	CloudID      string    `json:"cloud_id,omitempty"`
+}
+
+// oauthSource hands out access tokens, refreshing them as needed.
+type oauthSource struct {
+	cfg    oauthConfig
+	secret string
+	client *http.Client
+
+	mu  sync.Mutex
+	tok oauthToken
+}
+
+// loadOAuth reads JIRA_OAUTH and the stored token, running the
+// authorization-code flow when there is no refresh token yet.
+func loadOAuth(site string, client *http.Client) (*oauthSource, error) {
+	var cfg oauthConfig
+	ok, err := loadJSONSetting("JIRA_OAUTH", &cfg)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("JIRA_AUTH=oauth needs JIRA_OAUTH")
+	}
+	o, err := newOAuthSource(cfg, site, client)
+	if err != nil {
+		return nil, fmt.Errorf("JIRA_OAUTH: %w", err)
+	}
+	return o, nil
+}
+
+// newOAuthSource validates cfg and loads its token for site.
+func newOAuthSource(cfg oauthConfig, site string, client *http.Client) (*oauthSource, error) {
+	o := &oauthSource{cfg: cfg, secret: cfg.ClientSecret, client: client}
+	if cfg.ClientSecretEnv != "" {
+		o.secret = os.Getenv(cfg.ClientSecretEnv)
+	}
+	switch {
+	case cfg.ClientID == "":
+		return nil, errors.New("client_id is required")
+	case o.secret == "":
+		return nil, errors.New("client_secret or client_secret_env must be set")
+	case cfg.TokenPath == "":
+		return nil, errors.New("token_path is required")
+	}
+	if o.cfg.RedirectURL == "" {
+		o.cfg.RedirectURL = "http://localhost:8089/callback"
+	}
+	if len(o.cfg.Scopes) == 0 {
+		o.cfg.Scopes = defaultOAuthScopes
+	}
+	if o.cfg.AuthURL == "" {
+		o.cfg.AuthURL = "https://auth.atlassian.com"
+	}
+	if o.cfg.APIURL == "" {
+		o.cfg.APIURL = "https://api.atlassian.com"
+	}
+	o.cfg.AuthURL = strings.TrimRight(o.cfg.AuthURL, "/")
+	o.cfg.APIURL = strings.TrimRight(o.cfg.APIURL, "/")
+
+	b, err := os.ReadFile(cfg.TokenPath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, err
+	default:
+		if err := json.Unmarshal(b, &o.tok); err != nil {
+			return nil, fmt.Errorf("%s: %w", cfg.TokenPath, err)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), oauthAuthorizeTimeout)
+	defer cancel()
+	if o.tok.RefreshToken == "" {
+		if err := o.authorize(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.CloudID != "" {
+		o.tok.CloudID = cfg.CloudID
+	}
+	if o.tok.CloudID == "" {
+		if err := o.discoverCloudID(ctx, site); err != nil {
+			return nil, err
+		}
+		if err := o.save(); err != nil {
+			return nil, err
+		}
+	}
+	debugf("oauth: cloud id %s, token expires %s", o.tok.CloudID, o.tok.Expiry.Format(time.RFC3339))
+	return o, nil
+}
+
+// apiBaseURL is where Jira REST calls go with an OAuth token.
+func (o *oauthSource) apiBaseURL() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.cfg.APIURL + "/ex/jira/" + o.tok.CloudID
+}
+
+// Token returns a current access token, refreshing it if it expires within
+// the next minute.
+func (o *oauthSource) Token(ctx context.Context) (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.tok.AccessToken != "" && time.Until(o.tok.Expiry) > time.Minute {
+		return o.tok.AccessToken, nil
+	}
+	debugf("oauth: refreshing access token")
+	err := o.exchange(ctx, map[string]string{"grant_type": "refresh_token", "refresh_token": o.tok.RefreshToken})
+	if err != nil {
+		return "", fmt.Errorf("oauth token refresh: %w", err)
+	}
+	return o.tok.AccessToken, nil
+}
+
+// exchange posts a grant to the token endpoint and stores the result.
+// Callers hold o.mu, except during startup.
+func (o *oauthSource) exchange(ctx context.Context, grant map[string]string) error {
+	grant["client_id"] = o.cfg.ClientID
+	grant["client_secret"] = o.secret
+	var out struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := o.call(ctx, http.MethodPost, o.cfg.AuthURL+"/oauth/token", "", grant, &out); err != nil {
+		return err
+	}
+	o.tok.AccessToken = out.AccessToken
+	o.tok.Expiry = time.Now().Add(time.Duration(out.ExpiresIn) * time.Second)
+	if out.RefreshToken != "" {
+		o.tok.RefreshToken = out.RefreshToken // Atlassian rotates refresh tokens
+	}
+	return o.save()
+}
+
+// call makes a JSON request outside the Jira site (token endpoint,
+// accessible resources).
+func (o *oauthSource) call(ctx context.Context, method, u, bearer string, body, out any) error {
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s %s: %s - %s", method, u, resp.Status, b)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// authorize runs the authorization-code flow: it logs the consent URL,
+// waits for the redirect on redirect_url, and exchanges the code.
+func (o *oauthSource) authorize(ctx context.Context) error {
+	redirect, err := url.Parse(o.cfg.RedirectURL)
+	if err != nil || redirect.Host == "" {
+		return fmt.Errorf("invalid redirect_url %q", o.cfg.RedirectURL)
+	}
+	var nonce [16]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return err
+	}
+	state := hex.EncodeToString(nonce[:])
+
+	ln, err := net.Listen("tcp", redirect.Host)
+	if err != nil {
+		return fmt.Errorf("listening for the OAuth redirect: %w", err)
+	}
+	codes := make(chan string, 1)
+	errs := make(chan error, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(redirect.Path, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case q.Get("state") != state:
+			http.Error(w, "state mismatch", http.StatusBadRequest)
+			return
+		case q.Get("error") != "":
+			http.Error(w, "authorization failed: "+q.Get("error_description"), http.StatusBadRequest)
+			select {
+			case errs <- fmt.Errorf("authorization failed: %s %s", q.Get("error"), q.Get("error_description")):
+			default:
+			}
+			return
+		}
+		fmt.Fprintln(w, "Jira authorization complete; you can close this window.")
+		select {
+		case codes <- q.Get("code"):
+		default:
+		}
+	})
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	q := url.Values{}
+	q.Set("audience", "api.atlassian.com")
+	q.Set("client_id", o.cfg.ClientID)
+	q.Set("scope", strings.Join(o.cfg.Scopes, " "))
+	q.Set("redirect_uri", o.cfg.RedirectURL)
+	q.Set("state", state)
+	q.Set("response_type", "code")
+	q.Set("prompt", "consent")
+	logger.Printf("Jira OAuth: open this URL to authorize the server (waiting up to %s):\n%s", oauthAuthorizeTimeout, o.cfg.AuthURL+"/authorize?"+q.Encode())
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("no authorization received: %w", ctx.Err())
+	case err := <-errs:
+		return err
+	case code := <-codes:
+		return o.exchange(ctx, map[string]string{
+			"grant_type": "authorization_code", "code": code, "redirect_uri": o.cfg.RedirectURL,
+		})
+	}
+}
+
+// discoverCloudID finds the cloud id of site among the resources the token
+// can reach.
+func (o *oauthSource) discoverCloudID(ctx context.Context, site string) error {
+	token, err := o.Token(ctx)
+	if err != nil {
+		return err
+	}
+	var resources []struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	if err := o.call(ctx, http.MethodGet, o.cfg.APIURL+"/oauth/token/accessible-resources", token, nil, &resources); err != nil {
+		return err
+	}
+	var urls []string
+	for _, r := range resources {
+		if strings.EqualFold(strings.TrimRight(r.URL, "/"), site) {
+			o.tok.CloudID = r.ID
+			return nil
+		}
+		urls = append(urls, r.URL)
+	}
+	return fmt.Errorf("the OAuth app is not authorized for %s (authorized sites: %s)", site, orNone(strings.Join(urls, ", ")))
+}
+
+// save writes the token file atomically.
+func (o *oauthSource) save() error {
+	b, err := json.MarshalIndent(o.tok, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(o.cfg.TokenPath), 0o700); err != nil {
+		return err
+	}
+	tmp := o.cfg.TokenPath + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, o.cfg.TokenPath)
+}
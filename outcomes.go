@@ -0,0 +1,279 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Agent outcome signals ----
+//
+// Writes made during tool calls are remembered per session. When stats are
+// requested, each remembered action is checked against the detectors for
+// its kind, giving evidence of whether the agent's changes stuck: issues it
+// created later closed as duplicates, comments deleted soon after posting,
+// transitions someone undid. New signals are added to outcomeDetectors.
+
+const (
+	actionCreate     = "create_issue"
+	actionComment    = "comment"
+	actionTransition = "transition"
+)
+
+// maxTrackedActions bounds memory; the oldest actions are dropped first.
+const maxTrackedActions = 5000
+
+type agentAction struct {
+	Kind      string
+	Session   string
+	Tool      string
+	Key       string
+	CommentID string
+	At        time.Time
+
+	deletedAt time.Time       // comments deleted through this server
+	signals   map[string]bool // detectors that fired; signals are sticky
+}
+
+type outcomeTracker struct {
+	mu      sync.Mutex
+	actions []*agentAction
+}
+
+// recordAction remembers a write made during a tool call. Writes outside tool
+// calls (background jobs) are not the agent's and are ignored.
+func (c *JiraClient) recordAction(ctx context.Context, kind, key, commentID string) {
+	tc := currentToolCall(ctx)
+	if tc == nil {
+		return
+	}
+	session := sessionKey(tc.Session)
+	t := &c.outcomes
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.actions = append(t.actions, &agentAction{
+		Kind: kind, Session: session, Tool: tc.Name, Key: key, CommentID: commentID, At: time.Now(),
+	})
+	if n := len(t.actions); n > maxTrackedActions {
+		t.actions = append([]*agentAction(nil), t.actions[n-maxTrackedActions:]...)
+	}
+}
+
+// noteCommentDeleted records when a tracked comment was deleted through
+// this server, so deletion timing is exact.
+func (c *JiraClient) noteCommentDeleted(key, id string) {
+	t := &c.outcomes
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, a := range t.actions {
+		if a.Kind == actionComment && a.Key == key && a.CommentID == id && a.deletedAt.IsZero() {
+			a.deletedAt = time.Now()
+		}
+	}
+}
+
+type outcomeDetector struct {
+	Name        string
+	Kind        string
+	Description string
+	Detect      func(ctx context.Context, c *JiraClient, a *agentAction, now time.Time) (bool, error)
+}
+
+var outcomeDetectors = []outcomeDetector{
+	{
+		Name:        "closed_as_duplicate",
+		Kind:        actionCreate,
+		Description: "Created issue was later resolved as a duplicate",
+		Detect:      detectClosedAsDuplicate,
+	},
+	{
+		Name:        "comment_deleted_within_hour",
+		Kind:        actionComment,
+		Description: "Comment was deleted within an hour of being posted",
+		Detect:      detectCommentDeleted,
+	},
+	{
+		Name:        "transition_reverted",
+		Kind:        actionTransition,
+		Description: "Issue was moved back to the status it had before the transition",
+		Detect:      detectTransitionReverted,
+	},
+}
+
+func detectClosedAsDuplicate(ctx context.Context, c *JiraClient, a *agentAction, now time.Time) (bool, error) {
+	iss, err := c.issueFields(ctx, a.Key, "resolution,status")
+	if err != nil {
+		return false, err
+	}
+	return strings.Contains(strings.ToLower(fieldString(iss.Fields, "resolution", "name")), "duplicate") ||
+		strings.EqualFold(fieldString(iss.Fields, "status", "name"), "duplicate"), nil
+}
+
+// detectCommentDeleted can only place a deletion in time when it went
+// through this server or is seen within the hour; older disappearances are
+// not counted.
+func detectCommentDeleted(ctx context.Context, c *JiraClient, a *agentAction, now time.Time) (bool, error) {
+	if !a.deletedAt.IsZero() {
+		return a.deletedAt.Sub(a.At) <= time.Hour, nil
+	}
+	if now.Sub(a.At) > time.Hour {
+		return false, nil
+	}
+	_, err := c.GetComment(ctx, a.Key, a.CommentID)
+	var je *JiraError
+	if errors.As(err, &je) && je.StatusCode == http.StatusNotFound {
+		return true, nil
+	}
+	return false, err
+}
+
+func detectTransitionReverted(ctx context.Context, c *JiraClient, a *agentAction, now time.Time) (bool, error) {
+	histories, err := c.IssueChangelog(ctx, a.Key)
+	if err != nil {
+		return false, err
+	}
+	// The first status change at or after the action is ours (allowing for
+	// clock skew); a later change back to its from-status is a revert.
+	var from string
+	for _, h := range histories {
+		at, ok := parseJiraTime(h.Created)
+		if !ok || at.Before(a.At.Add(-time.Minute)) {
+			continue
+		}
+		for _, it := range h.Items {
+			if it.Field != "status" {
+				continue
+			}
+			if from == "" {
+				from = it.FromString
+			} else if it.ToString == from {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+type toolOutcomeStats struct {
+	Actions int            `json:"actions"`
+	Signals map[string]int `json:"signals"`
+}
+
+type sessionOutcomeStats struct {
+	Session string                       `json:"session"`
+	Tools   map[string]*toolOutcomeStats `json:"tools"`
+}
+
+// maxOutcomeChecks caps how many actions one stats request checks against
+// Jira; the most recent are checked first.
+const maxOutcomeChecks = 200
+
+// OutcomeStats evaluates tracked actions, optionally for one session only.
+func (c *JiraClient) OutcomeStats(ctx context.Context, session string) ([]sessionOutcomeStats, []string) {
+	t := &c.outcomes
+	t.mu.Lock()
+	actions := make([]*agentAction, 0, len(t.actions))
+	for _, a := range t.actions {
+		if session == "" || a.Session == session {
+			actions = append(actions, a)
+		}
+	}
+	t.mu.Unlock()
+
+	now := time.Now()
+	var warnings []string
+	checks := 0
+	for i := len(actions) - 1; i >= 0; i-- {
+		a := actions[i]
+		for _, d := range outcomeDetectors {
+			t.mu.Lock()
+			fired, snap := a.signals[d.Name], *a
+			t.mu.Unlock()
+			if d.Kind != a.Kind || fired || checks >= maxOutcomeChecks {
+				continue
+			}
+			checks++
+			ok, err := d.Detect(ctx, c, &snap, now)
+			if err != nil {
+				warnings = append(warnings, d.Name+" "+a.Key+": "+err.Error())
+				continue
+			}
+			if ok {
+				t.mu.Lock()
+				if a.signals == nil {
+					a.signals = map[string]bool{}
+				}
+				a.signals[d.Name] = true
+				t.mu.Unlock()
+			}
+		}
+	}
+	if checks >= maxOutcomeChecks {
+		warnings = append(warnings, "check limit reached; older actions were not re-checked")
+	}
+
+	bySession := map[string]*sessionOutcomeStats{}
+	t.mu.Lock()
+	for _, a := range actions {
+		s := bySession[a.Session]
+		if s == nil {
+			s = &sessionOutcomeStats{Session: a.Session, Tools: map[string]*toolOutcomeStats{}}
+			bySession[a.Session] = s
+		}
+		ts := s.Tools[a.Tool]
+		if ts == nil {
+			ts = &toolOutcomeStats{Signals: map[string]int{}}
+			s.Tools[a.Tool] = ts
+		}
+		ts.Actions++
+		for name := range a.signals {
+			ts.Signals[name]++
+		}
+	}
+	t.mu.Unlock()
+	out := make([]sessionOutcomeStats, 0, len(bySession))
+	for _, s := range bySession {
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Session < out[j].Session })
+	return out, warnings
+}
+
+func registerOutcomeTools(server *mcp.Server, jc *JiraClient) {
+	// agent_outcome_stats(session?)
+	type statsArgs struct {
+		Session string `json:"session,omitempty" jsonschema:"Session id, current for this session, or omit for all sessions"`
+	}
+	signals := make([]string, 0, len(outcomeDetectors))
+	for _, d := range outcomeDetectors {
+		signals = append(signals, d.Name)
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "agent_outcome_stats",
+		Title:       "Agent Outcome Stats",
+		Description: "Per-session, per-tool counts of Jira writes made through this server and of later signals that they did not help (" + strings.Join(signals, ", ") + ")",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args statsArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=agent_outcome_stats args={session:%q}", args.Session)
+		session := args.Session
+		if session == "current" {
+			session = sessionKey(req.Session)
+		}
+		stats, warnings := jc.OutcomeStats(ctx, session)
+		detectors := make([]map[string]string, 0, len(outcomeDetectors))
+		for _, d := range outcomeDetectors {
+			detectors = append(detectors, map[string]string{"name": d.Name, "applies_to": d.Kind, "description": d.Description})
+		}
+		out := map[string]any{"sessions": stats, "signals": detectors}
+		if len(warnings) > 0 {
+			out["warnings"] = warnings
+		}
+		return &mcp.CallToolResult{StructuredContent: out}, nil, nil
+	})
+}
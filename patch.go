@@ -0,0 +1,168 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Patch attachment summaries ----
+
+const patchPreviewLines = 40
+
+type patchFile struct {
+	Path    string `json:"path"`
+	OldPath string `json:"old_path,omitempty"` // set for renames
+	Status  string `json:"status"`             // modified, added, deleted, renamed, binary
+	Hunks   int    `json:"hunks"`
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+}
+
+type patchSummary struct {
+	AttachmentID string      `json:"attachment_id"`
+	Filename     string      `json:"filename"`
+	Files        []patchFile `json:"files"`
+	Hunks        int         `json:"hunks"`
+	Added        int         `json:"added"`
+	Removed      int         `json:"removed"`
+	Preview      string      `json:"preview"`
+	Truncated    bool        `json:"preview_truncated,omitempty"`
+}
+
+func stripDiffPrefix(p string) string {
+	p = strings.TrimSpace(strings.SplitN(p, "\t", 2)[0])
+	if strings.HasPrefix(p, "a/") || strings.HasPrefix(p, "b/") {
+		return p[2:]
+	}
+	return p
+}
+
+var hunkHeaderRe = regexp.MustCompile(`^@@ -\d+(?:,(\d+))? \+\d+(?:,(\d+))? @@`)
+
+func hunkCount(s string) int {
+	if s == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// parseUnifiedDiff summarizes a unified diff (git or plain diff -u output).
+func parseUnifiedDiff(text string) []patchFile {
+	var files []patchFile
+	var cur *patchFile
+	start := func(path string) {
+		files = append(files, patchFile{Path: path, Status: "modified"})
+		cur = &files[len(files)-1]
+	}
+	// Lines left in the current hunk, per its @@ header, so "---" lines
+	// inside a hunk and trailers after it are not miscounted.
+	oldLeft, newLeft := 0, 0
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if oldLeft > 0 || newLeft > 0 {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				cur.Added++
+				newLeft--
+			case strings.HasPrefix(line, "-"):
+				cur.Removed++
+				oldLeft--
+			case strings.HasPrefix(line, `\`):
+			default:
+				oldLeft--
+				newLeft--
+			}
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			parts := strings.Fields(line)
+			start(stripDiffPrefix(parts[len(parts)-1]))
+		case strings.HasPrefix(line, "--- "):
+			old := stripDiffPrefix(line[4:])
+			if cur == nil || cur.Hunks > 0 {
+				start(old)
+			}
+			if old == "/dev/null" {
+				cur.Status = "added"
+			}
+		case strings.HasPrefix(line, "+++ ") && cur != nil:
+			if p := stripDiffPrefix(line[4:]); p == "/dev/null" {
+				cur.Status = "deleted"
+			} else {
+				cur.Path = p
+			}
+		case strings.HasPrefix(line, "new file mode") && cur != nil:
+			cur.Status = "added"
+		case strings.HasPrefix(line, "deleted file mode") && cur != nil:
+			cur.Status = "deleted"
+		case strings.HasPrefix(line, "rename from ") && cur != nil:
+			cur.OldPath, cur.Status = strings.TrimPrefix(line, "rename from "), "renamed"
+		case strings.HasPrefix(line, "Binary files ") && cur != nil:
+			cur.Status = "binary"
+		case strings.HasPrefix(line, "@@") && cur != nil:
+			cur.Hunks++
+			if m := hunkHeaderRe.FindStringSubmatch(line); m != nil {
+				oldLeft, newLeft = hunkCount(m[1]), hunkCount(m[2])
+			}
+		}
+	}
+	return files
+}
+
+func looksLikeDiff(filename, mime, text string) bool {
+	name := strings.ToLower(filename)
+	if strings.HasSuffix(name, ".patch") || strings.HasSuffix(name, ".diff") || strings.Contains(mime, "diff") || strings.Contains(mime, "patch") {
+		return true
+	}
+	return strings.Contains(text, "\n@@ ") && (strings.Contains(text, "\n+++ ") || strings.HasPrefix(text, "diff --git "))
+}
+
+func registerPatchTools(server *mcp.Server, jc *JiraClient) {
+	// get_patch_summary(attachment_id)
+	type patchArgs struct {
+		AttachmentID string `json:"attachment_id" jsonschema:"Id of a .patch or .diff attachment (see list_attachments)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_patch_summary",
+		Title:       "Get Patch Summary",
+		Description: "Summarize a .patch/.diff attachment: changed files with hunk and line counts, plus a short preview",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args patchArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=get_patch_summary args={id:%q}", args.AttachmentID)
+		meta, err := jc.GetAttachment(ctx, args.AttachmentID)
+		if err != nil {
+			debugf("tool=get_patch_summary error=%v", err)
+			return nil, nil, err
+		}
+		if meta.Size > attachmentMaxBytes() {
+			return nil, nil, fmt.Errorf("attachment %s is %d bytes, over the %d byte limit", args.AttachmentID, meta.Size, attachmentMaxBytes())
+		}
+		b, _, err := jc.AttachmentContent(ctx, args.AttachmentID, false)
+		if err != nil {
+			debugf("tool=get_patch_summary error=%v", err)
+			return nil, nil, err
+		}
+		text := string(b)
+		if !looksLikeDiff(meta.Filename, meta.MimeType, text) {
+			return nil, nil, fmt.Errorf("attachment %s (%s) does not look like a diff or patch", args.AttachmentID, meta.Filename)
+		}
+		sum := patchSummary{AttachmentID: args.AttachmentID, Filename: meta.Filename, Files: parseUnifiedDiff(text)}
+		for _, f := range sum.Files {
+			sum.Hunks += f.Hunks
+			sum.Added += f.Added
+			sum.Removed += f.Removed
+		}
+		lines := strings.Split(text, "\n")
+		if len(lines) > patchPreviewLines {
+			lines, sum.Truncated = lines[:patchPreviewLines], true
+		}
+		sum.Preview = strings.Join(lines, "\n")
+		return &mcp.CallToolResult{StructuredContent: sum}, nil, nil
+	})
+}
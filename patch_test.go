@@ -0,0 +1,54 @@
+package jira
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		diff string
+		want []patchFile
+	}{
+		{
+			name: "dash lines inside a hunk",
+			diff: "--- a/notes.md\n+++ b/notes.md\n@@ -1,4 +1,4 @@\n title\n--- old rule\n+++ new rule\n-- old\n++ new\n",
+			want: []patchFile{{Path: "notes.md", Status: "modified", Hunks: 1, Added: 2, Removed: 2}},
+		},
+		{
+			name: "git format-patch with signature",
+			diff: "From abc Mon Sep 17 00:00:00 2001\nSubject: fix\n---\n a.go | 2 +-\n\ndiff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -3,3 +3,3 @@ func a() {\n x := 1\n-y := 2\n+y := 3\n z := 4\n-- \n2.43.0\n",
+			want: []patchFile{{Path: "a.go", Status: "modified", Hunks: 1, Added: 1, Removed: 1}},
+		},
+		{
+			name: "two hunks and no newline marker",
+			diff: "--- a/a.txt\n+++ b/a.txt\n@@ -1 +1 @@\n-a\n\\ No newline at end of file\n+b\n\\ No newline at end of file\n@@ -10,2 +10,3 @@\n k\n+l\n m\n",
+			want: []patchFile{{Path: "a.txt", Status: "modified", Hunks: 2, Added: 2, Removed: 1}},
+		},
+		{
+			name: "plain diff -u of two files",
+			diff: "--- one.txt\t2024-01-01\n+++ one.txt\t2024-01-02\n@@ -1 +1 @@\n-1\n+one\n--- two.txt\n+++ two.txt\n@@ -1,2 +1 @@\n-2\n two\n",
+			want: []patchFile{
+				{Path: "one.txt", Status: "modified", Hunks: 1, Added: 1, Removed: 1},
+				{Path: "two.txt", Status: "modified", Hunks: 1, Removed: 1},
+			},
+		},
+		{
+			name: "added, deleted and renamed",
+			diff: "diff --git a/new.go b/new.go\nnew file mode 100644\n--- /dev/null\n+++ b/new.go\n@@ -0,0 +1,2 @@\n+package x\n+--- not a header\n" +
+				"diff --git a/old.go b/old.go\ndeleted file mode 100644\n--- a/old.go\n+++ /dev/null\n@@ -1 +0,0 @@\n-package x\n" +
+				"diff --git a/from.go b/to.go\nsimilarity index 100%\nrename from from.go\nrename to to.go\n",
+			want: []patchFile{
+				{Path: "new.go", Status: "added", Hunks: 1, Added: 2},
+				{Path: "old.go", Status: "deleted", Hunks: 1, Removed: 1},
+				{Path: "to.go", OldPath: "from.go", Status: "renamed"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		if got := parseUnifiedDiff(tt.diff); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n got %+v\nwant %+v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Permission checks ----
+
+// defaultPermissionKeys are checked when the caller names none: the
+// permissions behind this server's write tools.
+var defaultPermissionKeys = []string{
+	"BROWSE_PROJECTS", "CREATE_ISSUES", "EDIT_ISSUES", "TRANSITION_ISSUES", "RESOLVE_ISSUES",
+	"ASSIGN_ISSUES", "ADD_COMMENTS", "LINK_ISSUES", "CREATE_ATTACHMENTS", "WORK_ON_ISSUES",
+	"MANAGE_WATCHERS", "SCHEDULE_ISSUES", "DELETE_ISSUES", "ADMINISTER_PROJECTS",
+}
+
+// permissionActions maps the everyday words for actions to permission keys.
+var permissionActions = map[string]string{
+	"browse": "BROWSE_PROJECTS", "view": "BROWSE_PROJECTS", "create": "CREATE_ISSUES",
+	"edit": "EDIT_ISSUES", "transition": "TRANSITION_ISSUES", "resolve": "RESOLVE_ISSUES",
+	"assign": "ASSIGN_ISSUES", "comment": "ADD_COMMENTS", "link": "LINK_ISSUES",
+	"attach": "CREATE_ATTACHMENTS", "worklog": "WORK_ON_ISSUES", "log_work": "WORK_ON_ISSUES",
+	"watch": "MANAGE_WATCHERS", "schedule": "SCHEDULE_ISSUES", "delete": "DELETE_ISSUES",
+	"administer": "ADMINISTER_PROJECTS",
+}
+
+type JiraPermission struct {
+	Key            string `json:"key"`
+	Name           string `json:"name"`
+	Type           string `json:"type"` // GLOBAL or PROJECT
+	Description    string `json:"description,omitempty"`
+	HavePermission bool   `json:"havePermission"`
+}
+
+// MyPermissions reports which of keys the authenticated user holds, on an
+// issue, in a project, or globally when both are empty.
+func (c *JiraClient) MyPermissions(ctx context.Context, issueKey, projectKey string, keys []string) (map[string]JiraPermission, error) {
+	q := url.Values{}
+	q.Set("permissions", strings.Join(keys, ","))
+	if issueKey != "" {
+		q.Set("issueKey", issueKey)
+	} else if projectKey != "" {
+		q.Set("projectKey", projectKey)
+	}
+	var out struct {
+		Permissions map[string]JiraPermission `json:"permissions"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/mypermissions?"+q.Encode(), nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Permissions, nil
+}
+
+func registerPermissionTools(server *mcp.Server, jc *JiraClient) {
+	// get_my_permissions(key?, project?, permissions?)
+	type myPermissionsArgs struct {
+		Key         string   `json:"key,omitempty" jsonschema:"Issue key to check against, e.g. PROJ-123; issue-level security is taken into account"`
+		Project     string   `json:"project,omitempty" jsonschema:"Project key to check against when no key is given (default the focus project)"`
+		Permissions []string `json:"permissions,omitempty" jsonschema:"Actions (edit, transition, comment, assign, create, delete, link, attach, worklog, watch) or Jira permission keys such as EDIT_ISSUES; default the common issue permissions"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_my_permissions",
+		Title:       "Get My Permissions",
+		Description: "Check whether the authenticated user may edit, transition, comment on, or otherwise act on an issue or in a project, before attempting a write. Lists what is allowed and what is denied with Jira's description of each permission",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args myPermissionsArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=get_my_permissions args={key:%q,project:%q,permissions:%v}", args.Key, args.Project, args.Permissions)
+		project := args.Project
+		if f := jc.focusFor(req.Session); args.Key == "" && project == "" && f != nil {
+			project = f.Project
+		}
+		keys := defaultPermissionKeys
+		if len(args.Permissions) > 0 {
+			keys = nil
+			for _, p := range args.Permissions {
+				p = strings.TrimSpace(p)
+				if k, ok := permissionActions[strings.ToLower(p)]; ok {
+					p = k
+				}
+				if p == "" {
+					return nil, nil, errors.New("empty permission")
+				}
+				keys = append(keys, strings.ToUpper(p))
+			}
+		}
+		perms, err := jc.MyPermissions(ctx, args.Key, project, keys)
+		if err != nil {
+			debugf("tool=get_my_permissions error=%v", err)
+			return nil, nil, err
+		}
+		allowed, denied := []string{}, []JiraPermission{}
+		for _, k := range keys {
+			p, ok := perms[k]
+			switch {
+			case !ok:
+				denied = append(denied, JiraPermission{Key: k, Description: "unknown permission key"})
+			case p.HavePermission:
+				allowed = append(allowed, k)
+			default:
+				denied = append(denied, p)
+			}
+		}
+		sort.Strings(allowed)
+		out := map[string]any{"allowed": allowed, "denied": denied}
+		switch {
+		case args.Key != "":
+			out["key"] = args.Key
+		case project != "":
+			out["project"] = project
+		}
+		if len(denied) > 0 {
+			// Jira doesn't say which scheme entry is missing; point at where it lives.
+			out["note"] = "Denied permissions are not granted to you by the project's permission scheme (or, for an issue, are withheld by its security level); a Jira admin or the project admin can change that"
+		}
+		return &mcp.CallToolResult{StructuredContent: out}, nil, nil
+	})
+}
@@ -0,0 +1,186 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Projects ----
+
+type JiraProject struct {
+	ID          string         `json:"id"`
+	Key         string         `json:"key"`
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Style       string         `json:"style,omitempty"` // classic or next-gen
+	TypeKey     string         `json:"projectTypeKey,omitempty"`
+	Lead        map[string]any `json:"lead,omitempty"`
+	Category    *struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"projectCategory,omitempty"`
+	IssueTypes []struct {
+		ID      string `json:"id"`
+		Name    string `json:"name"`
+		Subtask bool   `json:"subtask"`
+	} `json:"issueTypes,omitempty"`
+	Components []struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+	} `json:"components,omitempty"`
+	Versions []struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		Released    bool   `json:"released"`
+		Archived    bool   `json:"archived"`
+		ReleaseDate string `json:"releaseDate,omitempty"`
+	} `json:"versions,omitempty"`
+}
+
+// management names the project style the way the Jira UI does.
+func (p *JiraProject) management() string {
+	if p.Style == "next-gen" {
+		return "team-managed"
+	}
+	return "company-managed"
+}
+
+type projectPage struct {
+	Projects []JiraProject `json:"projects"`
+	Total    int           `json:"total"`
+	// NextStartAt is set when more projects match.
+	NextStartAt int `json:"next_start_at,omitempty"`
+}
+
+// ListProjects returns one page of the projects visible to the user whose
+// key or name contains query, optionally only those in a category.
+func (c *JiraClient) ListProjects(ctx context.Context, query, categoryID string, startAt, max int) (*projectPage, error) {
+	q := url.Values{}
+	q.Set("startAt", fmt.Sprintf("%d", startAt))
+	q.Set("maxResults", fmt.Sprintf("%d", max))
+	q.Set("expand", "description,lead")
+	q.Set("orderBy", "key")
+	if query != "" {
+		q.Set("query", query)
+	}
+	if categoryID != "" {
+		q.Set("categoryId", categoryID)
+	}
+	var page struct {
+		IsLast bool          `json:"isLast"`
+		Total  int           `json:"total"`
+		Values []JiraProject `json:"values"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/project/search?"+q.Encode(), nil, &page); err != nil {
+		return nil, err
+	}
+	out := &projectPage{Projects: page.Values, Total: page.Total}
+	if out.Projects == nil {
+		out.Projects = []JiraProject{}
+	}
+	if !page.IsLast && len(page.Values) > 0 {
+		out.NextStartAt = startAt + len(page.Values)
+	}
+	return out, nil
+}
+
+// GetProject returns a project with its issue types, components, and
+// versions.
+func (c *JiraClient) GetProject(ctx context.Context, project string) (*JiraProject, error) {
+	var out JiraProject
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/project/"+url.PathEscape(project)+"?expand=description,lead,issueTypes", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+type projectSummary struct {
+	Key        string `json:"key"`
+	Name       string `json:"name"`
+	Type       string `json:"type,omitempty"`
+	Management string `json:"management"`
+	Lead       string `json:"lead,omitempty"`
+	Category   string `json:"category,omitempty"`
+}
+
+func summarizeProject(p *JiraProject) projectSummary {
+	s := projectSummary{
+		Key: p.Key, Name: p.Name, Type: p.TypeKey, Management: p.management(),
+		Lead: fieldString(p.Lead, "displayName"),
+	}
+	if p.Category != nil {
+		s.Category = p.Category.Name
+	}
+	return s
+}
+
+func registerProjectTools(server *mcp.Server, jc *JiraClient) {
+	// list_projects(query?, category_id?, start_at?, max_results?)
+	type listProjectsArgs struct {
+		Query      string `json:"query,omitempty" jsonschema:"Only projects whose key or name contains this"`
+		CategoryID string `json:"category_id,omitempty" jsonschema:"Only projects in this project category"`
+		StartAt    int    `json:"start_at,omitempty" jsonschema:"Offset for pagination (next_start_at of the previous page)"`
+		MaxResults int    `json:"max_results,omitempty" jsonschema:"Maximum projects per page (default 50, max 100)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_projects",
+		Title:       "List Projects",
+		Description: "List the projects you can see, with key, name, lead, category, and whether each is team- or company-managed",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listProjectsArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_projects args={query:%q,category:%q,start:%d}", args.Query, args.CategoryID, args.StartAt)
+		limit := args.MaxResults
+		if limit <= 0 {
+			limit = 50
+		}
+		page, err := jc.ListProjects(ctx, args.Query, args.CategoryID, max(args.StartAt, 0), min(limit, 100))
+		if err != nil {
+			debugf("tool=list_projects error=%v", err)
+			return nil, nil, err
+		}
+		projects := make([]projectSummary, len(page.Projects))
+		for i := range page.Projects {
+			projects[i] = summarizeProject(&page.Projects[i])
+		}
+		res := map[string]any{"projects": projects, "total": page.Total}
+		if page.NextStartAt > 0 {
+			res["next_start_at"] = page.NextStartAt
+		}
+		return &mcp.CallToolResult{StructuredContent: res}, nil, nil
+	})
+
+	// get_project(project?)
+	type getProjectArgs struct {
+		Project string `json:"project,omitempty" jsonschema:"Project key or id (default the focus project)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_project",
+		Title:       "Get Project",
+		Description: "Get a project's details: lead, category, style (team- or company-managed), issue types, components, and versions",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args getProjectArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=get_project args={project:%q}", args.Project)
+		project := args.Project
+		if f := jc.focusFor(req.Session); project == "" && f != nil {
+			project = f.Project
+		}
+		if project == "" {
+			return nil, nil, errors.New("project is required (no focus project set)")
+		}
+		p, err := jc.GetProject(ctx, project)
+		if err != nil {
+			debugf("tool=get_project error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{
+			"project": summarizeProject(p), "description": p.Description,
+			"issue_types": p.IssueTypes, "components": p.Components, "versions": p.Versions,
+		}}, nil, nil
+	})
+}
@@ -0,0 +1,220 @@
+package jira
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Provenance of agent-created structures ----
+//
+// Compound tools (run_workflow, scaffold_project_structure, clone_issue,
+// seed_sandbox) open a provenance run. Everything created during the run is
+// collected, and at the end each issue involved gets a record of the run:
+// which tool and session, and everything else it created. That makes
+// agent-built structures traceable, and reversible, long after the
+// conversation is gone. JIRA_PROVENANCE chooses how the record is kept:
+// "property" (default; an issue property, invisible in the UI), "comment",
+// "both", or "off".
+
+// provenancePropertyPrefix starts the issue property key of each run's
+// record; the run id completes it.
+const provenancePropertyPrefix = "mcp.provenance."
+
+type provenanceItem struct {
+	Kind   string   `json:"kind"` // issue, comment, link, or sprint
+	Ref    string   `json:"ref"`  // issue key, or comment, link, or sprint id
+	Issues []string `json:"issues,omitempty"`
+}
+
+type provenanceRecord struct {
+	Run     string           `json:"run"`
+	Tool    string           `json:"tool"`
+	Session string           `json:"session"`
+	At      time.Time        `json:"at"`
+	Created []provenanceItem `json:"created"`
+}
+
+type provenanceRun struct {
+	mu  sync.Mutex
+	rec provenanceRecord
+}
+
+type provenanceKey struct{}
+
+func provenanceMode() string {
+	mode := strings.ToLower(strings.TrimSpace(os.Getenv("JIRA_PROVENANCE")))
+	switch mode {
+	case "":
+		return "property"
+	case "property", "comment", "both", "off":
+		return mode
+	}
+	logger.Printf("ignoring JIRA_PROVENANCE=%q (valid: property, comment, both, off)", mode)
+	return "property"
+}
+
+// beginProvenance starts collecting what the current tool call creates.
+func (c *JiraClient) beginProvenance(ctx context.Context) (context.Context, *provenanceRun) {
+	if c.provenance == "off" {
+		return ctx, nil
+	}
+	b := make([]byte, 3)
+	_, _ = rand.Read(b)
+	run := &provenanceRun{rec: provenanceRecord{
+		Run: time.Now().UTC().Format("20060102T150405") + "-" + hex.EncodeToString(b),
+		At:  time.Now().UTC(), Session: "default", Created: []provenanceItem{},
+	}}
+	if tc := currentToolCall(ctx); tc != nil {
+		run.rec.Tool, run.rec.Session = tc.Name, sessionKey(tc.Session)
+	}
+	return context.WithValue(ctx, provenanceKey{}, run), run
+}
+
+// noteCreated adds an object created under ctx to its provenance run, if
+// any. issues are the issues the object belongs to or connects.
+func noteCreated(ctx context.Context, kind, ref string, issues ...string) {
+	run, _ := ctx.Value(provenanceKey{}).(*provenanceRun)
+	if run == nil || ref == "" {
+		return
+	}
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	run.rec.Created = append(run.rec.Created, provenanceItem{Kind: kind, Ref: ref, Issues: issues})
+}
+
+// finishProvenance records the run on every issue it created or touched and
+// returns the run id, or "" when nothing was created. Failures are logged;
+// they never fail the tool call.
+func (c *JiraClient) finishProvenance(ctx context.Context, run *provenanceRun) string {
+	if run == nil {
+		return ""
+	}
+	run.mu.Lock()
+	rec := run.rec
+	rec.Created = append([]provenanceItem(nil), run.rec.Created...)
+	run.mu.Unlock()
+	if len(rec.Created) == 0 {
+		return ""
+	}
+	// The record's own writes are not part of the run.
+	ctx = context.WithoutCancel(context.WithValue(ctx, provenanceKey{}, (*provenanceRun)(nil)))
+	// Every involved issue gets the property; comments go only where the
+	// run created the issue or a link, not where it just commented.
+	var keys []string
+	structural := map[string]bool{}
+	for _, it := range rec.Created {
+		involved := it.Issues
+		if it.Kind == "issue" {
+			involved = append([]string{it.Ref}, it.Issues...)
+		}
+		for _, k := range involved {
+			if _, seen := structural[k]; !seen {
+				keys = append(keys, k)
+			}
+			structural[k] = structural[k] || it.Kind != "comment"
+		}
+	}
+	for _, key := range keys {
+		if c.provenance == "property" || c.provenance == "both" {
+			if err := c.SetIssueProperty(ctx, key, provenancePropertyPrefix+rec.Run, rec); err != nil {
+				debugf("provenance %s on %s: %v", rec.Run, key, err)
+			}
+		}
+		if (c.provenance == "comment" || c.provenance == "both") && structural[key] {
+			if _, err := c.AddComment(ctx, key, provenanceComment(rec, key)); err != nil {
+				debugf("provenance %s comment on %s: %v", rec.Run, key, err)
+			}
+		}
+	}
+	return rec.Run
+}
+
+func provenanceComment(rec provenanceRecord, key string) string {
+	var parts []string
+	for _, it := range rec.Created {
+		switch {
+		case it.Kind == "issue" && it.Ref == key, it.Kind == "comment":
+			continue
+		case it.Kind == "issue":
+			parts = append(parts, it.Ref)
+		default:
+			parts = append(parts, fmt.Sprintf("%s %s (%s)", it.Kind, it.Ref, strings.Join(it.Issues, ", ")))
+		}
+	}
+	msg := fmt.Sprintf("Recorded by %s, run %s (session %s).", rec.Tool, rec.Run, rec.Session)
+	if len(parts) > 0 {
+		msg += " Same run: " + strings.Join(parts, "; ") + "."
+	}
+	return msg
+}
+
+func (c *JiraClient) SetIssueProperty(ctx context.Context, key, property string, value any) error {
+	return c.doJSON(ctx, http.MethodPut, "/rest/api/3/issue/"+url.PathEscape(key)+"/properties/"+url.PathEscape(property), value, nil)
+}
+
+// IssueProvenance returns the provenance records stored on an issue, most
+// recent first.
+func (c *JiraClient) IssueProvenance(ctx context.Context, key string) ([]provenanceRecord, error) {
+	var list struct {
+		Keys []struct {
+			Key string `json:"key"`
+		} `json:"keys"`
+	}
+	base := "/rest/api/3/issue/" + url.PathEscape(key) + "/properties/"
+	if err := c.doJSON(ctx, http.MethodGet, base, nil, &list); err != nil {
+		return nil, err
+	}
+	out := []provenanceRecord{}
+	for _, k := range list.Keys {
+		if !strings.HasPrefix(k.Key, provenancePropertyPrefix) {
+			continue
+		}
+		var prop struct {
+			Value json.RawMessage `json:"value"`
+		}
+		if err := c.doJSON(ctx, http.MethodGet, base+url.PathEscape(k.Key), nil, &prop); err != nil {
+			return nil, err
+		}
+		var rec provenanceRecord
+		if err := json.Unmarshal(prop.Value, &rec); err != nil {
+			debugf("provenance %s on %s: %v", k.Key, key, err)
+			continue
+		}
+		out = append(out, rec)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].At.After(out[j].At) })
+	return out, nil
+}
+
+func registerProvenanceTools(server *mcp.Server, jc *JiraClient) {
+	// get_provenance(key)
+	type provenanceArgs struct {
+		Key string `json:"key" jsonschema:"Issue key"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_provenance",
+		Title:       "Get Provenance",
+		Description: "Show which agent runs (tool, session, time) created or linked an issue, and everything else each run created, to trace or undo agent-built structures",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args provenanceArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=get_provenance args={key:%q}", args.Key)
+		recs, err := jc.IssueProvenance(ctx, args.Key)
+		if err != nil {
+			debugf("tool=get_provenance error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"key": args.Key, "runs": recs}}, nil, nil
+	})
+}
@@ -0,0 +1,307 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- JSM queues and SLAs ----
+
+type JiraQueue struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	JQL        string   `json:"jql,omitempty"`
+	Fields     []string `json:"fields,omitempty"`
+	IssueCount int      `json:"issueCount"`
+}
+
+func (c *JiraClient) Queues(ctx context.Context, serviceDeskID string) ([]JiraQueue, error) {
+	q := url.Values{}
+	q.Set("includeCount", "true")
+	return sdPage[JiraQueue](ctx, c, "/rest/servicedeskapi/servicedesk/"+url.PathEscape(serviceDeskID)+"/queue", q, 0)
+}
+
+// Queue finds a service desk queue by id or name.
+func (c *JiraClient) Queue(ctx context.Context, serviceDeskID, ref string) (*JiraQueue, error) {
+	queues, err := c.Queues(ctx, serviceDeskID)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for i, q := range queues {
+		if q.ID == ref || strings.EqualFold(q.Name, ref) {
+			return &queues[i], nil
+		}
+		names = append(names, q.Name)
+	}
+	return nil, fmt.Errorf("no queue %q (queues: %s)", ref, orNone(strings.Join(names, ", ")))
+}
+
+// QueueIssues returns up to limit issues in a queue, in queue order.
+func (c *JiraClient) QueueIssues(ctx context.Context, serviceDeskID, queueID string, limit int) ([]JiraIssue, error) {
+	path := "/rest/servicedeskapi/servicedesk/" + url.PathEscape(serviceDeskID) + "/queue/" + url.PathEscape(queueID) + "/issue"
+	return sdPage[JiraIssue](ctx, c, path, nil, limit)
+}
+
+// slaCycle is one run of an SLA clock, ongoing or completed.
+type slaCycle struct {
+	StartTime     map[string]any `json:"startTime"`
+	StopTime      map[string]any `json:"stopTime,omitempty"`
+	BreachTime    map[string]any `json:"breachTime,omitempty"`
+	Breached      bool           `json:"breached"`
+	Paused        bool           `json:"paused"`
+	GoalDuration  map[string]any `json:"goalDuration"`
+	ElapsedTime   map[string]any `json:"elapsedTime"`
+	RemainingTime map[string]any `json:"remainingTime"`
+}
+
+type JiraSLA struct {
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	OngoingCycle    *slaCycle  `json:"ongoingCycle,omitempty"`
+	CompletedCycles []slaCycle `json:"completedCycles"`
+}
+
+func (c *JiraClient) RequestSLAs(ctx context.Context, key string) ([]JiraSLA, error) {
+	return sdPage[JiraSLA](ctx, c, "/rest/servicedeskapi/request/"+url.PathEscape(key)+"/sla", nil, 0)
+}
+
+type slaCycleView struct {
+	State          string  `json:"state"` // running, paused, or completed
+	Started        string  `json:"started,omitempty"`
+	Stopped        string  `json:"stopped,omitempty"`
+	BreachAt       string  `json:"breach_at,omitempty"`
+	Breached       bool    `json:"breached"`
+	Goal           string  `json:"goal,omitempty"`
+	Elapsed        string  `json:"elapsed,omitempty"`
+	Remaining      string  `json:"remaining,omitempty"`
+	RemainingHours float64 `json:"remaining_hours"`
+}
+
+type slaView struct {
+	Name      string         `json:"name"`
+	Ongoing   *slaCycleView  `json:"ongoing,omitempty"`
+	Completed []slaCycleView `json:"completed,omitempty"`
+	Breached  bool           `json:"breached"` // in any cycle
+}
+
+func viewSLACycle(cy *slaCycle, state string) slaCycleView {
+	millis, _ := cy.RemainingTime["millis"].(float64)
+	return slaCycleView{
+		State: state, Started: fieldString(cy.StartTime, "iso8601"), Stopped: fieldString(cy.StopTime, "iso8601"),
+		BreachAt: fieldString(cy.BreachTime, "iso8601"), Breached: cy.Breached,
+		Goal: fieldString(cy.GoalDuration, "friendly"), Elapsed: fieldString(cy.ElapsedTime, "friendly"),
+		Remaining: fieldString(cy.RemainingTime, "friendly"), RemainingHours: math.Round(millis/36000) / 100,
+	}
+}
+
+func viewSLA(s *JiraSLA) slaView {
+	v := slaView{Name: s.Name}
+	if s.OngoingCycle != nil {
+		state := "running"
+		if s.OngoingCycle.Paused {
+			state = "paused"
+		}
+		cy := viewSLACycle(s.OngoingCycle, state)
+		v.Ongoing = &cy
+		v.Breached = cy.Breached
+	}
+	for i := range s.CompletedCycles {
+		cy := viewSLACycle(&s.CompletedCycles[i], "completed")
+		v.Completed = append(v.Completed, cy)
+		v.Breached = v.Breached || cy.Breached
+	}
+	return v
+}
+
+// slaSummary counts, per SLA, how many requests breached it and lists them.
+type slaSummary struct {
+	Name     string   `json:"name"`
+	Requests int      `json:"requests"`
+	Breached int      `json:"breached"`
+	Running  int      `json:"running"`
+	Keys     []string `json:"breached_keys,omitempty"`
+}
+
+func registerQueueTools(server *mcp.Server, jc *JiraClient) {
+	// list_queues(service_desk?)
+	type listQueuesArgs struct {
+		ServiceDesk string `json:"service_desk,omitempty" jsonschema:"Service desk id or project key (default the session focus project)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_queues",
+		Title:       "List Queues",
+		Description: "List a service desk's agent queues with their JQL and issue counts",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listQueuesArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_queues args={service_desk:%q}", args.ServiceDesk)
+		sd, err := jc.serviceDeskFor(ctx, req.Session, args.ServiceDesk)
+		if err != nil {
+			return nil, nil, err
+		}
+		queues, err := jc.Queues(ctx, sd.ID)
+		if err != nil {
+			debugf("tool=list_queues error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"service_desk": sd.ProjectKey, "queues": queues}}, nil, nil
+	})
+
+	// get_queue_issues(service_desk?, queue, max_results?)
+	type queueIssuesArgs struct {
+		ServiceDesk string `json:"service_desk,omitempty" jsonschema:"Service desk id or project key (default the session focus project)"`
+		Queue       string `json:"queue" jsonschema:"Queue name or id (see list_queues)"`
+		MaxResults  int    `json:"max_results,omitempty" jsonschema:"Maximum issues (default 50, max 500)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_queue_issues",
+		Title:       "Get Queue Issues",
+		Description: "List the issues in a service desk queue, in queue order",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args queueIssuesArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=get_queue_issues args={service_desk:%q,queue:%q}", args.ServiceDesk, args.Queue)
+		sd, err := jc.serviceDeskFor(ctx, req.Session, args.ServiceDesk)
+		if err != nil {
+			return nil, nil, err
+		}
+		queue, err := jc.Queue(ctx, sd.ID, args.Queue)
+		if err != nil {
+			return nil, nil, err
+		}
+		limit := args.MaxResults
+		if limit <= 0 || limit > 500 {
+			limit = 50
+		}
+		issues, err := jc.QueueIssues(ctx, sd.ID, queue.ID, limit)
+		if err != nil {
+			debugf("tool=get_queue_issues error=%v", err)
+			return nil, nil, err
+		}
+		out := make([]map[string]any, len(issues))
+		for i, iss := range issues {
+			out[i] = map[string]any{"key": iss.Key}
+			for k, v := range iss.Fields {
+				if v != nil {
+					out[i][k] = displayValue(v)
+				}
+			}
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{
+			"queue": queue.Name, "issues": out, "count": len(out), "total": queue.IssueCount,
+		}}, nil, nil
+	})
+
+	// get_request_sla(key)
+	type requestSLAArgs struct {
+		Key string `json:"key" jsonschema:"Request issue key, e.g. HELP-12"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_request_sla",
+		Title:       "Get Request SLAs",
+		Description: "Read a request's SLAs (e.g. time to first response, time to resolution): goal, elapsed and remaining time, breach time, and whether each cycle breached",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args requestSLAArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=get_request_sla args={key:%q}", args.Key)
+		slas, err := jc.RequestSLAs(ctx, args.Key)
+		if err != nil {
+			debugf("tool=get_request_sla error=%v", err)
+			return nil, nil, err
+		}
+		views := make([]slaView, len(slas))
+		for i := range slas {
+			views[i] = viewSLA(&slas[i])
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"key": args.Key, "slas": views}}, nil, nil
+	})
+
+	// sla_breach_report(service_desk?, queue?, jql?, max_results?)
+	type breachReportArgs struct {
+		ServiceDesk string `json:"service_desk,omitempty" jsonschema:"Service desk id or project key, with queue (default the session focus project)"`
+		Queue       string `json:"queue,omitempty" jsonschema:"Report on the requests in this queue"`
+		JQL         string `json:"jql,omitempty" jsonschema:"Or report on the requests this JQL matches"`
+		MaxResults  int    `json:"max_results,omitempty" jsonschema:"Maximum requests to check (default 50, max 200)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "sla_breach_report",
+		Title:       "SLA Breach Report",
+		Description: "Check the SLAs of the requests in a queue or JQL result and report, per SLA, how many breached and which, plus running SLAs ordered by time left",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args breachReportArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=sla_breach_report args={service_desk:%q,queue:%q,jql:%q}", args.ServiceDesk, args.Queue, args.JQL)
+		if (args.Queue == "") == (args.JQL == "") {
+			return nil, nil, errors.New("give exactly one of queue or jql")
+		}
+		limit := args.MaxResults
+		if limit <= 0 || limit > 200 {
+			limit = 50
+		}
+		var issues []JiraIssue
+		var err error
+		if args.Queue != "" {
+			sd, err := jc.serviceDeskFor(ctx, req.Session, args.ServiceDesk)
+			if err != nil {
+				return nil, nil, err
+			}
+			queue, err := jc.Queue(ctx, sd.ID, args.Queue)
+			if err != nil {
+				return nil, nil, err
+			}
+			issues, err = jc.QueueIssues(ctx, sd.ID, queue.ID, limit)
+		} else {
+			issues, err = jc.SearchAll(ctx, args.JQL, []string{"summary"}, limit)
+		}
+		if err != nil {
+			debugf("tool=sla_breach_report error=%v", err)
+			return nil, nil, err
+		}
+		type running struct {
+			Key            string  `json:"key"`
+			SLA            string  `json:"sla"`
+			Remaining      string  `json:"remaining"`
+			RemainingHours float64 `json:"remaining_hours"`
+			Breached       bool    `json:"breached"`
+		}
+		byName := map[string]*slaSummary{}
+		var open []running
+		for _, iss := range issues {
+			slas, err := jc.RequestSLAs(ctx, iss.Key)
+			if err != nil {
+				debugf("tool=sla_breach_report error=%v", err)
+				return nil, nil, fmt.Errorf("%s: %w", iss.Key, err)
+			}
+			for i := range slas {
+				v := viewSLA(&slas[i])
+				sum := byName[v.Name]
+				if sum == nil {
+					sum = &slaSummary{Name: v.Name}
+					byName[v.Name] = sum
+				}
+				sum.Requests++
+				if v.Breached {
+					sum.Breached++
+					sum.Keys = append(sum.Keys, iss.Key)
+				}
+				if v.Ongoing != nil && v.Ongoing.State == "running" {
+					sum.Running++
+					open = append(open, running{Key: iss.Key, SLA: v.Name, Remaining: v.Ongoing.Remaining, RemainingHours: v.Ongoing.RemainingHours, Breached: v.Ongoing.Breached})
+				}
+			}
+		}
+		summaries := make([]*slaSummary, 0, len(byName))
+		for _, s := range byName {
+			summaries = append(summaries, s)
+		}
+		sort.Slice(summaries, func(i, j int) bool { return summaries[i].Name < summaries[j].Name })
+		sort.SliceStable(open, func(i, j int) bool { return open[i].RemainingHours < open[j].RemainingHours })
+		return &mcp.CallToolResult{StructuredContent: map[string]any{
+			"requests_checked": len(issues), "slas": summaries, "running": open,
+		}}, nil, nil
+	})
+}
@@ -0,0 +1,291 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Shared request budget ----
+//
+// All Jira requests draw from one token bucket. Interactive tool calls may
+// use the whole bucket; background subsystems (pollers, scheduled reports)
+// only take tokens above a reserve and always yield to waiting interactive
+// calls, so they cannot starve the agent. Configure with JIRA_RATE_LIMIT:
+//
+//	{"requests_per_second": 10, "burst": 20, "interactive_reserve": 0.5}
+//
+// A zero rate disables throttling; consumption is still counted.
+
+type rateLimitConfig struct {
+	RequestsPerSecond  float64 `json:"requests_per_second"`
+	Burst              int     `json:"burst,omitempty"`
+	InteractiveReserve float64 `json:"interactive_reserve,omitempty"` // fraction of burst background work may not touch
+}
+
+const subsystemInteractive = "interactive"
+
+type subsystemKey struct{}
+
+// withSubsystem marks ctx as background work on behalf of subsystem name.
+func withSubsystem(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, subsystemKey{}, name)
+}
+
+func subsystemOf(ctx context.Context) string {
+	if s, ok := ctx.Value(subsystemKey{}).(string); ok {
+		return s
+	}
+	return subsystemInteractive
+}
+
+type budgetStats struct {
+	Requests    int     `json:"requests"`
+	Waits       int     `json:"waits"`
+	WaitSeconds float64 `json:"wait_seconds"`
+}
+
+type rateBudget struct {
+	rate    float64
+	burst   float64
+	reserve float64
+
+	mu          sync.Mutex
+	tokens      float64
+	last        time.Time
+	interactive int // interactive callers currently waiting
+	stats       map[string]*budgetStats
+}
+
+func newRateBudget(cfg rateLimitConfig) *rateBudget {
+	b := &rateBudget{rate: cfg.RequestsPerSecond, burst: float64(cfg.Burst), stats: map[string]*budgetStats{}}
+	if b.burst <= 0 {
+		b.burst = math.Max(1, math.Ceil(b.rate))
+	}
+	reserve := cfg.InteractiveReserve
+	if reserve <= 0 || reserve >= 1 {
+		reserve = 0.5
+	}
+	b.reserve = math.Floor(b.burst * reserve)
+	b.tokens = b.burst
+	b.last = time.Now()
+	return b
+}
+
+func (b *rateBudget) refill(now time.Time) {
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+// wait blocks until ctx's subsystem may send one request.
+func (b *rateBudget) wait(ctx context.Context) error {
+	sub := subsystemOf(ctx)
+	background := sub != subsystemInteractive
+	start := time.Now()
+
+	b.mu.Lock()
+	st := b.stats[sub]
+	if st == nil {
+		st = &budgetStats{}
+		b.stats[sub] = st
+	}
+	if b.rate <= 0 {
+		st.Requests++
+		b.mu.Unlock()
+		return nil
+	}
+	if !background {
+		b.interactive++
+		defer func() {
+			b.mu.Lock()
+			b.interactive--
+			b.mu.Unlock()
+		}()
+	}
+	waited := false
+	for {
+		b.refill(time.Now())
+		floor := 0.0
+		if background {
+			floor = b.reserve
+		}
+		if (!background || b.interactive == 0) && b.tokens >= floor+1 {
+			b.tokens--
+			st.Requests++
+			if waited {
+				st.Waits++
+				st.WaitSeconds += time.Since(start).Seconds()
+			}
+			b.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration(math.Max(floor+1-b.tokens, 0.05) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+		waited = true
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+		b.mu.Lock()
+	}
+}
+
+type budgetSnapshot struct {
+	RequestsPerSecond float64                `json:"requests_per_second"`
+	Burst             float64                `json:"burst"`
+	Reserve           float64                `json:"interactive_reserve_tokens"`
+	Available         float64                `json:"available_tokens"`
+	Subsystems        map[string]budgetStats `json:"subsystems"`
+}
+
+func (b *rateBudget) snapshot() budgetSnapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(time.Now())
+	s := budgetSnapshot{
+		RequestsPerSecond: b.rate,
+		Burst:             b.burst,
+		Reserve:           b.reserve,
+		Available:         math.Floor(b.tokens),
+		Subsystems:        map[string]budgetStats{},
+	}
+	for name, st := range b.stats {
+		s.Subsystems[name] = *st
+	}
+	return s
+}
+
+func loadRateBudget() (*rateBudget, error) {
+	var cfg rateLimitConfig
+	if _, err := loadJSONSetting("JIRA_RATE_LIMIT", &cfg); err != nil {
+		return nil, err
+	}
+	return newRateBudget(cfg), nil
+}
+
+// ---- Jira's own rate limits ----
+//
+// Jira Cloud reports its budget on responses: X-RateLimit-Limit and
+// X-RateLimit-Remaining, X-RateLimit-Reset (when the window refills),
+// X-RateLimit-NearLimit once less than a fifth is left, and RateLimit-Reason
+// and Retry-After on 429s. The latest values are kept so operators can see
+// how close agent workloads come to the limit before requests fail.
+
+type jiraRateLimit struct {
+	Limit          *int       `json:"limit,omitempty"`
+	Remaining      *int       `json:"remaining,omitempty"`
+	LowestSeen     *int       `json:"lowest_remaining_seen,omitempty"`
+	Reset          *time.Time `json:"reset,omitempty"`
+	NearLimit      bool       `json:"near_limit"`
+	ObservedAt     *time.Time `json:"observed_at,omitempty"` // last response carrying the headers
+	RateLimited    int        `json:"rate_limited_responses"`
+	LastLimitedAt  *time.Time `json:"last_rate_limited_at,omitempty"`
+	LastReason     string     `json:"last_reason,omitempty"`
+	LastRetryAfter float64    `json:"last_retry_after_seconds,omitempty"`
+}
+
+type rateLimitTelemetry struct {
+	mu    sync.Mutex
+	state jiraRateLimit
+}
+
+func headerInt(h http.Header, name string) (int, bool) {
+	n, err := strconv.Atoi(strings.TrimSpace(h.Get(name)))
+	return n, err == nil
+}
+
+// note records the rate-limit headers of a response.
+func (t *rateLimitTelemetry) note(resp *http.Response) {
+	h := resp.Header
+	limit, hasLimit := headerInt(h, "X-RateLimit-Limit")
+	remaining, hasRemaining := headerInt(h, "X-RateLimit-Remaining")
+	reset, resetErr := time.Parse(time.RFC3339, strings.TrimSpace(h.Get("X-RateLimit-Reset")))
+	near := h.Get("X-RateLimit-NearLimit")
+	limited := resp.StatusCode == http.StatusTooManyRequests
+	if !hasLimit && !hasRemaining && resetErr != nil && near == "" && !limited {
+		return
+	}
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := &t.state
+	s.ObservedAt = &now
+	if hasLimit {
+		s.Limit = &limit
+	}
+	if hasRemaining {
+		s.Remaining = &remaining
+		if s.LowestSeen == nil || remaining < *s.LowestSeen {
+			s.LowestSeen = &remaining
+		}
+	}
+	if resetErr == nil {
+		s.Reset = &reset
+	}
+	s.NearLimit = strings.EqualFold(near, "true")
+	if limited {
+		s.RateLimited++
+		s.LastLimitedAt = &now
+		s.LastReason = h.Get("RateLimit-Reason")
+		if secs, err := strconv.ParseFloat(h.Get("Retry-After"), 64); err == nil {
+			s.LastRetryAfter = secs
+		}
+	}
+}
+
+func (t *rateLimitTelemetry) snapshot() jiraRateLimit {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.state
+}
+
+type metricsReport struct {
+	budgetSnapshot
+	Jira jiraRateLimit `json:"jira_rate_limit"`
+}
+
+func (c *JiraClient) metrics() metricsReport {
+	return metricsReport{budgetSnapshot: c.budget.snapshot(), Jira: c.quota.snapshot()}
+}
+
+func registerRateLimitTools(server *mcp.Server, jc *JiraClient) {
+	// rate_limit_status()
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "rate_limit_status",
+		Title:       "Rate Limit Status",
+		Description: "Show Jira's reported rate-limit budget (remaining requests, reset time, recent 429s) and this server's own request budget per subsystem",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args struct{}) (*mcp.CallToolResult, any, error) {
+		debugf("tool=rate_limit_status")
+		return &mcp.CallToolResult{StructuredContent: jc.metrics()}, nil, nil
+	})
+}
+
+func registerMetricsResources(server *mcp.Server, jc *JiraClient) {
+	server.AddResource(&mcp.Resource{
+		URI:         "jira://metrics",
+		Name:        "metrics",
+		Title:       "Request Budget Metrics",
+		Description: "Request budget configuration and consumption per subsystem, and Jira's reported rate-limit budget",
+		MIMEType:    "application/json",
+	}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		debugf("resource=jira://metrics")
+		b, err := json.MarshalIndent(jc.metrics(), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{
+			URI: req.Params.URI, MIMEType: "application/json", Text: string(b),
+		}}}, nil
+	})
+}
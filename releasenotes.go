@@ -0,0 +1,148 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Release notes ----
+
+const releaseNotesLimit = 1000
+
+// releaseTypeRank orders issue type sections: new work before fixes, then
+// anything else alphabetically.
+var releaseTypeRank = map[string]int{"epic": 0, "new feature": 1, "story": 2, "improvement": 3, "task": 4, "bug": 5}
+
+type releaseNoteItem struct {
+	Key     string `json:"key"`
+	Summary string `json:"summary"`
+	URL     string `json:"url"`
+}
+
+type releaseNoteGroup struct {
+	Type   string            `json:"type"`
+	Issues []releaseNoteItem `json:"issues"`
+}
+
+type releaseNotes struct {
+	Project  string             `json:"project"`
+	Version  versionView        `json:"version"`
+	JQL      string             `json:"jql"`
+	Total    int                `json:"total"`
+	Groups   []releaseNoteGroup `json:"groups"`
+	Markdown string             `json:"markdown"`
+}
+
+// ReleaseNotes collects the resolved issues in a project's fix version,
+// grouped by issue type, and renders them as a Markdown changelog.
+func (c *JiraClient) ReleaseNotes(ctx context.Context, project, version string, subtasks bool) (*releaseNotes, error) {
+	v, err := c.findVersion(ctx, project, version)
+	if err != nil {
+		return nil, err
+	}
+	jql := fmt.Sprintf("project = %s AND fixVersion = %s AND resolution IS NOT EMPTY", quoteJQL(project), v.ID)
+	if !subtasks {
+		jql += " AND issuetype NOT IN subTaskIssueTypes()"
+	}
+	jql += " ORDER BY issuetype, key"
+	issues, err := c.SearchAll(ctx, jql, []string{"summary", "issuetype"}, releaseNotesLimit)
+	if err != nil {
+		return nil, err
+	}
+	byType := map[string][]releaseNoteItem{}
+	for _, iss := range issues {
+		t := fieldString(iss.Fields, "issuetype", "name")
+		byType[t] = append(byType[t], releaseNoteItem{
+			Key: iss.Key, Summary: fieldString(iss.Fields, "summary"),
+			URL: c.forSite(ctx).BaseURL + "/browse/" + url.PathEscape(iss.Key),
+		})
+	}
+	notes := &releaseNotes{Project: project, Version: viewVersion(v), JQL: jql, Total: len(issues), Groups: []releaseNoteGroup{}}
+	for t, items := range byType {
+		notes.Groups = append(notes.Groups, releaseNoteGroup{Type: t, Issues: items})
+	}
+	sort.Slice(notes.Groups, func(i, j int) bool {
+		ri, iok := releaseTypeRank[strings.ToLower(notes.Groups[i].Type)]
+		rj, jok := releaseTypeRank[strings.ToLower(notes.Groups[j].Type)]
+		if iok != jok {
+			return iok
+		}
+		if ri != rj {
+			return ri < rj
+		}
+		return notes.Groups[i].Type < notes.Groups[j].Type
+	})
+	notes.Markdown = notes.render()
+	return notes, nil
+}
+
+func (n *releaseNotes) render() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s %s\n", n.Project, n.Version.Name)
+	if n.Version.ReleaseDate != "" {
+		fmt.Fprintf(&b, "\nReleased %s\n", n.Version.ReleaseDate)
+	}
+	if n.Total == 0 {
+		b.WriteString("\nNo resolved issues.\n")
+	}
+	for _, g := range n.Groups {
+		fmt.Fprintf(&b, "\n## %s (%d)\n\n", g.Type, len(g.Issues))
+		for _, it := range g.Issues {
+			fmt.Fprintf(&b, "- [%s](%s) %s\n", it.Key, it.URL, it.Summary)
+		}
+	}
+	return b.String()
+}
+
+func registerReleaseNoteTools(server *mcp.Server, jc *JiraClient) {
+	// generate_release_notes(project?, version, include_subtasks?, post_to_issue?, set_version_description?)
+	type releaseNotesArgs struct {
+		Project               string `json:"project,omitempty" jsonschema:"Project key (default the focus project)"`
+		Version               string `json:"version" jsonschema:"Fix version name or id"`
+		IncludeSubtasks       bool   `json:"include_subtasks,omitempty" jsonschema:"List resolved sub-tasks too"`
+		PostToIssue           string `json:"post_to_issue,omitempty" jsonschema:"Issue key to post the notes to as a comment, e.g. the release ticket"`
+		SetVersionDescription bool   `json:"set_version_description,omitempty" jsonschema:"Replace the version's description with the notes"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "generate_release_notes",
+		Title:       "Generate Release Notes",
+		Description: "Render a Markdown changelog of a fix version's resolved issues grouped by issue type, optionally posting it as a comment or setting it as the version description",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args releaseNotesArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=generate_release_notes args={project:%q,version:%q,post_to:%q,set_desc:%t}", args.Project, args.Version, args.PostToIssue, args.SetVersionDescription)
+		project := args.Project
+		if f := jc.focusFor(req.Session); project == "" && f != nil {
+			project = f.Project
+		}
+		if project == "" || strings.TrimSpace(args.Version) == "" {
+			return nil, nil, errors.New("project and version are required")
+		}
+		notes, err := jc.ReleaseNotes(ctx, project, args.Version, args.IncludeSubtasks)
+		if err != nil {
+			debugf("tool=generate_release_notes error=%v", err)
+			return nil, nil, err
+		}
+		res := map[string]any{"notes": notes}
+		if args.PostToIssue != "" {
+			c, err := jc.AddComment(ctx, args.PostToIssue, notes.Markdown)
+			if err != nil {
+				debugf("tool=generate_release_notes error=%v", err)
+				return nil, nil, fmt.Errorf("notes generated but not posted to %s: %w", args.PostToIssue, err)
+			}
+			res["comment_id"] = c.ID
+		}
+		if args.SetVersionDescription {
+			if _, err := jc.UpdateVersion(ctx, notes.Version.ID, map[string]any{"description": notes.Markdown}); err != nil {
+				debugf("tool=generate_release_notes error=%v", err)
+				return nil, nil, fmt.Errorf("notes generated but version description not set: %w", err)
+			}
+			res["version_description_set"] = true
+		}
+		return &mcp.CallToolResult{StructuredContent: res}, nil, nil
+	})
+}
@@ -0,0 +1,286 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Remote links to external trackers ----
+//
+// A small registry of target types gives remote links the application
+// metadata, icon, and status Jira needs to render them richly. Built-in
+// types cover GitHub pull requests, PagerDuty incidents, and Zendesk
+// tickets; JIRA_REMOTE_LINK_TYPES adds or overrides types, e.g.
+//
+//	{"sentry": {"application_type": "io.sentry", "application_name": "Sentry",
+//	            "relationship": "error", "url_pattern": "sentry\\.io/issues/",
+//	            "icon_url": "https://sentry.io/favicon.ico", "resolved_statuses": ["resolved"]}}
+
+type remoteLinkType struct {
+	AppType          string   `json:"application_type"`
+	AppName          string   `json:"application_name"`
+	Relationship     string   `json:"relationship,omitempty"`
+	IconURL          string   `json:"icon_url,omitempty"`
+	URLPattern       string   `json:"url_pattern,omitempty"`
+	ResolvedStatuses []string `json:"resolved_statuses,omitempty"`
+
+	urlRe *regexp.Regexp
+}
+
+var builtinRemoteLinkTypes = map[string]remoteLinkType{
+	"github_pr": {
+		AppType:          "com.github",
+		AppName:          "GitHub",
+		Relationship:     "pull request",
+		IconURL:          "https://github.githubassets.com/favicons/favicon.png",
+		URLPattern:       `^https://github\.com/[^/]+/[^/]+/pull/\d+`,
+		ResolvedStatuses: []string{"merged", "closed"},
+	},
+	"pagerduty": {
+		AppType:          "com.pagerduty",
+		AppName:          "PagerDuty",
+		Relationship:     "incident",
+		IconURL:          "https://www.pagerduty.com/favicon.ico",
+		URLPattern:       `^https://[^/]+\.pagerduty\.com/incidents/`,
+		ResolvedStatuses: []string{"resolved"},
+	},
+	"zendesk": {
+		AppType:          "com.zendesk",
+		AppName:          "Zendesk",
+		Relationship:     "support ticket",
+		IconURL:          "https://www.zendesk.com/favicon.ico",
+		URLPattern:       `^https://[^/]+\.zendesk\.com/agent/tickets/\d+`,
+		ResolvedStatuses: []string{"solved", "closed"},
+	},
+}
+
+func loadRemoteLinkTypes() (map[string]remoteLinkType, error) {
+	types := map[string]remoteLinkType{}
+	for name, t := range builtinRemoteLinkTypes {
+		types[name] = t
+	}
+	var custom map[string]remoteLinkType
+	if _, err := loadJSONSetting("JIRA_REMOTE_LINK_TYPES", &custom); err != nil {
+		return nil, err
+	}
+	for name, t := range custom {
+		types[strings.ToLower(name)] = t
+	}
+	for name, t := range types {
+		if t.URLPattern == "" {
+			continue
+		}
+		re, err := regexp.Compile(t.URLPattern)
+		if err != nil {
+			return nil, fmt.Errorf("remote link type %q: bad url_pattern: %w", name, err)
+		}
+		t.urlRe = re
+		types[name] = t
+	}
+	return types, nil
+}
+
+// detectRemoteLinkType picks the type whose url_pattern matches link.
+func detectRemoteLinkType(types map[string]remoteLinkType, link string) string {
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if re := types[name].urlRe; re != nil && re.MatchString(link) {
+			return name
+		}
+	}
+	return ""
+}
+
+type RemoteLinkInput struct {
+	URL          string
+	Title        string
+	Summary      string
+	Status       string
+	Relationship string
+	IconURL      string
+}
+
+// remoteLinkBody builds the remote link payload. The URL doubles as the
+// global id, so linking the same target again updates the existing link.
+func remoteLinkBody(t *remoteLinkType, in RemoteLinkInput) map[string]any {
+	obj := map[string]any{"url": in.URL, "title": in.Title}
+	if in.Summary != "" {
+		obj["summary"] = in.Summary
+	}
+	body := map[string]any{"globalId": in.URL, "object": obj}
+	rel := in.Relationship
+	if in.IconURL != "" {
+		obj["icon"] = map[string]any{"url16x16": in.IconURL, "title": in.Title}
+	}
+	if t != nil {
+		body["application"] = map[string]any{"type": t.AppType, "name": t.AppName}
+		if t.IconURL != "" && in.IconURL == "" {
+			obj["icon"] = map[string]any{"url16x16": t.IconURL, "title": t.AppName}
+		}
+		if rel == "" {
+			rel = t.Relationship
+		}
+		if in.Status != "" {
+			obj["status"] = map[string]any{
+				"resolved": containsFold(t.ResolvedStatuses, in.Status),
+				"icon":     map[string]any{"title": in.Status},
+			}
+		}
+	}
+	if rel != "" {
+		body["relationship"] = rel
+	}
+	return body
+}
+
+type JiraRemoteLink struct {
+	ID           int            `json:"id"`
+	GlobalID     string         `json:"globalId,omitempty"`
+	Application  map[string]any `json:"application,omitempty"`
+	Relationship string         `json:"relationship,omitempty"`
+	Object       struct {
+		URL     string         `json:"url"`
+		Title   string         `json:"title"`
+		Summary string         `json:"summary,omitempty"`
+		Icon    map[string]any `json:"icon,omitempty"`
+		Status  map[string]any `json:"status,omitempty"`
+	} `json:"object"`
+}
+
+func (c *JiraClient) ListRemoteLinks(ctx context.Context, key string) ([]JiraRemoteLink, error) {
+	var out []JiraRemoteLink
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/issue/"+url.PathEscape(key)+"/remotelink", nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DeleteRemoteLink removes a remote link by numeric id, or by global id
+// (the linked URL for links made by add_remote_link).
+func (c *JiraClient) DeleteRemoteLink(ctx context.Context, key, id string) error {
+	path := "/rest/api/3/issue/" + url.PathEscape(key) + "/remotelink"
+	if _, err := strconv.Atoi(id); err == nil {
+		path += "/" + id
+	} else {
+		path += "?globalId=" + url.QueryEscape(id)
+	}
+	return c.doJSON(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// AddRemoteLink creates or updates a remote link and returns its id.
+func (c *JiraClient) AddRemoteLink(ctx context.Context, key string, body map[string]any) (int, error) {
+	var out struct {
+		ID   int    `json:"id"`
+		Self string `json:"self"`
+	}
+	if err := c.doJSON(ctx, http.MethodPost, "/rest/api/3/issue/"+url.PathEscape(key)+"/remotelink", body, &out); err != nil {
+		return 0, err
+	}
+	return out.ID, nil
+}
+
+func registerRemoteLinkTools(server *mcp.Server, jc *JiraClient) {
+	types, err := loadRemoteLinkTypes()
+	if err != nil {
+		logger.Printf("ignoring JIRA_REMOTE_LINK_TYPES: %v", err)
+		types = builtinRemoteLinkTypes
+	}
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// add_remote_link(key, url, title, type?, summary?, status?, relationship?, icon_url?)
+	type addRemoteLinkArgs struct {
+		Key          string `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+		URL          string `json:"url" jsonschema:"Link target"`
+		Title        string `json:"title" jsonschema:"Link title, e.g. the PR or ticket title"`
+		Type         string `json:"type,omitempty" jsonschema:"Target type (detected from the URL when omitted); none for a plain link"`
+		Summary      string `json:"summary,omitempty" jsonschema:"One-line summary shown under the title"`
+		Status       string `json:"status,omitempty" jsonschema:"Target status, e.g. open, merged, resolved"`
+		Relationship string `json:"relationship,omitempty" jsonschema:"Relationship label (defaults to the type's)"`
+		IconURL      string `json:"icon_url,omitempty" jsonschema:"16x16 icon URL (defaults to the type's)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "add_remote_link",
+		Title:       "Add Remote Link",
+		Description: "Link an issue to an external item (" + strings.Join(names, ", ") + ") with an icon and status so it renders richly in Jira. Linking the same URL again updates the link",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args addRemoteLinkArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=add_remote_link args={key:%q,url:%q,type:%q}", args.Key, args.URL, args.Type)
+		if _, err := url.ParseRequestURI(args.URL); err != nil {
+			return nil, nil, fmt.Errorf("invalid url %q: %w", args.URL, err)
+		}
+		typeName := strings.ToLower(args.Type)
+		if typeName == "" {
+			typeName = detectRemoteLinkType(types, args.URL)
+		}
+		var t *remoteLinkType
+		if typeName != "" && typeName != "none" {
+			tt, ok := types[typeName]
+			if !ok {
+				return nil, nil, fmt.Errorf("unknown remote link type %q (valid: %s, none)", args.Type, strings.Join(names, ", "))
+			}
+			t = &tt
+		}
+		id, err := jc.AddRemoteLink(ctx, args.Key, remoteLinkBody(t, RemoteLinkInput{
+			URL: args.URL, Title: args.Title, Summary: args.Summary, Status: args.Status, Relationship: args.Relationship, IconURL: args.IconURL,
+		}))
+		if err != nil {
+			debugf("tool=add_remote_link error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"key": args.Key, "link_id": id, "type": typeName, "url": args.URL}}, nil, nil
+	})
+
+	// list_remote_links(key)
+	type listRemoteLinksArgs struct {
+		Key string `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_remote_links",
+		Title:       "List Remote Links",
+		Description: "List the external links (web pages, PRs, incidents) attached to an issue",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listRemoteLinksArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_remote_links args={key:%q}", args.Key)
+		links, err := jc.ListRemoteLinks(ctx, args.Key)
+		if err != nil {
+			debugf("tool=list_remote_links error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"key": args.Key, "remote_links": links}}, nil, nil
+	})
+
+	// delete_remote_link(key, link_id)
+	type deleteRemoteLinkArgs struct {
+		Key    string `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+		LinkID string `json:"link_id" jsonschema:"Remote link id, or its global id (the URL for links made by add_remote_link)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "delete_remote_link",
+		Title:       "Delete Remote Link",
+		Description: "Remove an external link from an issue",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: ptr(true), IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args deleteRemoteLinkArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=delete_remote_link args={key:%q,id:%q}", args.Key, args.LinkID)
+		if err := jc.DeleteRemoteLink(ctx, args.Key, args.LinkID); err != nil {
+			debugf("tool=delete_remote_link error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"key": args.Key, "link_id": args.LinkID, "deleted": true}}, nil, nil
+	})
+}
@@ -0,0 +1,249 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Project structure scaffolding ----
+//
+// scaffold_project_structure takes a declarative spec and creates the
+// components and versions it names (reusing existing ones with the same
+// name), then its epics and their child stories in spec order.
+
+type scaffoldNamed struct {
+	Name        string `json:"name" jsonschema:"Name"`
+	Description string `json:"description,omitempty" jsonschema:"Description"`
+	ReleaseDate string `json:"release_date,omitempty" jsonschema:"Versions only: release date (YYYY-MM-DD)"`
+}
+
+type scaffoldIssue struct {
+	Summary     string         `json:"summary" jsonschema:"Issue summary"`
+	Description string         `json:"description,omitempty" jsonschema:"Description (Markdown)"`
+	Type        string         `json:"type,omitempty" jsonschema:"Issue type (default Epic for epics, Story for children)"`
+	Labels      []string       `json:"labels,omitempty" jsonschema:"Labels, in addition to the spec-wide labels"`
+	Components  []string       `json:"components,omitempty" jsonschema:"Component names"`
+	FixVersions []string       `json:"fix_versions,omitempty" jsonschema:"Version names"`
+	Fields      map[string]any `json:"fields,omitempty" jsonschema:"Extra fields keyed by id, name, or alias"`
+}
+
+type scaffoldEpic struct {
+	scaffoldIssue
+	Stories []scaffoldIssue `json:"stories,omitempty" jsonschema:"Child issues, in order"`
+}
+
+type scaffoldSpec struct {
+	Components []scaffoldNamed `json:"components,omitempty" jsonschema:"Components to ensure exist"`
+	Versions   []scaffoldNamed `json:"versions,omitempty" jsonschema:"Versions to ensure exist"`
+	Labels     []string        `json:"labels,omitempty" jsonschema:"Labels applied to every created issue"`
+	Epics      []scaffoldEpic  `json:"epics" jsonschema:"Epics with their child stories, in order"`
+}
+
+type scaffoldRef struct {
+	Name    string `json:"name"`
+	ID      string `json:"id"`
+	Created bool   `json:"created"`
+}
+
+type scaffoldNode struct {
+	Key      string         `json:"key"`
+	Summary  string         `json:"summary"`
+	Children []scaffoldNode `json:"children,omitempty"`
+}
+
+type scaffoldResult struct {
+	Project    string         `json:"project"`
+	Components []scaffoldRef  `json:"components,omitempty"`
+	Versions   []scaffoldRef  `json:"versions,omitempty"`
+	Tree       []scaffoldNode `json:"tree"`
+	Created    int            `json:"issues_created"`
+	Warnings   []string       `json:"warnings,omitempty"`
+	Error      string         `json:"error,omitempty"` // set when scaffolding stopped part way
+	Provenance string         `json:"provenance_run,omitempty"`
+}
+
+type jiraNamedObject struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// ensureNamed looks name up in existing (case-insensitively) and otherwise
+// POSTs body to path.
+func (c *JiraClient) ensureNamed(ctx context.Context, existing []jiraNamedObject, path string, body map[string]any) (scaffoldRef, error) {
+	name, _ := body["name"].(string)
+	for _, o := range existing {
+		if strings.EqualFold(o.Name, name) {
+			return scaffoldRef{Name: o.Name, ID: o.ID}, nil
+		}
+	}
+	var out jiraNamedObject
+	if err := c.doJSON(ctx, http.MethodPost, path, body, &out); err != nil {
+		return scaffoldRef{}, fmt.Errorf("creating %q: %w", name, err)
+	}
+	noteCreated(ctx, path[strings.LastIndex(path, "/")+1:], out.ID)
+	return scaffoldRef{Name: out.Name, ID: out.ID, Created: true}, nil
+}
+
+func (c *JiraClient) scaffoldIssueFields(ctx context.Context, project, issueType string, spec *scaffoldSpec, in scaffoldIssue) (map[string]any, error) {
+	fields, err := c.resolveFieldKeys(ctx, in.Fields)
+	if err != nil {
+		return nil, err
+	}
+	if fields == nil {
+		fields = map[string]any{}
+	}
+	fields["project"] = map[string]any{"key": project}
+	fields["issuetype"] = map[string]any{"name": issueType}
+	fields["summary"] = in.Summary
+	if in.Description != "" {
+		fields["description"] = c.richText(ctx, in.Description)
+	}
+	if labels := append(append([]string{}, spec.Labels...), in.Labels...); len(labels) > 0 {
+		fields["labels"] = labels
+	}
+	named := func(names []string) []any {
+		out := make([]any, 0, len(names))
+		for _, n := range names {
+			out = append(out, map[string]any{"name": n})
+		}
+		return out
+	}
+	if len(in.Components) > 0 {
+		fields["components"] = named(in.Components)
+	}
+	if len(in.FixVersions) > 0 {
+		fields["fixVersions"] = named(in.FixVersions)
+	}
+	return fields, nil
+}
+
+// rankInOrder ranks keys so they appear in the given order.
+func (c *JiraClient) rankInOrder(ctx context.Context, keys []string) error {
+	if len(keys) < 2 {
+		return nil
+	}
+	_, err := c.RankIssues(ctx, keys[1:], "", keys[0])
+	return err
+}
+
+// ScaffoldProject creates spec's structure in project. On failure the
+// result still lists everything created so far.
+func (c *JiraClient) ScaffoldProject(ctx context.Context, project string, spec *scaffoldSpec) *scaffoldResult {
+	res := &scaffoldResult{Project: project, Tree: []scaffoldNode{}}
+	fail := func(err error) *scaffoldResult {
+		res.Error = err.Error()
+		return res
+	}
+
+	if len(spec.Components) > 0 {
+		var existing []jiraNamedObject
+		if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/project/"+url.PathEscape(project)+"/components", nil, &existing); err != nil {
+			return fail(err)
+		}
+		for _, comp := range spec.Components {
+			ref, err := c.ensureNamed(ctx, existing, "/rest/api/3/component", map[string]any{
+				"name": comp.Name, "description": comp.Description, "project": project,
+			})
+			if err != nil {
+				return fail(fmt.Errorf("component: %w", err))
+			}
+			res.Components = append(res.Components, ref)
+		}
+	}
+	if len(spec.Versions) > 0 {
+		var existing []jiraNamedObject
+		if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/project/"+url.PathEscape(project)+"/versions", nil, &existing); err != nil {
+			return fail(err)
+		}
+		for _, v := range spec.Versions {
+			body := map[string]any{"name": v.Name, "description": v.Description, "project": project}
+			if v.ReleaseDate != "" {
+				body["releaseDate"] = v.ReleaseDate
+			}
+			ref, err := c.ensureNamed(ctx, existing, "/rest/api/3/version", body)
+			if err != nil {
+				return fail(fmt.Errorf("version: %w", err))
+			}
+			res.Versions = append(res.Versions, ref)
+		}
+	}
+
+	var epicKeys []string
+	for _, epic := range spec.Epics {
+		typ := epic.Type
+		if typ == "" {
+			typ = "Epic"
+		}
+		fields, err := c.scaffoldIssueFields(ctx, project, typ, spec, epic.scaffoldIssue)
+		if err != nil {
+			return fail(err)
+		}
+		created, err := c.CreateIssueFields(ctx, fields)
+		if err != nil {
+			return fail(fmt.Errorf("epic %q: %w", epic.Summary, err))
+		}
+		res.Created++
+		epicKeys = append(epicKeys, created.Key)
+		res.Tree = append(res.Tree, scaffoldNode{Key: created.Key, Summary: epic.Summary})
+		node := &res.Tree[len(res.Tree)-1]
+
+		var storyKeys []string
+		for _, story := range epic.Stories {
+			typ := story.Type
+			if typ == "" {
+				typ = "Story"
+			}
+			sf, err := c.scaffoldIssueFields(ctx, project, typ, spec, story)
+			if err != nil {
+				return fail(err)
+			}
+			sf["parent"] = map[string]any{"key": created.Key}
+			child, err := c.CreateIssueFields(ctx, sf)
+			if err != nil {
+				return fail(fmt.Errorf("story %q under %s: %w", story.Summary, created.Key, err))
+			}
+			res.Created++
+			storyKeys = append(storyKeys, child.Key)
+			node.Children = append(node.Children, scaffoldNode{Key: child.Key, Summary: story.Summary})
+		}
+		if err := c.rankInOrder(ctx, storyKeys); err != nil {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("could not rank stories under %s: %v", created.Key, err))
+		}
+	}
+	if err := c.rankInOrder(ctx, epicKeys); err != nil {
+		res.Warnings = append(res.Warnings, fmt.Sprintf("could not rank epics: %v", err))
+	}
+	return res
+}
+
+func registerScaffoldTools(server *mcp.Server, jc *JiraClient) {
+	// scaffold_project_structure(project, spec)
+	type scaffoldArgs struct {
+		Project string       `json:"project" jsonschema:"Project key"`
+		Spec    scaffoldSpec `json:"spec" jsonschema:"Structure to create: components, versions, labels, and epics with child stories"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "scaffold_project_structure",
+		Title:       "Scaffold Project Structure",
+		Description: "Create a project's initial structure in one call from a spec: components and versions (existing ones are reused), then epics with their child stories in order. Returns a tree of created keys",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args scaffoldArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=scaffold_project_structure args={project:%q,epics:%d,components:%d,versions:%d}", args.Project, len(args.Spec.Epics), len(args.Spec.Components), len(args.Spec.Versions))
+		if args.Project == "" {
+			return nil, nil, errors.New("project is required")
+		}
+		ctx, run := jc.beginProvenance(ctx)
+		res := jc.ScaffoldProject(ctx, args.Project, &args.Spec)
+		res.Provenance = jc.finishProvenance(ctx, run)
+		if res.Error != "" {
+			debugf("tool=scaffold_project_structure error=%s", res.Error)
+			return &mcp.CallToolResult{IsError: true, StructuredContent: res}, nil, nil
+		}
+		return &mcp.CallToolResult{StructuredContent: res}, nil, nil
+	})
+}
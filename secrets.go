@@ -0,0 +1,156 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ---- Tokens from files and the OS keychain ----
+//
+// Instead of JIRA_API_TOKEN, the token can come from a file
+// (JIRA_API_TOKEN_FILE) or the OS keychain (JIRA_API_TOKEN_KEYCHAIN, the
+// service name; the account is JIRA_USER_EMAIL when set):
+//
+//	macOS:   security add-generic-password -s jira-mcp -a me@acme.com -w
+//	Linux:   secret-tool store --label "Jira API token" service jira-mcp account me@acme.com
+//	Windows: cmdkey /generic:jira-mcp /user:me@acme.com /pass
+//
+// Either is re-read every JIRA_SECRET_REFRESH_SECONDS (default 300) so a
+// rotated token takes effect without a restart. A failed re-read keeps the
+// previous token.
+
+const defaultSecretRefresh = 5 * time.Minute
+
+// secret is a value read from outside the process and cached for refresh.
+type secret struct {
+	source  string // for messages, e.g. JIRA_API_TOKEN_FILE
+	read    func() (string, error)
+	refresh time.Duration
+
+	mu      sync.Mutex
+	value   string
+	fetched time.Time
+}
+
+func newSecret(source string, read func() (string, error)) *secret {
+	s := &secret{source: source, read: read, refresh: defaultSecretRefresh}
+	if v, err := strconv.Atoi(os.Getenv("JIRA_SECRET_REFRESH_SECONDS")); err == nil && v > 0 {
+		s.refresh = time.Duration(v) * time.Second
+	}
+	return s
+}
+
+// get returns the value, re-reading it once it is older than the refresh
+// interval. Only the first read can fail.
+func (s *secret) get() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.value != "" && time.Since(s.fetched) < s.refresh {
+		return s.value, nil
+	}
+	v, err := s.read()
+	if err == nil && v == "" {
+		err = errors.New("empty")
+	}
+	switch {
+	case err == nil:
+		if s.value != "" && v != s.value {
+			logger.Printf("%s: token changed; using the new one", s.source)
+		}
+		s.value = v
+	case s.value == "":
+		return "", fmt.Errorf("%s: %w", s.source, err)
+	default:
+		debugf("%s: re-read failed, keeping the previous token: %v", s.source, err)
+	}
+	s.fetched = time.Now()
+	return s.value, nil
+}
+
+// tokenSecret returns the secret configured by file (a path) or keychain
+// (a service name), or nil when both are empty. fileSetting and
+// keychainSetting name the settings in messages.
+func tokenSecret(fileSetting, file, keychainSetting, keychain, account string) *secret {
+	switch {
+	case file != "":
+		return newSecret(fileSetting, func() (string, error) {
+			b, err := os.ReadFile(file)
+			return strings.TrimSpace(string(b)), err
+		})
+	case keychain != "":
+		return newSecret(keychainSetting, func() (string, error) {
+			return readKeychain(keychain, account)
+		})
+	}
+	return nil
+}
+
+// readKeychain looks up the password stored for service (and account, if
+// not empty) in the OS credential store.
+func readKeychain(service, account string) (string, error) {
+	if runtime.GOOS == "windows" {
+		return readWindowsCredential(service)
+	}
+	var argv []string
+	switch runtime.GOOS {
+	case "darwin":
+		argv = []string{"security", "find-generic-password", "-s", service, "-w"}
+		if account != "" {
+			argv = append(argv, "-a", account)
+		}
+	default:
+		argv = []string{"secret-tool", "lookup", "service", service}
+		if account != "" {
+			argv = append(argv, "account", account)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w: %s", argv[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// tokenAuth builds the Authorization header from a token that may change.
+type tokenAuth struct {
+	token  *secret
+	email  string // empty for Bearer tokens
+	bearer bool
+}
+
+// authHeader is the default credential's Authorization header: Auth, kept
+// current with a rotating token.
+func (c *JiraClient) authHeader() string {
+	if c.tokenAuth != nil {
+		if h, err := c.tokenAuth.header(); err == nil {
+			return h
+		}
+	}
+	return c.Auth
+}
+
+func (t *tokenAuth) header() (string, error) {
+	tok, err := t.token.get()
+	if err != nil {
+		return "", err
+	}
+	if t.bearer {
+		return "Bearer " + tok, nil
+	}
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(t.email+":"+tok)), nil
+}
@@ -0,0 +1,287 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Sandbox seeding ----
+//
+// seed_sandbox fills a non-production project with believable data for
+// demos and agent testing: sprints, issues created through the bulk API,
+// then statuses, sprint membership, comments, and links. Every seeded issue
+// carries a label (default "seeded") so the data is easy to find and clean
+// up. It refuses to run against production instances.
+
+type seedLink struct {
+	Type string `json:"type" jsonschema:"Link type name, e.g. Blocks or Relates"`
+	To   string `json:"to" jsonschema:"ref of the other seeded issue"`
+}
+
+type seedIssue struct {
+	Ref         string     `json:"ref,omitempty" jsonschema:"Name other issues use to link to this one"`
+	Type        string     `json:"type,omitempty" jsonschema:"Issue type (default Task)"`
+	Summary     string     `json:"summary" jsonschema:"Issue summary"`
+	Description string     `json:"description,omitempty" jsonschema:"Description (Markdown)"`
+	Priority    string     `json:"priority,omitempty" jsonschema:"Priority name"`
+	Labels      []string   `json:"labels,omitempty" jsonschema:"Labels"`
+	Status      string     `json:"status,omitempty" jsonschema:"Status to move to after creation (one transition away)"`
+	Sprint      string     `json:"sprint,omitempty" jsonschema:"Name of a seeded sprint to put the issue in"`
+	Comments    []string   `json:"comments,omitempty" jsonschema:"Comments to add (Markdown), in order"`
+	Links       []seedLink `json:"links,omitempty" jsonschema:"Links from this issue: this issue <type's outward verb> to, e.g. blocks"`
+}
+
+type seedSprint struct {
+	Name string `json:"name" jsonschema:"Sprint name"`
+	Goal string `json:"goal,omitempty" jsonschema:"Sprint goal"`
+}
+
+type seedSpec struct {
+	Sprints []seedSprint `json:"sprints,omitempty" jsonschema:"Sprints to create on the board (future state)"`
+	Issues  []seedIssue  `json:"issues" jsonschema:"Issues to create"`
+}
+
+// seedPresets are ready-made specs for seed_sandbox.
+var seedPresets = map[string]func() *seedSpec{
+	"scrum":   scrumSeed,
+	"support": supportSeed,
+}
+
+func scrumSeed() *seedSpec {
+	return &seedSpec{
+		Sprints: []seedSprint{
+			{Name: "Sprint 1", Goal: "Ship the new sign-up flow"},
+			{Name: "Sprint 2", Goal: "Harden payments"},
+		},
+		Issues: []seedIssue{
+			{Ref: "signup", Type: "Story", Summary: "As a visitor I can sign up with my work email", Priority: "High", Sprint: "Sprint 1", Status: "In Progress",
+				Description: "Replace the legacy form.\n\n**Acceptance criteria**\n- Email verification link expires after 24h\n- Duplicate emails are rejected with a clear message",
+				Comments:    []string{"Design is in Figma, link in the epic.", "Backend endpoint is merged; wiring up the form next."}},
+			{Ref: "verify", Type: "Story", Summary: "Send verification email with branded template", Sprint: "Sprint 1",
+				Links: []seedLink{{Type: "Blocks", To: "signup"}}},
+			{Ref: "sso", Type: "Story", Summary: "Allow sign-in with Google Workspace SSO", Priority: "Medium", Sprint: "Sprint 1", Labels: []string{"auth"}},
+			{Ref: "captcha", Type: "Task", Summary: "Add bot protection to the sign-up form", Sprint: "Sprint 1", Status: "Done"},
+			{Ref: "dupe", Type: "Bug", Summary: "Sign-up accepts emails with trailing spaces as new accounts", Priority: "High", Sprint: "Sprint 1",
+				Description: "Steps to reproduce:\n1. Sign up with `ana@example.com `\n2. Sign up again with `ana@example.com`\n\nBoth succeed.",
+				Comments:    []string{"Reproduced on staging."}, Links: []seedLink{{Type: "Relates", To: "signup"}}},
+			{Ref: "retry", Type: "Story", Summary: "Retry failed card payments with exponential backoff", Priority: "High", Sprint: "Sprint 2", Labels: []string{"payments"}},
+			{Ref: "refund", Type: "Story", Summary: "Support partial refunds from the admin console", Sprint: "Sprint 2", Labels: []string{"payments"}},
+			{Ref: "webhook", Type: "Bug", Summary: "Payment webhook times out under load", Priority: "Highest", Sprint: "Sprint 2", Labels: []string{"payments"},
+				Comments: []string{"Seeing p99 of 31s in the gateway logs.", "Probably the synchronous ledger write; moving it to a queue."},
+				Links:    []seedLink{{Type: "Blocks", To: "retry"}}},
+			{Type: "Task", Summary: "Upgrade the payments SDK to v5", Sprint: "Sprint 2"},
+			{Type: "Story", Summary: "Dark mode for the account settings page", Priority: "Low"},
+			{Type: "Task", Summary: "Remove the unused legacy analytics script"},
+			{Type: "Bug", Summary: "Avatar upload fails for PNGs over 2 MB", Priority: "Medium"},
+		},
+	}
+}
+
+func supportSeed() *seedSpec {
+	return &seedSpec{
+		Issues: []seedIssue{
+			{Ref: "login", Type: "Bug", Summary: "Customers on iOS 17 are logged out every hour", Priority: "Highest", Labels: []string{"customer", "mobile"}, Status: "In Progress",
+				Comments: []string{"Three enterprise tenants reported this today.", "Token refresh fails when the app is backgrounded."}},
+			{Type: "Bug", Summary: "CSV export drops rows containing emoji", Priority: "Medium", Labels: []string{"customer"}},
+			{Ref: "invoice", Type: "Bug", Summary: "Invoice PDF shows the wrong VAT rate for Austria", Priority: "High", Labels: []string{"billing", "customer"}},
+			{Type: "Task", Summary: "Refund duplicate charge for order 48213", Priority: "High", Labels: []string{"billing"}, Status: "Done",
+				Links: []seedLink{{Type: "Relates", To: "invoice"}}},
+			{Type: "Task", Summary: "Update the help-center article on SSO setup", Priority: "Low", Labels: []string{"docs"}},
+			{Type: "Bug", Summary: "Password reset email lands in spam for Outlook users", Priority: "Medium", Labels: []string{"customer", "email"},
+				Comments: []string{"SPF record is missing the new mail provider."}},
+			{Type: "Task", Summary: "Investigate slow dashboard loads for tenant acme-eu", Labels: []string{"performance"},
+				Links: []seedLink{{Type: "Relates", To: "login"}}},
+			{Type: "Bug", Summary: "Timezone shown as UTC in scheduled report emails", Priority: "Low", Labels: []string{"customer"}},
+		},
+	}
+}
+
+type seedResult struct {
+	Project    string            `json:"project"`
+	Label      string            `json:"label"`
+	Sprints    map[string]int    `json:"sprints,omitempty"` // name -> id
+	Issues     map[string]string `json:"issues"`            // ref (or summary) -> key
+	Created    int               `json:"issues_created"`
+	Comments   int               `json:"comments_added"`
+	Links      int               `json:"links_created"`
+	Warnings   []string          `json:"warnings,omitempty"`
+	Error      string            `json:"error,omitempty"` // set when seeding stopped part way
+	Provenance string            `json:"provenance_run,omitempty"`
+}
+
+// SeedProject creates spec in project. On failure the result still lists
+// everything created so far; problems with individual comments, links, or
+// transitions are warnings.
+func (c *JiraClient) SeedProject(ctx context.Context, project string, boardID int, spec *seedSpec, label string) *seedResult {
+	res := &seedResult{Project: project, Label: label, Issues: map[string]string{}}
+	warn := func(format string, args ...any) { res.Warnings = append(res.Warnings, fmt.Sprintf(format, args...)) }
+
+	sprints := map[string]int{}
+	for _, sp := range spec.Sprints {
+		s, err := c.CreateSprint(ctx, boardID, JiraSprint{Name: sp.Name, Goal: sp.Goal})
+		if err != nil {
+			res.Error = fmt.Sprintf("sprint %q: %v", sp.Name, err)
+			return res
+		}
+		sprints[sp.Name] = s.ID
+	}
+	if len(sprints) > 0 {
+		res.Sprints = sprints
+	}
+
+	fields := make([]map[string]any, len(spec.Issues))
+	for i, in := range spec.Issues {
+		typ := in.Type
+		if typ == "" {
+			typ = "Task"
+		}
+		f := map[string]any{
+			"project":   map[string]any{"key": project},
+			"issuetype": map[string]any{"name": typ},
+			"summary":   in.Summary,
+			"labels":    append([]string{label}, in.Labels...),
+		}
+		if in.Description != "" {
+			f["description"] = c.richText(ctx, in.Description)
+		}
+		if in.Priority != "" {
+			f["priority"] = map[string]any{"name": in.Priority}
+		}
+		fields[i] = f
+	}
+	keys, errs, err := c.CreateIssuesBulk(ctx, fields)
+	byRef := map[string]string{}
+	for i, k := range keys {
+		if k == "" {
+			continue
+		}
+		name := spec.Issues[i].Ref
+		if name == "" {
+			name = spec.Issues[i].Summary
+		}
+		res.Issues[name] = k
+		byRef[spec.Issues[i].Ref] = k
+		res.Created++
+	}
+	for i, msg := range errs {
+		warn("issue %q not created: %s", spec.Issues[i].Summary, msg)
+	}
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+
+	inSprint := map[string][]string{}
+	for i, in := range spec.Issues {
+		if in.Sprint != "" && keys[i] != "" {
+			inSprint[in.Sprint] = append(inSprint[in.Sprint], keys[i])
+		}
+	}
+	for name, ks := range inSprint {
+		id, ok := sprints[name]
+		if !ok {
+			warn("sprint %q is not in the spec; %d issues left in the backlog", name, len(ks))
+			continue
+		}
+		if err := c.MoveToSprint(ctx, id, ks); err != nil {
+			warn("moving issues to %q: %v", name, err)
+		}
+	}
+
+	for i, in := range spec.Issues {
+		key := keys[i]
+		if key == "" {
+			continue
+		}
+		if in.Status != "" {
+			ts, err := c.ListTransitions(ctx, key)
+			if err == nil {
+				var t *JiraTransition
+				if t, err = findTransition(ts, in.Status); err == nil {
+					err = c.TransitionIssue(ctx, key, t.ID, nil, "")
+				}
+			}
+			if err != nil {
+				warn("%s: status %q: %v", key, in.Status, err)
+			}
+		}
+		for _, body := range in.Comments {
+			if _, err := c.AddComment(ctx, key, body); err != nil {
+				warn("%s: comment: %v", key, err)
+				continue
+			}
+			res.Comments++
+		}
+		for _, l := range in.Links {
+			to, ok := byRef[l.To]
+			if !ok || l.To == "" {
+				warn("%s: link to unknown ref %q", key, l.To)
+				continue
+			}
+			// "key blocks to": to is the inward side ("is blocked by").
+			if _, err := c.LinkIssues(ctx, l.Type, to, key); err != nil {
+				warn("%s: link %s %s: %v", key, l.Type, to, err)
+				continue
+			}
+			res.Links++
+		}
+	}
+	return res
+}
+
+func registerSeedTools(server *mcp.Server, jc *JiraClient) {
+	// seed_sandbox(project, preset?, spec?, board_id?, label?)
+	type seedArgs struct {
+		Project string    `json:"project" jsonschema:"Sandbox project key"`
+		Preset  string    `json:"preset,omitempty" jsonschema:"Ready-made data set: scrum (two sprints of stories and bugs) or support (customer bugs and tasks)"`
+		Spec    *seedSpec `json:"spec,omitempty" jsonschema:"Custom data set, instead of a preset"`
+		BoardID int       `json:"board_id,omitempty" jsonschema:"Board to create sprints on (required when the data has sprints)"`
+		Label   string    `json:"label,omitempty" jsonschema:"Label put on every seeded issue (default seeded)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "seed_sandbox",
+		Title:       "Seed Sandbox",
+		Description: "Admin: fill a non-production project with realistic test data (issues, comments, sprints, links) from a preset or spec. Refuses production instances",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args seedArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=seed_sandbox args={project:%q,preset:%q,spec:%t,board:%d}", args.Project, args.Preset, args.Spec != nil, args.BoardID)
+		if ic := jc.forSite(ctx).Instance; ic.Production {
+			return nil, nil, fmt.Errorf("seed_sandbox only runs against non-production instances; %q is production", ic.Label)
+		}
+		if args.Project == "" {
+			return nil, nil, errors.New("project is required")
+		}
+		spec := args.Spec
+		switch {
+		case spec != nil && args.Preset != "":
+			return nil, nil, errors.New("give preset or spec, not both")
+		case spec == nil:
+			preset, ok := seedPresets[strings.ToLower(args.Preset)]
+			if !ok {
+				return nil, nil, fmt.Errorf("unknown preset %q (valid: scrum, support), or pass a spec", args.Preset)
+			}
+			spec = preset()
+		}
+		if len(spec.Issues) == 0 {
+			return nil, nil, errors.New("the spec has no issues")
+		}
+		if len(spec.Sprints) > 0 && args.BoardID == 0 {
+			return nil, nil, errors.New("board_id is required to create sprints (see list_boards)")
+		}
+		label := args.Label
+		if label == "" {
+			label = "seeded"
+		}
+		ctx, run := jc.beginProvenance(ctx)
+		res := jc.SeedProject(ctx, strings.ToUpper(args.Project), args.BoardID, spec, label)
+		res.Provenance = jc.finishProvenance(ctx, run)
+		if res.Error != "" {
+			debugf("tool=seed_sandbox error=%s", res.Error)
+			return &mcp.CallToolResult{IsError: true, StructuredContent: res}, nil, nil
+		}
+		return &mcp.CallToolResult{StructuredContent: res}, nil, nil
+	})
+}
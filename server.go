@@ -0,0 +1,307 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Embedding ----
+//
+// Server ties the MCP server, its Jira client, and background work (the
+// write queue) to an explicit lifecycle, so the tools can run inside
+// another program: build a JiraClient (NewJiraClientFromEnv), pass it to
+// NewServer, then Run it over any transport, or Start it and hand
+// Options.MCPServer to your own transport to serve these tools alongside
+// others. Log output goes to the standard logger (log.Default), so
+// log.SetOutput redirects it for the whole process.
+
+// Options configures NewServer. The zero value serves a new MCP server.
+type Options struct {
+	// MCPServer, if set, receives the tools, resources, and middleware
+	// instead of a new server, to compose them with other tools.
+	MCPServer *mcp.Server
+
+	// HTTPClient, if set, replaces the client used to call Jira (and with
+	// it any JIRA_EGRESS transport).
+	HTTPClient *http.Client
+
+	// Archive, if set, replaces the store issue snapshots are kept in
+	// (JIRA_ARCHIVE_DIR).
+	Archive ArchiveStore
+
+	// Name and Version identify a new MCP server (default "jira", "0.1.0").
+	Name    string
+	Version string
+}
+
+type Server struct {
+	MCP    *mcp.Server
+	Client *JiraClient
+
+	owned     bool // MCP was created by NewServer
+	calls     *callLog
+	snapshots snapshotStore // search snapshots; see snapshots.go
+	inflight  atomic.Int64  // HTTP requests being handled; see RunHTTP
+
+	mu      sync.Mutex
+	cancel  context.CancelFunc // stops background work; nil until Start
+	workers sync.WaitGroup
+}
+
+// NewServer registers the Jira tools and resources for jc. Nothing runs and
+// Jira is not contacted until Start or Run.
+func NewServer(jc *JiraClient, opts *Options) (*Server, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	if opts.HTTPClient != nil {
+		jc.Client = wrapClientForDebug(opts.HTTPClient)
+	}
+	if opts.Archive != nil {
+		jc.Archive = opts.Archive
+	}
+	if jc.Archive == nil {
+		jc.Archive = &memArchive{}
+	}
+	if jc.sites == nil {
+		jc.site, jc.sites = defaultSiteName, []*JiraClient{jc}
+	}
+	for _, site := range jc.sites[1:] {
+		site.Client, site.Archive = jc.Client, jc.Archive
+	}
+	s := &Server{MCP: opts.MCPServer, Client: jc}
+	if s.MCP == nil {
+		name, version := opts.Name, opts.Version
+		if name == "" {
+			name = "jira"
+		}
+		if version == "" {
+			version = "0.1.0"
+		}
+		debugf("Starting MCP server: name=%s version=%s", name, version)
+		instructions := jc.Instance.banner()
+		if len(jc.sites) > 1 {
+			instructions += " Other Jira sites are reachable through each tool's site argument; see list_sites."
+		}
+		s.MCP = mcp.NewServer(&mcp.Implementation{Name: name, Version: version}, &mcp.ServerOptions{
+			Instructions:       instructions,
+			SubscribeHandler:   subscriptionHandler[*mcp.SubscribeRequest],
+			UnsubscribeHandler: subscriptionHandler[*mcp.UnsubscribeRequest],
+		})
+		s.owned = true
+	}
+	calls, err := openCallLog()
+	if err != nil {
+		return nil, err
+	}
+	s.calls = calls
+	if calls != nil {
+		s.MCP.AddReceivingMiddleware(callLogMiddleware(calls, jc))
+	}
+	s.MCP.AddReceivingMiddleware(instanceMiddleware(jc))
+	s.MCP.AddReceivingMiddleware(capabilityMiddleware(jc))
+	mode, err := loadSessionAuthMode()
+	if err != nil {
+		return nil, err
+	}
+	if mode != sessionAuthOff {
+		s.MCP.AddReceivingMiddleware(sessionAuthMiddleware(jc, mode))
+	}
+	if f := loadToolFilter(); f != nil {
+		s.MCP.AddReceivingMiddleware(toolFilterMiddleware(f))
+	}
+	if len(jc.sites) > 1 {
+		// Outermost, so the rest see the selected site and not the argument.
+		s.MCP.AddReceivingMiddleware(siteMiddleware(jc))
+	}
+	logger.Print(jc.Instance.banner())
+	registerAll(s.MCP, jc, &s.snapshots)
+	return s, nil
+}
+
+// Start begins background work, which runs until Shutdown or until ctx
+// ends.
+func (s *Server) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		return errors.New("server already started")
+	}
+	ctx, s.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	if jc := s.Client; jc.queue != nil {
+		s.workers.Add(1)
+		go func() {
+			defer s.workers.Done()
+			jc.runWriteQueue(ctx, s.MCP)
+		}()
+	}
+	return nil
+}
+
+// Run starts the server and serves one client over t until the client
+// disconnects or ctx ends, then shuts down.
+func (s *Server) Run(ctx context.Context, t mcp.Transport) error {
+	if err := s.Start(ctx); err != nil {
+		return err
+	}
+	err := s.MCP.Run(ctx, t)
+	if serr := s.Shutdown(context.WithoutCancel(ctx)); err == nil {
+		err = serr
+	}
+	return err
+}
+
+// Shutdown stops background work and waits for it until ctx ends. It
+// closes the server's sessions when NewServer created the MCP server.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.mu.Unlock()
+	if s.owned {
+		for ss := range s.MCP.Sessions() {
+			_ = ss.Close()
+		}
+	}
+	done := make(chan struct{})
+	go func() {
+		s.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if s.calls != nil {
+		return s.calls.close()
+	}
+	return nil
+}
+
+// registerAll adds every tool and resource to server.
+func registerAll(server *mcp.Server, jc *JiraClient, snapshots *snapshotStore) {
+	registerCoreTools(server, jc, snapshots)
+	registerIssueTools(server, jc)
+	registerTransitionTools(server, jc)
+	registerUserTools(server, jc)
+	registerIncidentTools(server, jc)
+	registerBacklogTools(server, jc)
+	registerRichTextTools(server)
+	registerWorkflowTools(server, jc)
+	registerCommentTools(server, jc)
+	registerRemoteLinkTools(server, jc)
+	registerSnapshotTools(server, jc, snapshots)
+	registerAttachmentTools(server, jc)
+	registerPatchTools(server, jc)
+	registerTriageTools(server, jc)
+	registerLinkTools(server, jc)
+	registerWorklogTools(server, jc)
+	registerCloneTools(server, jc)
+	registerScaffoldTools(server, jc)
+	registerWatcherTools(server, jc)
+	registerOutcomeTools(server, jc)
+	registerLabelTools(server, jc)
+	registerFocusTools(server, jc)
+	registerJPDTools(server, jc)
+	registerWriteQueueTools(server, jc)
+	registerJQLTools(server, jc)
+	registerFilterTools(server, jc)
+	registerBoardTools(server, jc)
+	registerSeedTools(server, jc)
+	registerLintTools(server, jc)
+	registerSprintTools(server, jc)
+	registerRateLimitTools(server, jc)
+	registerProvenanceTools(server, jc)
+	registerEpicTools(server, jc)
+	registerUploadTools(server, jc)
+	registerProjectTools(server, jc)
+	registerBranchTools(server, jc)
+	registerComponentTools(server, jc)
+	registerNotificationTools(server, jc)
+	registerVersionTools(server, jc)
+	registerReleaseNoteTools(server, jc)
+	registerArchiveTools(server, jc)
+	registerSignalTools(server, jc)
+	registerTempoTools(server, jc)
+	registerEstimateTools(server, jc)
+	registerServiceDeskTools(server, jc)
+	registerQueueTools(server, jc)
+	registerApprovalTools(server, jc)
+	registerCustomerTools(server, jc)
+	registerFieldTools(server, jc)
+	registerCatalogTools(server, jc)
+	registerPermissionTools(server, jc)
+	registerGroupTools(server, jc)
+	registerSiteTools(server, jc)
+	// Custom tools come last so that one may replace a built-in.
+	registerCustomTools(server, jc)
+	registerMetricsResources(server, jc)
+	registerHealthResources(server, jc)
+	registerStatusResources(server, jc)
+}
+
+// toolFilter hides tools: with an allowlist (JIRA_TOOLS) every other tool,
+// and the tools in JIRA_DISABLED_TOOLS.
+type toolFilter struct {
+	enabled  map[string]bool // nil allows all
+	disabled map[string]bool
+}
+
+func loadToolFilter() *toolFilter {
+	names := func(v string) map[string]bool {
+		var m map[string]bool
+		for _, n := range strings.Split(v, ",") {
+			if n = strings.TrimSpace(n); n != "" {
+				if m == nil {
+					m = map[string]bool{}
+				}
+				m[n] = true
+			}
+		}
+		return m
+	}
+	f := &toolFilter{enabled: names(os.Getenv("JIRA_TOOLS")), disabled: names(os.Getenv("JIRA_DISABLED_TOOLS"))}
+	if f.enabled == nil && f.disabled == nil {
+		return nil
+	}
+	return f
+}
+
+func (f *toolFilter) allows(name string) bool {
+	return (f.enabled == nil || f.enabled[name]) && !f.disabled[name]
+}
+
+// toolFilterMiddleware leaves filtered tools out of tools/list and refuses
+// calls to them.
+func toolFilterMiddleware(f *toolFilter) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if ctreq, ok := req.(*mcp.CallToolRequest); ok && method == "tools/call" && !f.allows(ctreq.Params.Name) {
+				return nil, fmt.Errorf("tool %q is disabled by the server configuration", ctreq.Params.Name)
+			}
+			res, err := next(ctx, method, req)
+			ltres, ok := res.(*mcp.ListToolsResult)
+			if method != "tools/list" || !ok || err != nil {
+				return res, err
+			}
+			out := *ltres
+			out.Tools = make([]*mcp.Tool, 0, len(ltres.Tools))
+			for _, t := range ltres.Tools {
+				if f.allows(t.Name) {
+					out.Tools = append(out.Tools, t)
+				}
+			}
+			return &out, nil
+		}
+	}
+}
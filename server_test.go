@@ -0,0 +1,80 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	jc, _ := fakeSite(t, "default")
+	s, err := NewServer(jc, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestServerShutdownBeforeStart(t *testing.T) {
+	s := newTestServer(t)
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown before Start = %v, want nil", err)
+	}
+}
+
+func TestServerStartTwice(t *testing.T) {
+	s := newTestServer(t)
+	ctx := context.Background()
+	if err := s.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Start(ctx); err == nil {
+		t.Error("second Start succeeded, want an error")
+	}
+	if err := s.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown = %v", err)
+	}
+}
+
+func TestServerShutdownTimeout(t *testing.T) {
+	s := newTestServer(t)
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// A worker that ignores cancellation outlives Shutdown's deadline.
+	release := make(chan struct{})
+	s.workers.Add(1)
+	go func() {
+		defer s.workers.Done()
+		<-release
+	}()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := s.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown with a running worker = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestServerRun(t *testing.T) {
+	s := newTestServer(t)
+	st, ct := mcp.NewInMemoryTransports()
+	done := make(chan error, 1)
+	go func() { done <- s.Run(context.Background(), st) }()
+
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil).Connect(context.Background(), ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs.Close()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after the client disconnected")
+	}
+}
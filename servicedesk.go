@@ -0,0 +1,543 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Jira Service Management ----
+//
+// The JSM API (/rest/servicedeskapi) works with customer requests rather
+// than issues: a request is raised in a service desk with a request type,
+// whose fields are what the customer portal shows. Requests are still
+// issues, so the issue tools work on them too; these tools add the
+// customer-facing view. JSM calls go through the "servicedesk" credential
+// route when JIRA_CREDENTIALS has one.
+
+// sdPage returns up to limit values (all when limit <= 0) from a paged
+// servicedeskapi collection.
+func sdPage[T any](ctx context.Context, c *JiraClient, path string, q url.Values, limit int) ([]T, error) {
+	if q == nil {
+		q = url.Values{}
+	}
+	var out []T
+	for {
+		q.Set("start", strconv.Itoa(len(out)))
+		q.Set("limit", "100")
+		var page struct {
+			IsLastPage bool `json:"isLastPage"`
+			Values     []T  `json:"values"`
+		}
+		if err := c.doJSON(ctx, http.MethodGet, path+"?"+q.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		out = append(out, page.Values...)
+		if limit > 0 && len(out) >= limit {
+			return out[:limit], nil
+		}
+		if page.IsLastPage || len(page.Values) == 0 {
+			return out, nil
+		}
+	}
+}
+
+type JiraServiceDesk struct {
+	ID          string `json:"id"`
+	ProjectID   string `json:"projectId"`
+	ProjectKey  string `json:"projectKey"`
+	ProjectName string `json:"projectName"`
+}
+
+func (c *JiraClient) ServiceDesks(ctx context.Context) ([]JiraServiceDesk, error) {
+	return sdPage[JiraServiceDesk](ctx, c, "/rest/servicedeskapi/servicedesk", nil, 0)
+}
+
+// ServiceDesk finds a service desk by id or project key.
+func (c *JiraClient) ServiceDesk(ctx context.Context, ref string) (*JiraServiceDesk, error) {
+	desks, err := c.ServiceDesks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for i, d := range desks {
+		if d.ID == ref || strings.EqualFold(d.ProjectKey, ref) {
+			return &desks[i], nil
+		}
+		keys = append(keys, d.ProjectKey)
+	}
+	return nil, fmt.Errorf("no service desk %q (service desks: %s)", ref, orNone(strings.Join(keys, ", ")))
+}
+
+// serviceDeskFor resolves a tool's service_desk argument, falling back to
+// the session focus project.
+func (c *JiraClient) serviceDeskFor(ctx context.Context, ss *mcp.ServerSession, ref string) (*JiraServiceDesk, error) {
+	if f := c.focusFor(ss); ref == "" && f != nil {
+		ref = f.Project
+	}
+	if ref == "" {
+		return nil, errors.New("service_desk is required (or set a focus project)")
+	}
+	return c.ServiceDesk(ctx, ref)
+}
+
+type JiraRequestType struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	HelpText    string `json:"helpText,omitempty"`
+}
+
+func (c *JiraClient) RequestTypes(ctx context.Context, serviceDeskID string) ([]JiraRequestType, error) {
+	return sdPage[JiraRequestType](ctx, c, "/rest/servicedeskapi/servicedesk/"+url.PathEscape(serviceDeskID)+"/requesttype", nil, 0)
+}
+
+// RequestType finds a service desk's request type by id or name.
+func (c *JiraClient) RequestType(ctx context.Context, serviceDeskID, ref string) (*JiraRequestType, error) {
+	types, err := c.RequestTypes(ctx, serviceDeskID)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for i, t := range types {
+		if t.ID == ref || strings.EqualFold(t.Name, ref) {
+			return &types[i], nil
+		}
+		names = append(names, t.Name)
+	}
+	return nil, fmt.Errorf("no request type %q (request types: %s)", ref, orNone(strings.Join(names, ", ")))
+}
+
+type requestTypeField struct {
+	FieldID     string `json:"fieldId"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required"`
+	ValidValues []struct {
+		Value string `json:"value"`
+		Label string `json:"label"`
+	} `json:"validValues,omitempty"`
+	JiraSchema map[string]any `json:"jiraSchema,omitempty"`
+}
+
+func (c *JiraClient) RequestTypeFields(ctx context.Context, serviceDeskID, requestTypeID string) ([]requestTypeField, error) {
+	var out struct {
+		Fields []requestTypeField `json:"requestTypeFields"`
+	}
+	path := "/rest/servicedeskapi/servicedesk/" + url.PathEscape(serviceDeskID) + "/requesttype/" + url.PathEscape(requestTypeID) + "/field"
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Fields, nil
+}
+
+// requestFieldValues maps values given by field id or name onto the
+// request type's fields. Values with a fixed set of choices may be given by
+// label and are sent as option ids. Missing required fields are an error.
+func requestFieldValues(fields []requestTypeField, given map[string]any) (map[string]any, error) {
+	out := map[string]any{}
+	used := map[string]bool{}
+	for _, f := range fields {
+		var v any
+		var ok bool
+		for k, gv := range given {
+			if k == f.FieldID || strings.EqualFold(k, f.Name) {
+				v, ok = gv, true
+				used[k] = true
+				break
+			}
+		}
+		if !ok {
+			if f.Required {
+				return nil, fmt.Errorf("%s (%s) is required", f.Name, f.FieldID)
+			}
+			continue
+		}
+		if len(f.ValidValues) == 0 {
+			out[f.FieldID] = v
+			continue
+		}
+		var ids []any
+		for _, s := range valueStrings(v) {
+			id := ""
+			var labels []string
+			for _, vv := range f.ValidValues {
+				if vv.Value == s || strings.EqualFold(vv.Label, s) {
+					id = vv.Value
+				}
+				labels = append(labels, vv.Label)
+			}
+			if id == "" {
+				return nil, fmt.Errorf("%s: %q is not one of %s", f.Name, s, strings.Join(labels, ", "))
+			}
+			ids = append(ids, map[string]any{"id": id})
+		}
+		if fieldString(f.JiraSchema, "type") == "array" {
+			out[f.FieldID] = ids
+		} else if len(ids) == 1 {
+			out[f.FieldID] = ids[0]
+		} else {
+			return nil, fmt.Errorf("%s takes one value", f.Name)
+		}
+	}
+	for k := range given {
+		if !used[k] {
+			var names []string
+			for _, f := range fields {
+				names = append(names, f.Name)
+			}
+			return nil, fmt.Errorf("request type has no field %q (fields: %s)", k, strings.Join(names, ", "))
+		}
+	}
+	return out, nil
+}
+
+// JiraRequest is a customer request as the servicedeskapi returns it.
+type JiraRequest struct {
+	IssueID       string         `json:"issueId"`
+	IssueKey      string         `json:"issueKey"`
+	RequestTypeID string         `json:"requestTypeId"`
+	ServiceDeskID string         `json:"serviceDeskId"`
+	CreatedDate   map[string]any `json:"createdDate"`
+	Reporter      map[string]any `json:"reporter"`
+	FieldValues   []struct {
+		FieldID string `json:"fieldId"`
+		Label   string `json:"label"`
+		Value   any    `json:"value"`
+	} `json:"requestFieldValues"`
+	CurrentStatus map[string]any   `json:"currentStatus"`
+	RequestType   *JiraRequestType `json:"requestType,omitempty"`
+	Links         map[string]any   `json:"_links"`
+}
+
+type requestView struct {
+	Key            string         `json:"key"`
+	ServiceDeskID  string         `json:"service_desk_id"`
+	RequestType    string         `json:"request_type"`
+	Status         string         `json:"status"`
+	StatusCategory string         `json:"status_category"`
+	StatusDate     string         `json:"status_date,omitempty"`
+	Created        string         `json:"created"`
+	Reporter       string         `json:"reporter,omitempty"`
+	Fields         map[string]any `json:"fields,omitempty"`
+	PortalURL      string         `json:"portal_url,omitempty"`
+}
+
+func viewRequest(r *JiraRequest) requestView {
+	v := requestView{
+		Key: r.IssueKey, ServiceDeskID: r.ServiceDeskID, RequestType: r.RequestTypeID,
+		Status: fieldString(r.CurrentStatus, "status"), StatusCategory: fieldString(r.CurrentStatus, "statusCategory"),
+		StatusDate: fieldString(r.CurrentStatus, "statusDate", "iso8601"), Created: fieldString(r.CreatedDate, "iso8601"),
+		Reporter: fieldString(r.Reporter, "displayName"), PortalURL: fieldString(r.Links, "web"),
+	}
+	if r.RequestType != nil {
+		v.RequestType = r.RequestType.Name
+	}
+	for _, f := range r.FieldValues {
+		if f.Value == nil {
+			continue
+		}
+		if v.Fields == nil {
+			v.Fields = map[string]any{}
+		}
+		v.Fields[f.Label] = displayValue(f.Value)
+	}
+	return v
+}
+
+// CreateRequest raises a customer request. onBehalfOf is a customer's
+// email or accountId; participants are accountIds.
+func (c *JiraClient) CreateRequest(ctx context.Context, serviceDeskID, requestTypeID string, values map[string]any, onBehalfOf string, participants []string) (*JiraRequest, error) {
+	body := map[string]any{
+		"serviceDeskId": serviceDeskID, "requestTypeId": requestTypeID, "requestFieldValues": values,
+	}
+	if onBehalfOf != "" {
+		body["raiseOnBehalfOf"] = onBehalfOf
+	}
+	if len(participants) > 0 {
+		body["requestParticipants"] = participants
+	}
+	var out JiraRequest
+	if err := c.doJSON(ctx, http.MethodPost, "/rest/servicedeskapi/request", body, &out); err != nil {
+		return nil, err
+	}
+	c.recordAction(ctx, actionCreate, out.IssueKey, "")
+	noteCreated(ctx, "issue", out.IssueKey)
+	return &out, nil
+}
+
+func (c *JiraClient) GetRequest(ctx context.Context, key string) (*JiraRequest, error) {
+	var out JiraRequest
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/servicedeskapi/request/"+url.PathEscape(key)+"?expand=requestType", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+type requestStatusChange struct {
+	Status         string `json:"status"`
+	StatusCategory string `json:"status_category"`
+	At             string `json:"at"`
+}
+
+// RequestStatusHistory returns a request's status changes, newest first.
+func (c *JiraClient) RequestStatusHistory(ctx context.Context, key string) ([]requestStatusChange, error) {
+	raw, err := sdPage[map[string]any](ctx, c, "/rest/servicedeskapi/request/"+url.PathEscape(key)+"/status", nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]requestStatusChange, len(raw))
+	for i, s := range raw {
+		out[i] = requestStatusChange{
+			Status: fieldString(s, "status"), StatusCategory: fieldString(s, "statusCategory"), At: fieldString(s, "statusDate", "iso8601"),
+		}
+	}
+	return out, nil
+}
+
+func registerServiceDeskTools(server *mcp.Server, jc *JiraClient) {
+	// list_request_types(service_desk)
+	type listRequestTypesArgs struct {
+		ServiceDesk string `json:"service_desk,omitempty" jsonschema:"Service desk id or project key (default the session focus project); omit to list the service desks"`
+		Fields      bool   `json:"fields,omitempty" jsonschema:"Include each request type's fields, with required flags and allowed values"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_request_types",
+		Title:       "List Request Types",
+		Description: "List the service desks, or the request types of one with their portal fields, as needed for create_request",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listRequestTypesArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_request_types args={service_desk:%q,fields:%t}", args.ServiceDesk, args.Fields)
+		if f := jc.focusFor(req.Session); args.ServiceDesk == "" && f != nil {
+			args.ServiceDesk = f.Project
+		}
+		if args.ServiceDesk == "" {
+			desks, err := jc.ServiceDesks(ctx)
+			if err != nil {
+				debugf("tool=list_request_types error=%v", err)
+				return nil, nil, err
+			}
+			return &mcp.CallToolResult{StructuredContent: map[string]any{"service_desks": desks}}, nil, nil
+		}
+		sd, err := jc.ServiceDesk(ctx, args.ServiceDesk)
+		if err != nil {
+			return nil, nil, err
+		}
+		types, err := jc.RequestTypes(ctx, sd.ID)
+		if err != nil {
+			debugf("tool=list_request_types error=%v", err)
+			return nil, nil, err
+		}
+		out := make([]map[string]any, len(types))
+		for i, t := range types {
+			out[i] = map[string]any{"id": t.ID, "name": t.Name, "description": t.Description}
+			if args.Fields {
+				fields, err := jc.RequestTypeFields(ctx, sd.ID, t.ID)
+				if err != nil {
+					debugf("tool=list_request_types error=%v", err)
+					return nil, nil, err
+				}
+				out[i]["fields"] = fields
+			}
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"service_desk": sd, "request_types": out}}, nil, nil
+	})
+
+	// create_request(service_desk?, request_type, summary, description?, fields?, on_behalf_of?, participants?)
+	type createRequestArgs struct {
+		ServiceDesk  string         `json:"service_desk,omitempty" jsonschema:"Service desk id or project key (default the session focus project)"`
+		RequestType  string         `json:"request_type" jsonschema:"Request type name or id (see list_request_types)"`
+		Summary      string         `json:"summary"`
+		Description  string         `json:"description,omitempty" jsonschema:"What the customer needs, as plain text"`
+		Fields       map[string]any `json:"fields,omitempty" jsonschema:"Other request type fields by field id or portal name; choices may be given by label"`
+		OnBehalfOf   string         `json:"on_behalf_of,omitempty" jsonschema:"Customer email or accountId to raise the request for"`
+		Participants []string       `json:"participants,omitempty" jsonschema:"Users to add as request participants (accountId, email, or display name)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "create_request",
+		Title:       "Create Service Request",
+		Description: "Raise a Jira Service Management customer request with a request type and its portal fields, optionally on behalf of a customer",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args createRequestArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=create_request args={service_desk:%q,type:%q,summary:%q,on_behalf_of:%q}", args.ServiceDesk, args.RequestType, args.Summary, args.OnBehalfOf)
+		sd, err := jc.serviceDeskFor(ctx, req.Session, args.ServiceDesk)
+		if err != nil {
+			return nil, nil, err
+		}
+		rt, err := jc.RequestType(ctx, sd.ID, args.RequestType)
+		if err != nil {
+			return nil, nil, err
+		}
+		fields, err := jc.RequestTypeFields(ctx, sd.ID, rt.ID)
+		if err != nil {
+			debugf("tool=create_request error=%v", err)
+			return nil, nil, err
+		}
+		given := map[string]any{}
+		for k, v := range args.Fields {
+			given[k] = v
+		}
+		given["summary"] = args.Summary
+		if args.Description != "" {
+			given["description"] = args.Description
+		}
+		values, err := requestFieldValues(fields, given)
+		if err != nil {
+			return nil, nil, fmt.Errorf("request type %s: %w", rt.Name, err)
+		}
+		var participants []string
+		for _, p := range args.Participants {
+			u, err := jc.ResolveUser(ctx, p)
+			if err != nil {
+				return nil, nil, fmt.Errorf("participant %q: %w", p, err)
+			}
+			participants = append(participants, u.AccountID)
+		}
+		r, err := jc.CreateRequest(ctx, sd.ID, rt.ID, values, args.OnBehalfOf, participants)
+		if err != nil {
+			debugf("tool=create_request error=%v", err)
+			return nil, nil, err
+		}
+		v := viewRequest(r)
+		v.RequestType = rt.Name
+		return &mcp.CallToolResult{StructuredContent: v}, nil, nil
+	})
+
+	// list_requests(service_desk?, status?, request_type?, search?, max_results?)
+	type listRequestsArgs struct {
+		ServiceDesk string `json:"service_desk,omitempty" jsonschema:"Service desk id or project key (default the session focus project; all desks when neither)"`
+		Status      string `json:"status,omitempty" jsonschema:"open (default), closed, or all"`
+		RequestType string `json:"request_type,omitempty" jsonschema:"Only this request type (name or id; needs service_desk)"`
+		Search      string `json:"search,omitempty" jsonschema:"Only requests whose summary matches this text"`
+		MaxResults  int    `json:"max_results,omitempty" jsonschema:"Maximum requests (default 50, max 500)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_requests",
+		Title:       "List Service Requests",
+		Description: "List customer requests in a service desk as the portal shows them: request type, customer-facing status, and reporter",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listRequestsArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_requests args={service_desk:%q,status:%q,type:%q,search:%q}", args.ServiceDesk, args.Status, args.RequestType, args.Search)
+		if f := jc.focusFor(req.Session); args.ServiceDesk == "" && f != nil {
+			args.ServiceDesk = f.Project
+		}
+		q := url.Values{}
+		q.Set("requestOwnership", "ALL_REQUESTS")
+		q.Set("expand", "requestType")
+		switch args.Status {
+		case "", "open":
+			q.Set("requestStatus", "OPEN_REQUESTS")
+		case "closed":
+			q.Set("requestStatus", "CLOSED_REQUESTS")
+		case "all":
+			q.Set("requestStatus", "ALL_REQUESTS")
+		default:
+			return nil, nil, fmt.Errorf("unknown status %q (valid: open, closed, all)", args.Status)
+		}
+		if args.Search != "" {
+			q.Set("searchTerm", args.Search)
+		}
+		if args.ServiceDesk != "" {
+			sd, err := jc.ServiceDesk(ctx, args.ServiceDesk)
+			if err != nil {
+				return nil, nil, err
+			}
+			q.Set("serviceDeskId", sd.ID)
+			if args.RequestType != "" {
+				rt, err := jc.RequestType(ctx, sd.ID, args.RequestType)
+				if err != nil {
+					return nil, nil, err
+				}
+				q.Set("requestTypeId", rt.ID)
+			}
+		} else if args.RequestType != "" {
+			return nil, nil, errors.New("request_type needs service_desk")
+		}
+		limit := args.MaxResults
+		if limit <= 0 || limit > 500 {
+			limit = 50
+		}
+		reqs, err := sdPage[JiraRequest](ctx, jc, "/rest/servicedeskapi/request", q, limit)
+		if err != nil {
+			debugf("tool=list_requests error=%v", err)
+			return nil, nil, err
+		}
+		views := make([]requestView, len(reqs))
+		for i := range reqs {
+			// Lists keep just the summary; get_request has the rest.
+			for j := range reqs[i].FieldValues {
+				if reqs[i].FieldValues[j].FieldID != "summary" {
+					reqs[i].FieldValues[j].Value = nil
+				}
+			}
+			views[i] = viewRequest(&reqs[i])
+			views[i].PortalURL = ""
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"requests": views, "count": len(views)}}, nil, nil
+	})
+
+	// get_request(key, include_status_history?)
+	type getRequestArgs struct {
+		Key           string `json:"key" jsonschema:"Request issue key, e.g. HELP-12"`
+		StatusHistory bool   `json:"include_status_history,omitempty" jsonschema:"Include the customer-facing status changes"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_request",
+		Title:       "Get Service Request",
+		Description: "Get a customer request as the portal shows it: request type, customer-facing status, reporter, portal fields, and portal link",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args getRequestArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=get_request args={key:%q,history:%t}", args.Key, args.StatusHistory)
+		r, err := jc.GetRequest(ctx, args.Key)
+		if err != nil {
+			debugf("tool=get_request error=%v", err)
+			return nil, nil, err
+		}
+		out := map[string]any{"request": viewRequest(r)}
+		if args.StatusHistory {
+			history, err := jc.RequestStatusHistory(ctx, r.IssueKey)
+			if err != nil {
+				debugf("tool=get_request error=%v", err)
+				return nil, nil, err
+			}
+			out["status_history"] = history
+		}
+		return &mcp.CallToolResult{StructuredContent: out}, nil, nil
+	})
+}
+
+// AddRequestComment comments on a service desk request: a reply the
+// customer sees when public, otherwise an internal note. The service desk
+// API takes the body as plain text.
+func (c *JiraClient) AddRequestComment(ctx context.Context, key, body string, public bool) (*JiraComment, error) {
+	var out struct {
+		ID      string         `json:"id"`
+		Body    string         `json:"body"`
+		Public  bool           `json:"public"`
+		Author  map[string]any `json:"author"`
+		Created map[string]any `json:"created"`
+	}
+	path := "/rest/servicedeskapi/request/" + url.PathEscape(key) + "/comment"
+	if err := c.doJSON(ctx, http.MethodPost, path, map[string]any{"body": body, "public": public}, &out); err != nil {
+		return nil, err
+	}
+	c.recordAction(ctx, actionComment, key, out.ID)
+	noteCreated(ctx, "comment", out.ID, key)
+	return &JiraComment{
+		ID: out.ID, Author: out.Author, Body: out.Body, Created: fieldString(out.Created, "iso8601"), JSDPublic: &out.Public,
+	}, nil
+}
+
+// addComment posts through the service desk API when public is set, so the
+// visibility is enforced by Jira, and through the issue API otherwise.
+func (c *JiraClient) addComment(ctx context.Context, key, body string, public *bool) (*JiraComment, error) {
+	if public != nil {
+		return c.AddRequestComment(ctx, key, body, *public)
+	}
+	return c.AddComment(ctx, key, body)
+}
@@ -0,0 +1,172 @@
+package jira
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Per-session credentials ----
+//
+// A shared HTTP server normally acts as one Jira identity. With
+// JIRA_SESSION_AUTH=allow (or require), each client can bring its own
+// credentials so Jira attributes its actions to the person using it:
+//
+//   - the X-Jira-Authorization header on each request, e.g. "Basic
+//     base64(email:token)" or "Bearer <PAT>";
+//   - the Authorization header, when the endpoint is not protected by
+//     JIRA_MCP_TOKEN (which then occupies that header);
+//   - the initialize request's _meta:
+//     {"jira/credentials": {"email": "me@acme.com", "token": "..."}}, with
+//     email omitted for a PAT.
+//
+// Under allow, sessions without credentials use the server's; under require
+// their tool calls, resource reads, and subscriptions fail. Session credentials apply to the default site
+// only, and their writes are never queued, since the queue replays them as
+// the server.
+
+type sessionAuthMode string
+
+const (
+	sessionAuthOff     sessionAuthMode = "off"
+	sessionAuthAllow   sessionAuthMode = "allow"
+	sessionAuthRequire sessionAuthMode = "require"
+)
+
+// maxSessionClients bounds the client cache; it is reset when full.
+const maxSessionClients = 256
+
+func loadSessionAuthMode() (sessionAuthMode, error) {
+	switch m := sessionAuthMode(strings.ToLower(os.Getenv("JIRA_SESSION_AUTH"))); m {
+	case "", sessionAuthOff:
+		return sessionAuthOff, nil
+	case sessionAuthAllow, sessionAuthRequire:
+		return m, nil
+	default:
+		return "", fmt.Errorf("JIRA_SESSION_AUTH: unknown value %q (valid: off, allow, require)", m)
+	}
+}
+
+// sessionClients caches one client per distinct credential, keyed by its
+// hash so the map never holds a usable secret as a key.
+type sessionClients struct {
+	mu      sync.Mutex
+	clients map[[sha256.Size]byte]*JiraClient
+}
+
+func (sc *sessionClients) get(jc *JiraClient, auth string) *JiraClient {
+	key := sha256.Sum256([]byte(auth))
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if c := sc.clients[key]; c != nil {
+		return c
+	}
+	if sc.clients == nil || len(sc.clients) >= maxSessionClients {
+		sc.clients = map[[sha256.Size]byte]*JiraClient{}
+	}
+	c := jc.withAuth(auth)
+	sc.clients[key] = c
+	return c
+}
+
+// withAuth returns a client for the same site as c that authenticates with
+// auth. It shares c's configuration and rate budget; its caches are its
+// own, since what a user can see depends on who they are.
+func (c *JiraClient) withAuth(auth string) *JiraClient {
+	s := &JiraClient{
+		BaseURL:           c.BaseURL,
+		Auth:              auth,
+		Client:            c.Client,
+		FieldAliases:      c.FieldAliases,
+		ProjectDefaults:   c.ProjectDefaults,
+		DefaultProject:    c.DefaultProject,
+		DefaultMaxResults: c.DefaultMaxResults,
+		DefaultFields:     c.DefaultFields,
+		Instance:          c.Instance,
+		Archive:           c.Archive,
+		budget:            c.budget,
+		queue:             c.queue,
+		provenance:        c.provenance,
+		site:              c.site,
+		passthrough:       true,
+	}
+	c.api.mu.Lock()
+	s.api.resolved, s.api.v2 = c.api.resolved, c.api.v2
+	c.api.mu.Unlock()
+	s.legacySearch.Store(c.legacySearch.Load())
+	return s
+}
+
+// sessionCredential returns the Authorization value the caller supplied for
+// Jira, or "".
+func sessionCredential(req mcp.Request) string {
+	if extra := req.GetExtra(); extra != nil && extra.Header != nil {
+		if v := strings.TrimSpace(extra.Header.Get("X-Jira-Authorization")); v != "" {
+			return v
+		}
+		if v := strings.TrimSpace(extra.Header.Get("Authorization")); v != "" {
+			return v
+		}
+	}
+	ss, ok := req.GetSession().(*mcp.ServerSession)
+	if !ok || ss == nil {
+		return ""
+	}
+	ip := ss.InitializeParams()
+	if ip == nil || ip.Meta["jira/credentials"] == nil {
+		return ""
+	}
+	var cred struct {
+		Email string `json:"email"`
+		Token string `json:"token"`
+	}
+	b, err := json.Marshal(ip.Meta["jira/credentials"])
+	if err != nil || json.Unmarshal(b, &cred) != nil || cred.Token == "" {
+		return ""
+	}
+	if cred.Email == "" {
+		return "Bearer " + cred.Token
+	}
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(cred.Email+":"+cred.Token))
+}
+
+// sessionAuthMiddleware runs each tool call, resource read, and
+// subscription as the caller when they sent credentials.
+func sessionAuthMiddleware(jc *JiraClient, mode sessionAuthMode) mcp.Middleware {
+	var cache sessionClients
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			switch method {
+			case "tools/call", "resources/read", "resources/subscribe":
+			default:
+				return next(ctx, method, req)
+			}
+			auth := sessionCredential(req)
+			fail := func(msg string) (mcp.Result, error) {
+				if method != "tools/call" {
+					return nil, errors.New(msg)
+				}
+				return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: msg}}}, nil
+			}
+			switch {
+			case auth == "" && mode == sessionAuthRequire:
+				return fail("this server requires your own Jira credentials: send an X-Jira-Authorization header, or jira/credentials ({email, token}) in the initialize request's _meta")
+			case auth == "":
+				return next(ctx, method, req)
+			case !strings.HasPrefix(auth, "Basic ") && !strings.HasPrefix(auth, "Bearer "):
+				return fail("Jira credentials must be a Basic or Bearer authorization value")
+			case jc.forSite(ctx) != jc:
+				return fail(fmt.Sprintf("your Jira credentials are for site %q; other sites cannot be used with them", jc.site))
+			}
+			return next(withSite(ctx, cache.get(jc, auth)), method, req)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package jira
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// clientAs is a client that sends email and token as jira/credentials in
+// its initialize request's _meta.
+func clientAs(email, token string) *mcp.Client {
+	c := mcp.NewClient(&mcp.Implementation{Name: "test"}, nil)
+	c.AddSendingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if ir, ok := req.(*mcp.InitializeRequest); ok {
+				ir.Params.Meta = mcp.Meta{"jira/credentials": map[string]any{"email": email, "token": token}}
+			}
+			return next(ctx, method, req)
+		}
+	})
+	return c
+}
+
+func basicAuth(email, token string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(email+":"+token))
+}
+
+func TestSessionAuthRequireResources(t *testing.T) {
+	jc, log := fakeSite(t, "default")
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, &mcp.ServerOptions{
+		SubscribeHandler:   func(context.Context, *mcp.SubscribeRequest) error { return nil },
+		UnsubscribeHandler: func(context.Context, *mcp.UnsubscribeRequest) error { return nil },
+	})
+	registerAttachmentTools(server, jc)
+	server.AddReceivingMiddleware(sessionAuthMiddleware(jc, sessionAuthRequire))
+	anon := connect(t, server, nil)
+	alice := connect(t, server, clientAs("alice@example.com", "a-token"))
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		cs       *mcp.ClientSession
+		op       func(cs *mcp.ClientSession) error
+		wantErr  bool
+		wantAuth string // what Jira must see; "" for no request
+	}{
+		{"read without credentials", anon, func(cs *mcp.ClientSession) error {
+			_, err := cs.ReadResource(ctx, &mcp.ReadResourceParams{URI: "jira://attachment/1"})
+			return err
+		}, true, ""},
+		{"subscribe without credentials", anon, func(cs *mcp.ClientSession) error {
+			return cs.Subscribe(ctx, &mcp.SubscribeParams{URI: "jira://status"})
+		}, true, ""},
+		{"read with credentials", alice, func(cs *mcp.ClientSession) error {
+			_, err := cs.ReadResource(ctx, &mcp.ReadResourceParams{URI: "jira://attachment/1"})
+			return err
+		}, false, basicAuth("alice@example.com", "a-token")},
+		{"subscribe with credentials", alice, func(cs *mcp.ClientSession) error {
+			return cs.Subscribe(ctx, &mcp.SubscribeParams{URI: "jira://status"})
+		}, false, ""},
+	}
+	for _, tt := range tests {
+		before := len(log.all())
+		if err := tt.op(tt.cs); (err != nil) != tt.wantErr {
+			t.Errorf("%s: error = %v, want error %t", tt.name, err, tt.wantErr)
+		}
+		seen := log.all()[before:]
+		if tt.wantAuth != "" && len(seen) == 0 {
+			t.Errorf("%s: no request reached Jira", tt.name)
+		}
+		for _, auth := range seen {
+			if auth != tt.wantAuth {
+				t.Errorf("%s: Jira saw Authorization %q, want %q", tt.name, auth, tt.wantAuth)
+			}
+		}
+	}
+}
+
+func TestSessionAuthPassthroughIsolation(t *testing.T) {
+	jc, log := fakeSite(t, "default")
+	dc, dcLog := fakeSite(t, "dc")
+	jc.sites = []*JiraClient{jc, dc}
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	registerAttachmentTools(server, jc)
+	server.AddReceivingMiddleware(sessionAuthMiddleware(jc, sessionAuthAllow))
+	sessions := map[string]*mcp.ClientSession{
+		"alice": connect(t, server, clientAs("alice@example.com", "a-token")),
+		"bob":   connect(t, server, clientAs("", "bob-pat")),
+		"anon":  connect(t, server, nil),
+	}
+
+	tests := []struct {
+		session  string
+		uri      string
+		wantAuth string // "" when the read must fail
+	}{
+		{"alice", "jira://attachment/1", basicAuth("alice@example.com", "a-token")},
+		{"bob", "jira://attachment/1", "Bearer bob-pat"},
+		{"anon", "jira://attachment/1", "Basic default"},
+		{"alice", "jira://attachment/1", basicAuth("alice@example.com", "a-token")},
+		{"alice", "jira://attachment/1?site=dc", ""},
+		{"anon", "jira://attachment/1?site=dc", "Basic dc"},
+	}
+	for i, tt := range tests {
+		before, dcBefore := len(log.all()), len(dcLog.all())
+		_, err := sessions[tt.session].ReadResource(context.Background(), &mcp.ReadResourceParams{URI: tt.uri})
+		if (err != nil) != (tt.wantAuth == "") {
+			t.Errorf("%d %s %s: error = %v", i, tt.session, tt.uri, err)
+			continue
+		}
+		seen := append(log.all()[before:], dcLog.all()[dcBefore:]...)
+		if tt.wantAuth == "" && len(seen) > 0 {
+			t.Errorf("%d %s %s: refused read still reached Jira as %q", i, tt.session, tt.uri, seen)
+		}
+		for _, auth := range seen {
+			if auth != tt.wantAuth {
+				t.Errorf("%d %s %s: Jira saw Authorization %q, want %q", i, tt.session, tt.uri, auth, tt.wantAuth)
+			}
+		}
+	}
+}
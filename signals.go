@@ -0,0 +1,263 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Conversation escalation signals ----
+//
+// conversation_signals reads an issue's comment thread for signs it is
+// about to escalate: a burst of comments, the reporter following up with
+// nobody answering, talk of SLAs or deadlines, and escalation language.
+// Each cue adds points to a 0-100 risk score and is returned as evidence.
+// With use_sampling, the client's model also rates the thread, and the
+// score is the mean of both.
+
+const (
+	signalRecentWindow   = 24 * time.Hour
+	signalQuoteRunes     = 160
+	signalSamplingRunes  = 12000
+	signalSamplingTokens = 400
+)
+
+// signalCues are the phrases scored in comment text, with their points.
+// Each cue counts once however often it appears.
+var signalCues = []struct {
+	Kind   string
+	Points int
+	Re     *regexp.Regexp
+}{
+	{"sla", 15, regexp.MustCompile(`(?i)\b(sla|slas|breach(ed)?|deadline|overdue|contract(ual)?|penalt(y|ies))\b`)},
+	{"escalation", 15, regexp.MustCompile(`(?i)\b(escalat\w*|your manager|management|executive|ceo|cto|legal|lawyer|refund|cancel\w*|churn)\b`)},
+	{"frustration", 10, regexp.MustCompile(`(?i)\b(unacceptable|ridiculous|frustrat\w*|disappoint\w*|still (not|no|waiting)|yet again|once again|how long)\b|!{2,}`)},
+	{"urgency", 10, regexp.MustCompile(`(?i)\b(urgent(ly)?|asap|immediately|critical|blocker|blocking|outage|down for)\b`)},
+	{"chasing", 10, regexp.MustCompile(`(?i)\b(any update|any news|following up|follow(ing)?-up|gentle reminder|bump(ing)?|chasing|reminder)\b`)},
+}
+
+type conversationSignal struct {
+	Kind      string `json:"kind"`
+	Points    int    `json:"points"`
+	Detail    string `json:"detail"`
+	CommentID string `json:"comment_id,omitempty"`
+	Quote     string `json:"quote,omitempty"`
+}
+
+type samplingAssessment struct {
+	Model   string   `json:"model,omitempty"`
+	Score   int      `json:"score"`
+	Summary string   `json:"summary,omitempty"`
+	Cues    []string `json:"cues,omitempty"`
+}
+
+type conversationSignals struct {
+	Key            string               `json:"key"`
+	RiskScore      int                  `json:"risk_score"`
+	Level          string               `json:"level"` // low, medium, or high
+	Method         string               `json:"method"`
+	HeuristicScore int                  `json:"heuristic_score"`
+	Comments       int                  `json:"comments"`
+	Signals        []conversationSignal `json:"signals"`
+	Model          *samplingAssessment  `json:"model_assessment,omitempty"`
+	SamplingError  string               `json:"sampling_error,omitempty"`
+}
+
+func riskLevel(score int) string {
+	switch {
+	case score >= 60:
+		return "high"
+	case score >= 30:
+		return "medium"
+	}
+	return "low"
+}
+
+// quoteAround returns up to signalQuoteRunes of text centred on the match
+// at loc.
+func quoteAround(text string, loc []int) string {
+	r := []rune(text)
+	start := len([]rune(text[:loc[0]])) - signalQuoteRunes/2
+	start = max(start, 0)
+	end := min(start+signalQuoteRunes, len(r))
+	q := strings.Join(strings.Fields(string(r[start:end])), " ")
+	if start > 0 {
+		q = "…" + q
+	}
+	if end < len(r) {
+		q += "…"
+	}
+	return q
+}
+
+// threadSignals scores comments (oldest first) on an issue reported by
+// reporter (an accountId).
+func threadSignals(comments []JiraComment, reporter string, now time.Time) []conversationSignal {
+	out := []conversationSignal{}
+	recent := 0
+	for _, cm := range comments {
+		if t, ok := parseJiraTime(cm.Created); ok && now.Sub(t) <= signalRecentWindow {
+			recent++
+		}
+	}
+	if recent >= 3 {
+		out = append(out, conversationSignal{
+			Kind: "frequency", Points: min(5*recent, 20),
+			Detail: fmt.Sprintf("%d comments in the last %s", recent, signalRecentWindow),
+		})
+	}
+
+	// Trailing comments by the reporter that nobody has answered.
+	unanswered := 0
+	var firstUnanswered *JiraComment
+	for i := len(comments) - 1; i >= 0; i-- {
+		if reporter == "" || fieldString(comments[i].Author, "accountId") != reporter {
+			break
+		}
+		unanswered++
+		firstUnanswered = &comments[i]
+	}
+	if (unanswered > 0 && len(comments) > unanswered) || unanswered >= 2 {
+		points := 10 * min(unanswered, 3)
+		detail := fmt.Sprintf("reporter has posted %d comment(s) with no reply", unanswered)
+		if t, ok := parseJiraTime(firstUnanswered.Created); ok {
+			waited := now.Sub(t)
+			detail += fmt.Sprintf(", waiting %dh", int(waited.Hours()))
+			if waited > 2*signalRecentWindow {
+				points += 10
+			}
+		}
+		out = append(out, conversationSignal{Kind: "unanswered_reporter", Points: points, Detail: detail, CommentID: firstUnanswered.ID})
+	}
+
+	// Language cues, attributed to the latest comment using them.
+	for _, cue := range signalCues {
+		for i := len(comments) - 1; i >= 0; i-- {
+			text := adfToMarkdown(comments[i].Body)
+			if loc := cue.Re.FindStringIndex(text); loc != nil {
+				out = append(out, conversationSignal{
+					Kind: cue.Kind, Points: cue.Points, CommentID: comments[i].ID, Quote: quoteAround(text, loc),
+					Detail: fmt.Sprintf("%s wrote %q", orNone(fieldString(comments[i].Author, "displayName")), text[loc[0]:loc[1]]),
+				})
+				break
+			}
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Points > out[j].Points })
+	return out
+}
+
+// sampleAssessment asks the client's model to rate the thread.
+func sampleAssessment(ctx context.Context, ss *mcp.ServerSession, key string, comments []JiraComment) (*samplingAssessment, error) {
+	if ss == nil {
+		return nil, errors.New("no client session")
+	}
+	if p := ss.InitializeParams(); p == nil || p.Capabilities == nil || p.Capabilities.Sampling == nil {
+		return nil, errors.New("the client does not support sampling")
+	}
+	var thread strings.Builder
+	for _, cm := range comments {
+		fmt.Fprintf(&thread, "[%s] %s:\n%s\n\n", cm.Created, orNone(fieldString(cm.Author, "displayName")), adfToMarkdown(cm.Body))
+	}
+	text := thread.String()
+	if r := []rune(text); len(r) > signalSamplingRunes {
+		// The newest comments matter most.
+		text = "…" + string(r[len(r)-signalSamplingRunes:])
+	}
+	res, err := ss.CreateMessage(ctx, &mcp.CreateMessageParams{
+		SystemPrompt: "You assess support ticket threads for escalation risk. Reply with only a JSON object: " +
+			`{"score": 0-100, "summary": "one sentence", "cues": ["short phrases from the thread"]}`,
+		Messages: []*mcp.SamplingMessage{{
+			Role:    "user",
+			Content: &mcp.TextContent{Text: fmt.Sprintf("How likely is %s to escalate?\n\n%s", key, text)},
+		}},
+		MaxTokens:   signalSamplingTokens,
+		Temperature: 0,
+	})
+	if err != nil {
+		return nil, err
+	}
+	tc, ok := res.Content.(*mcp.TextContent)
+	if !ok {
+		return nil, errors.New("the model did not reply with text")
+	}
+	reply := tc.Text
+	if i, j := strings.Index(reply, "{"), strings.LastIndex(reply, "}"); i >= 0 && j > i {
+		reply = reply[i : j+1]
+	}
+	var a samplingAssessment
+	if err := json.Unmarshal([]byte(reply), &a); err != nil {
+		return nil, fmt.Errorf("unreadable model reply: %w", err)
+	}
+	a.Score = min(max(a.Score, 0), 100)
+	a.Model = res.Model
+	return &a, nil
+}
+
+// ConversationSignals scores key's comment thread for escalation risk.
+func (c *JiraClient) ConversationSignals(ctx context.Context, ss *mcp.ServerSession, key string, sampling bool) (*conversationSignals, error) {
+	iss, err := c.GetIssueFields(ctx, key, []string{"reporter"})
+	if err != nil {
+		return nil, err
+	}
+	comments, err := c.AllComments(ctx, iss.Key)
+	if err != nil {
+		return nil, err
+	}
+	sigs := threadSignals(comments, fieldString(iss.Fields, "reporter", "accountId"), time.Now())
+	score := 0
+	for _, s := range sigs {
+		score += s.Points
+	}
+	res := &conversationSignals{
+		Key: iss.Key, HeuristicScore: min(score, 100), Comments: len(comments), Signals: sigs, Method: "heuristic",
+	}
+	res.RiskScore = res.HeuristicScore
+	if sampling && len(comments) > 0 {
+		a, err := sampleAssessment(ctx, ss, iss.Key, comments)
+		if err != nil {
+			// The heuristic result stands on its own.
+			debugf("conversation_signals sampling: %v", err)
+			res.SamplingError = err.Error()
+		} else {
+			res.Model = a
+			res.Method = "heuristic+sampling"
+			res.RiskScore = int(math.Round(float64(res.HeuristicScore+a.Score) / 2))
+		}
+	}
+	res.Level = riskLevel(res.RiskScore)
+	return res, nil
+}
+
+func registerSignalTools(server *mcp.Server, jc *JiraClient) {
+	// conversation_signals(key, use_sampling?)
+	type signalsArgs struct {
+		Key         string `json:"key" jsonschema:"Issue whose comment thread to assess"`
+		UseSampling bool   `json:"use_sampling,omitempty" jsonschema:"Also ask the client's model to rate the thread (requires sampling support)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "conversation_signals",
+		Title:       "Conversation Signals",
+		Description: "Assess an issue's comment thread for escalation risk (comment bursts, unanswered reporter follow-ups, SLA talk, escalation language) and return a 0-100 risk score with the evidence",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args signalsArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=conversation_signals args={key:%q,sampling:%t}", args.Key, args.UseSampling)
+		if args.Key == "" {
+			return nil, nil, errors.New("key is required")
+		}
+		res, err := jc.ConversationSignals(ctx, req.Session, args.Key, args.UseSampling)
+		if err != nil {
+			debugf("tool=conversation_signals error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: res}, nil, nil
+	})
+}
@@ -0,0 +1,342 @@
+package jira
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Multiple Jira sites ----
+//
+// The site configured by JIRA_INSTANCE_URL is the default site. JIRA_SITES
+// (or the file named by JIRA_SITES_FILE) adds more by name:
+//
+//	{"eu": {"url": "https://acme-eu.atlassian.net", "email": "me@acme.com",
+//	        "token_env": "JIRA_EU_TOKEN"},
+//	 "dc": {"url": "https://jira.acme.internal", "auth": "pat",
+//	        "token_env": "JIRA_DC_TOKEN", "production": true}}
+//
+// Every tool then takes an optional site argument. Each site has its own
+// credentials, API version, instance policy, rate budget, and caches; field
+// aliases, project defaults, the archive, and the write queue are shared.
+// Tempo and JIRA_AUTH_ROUTES apply to the default site only.
+
+// defaultSiteName is how the JIRA_INSTANCE_URL site is named.
+const defaultSiteName = "default"
+
+type siteConfig struct {
+	URL        string       `json:"url"`
+	Auth       string       `json:"auth,omitempty"` // basic (default), pat, or oauth
+	Email      string       `json:"email,omitempty"`
+	Token      string       `json:"token,omitempty"`
+	TokenEnv   string       `json:"token_env,omitempty"` // read the token from this variable instead
+	TokenFile  string       `json:"token_file,omitempty"`
+	Keychain   string       `json:"token_keychain,omitempty"` // keychain service; see secrets.go
+	APIVersion string       `json:"api_version,omitempty"`
+	Label      string       `json:"label,omitempty"`
+	Production bool         `json:"production,omitempty"`
+	Writes     string       `json:"writes,omitempty"`
+	OAuth      *oauthConfig `json:"oauth,omitempty"`
+}
+
+// loadSites reads JIRA_SITES and builds a client for each site, sharing
+// c's HTTP client and configuration.
+func (c *JiraClient) loadSites() error {
+	c.site = defaultSiteName
+	c.sites = []*JiraClient{c}
+	var cfgs map[string]siteConfig
+	if _, err := loadJSONSetting("JIRA_SITES", &cfgs); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(cfgs))
+	for name := range cfgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if name == "" || strings.EqualFold(name, defaultSiteName) {
+			return fmt.Errorf("JIRA_SITES: %q names the JIRA_INSTANCE_URL site; pick another name", name)
+		}
+		s, err := c.newSiteClient(name, cfgs[name])
+		if err != nil {
+			return fmt.Errorf("JIRA_SITES[%s]: %w", name, err)
+		}
+		c.sites = append(c.sites, s)
+		debugf("site %s: %s (%s)", name, s.BaseURL, s.Instance.Label)
+	}
+	return nil
+}
+
+func (c *JiraClient) newSiteClient(name string, sc siteConfig) (*JiraClient, error) {
+	baseURL := strings.TrimRight(sc.URL, "/")
+	if _, err := url.ParseRequestURI(baseURL); err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	token := sc.Token
+	if sc.TokenEnv != "" {
+		token = os.Getenv(sc.TokenEnv)
+	}
+	rotating := tokenSecret("token_file", sc.TokenFile, "token_keychain", sc.Keychain, sc.Email)
+	if token == "" && rotating != nil {
+		var err error
+		if token, err = rotating.get(); err != nil {
+			return nil, err
+		}
+	} else {
+		rotating = nil
+	}
+	s := &JiraClient{
+		BaseURL:         baseURL,
+		Client:          c.Client,
+		FieldAliases:    c.FieldAliases,
+		ProjectDefaults: c.ProjectDefaults,
+		Instance:        instanceConfig{Label: sc.Label, Production: sc.Production, Writes: sc.Writes},
+		Archive:         c.Archive,
+		queue:           c.queue,
+		provenance:      c.provenance,
+		site:            name,
+	}
+	switch strings.ToLower(sc.Auth) {
+	case "", "basic":
+		if sc.Email == "" || token == "" {
+			return nil, errors.New("email and token (or token_env, token_file, token_keychain) must be set")
+		}
+		s.Auth = "Basic " + base64.StdEncoding.EncodeToString([]byte(sc.Email+":"+token))
+		if rotating != nil {
+			s.tokenAuth = &tokenAuth{token: rotating, email: sc.Email}
+		}
+	case "pat":
+		if token == "" {
+			return nil, errors.New("token (or token_env, token_file, token_keychain) must be set")
+		}
+		s.Auth = "Bearer " + token
+		if rotating != nil {
+			s.tokenAuth = &tokenAuth{token: rotating, bearer: true}
+		}
+	case "oauth":
+		if sc.OAuth == nil {
+			return nil, errors.New("auth oauth needs an oauth block")
+		}
+		o, err := newOAuthSource(*sc.OAuth, baseURL, c.Client)
+		if err != nil {
+			return nil, fmt.Errorf("oauth: %w", err)
+		}
+		s.OAuth = o
+	default:
+		return nil, fmt.Errorf("unknown auth %q (valid: basic, pat, oauth)", sc.Auth)
+	}
+	if err := s.Instance.normalize(baseURL); err != nil {
+		return nil, err
+	}
+	if err := s.setAPIVersion(sc.APIVersion); err != nil {
+		return nil, fmt.Errorf("api_version: %w", err)
+	}
+	if os.Getenv("JIRA_SEARCH_API") == "legacy" {
+		s.legacySearch.Store(true)
+	}
+	budget, err := loadRateBudget()
+	if err != nil {
+		return nil, err
+	}
+	s.budget = budget
+	return s, nil
+}
+
+// siteNamed returns the site called name, the default site for "", or nil.
+func (c *JiraClient) siteNamed(name string) *JiraClient {
+	if name == "" {
+		return c
+	}
+	for _, s := range c.sites {
+		if strings.EqualFold(s.site, name) {
+			return s
+		}
+	}
+	return nil
+}
+
+func (c *JiraClient) siteNames() []string {
+	names := make([]string, len(c.sites))
+	for i, s := range c.sites {
+		names[i] = s.site
+	}
+	return names
+}
+
+type siteKey struct{}
+
+func withSite(ctx context.Context, site *JiraClient) context.Context {
+	return context.WithValue(ctx, siteKey{}, site)
+}
+
+// forSite returns the client for the site the current tool call selected,
+// or c when it selected none.
+func (c *JiraClient) forSite(ctx context.Context) *JiraClient {
+	if s, ok := ctx.Value(siteKey{}).(*JiraClient); ok {
+		return s
+	}
+	return c
+}
+
+// resourceSite selects the site named by a resource URI's site parameter
+// ("" for the default) for a resource read. A session using its own
+// credentials can only read from the default site.
+func (c *JiraClient) resourceSite(ctx context.Context, name string) (context.Context, error) {
+	s := c.siteNamed(name)
+	if s == nil {
+		return ctx, fmt.Errorf("unknown site %q (configured: %s)", name, strings.Join(c.siteNames(), ", "))
+	}
+	if c.forSite(ctx).passthrough {
+		if s != c {
+			return ctx, fmt.Errorf("your Jira credentials are for site %q; other sites cannot be used with them", c.site)
+		}
+		return ctx, nil
+	}
+	return withSite(ctx, s), nil
+}
+
+// siteQuery is the query string that names the current call's site in a
+// resource URI, or "" for the default site.
+func (c *JiraClient) siteQuery(ctx context.Context) string {
+	s := c.forSite(ctx)
+	if s == c || s.passthrough {
+		return ""
+	}
+	return "?site=" + url.QueryEscape(s.site)
+}
+
+// siteMiddleware takes the site argument off tool calls and selects that
+// site for the call, and adds the argument to every tool in tools/list.
+func siteMiddleware(jc *JiraClient) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			switch method {
+			case "tools/call":
+				ctreq, ok := req.(*mcp.CallToolRequest)
+				if !ok || len(ctreq.Params.Arguments) == 0 {
+					break
+				}
+				var args map[string]json.RawMessage
+				if json.Unmarshal(ctreq.Params.Arguments, &args) != nil {
+					break // let the tool report malformed arguments
+				}
+				raw, ok := args["site"]
+				if !ok {
+					break
+				}
+				var name string
+				var site *JiraClient
+				if json.Unmarshal(raw, &name) == nil {
+					site = jc.siteNamed(name)
+				}
+				if site == nil {
+					return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{
+						Text: fmt.Sprintf("unknown site %s (configured: %s)", raw, strings.Join(jc.siteNames(), ", ")),
+					}}}, nil
+				}
+				delete(args, "site")
+				b, err := json.Marshal(args)
+				if err != nil {
+					return nil, err
+				}
+				params := *ctreq.Params
+				params.Arguments = b
+				cp := *ctreq
+				cp.Params = &params
+				return next(withSite(ctx, site), method, &cp)
+			case "tools/list":
+				res, err := next(ctx, method, req)
+				ltres, ok := res.(*mcp.ListToolsResult)
+				if !ok || err != nil {
+					return res, err
+				}
+				desc := "Jira site to run against, one of " + strings.Join(jc.siteNames(), ", ") + " (default " + jc.site + "); see list_sites"
+				out := *ltres
+				out.Tools = make([]*mcp.Tool, len(ltres.Tools))
+				for i, t := range ltres.Tools {
+					out.Tools[i] = withSiteArgument(t, desc)
+				}
+				return &out, nil
+			}
+			return next(ctx, method, req)
+		}
+	}
+}
+
+// withSiteArgument returns a copy of t whose input schema has a site
+// property. The registered tool is left untouched.
+func withSiteArgument(t *mcp.Tool, desc string) *mcp.Tool {
+	b, err := json.Marshal(t.InputSchema)
+	var schema map[string]any
+	if err != nil || json.Unmarshal(b, &schema) != nil || schema == nil {
+		return t
+	}
+	props, _ := schema["properties"].(map[string]any)
+	if props == nil {
+		props = map[string]any{}
+	}
+	props["site"] = map[string]any{"type": "string", "description": desc}
+	schema["properties"] = props
+	cp := *t
+	cp.InputSchema = schema
+	return &cp
+}
+
+type siteView struct {
+	Name       string `json:"name"`
+	URL        string `json:"url"`
+	Label      string `json:"label"`
+	Production bool   `json:"production"`
+	Writes     string `json:"writes"`
+	Auth       string `json:"auth"`
+	APIVersion string `json:"api_version"` // 2, 3, or auto until first contact
+	Default    bool   `json:"default,omitempty"`
+}
+
+func (c *JiraClient) siteView() siteView {
+	v := siteView{
+		Name: c.site, URL: c.BaseURL, Label: c.Instance.Label, Production: c.Instance.Production,
+		Writes: c.Instance.Writes, Auth: "basic", APIVersion: "auto", Default: c.site == defaultSiteName,
+	}
+	switch {
+	case c.OAuth != nil:
+		v.Auth = "oauth"
+	case strings.HasPrefix(c.Auth, "Bearer "):
+		v.Auth = "pat"
+	}
+	c.api.mu.Lock()
+	if c.api.resolved {
+		v.APIVersion = "3"
+		if c.api.v2 {
+			v.APIVersion = "2"
+		}
+	}
+	c.api.mu.Unlock()
+	return v
+}
+
+func registerSiteTools(server *mcp.Server, jc *JiraClient) {
+	// list_sites()
+	type listSitesArgs struct{}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_sites",
+		Title:       "List Sites",
+		Description: "List the configured Jira sites with their URL, label, production flag, write policy, and auth type. Pass a site's name as the site argument of any tool to work on it",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listSitesArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_sites args={}")
+		sites := make([]siteView, len(jc.sites))
+		for i, s := range jc.sites {
+			sites[i] = s.siteView()
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"sites": sites}}, nil, nil
+	})
+}
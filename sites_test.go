@@ -0,0 +1,59 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestSiteMiddleware(t *testing.T) {
+	jc, _ := fakeSite(t, "default")
+	dc, _ := fakeSite(t, "dc")
+	jc.sites = []*JiraClient{jc, dc}
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	type echoArgs struct {
+		Key string `json:"key"`
+	}
+	mcp.AddTool(server, &mcp.Tool{Name: "which_site"}, func(ctx context.Context, req *mcp.CallToolRequest, args echoArgs) (*mcp.CallToolResult, any, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: jc.forSite(ctx).site + " " + args.Key}}}, nil, nil
+	})
+	server.AddReceivingMiddleware(siteMiddleware(jc))
+	cs := connect(t, server, nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		args    map[string]any
+		want    string
+		wantErr bool
+	}{
+		{"no site", map[string]any{"key": "PROJ-1"}, "default PROJ-1", false},
+		{"named", map[string]any{"key": "PROJ-1", "site": "dc"}, "dc PROJ-1", false},
+		{"any case", map[string]any{"key": "PROJ-1", "site": "DC"}, "dc PROJ-1", false},
+		{"default by name", map[string]any{"key": "PROJ-1", "site": "default"}, "default PROJ-1", false},
+		{"unknown", map[string]any{"key": "PROJ-1", "site": "cloud"}, "", true},
+		{"not a string", map[string]any{"key": "PROJ-1", "site": 2}, "", true},
+	}
+	for _, tt := range tests {
+		res, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: "which_site", Arguments: tt.args})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		text := res.Content[0].(*mcp.TextContent).Text
+		if res.IsError != tt.wantErr || (!tt.wantErr && text != tt.want) {
+			t.Errorf("%s: result %q (error %t), want %q (error %t)", tt.name, text, res.IsError, tt.want, tt.wantErr)
+		}
+	}
+
+	list, err := cs.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := json.Marshal(list.Tools[0].InputSchema)
+	if !strings.Contains(string(b), `"site"`) || !strings.Contains(string(b), `"key"`) {
+		t.Errorf("listed schema %s lacks the site or key argument", b)
+	}
+}
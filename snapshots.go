@@ -0,0 +1,240 @@
+package jira
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Stable search snapshots ----
+//
+// Large sweeps page through results while issues keep changing, so later
+// pages can skip or repeat issues. A snapshot freezes the matching key list
+// at query time; details are then paged from that list. Snapshots belong to
+// the session and site that took them, live for the server's lifetime (the
+// oldest are dropped beyond maxSnapshots per session), and are readable as
+// jira://search-snapshot/{id}.
+
+const (
+	maxSnapshots       = 20
+	maxStoredSnapshots = 500 // across sessions
+	maxSnapshotKeys    = 10000
+)
+
+type searchSnapshot struct {
+	ID        string    `json:"id"`
+	JQL       string    `json:"jql"`
+	CreatedAt time.Time `json:"created_at"`
+	Truncated bool      `json:"truncated,omitempty"`
+	Keys      []string  `json:"keys"`
+
+	session string
+	site    string
+}
+
+type snapshotStore struct {
+	mu    sync.Mutex
+	order []string // oldest first
+	items map[string]*searchSnapshot
+}
+
+// add stores snap for session under a new random id.
+func (s *snapshotStore) add(session string, snap *searchSnapshot) {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	snap.ID, snap.session = hex.EncodeToString(b), session
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.items == nil {
+		s.items = map[string]*searchSnapshot{}
+	}
+	s.items[snap.ID] = snap
+	s.order = append(s.order, snap.ID)
+	mine := 0
+	for _, id := range s.order {
+		if s.items[id].session == session {
+			mine++
+		}
+	}
+	s.order = slices.DeleteFunc(s.order, func(id string) bool {
+		if mine > maxSnapshots && s.items[id].session == session {
+			mine--
+			delete(s.items, id)
+			return true
+		}
+		return false
+	})
+	for len(s.order) > maxStoredSnapshots {
+		delete(s.items, s.order[0])
+		s.order = s.order[1:]
+	}
+}
+
+// get returns session's snapshot id on site; other sessions' and sites'
+// snapshots are invisible.
+func (s *snapshotStore) get(session, site, id string) (*searchSnapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap, ok := s.items[id]
+	if !ok || snap.session != session || snap.site != site {
+		return nil, fmt.Errorf("unknown or expired snapshot %q", id)
+	}
+	return snap, nil
+}
+
+// SnapshotSearch records the keys matching jql, in result order.
+func (c *JiraClient) SnapshotSearch(ctx context.Context, jql string) (*searchSnapshot, error) {
+	issues, err := c.SearchAll(ctx, jql, []string{"key"}, maxSnapshotKeys+1)
+	if err != nil {
+		return nil, err
+	}
+	snap := &searchSnapshot{JQL: jql, CreatedAt: time.Now().UTC(), site: c.forSite(ctx).site}
+	if len(issues) > maxSnapshotKeys {
+		issues, snap.Truncated = issues[:maxSnapshotKeys], true
+	}
+	for _, iss := range issues {
+		snap.Keys = append(snap.Keys, iss.Key)
+	}
+	return snap, nil
+}
+
+type snapshotPage struct {
+	SnapshotID  string      `json:"snapshot_id"`
+	JQL         string      `json:"jql"`
+	Total       int         `json:"total"`
+	StartAt     int         `json:"start_at"`
+	NextStartAt int         `json:"next_start_at,omitempty"`
+	Issues      []JiraIssue `json:"issues"`
+	Missing     []string    `json:"missing,omitempty"` // deleted or no longer visible since the snapshot
+}
+
+// issueKeyInErrorRe finds the keys Jira names when a key in a "key in"
+// query no longer exists or is not visible, e.g. "An issue with key
+// 'PROJ-9' does not exist for field 'key'."
+var issueKeyInErrorRe = regexp.MustCompile(`'([A-Za-z][A-Za-z0-9_]*-[0-9]+)'`)
+
+// SnapshotPage fetches current details for keys [startAt, startAt+max) of
+// a snapshot, in snapshot order. Keys Jira rejects as deleted or invisible
+// are dropped from the query and reported as missing.
+func (c *JiraClient) SnapshotPage(ctx context.Context, snap *searchSnapshot, startAt, max int, fields []string) (*snapshotPage, error) {
+	if max <= 0 || max > 100 {
+		max = 50
+	}
+	page := &snapshotPage{SnapshotID: snap.ID, JQL: snap.JQL, Total: len(snap.Keys), StartAt: startAt, Issues: []JiraIssue{}}
+	if startAt < 0 || startAt >= len(snap.Keys) {
+		return page, nil
+	}
+	keys := snap.Keys[startAt:min(startAt+max, len(snap.Keys))]
+	if next := startAt + len(keys); next < len(snap.Keys) {
+		page.NextStartAt = next
+	}
+	byKey := map[string]JiraIssue{}
+	query := slices.Clone(keys)
+	for len(query) > 0 {
+		res, err := c.searchPage(ctx, "key in ("+strings.Join(query, ", ")+")", "", len(query), fields, nil)
+		var je *JiraError
+		if errors.As(err, &je) && je.StatusCode == http.StatusBadRequest {
+			n := len(query)
+			for _, m := range issueKeyInErrorRe.FindAllStringSubmatch(je.Body, -1) {
+				query = slices.DeleteFunc(query, func(k string) bool { return strings.EqualFold(k, m[1]) })
+			}
+			if len(query) < n {
+				continue
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, iss := range res.Issues {
+			byKey[iss.Key] = iss
+		}
+		break
+	}
+	for _, k := range keys {
+		if iss, ok := byKey[k]; ok {
+			page.Issues = append(page.Issues, iss)
+		} else {
+			page.Missing = append(page.Missing, k)
+		}
+	}
+	return page, nil
+}
+
+func registerSnapshotTools(server *mcp.Server, jc *JiraClient, snapshots *snapshotStore) {
+	// read_search_snapshot(snapshot_id, start_at?, max_results?, fields?, raw_adf?)
+	type readSnapshotArgs struct {
+		SnapshotID string   `json:"snapshot_id" jsonschema:"Id returned by search_issues with snapshot=true"`
+		StartAt    int      `json:"start_at,omitempty" jsonschema:"Position in the snapshot's key list"`
+		MaxResults int      `json:"max_results,omitempty" jsonschema:"Page size (default 50, max 100)"`
+		Fields     []string `json:"fields,omitempty" jsonschema:"Fields to return by name, id, or alias; slim for key/summary/status/assignee/priority/updated (default *navigable)"`
+		RawADF     bool     `json:"raw_adf,omitempty" jsonschema:"Return rich-text fields as raw ADF instead of Markdown"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "read_search_snapshot",
+		Title:       "Read Search Snapshot",
+		Description: "Page current issue details from a search snapshot, in the order frozen at query time. Issues deleted since are listed as missing",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args readSnapshotArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=read_search_snapshot args={id:%q,start:%d,max:%d}", args.SnapshotID, args.StartAt, args.MaxResults)
+		snap, err := snapshots.get(sessionKey(req.Session), jc.forSite(ctx).site, args.SnapshotID)
+		if err != nil {
+			return nil, nil, err
+		}
+		fields, err := jc.resolveFieldSelection(ctx, args.Fields)
+		if err != nil {
+			return nil, nil, err
+		}
+		page, err := jc.SnapshotPage(ctx, snap, args.StartAt, args.MaxResults, fields)
+		if err != nil {
+			debugf("tool=read_search_snapshot error=%v", err)
+			return nil, nil, err
+		}
+		if !args.RawADF {
+			for i := range page.Issues {
+				renderIssueText(&page.Issues[i])
+			}
+		}
+		return &mcp.CallToolResult{StructuredContent: page}, nil, nil
+	})
+
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: "jira://search-snapshot/{id}{?site}",
+		Name:        "search-snapshot",
+		Title:       "Search Snapshot",
+		Description: "The JQL and frozen key list of one of this session's search snapshots; site names a site other than the default",
+		MIMEType:    "application/json",
+	}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		u, err := url.Parse(req.Params.URI)
+		if err != nil {
+			return nil, err
+		}
+		id, site := strings.TrimPrefix(u.Path, "/"), u.Query().Get("site")
+		debugf("resource=jira://search-snapshot id=%q site=%q", id, site)
+		if ctx, err = jc.resourceSite(ctx, site); err != nil {
+			return nil, err
+		}
+		snap, err := snapshots.get(sessionKey(req.Session), jc.forSite(ctx).site, id)
+		if err != nil {
+			return nil, mcp.ResourceNotFoundError(req.Params.URI)
+		}
+		b, err := json.MarshalIndent(snap, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{
+			URI: req.Params.URI, MIMEType: "application/json", Text: string(b),
+		}}}, nil
+	})
+}
@@ -0,0 +1,77 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotStore(t *testing.T) {
+	var s snapshotStore
+	mine := &searchSnapshot{site: "default"}
+	s.add("a", mine)
+	tests := []struct {
+		name          string
+		session, site string
+		wantErr       bool
+	}{
+		{"owner", "a", "default", false},
+		{"other session", "b", "default", true},
+		{"other site", "a", "dc", true},
+	}
+	for _, tt := range tests {
+		if _, err := s.get(tt.session, tt.site, mine.ID); (err != nil) != tt.wantErr {
+			t.Errorf("%s: get = %v, want error %t", tt.name, err, tt.wantErr)
+		}
+	}
+
+	other := &searchSnapshot{site: "default"}
+	s.add("b", other)
+	if other.ID == mine.ID || len(mine.ID) < 16 {
+		t.Errorf("ids %q and %q should be distinct and random", mine.ID, other.ID)
+	}
+	for range maxSnapshots {
+		s.add("a", &searchSnapshot{site: "default"})
+	}
+	if _, err := s.get("a", "default", mine.ID); err == nil {
+		t.Errorf("session a's oldest snapshot survived %d newer ones", maxSnapshots)
+	}
+	if _, err := s.get("b", "default", other.ID); err != nil {
+		t.Errorf("session a's snapshots evicted session b's: %v", err)
+	}
+}
+
+func TestSnapshotPageMissingKeys(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jql := r.URL.Query().Get("jql")
+		switch {
+		case strings.Contains(jql, "PROJ-2"):
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errorMessages": ["An issue with key 'PROJ-2' does not exist for field 'key'."]}`))
+		case jql == "key in (PROJ-1, PROJ-3, PROJ-4)":
+			// PROJ-3 and PROJ-4 are no longer visible, so Jira just omits them.
+			w.Write([]byte(`{"issues": [{"key": "PROJ-1", "fields": {}}], "isLast": true}`))
+		default:
+			http.Error(w, "unexpected query "+jql, http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+	jc := &JiraClient{BaseURL: srv.URL, Client: srv.Client(), site: "default"}
+	jc.api.resolved = true
+
+	snap := &searchSnapshot{ID: "x", Keys: []string{"PROJ-1", "PROJ-2", "PROJ-3", "PROJ-4"}}
+	page, err := jc.SnapshotPage(context.Background(), snap, 0, 10, []string{"summary"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, iss := range page.Issues {
+		got = append(got, iss.Key)
+	}
+	if !reflect.DeepEqual(got, []string{"PROJ-1"}) || !reflect.DeepEqual(page.Missing, []string{"PROJ-2", "PROJ-3", "PROJ-4"}) {
+		t.Errorf("issues %q missing %q, want [PROJ-1] [PROJ-2 PROJ-3 PROJ-4]", got, page.Missing)
+	}
+}
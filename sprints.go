@@ -0,0 +1,423 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Sprints ----
+
+// ListSprints returns a board's sprints, optionally only those in the given
+// states (future, active, closed).
+func (c *JiraClient) ListSprints(ctx context.Context, boardID int, states []string, max int) ([]JiraSprint, error) {
+	var out []JiraSprint
+	for startAt := 0; len(out) < max; {
+		q := url.Values{}
+		q.Set("startAt", fmt.Sprintf("%d", startAt))
+		q.Set("maxResults", fmt.Sprintf("%d", min(max-len(out), 50)))
+		if len(states) > 0 {
+			q.Set("state", strings.Join(states, ","))
+		}
+		var page struct {
+			IsLast bool         `json:"isLast"`
+			Values []JiraSprint `json:"values"`
+		}
+		if err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/rest/agile/1.0/board/%d/sprint?%s", boardID, q.Encode()), nil, &page); err != nil {
+			return nil, err
+		}
+		out = append(out, page.Values...)
+		startAt += len(page.Values)
+		if page.IsLast || len(page.Values) == 0 {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (c *JiraClient) GetSprint(ctx context.Context, id int) (*JiraSprint, error) {
+	var out JiraSprint
+	if err := c.doJSON(ctx, http.MethodGet, fmt.Sprintf("/rest/agile/1.0/sprint/%d", id), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SprintIssues returns the issues in a sprint in rank order.
+func (c *JiraClient) SprintIssues(ctx context.Context, id int, fields []string, limit int) ([]JiraIssue, error) {
+	return c.agileIssues(ctx, fmt.Sprintf("/rest/agile/1.0/sprint/%d/issue", id), fields, limit)
+}
+
+// statusBreakdown counts issues by status and by status category (To Do,
+// In Progress, Done).
+func statusBreakdown(issues []JiraIssue) map[string]any {
+	byStatus, byCategory := map[string]int{}, map[string]int{}
+	for _, iss := range issues {
+		if s := fieldString(iss.Fields, "status", "name"); s != "" {
+			byStatus[s]++
+		}
+		if cat := fieldString(iss.Fields, "status", "statusCategory", "name"); cat != "" {
+			byCategory[cat]++
+		}
+	}
+	return map[string]any{"total": len(issues), "by_status": byStatus, "by_category": byCategory}
+}
+
+// sprintDate parses a sprint date given as YYYY-MM-DD or an RFC 3339 or
+// Jira timestamp.
+func sprintDate(s string) (time.Time, error) {
+	t, ok := parseJiraTime(s)
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD or an ISO 8601 timestamp)", s)
+	}
+	return t, nil
+}
+
+// sprintCompletion is what complete_sprint did.
+type sprintCompletion struct {
+	Sprint     *JiraSprint `json:"sprint"`
+	Completed  int         `json:"completed_issues"`
+	Incomplete []string    `json:"incomplete_issues"`
+	MovedTo    string      `json:"incomplete_moved_to"` // backlog or a sprint name
+	Error      string      `json:"error,omitempty"`
+}
+
+// CompleteSprint closes an active sprint. Jira returns the incomplete issues
+// to the backlog; with target set they then move on to that sprint, after
+// closing so the sprint report still counts them as not completed.
+// Subtasks follow their parents.
+func (c *JiraClient) CompleteSprint(ctx context.Context, id int, target *JiraSprint) (*sprintCompletion, error) {
+	issues, err := c.SprintIssues(ctx, id, []string{"status", "issuetype"}, 0)
+	if err != nil {
+		return nil, err
+	}
+	res := &sprintCompletion{Incomplete: []string{}, MovedTo: "backlog"}
+	for _, iss := range issues {
+		switch {
+		case fieldString(iss.Fields, "status", "statusCategory", "key") == "done":
+			res.Completed++
+		case fieldPath(iss.Fields, "issuetype", "subtask") != true:
+			res.Incomplete = append(res.Incomplete, iss.Key)
+		}
+	}
+	if res.Sprint, err = c.UpdateSprint(ctx, id, map[string]any{"state": "closed"}); err != nil {
+		return nil, err
+	}
+	if target != nil && len(res.Incomplete) > 0 {
+		if err := c.MoveToSprint(ctx, target.ID, res.Incomplete); err != nil {
+			res.Error = fmt.Sprintf("sprint closed, but moving incomplete issues to %q failed (they are in the backlog): %v", target.Name, err)
+			return res, nil
+		}
+		res.MovedTo = target.Name
+	}
+	return res, nil
+}
+
+func registerSprintTools(server *mcp.Server, jc *JiraClient) {
+	// list_sprints(board_id, state?, max_results?)
+	type listSprintsArgs struct {
+		BoardID    int      `json:"board_id" jsonschema:"Board id (see list_boards)"`
+		State      []string `json:"state,omitempty" jsonschema:"Only sprints in these states: future, active, closed (default active and future)"`
+		MaxResults int      `json:"max_results,omitempty" jsonschema:"Maximum sprints (default 50)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_sprints",
+		Title:       "List Sprints",
+		Description: "List a board's sprints with their state, goal, and dates; active and future sprints by default",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listSprintsArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_sprints args={board:%d,state:%v}", args.BoardID, args.State)
+		states := args.State
+		if len(states) == 0 {
+			states = []string{"active", "future"}
+		}
+		for i, s := range states {
+			states[i] = strings.ToLower(s)
+			switch states[i] {
+			case "future", "active", "closed":
+			default:
+				return nil, nil, fmt.Errorf("unknown sprint state %q (valid: future, active, closed)", s)
+			}
+		}
+		max := args.MaxResults
+		if max <= 0 {
+			max = 50
+		}
+		sprints, err := jc.ListSprints(ctx, args.BoardID, states, max)
+		if err != nil {
+			debugf("tool=list_sprints error=%v", err)
+			return nil, nil, err
+		}
+		if sprints == nil {
+			sprints = []JiraSprint{}
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"board_id": args.BoardID, "sprints": sprints}}, nil, nil
+	})
+
+	// get_sprint(sprint_id)
+	type sprintArgs struct {
+		SprintID int `json:"sprint_id" jsonschema:"Sprint id (see list_sprints)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_sprint",
+		Title:       "Get Sprint",
+		Description: "Get a sprint's name, state, goal, and dates",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args sprintArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=get_sprint args={sprint:%d}", args.SprintID)
+		s, err := jc.GetSprint(ctx, args.SprintID)
+		if err != nil {
+			debugf("tool=get_sprint error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: s}, nil, nil
+	})
+
+	// get_sprint_issues(sprint_id, fields?, max_results?, raw_adf?)
+	type sprintIssuesArgs struct {
+		SprintID   int      `json:"sprint_id" jsonschema:"Sprint id (see list_sprints)"`
+		Fields     []string `json:"fields,omitempty" jsonschema:"Fields to return by name, id, or alias (default slim)"`
+		MaxResults int      `json:"max_results,omitempty" jsonschema:"Maximum issues (default 200)"`
+		RawADF     bool     `json:"raw_adf,omitempty" jsonschema:"Return rich-text fields as raw ADF instead of Markdown"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_sprint_issues",
+		Title:       "Get Sprint Issues",
+		Description: "List the issues in a sprint in rank order, with counts by status and status category",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args sprintIssuesArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=get_sprint_issues args={sprint:%d,max:%d}", args.SprintID, args.MaxResults)
+		sel := args.Fields
+		if len(sel) == 0 {
+			sel = []string{"slim", "issuetype"}
+		}
+		fields, err := jc.resolveFieldSelection(ctx, sel)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !containsFold(fields, "status") && !containsFold(fields, "*all") && !containsFold(fields, "*navigable") {
+			fields = append(fields, "status")
+		}
+		limit := args.MaxResults
+		if limit <= 0 {
+			limit = 200
+		}
+		issues, err := jc.SprintIssues(ctx, args.SprintID, fields, limit)
+		if err != nil {
+			debugf("tool=get_sprint_issues error=%v", err)
+			return nil, nil, err
+		}
+		if issues == nil {
+			issues = []JiraIssue{}
+		}
+		if !args.RawADF {
+			for i := range issues {
+				renderIssueText(&issues[i])
+			}
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{
+			"sprint_id": args.SprintID, "issues": issues, "breakdown": statusBreakdown(issues),
+		}}, nil, nil
+	})
+
+	// move_to_sprint(sprint_id, keys)
+	type moveToSprintArgs struct {
+		SprintID int      `json:"sprint_id" jsonschema:"Sprint id (see list_sprints)"`
+		Keys     []string `json:"keys" jsonschema:"Issue keys to move"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "move_to_sprint",
+		Title:       "Move to Sprint",
+		Description: "Move issues into a future or active sprint; any number of keys, sent in batches of 50",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args moveToSprintArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=move_to_sprint args={sprint:%d,keys:%v}", args.SprintID, args.Keys)
+		if len(args.Keys) == 0 {
+			return nil, nil, errors.New("keys is required")
+		}
+		if err := jc.MoveToSprint(ctx, args.SprintID, args.Keys); err != nil {
+			debugf("tool=move_to_sprint error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"sprint_id": args.SprintID, "moved": args.Keys}}, nil, nil
+	})
+
+	// move_to_backlog(keys)
+	type moveToBacklogArgs struct {
+		Keys []string `json:"keys" jsonschema:"Issue keys to move"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "move_to_backlog",
+		Title:       "Move to Backlog",
+		Description: "Take issues out of their sprint and put them back in the backlog; any number of keys, sent in batches of 50",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args moveToBacklogArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=move_to_backlog args={keys:%v}", args.Keys)
+		if len(args.Keys) == 0 {
+			return nil, nil, errors.New("keys is required")
+		}
+		if err := jc.MoveToBacklog(ctx, args.Keys); err != nil {
+			debugf("tool=move_to_backlog error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"moved": args.Keys}}, nil, nil
+	})
+
+	// create_sprint(board_id, name, goal?, start_date?, end_date?)
+	type createSprintArgs struct {
+		BoardID   int    `json:"board_id" jsonschema:"Board id (see list_boards)"`
+		Name      string `json:"name" jsonschema:"Sprint name"`
+		Goal      string `json:"goal,omitempty" jsonschema:"Sprint goal"`
+		StartDate string `json:"start_date,omitempty" jsonschema:"Planned start, YYYY-MM-DD or ISO 8601"`
+		EndDate   string `json:"end_date,omitempty" jsonschema:"Planned end, YYYY-MM-DD or ISO 8601"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "create_sprint",
+		Title:       "Create Sprint",
+		Description: "Create a future sprint on a scrum board",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args createSprintArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=create_sprint args={board:%d,name:%q}", args.BoardID, args.Name)
+		if strings.TrimSpace(args.Name) == "" {
+			return nil, nil, errors.New("name is required")
+		}
+		sp := JiraSprint{Name: args.Name, Goal: args.Goal}
+		for _, d := range []struct {
+			in  string
+			out *string
+		}{{args.StartDate, &sp.StartDate}, {args.EndDate, &sp.EndDate}} {
+			if d.in == "" {
+				continue
+			}
+			t, err := sprintDate(d.in)
+			if err != nil {
+				return nil, nil, err
+			}
+			*d.out = t.Format(time.RFC3339)
+		}
+		s, err := jc.CreateSprint(ctx, args.BoardID, sp)
+		if err != nil {
+			debugf("tool=create_sprint error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: s}, nil, nil
+	})
+
+	// start_sprint(sprint_id, goal?, start_date?, end_date?, duration_days?)
+	type startSprintArgs struct {
+		SprintID     int    `json:"sprint_id" jsonschema:"Future sprint to start (see list_sprints)"`
+		Goal         string `json:"goal,omitempty" jsonschema:"Sprint goal (keeps the current goal when empty)"`
+		StartDate    string `json:"start_date,omitempty" jsonschema:"YYYY-MM-DD or ISO 8601 (default now)"`
+		EndDate      string `json:"end_date,omitempty" jsonschema:"YYYY-MM-DD or ISO 8601 (default start plus duration_days)"`
+		DurationDays int    `json:"duration_days,omitempty" jsonschema:"Sprint length when end_date is not given (default 14)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "start_sprint",
+		Title:       "Start Sprint",
+		Description: "Start a future sprint with a goal and start/end dates",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args startSprintArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=start_sprint args={sprint:%d,start:%q,end:%q,days:%d}", args.SprintID, args.StartDate, args.EndDate, args.DurationDays)
+		start, end := time.Now(), time.Time{}
+		var err error
+		if args.StartDate != "" {
+			if start, err = sprintDate(args.StartDate); err != nil {
+				return nil, nil, err
+			}
+		}
+		if args.EndDate != "" {
+			if end, err = sprintDate(args.EndDate); err != nil {
+				return nil, nil, err
+			}
+		} else {
+			days := args.DurationDays
+			if days <= 0 {
+				days = 14
+			}
+			end = start.AddDate(0, 0, days)
+		}
+		if !end.After(start) {
+			return nil, nil, errors.New("end_date must be after start_date")
+		}
+		cur, err := jc.GetSprint(ctx, args.SprintID)
+		if err != nil {
+			debugf("tool=start_sprint error=%v", err)
+			return nil, nil, err
+		}
+		if cur.State != "future" {
+			return nil, nil, fmt.Errorf("sprint %q is %s; only future sprints can be started", cur.Name, cur.State)
+		}
+		changes := map[string]any{"state": "active", "startDate": start.Format(time.RFC3339), "endDate": end.Format(time.RFC3339)}
+		if args.Goal != "" {
+			changes["goal"] = args.Goal
+		}
+		s, err := jc.UpdateSprint(ctx, args.SprintID, changes)
+		if err != nil {
+			debugf("tool=start_sprint error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: s}, nil, nil
+	})
+
+	// complete_sprint(sprint_id, move_incomplete_to?, target_sprint_id?)
+	type completeSprintArgs struct {
+		SprintID         int    `json:"sprint_id" jsonschema:"Active sprint to complete"`
+		MoveIncompleteTo string `json:"move_incomplete_to,omitempty" jsonschema:"Where unfinished issues go: backlog (default), next (the board's next future sprint), or sprint (target_sprint_id)"`
+		TargetSprintID   int    `json:"target_sprint_id,omitempty" jsonschema:"With move_incomplete_to=sprint: the sprint to move unfinished issues to"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "complete_sprint",
+		Title:       "Complete Sprint",
+		Description: "Complete an active sprint, moving unfinished issues to the backlog, the next sprint, or a chosen sprint",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args completeSprintArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=complete_sprint args={sprint:%d,to:%q,target:%d}", args.SprintID, args.MoveIncompleteTo, args.TargetSprintID)
+		cur, err := jc.GetSprint(ctx, args.SprintID)
+		if err != nil {
+			debugf("tool=complete_sprint error=%v", err)
+			return nil, nil, err
+		}
+		if cur.State != "active" {
+			return nil, nil, fmt.Errorf("sprint %q is %s; only active sprints can be completed", cur.Name, cur.State)
+		}
+		var target *JiraSprint
+		switch strings.ToLower(args.MoveIncompleteTo) {
+		case "", "backlog":
+		case "next":
+			future, err := jc.ListSprints(ctx, cur.OriginBoardID, []string{"future"}, 1)
+			if err != nil {
+				debugf("tool=complete_sprint error=%v", err)
+				return nil, nil, err
+			}
+			if len(future) == 0 {
+				return nil, nil, errors.New("the board has no future sprint; create one first or use move_incomplete_to=backlog")
+			}
+			target = &future[0]
+		case "sprint":
+			if args.TargetSprintID == 0 || args.TargetSprintID == args.SprintID {
+				return nil, nil, errors.New("move_incomplete_to=sprint needs a different target_sprint_id")
+			}
+			if target, err = jc.GetSprint(ctx, args.TargetSprintID); err != nil {
+				debugf("tool=complete_sprint error=%v", err)
+				return nil, nil, err
+			}
+			if target.State == "closed" {
+				return nil, nil, fmt.Errorf("target sprint %q is closed", target.Name)
+			}
+		default:
+			return nil, nil, fmt.Errorf("unknown move_incomplete_to %q (valid: backlog, next, sprint)", args.MoveIncompleteTo)
+		}
+		res, err := jc.CompleteSprint(ctx, args.SprintID, target)
+		if err != nil {
+			debugf("tool=complete_sprint error=%v", err)
+			return nil, nil, err
+		}
+		if res.Error != "" {
+			return &mcp.CallToolResult{IsError: true, StructuredContent: res}, nil, nil
+		}
+		return &mcp.CallToolResult{StructuredContent: res}, nil, nil
+	})
+}
@@ -0,0 +1,268 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Service status ----
+//
+// jira://status summarizes conditions agents should adapt to rather than
+// retry through: sustained rate limiting, rejected credentials, read-only
+// mode, and maintenance. When the set of conditions changes the resource is
+// marked updated for subscribers and every session gets a logging
+// notification, so agents can back off (or resume) without polling.
+
+const (
+	// rateLimitWindow and rateLimitSustained define "sustained": this many
+	// 429s within the window.
+	rateLimitWindow    = 5 * time.Minute
+	rateLimitSustained = 3
+
+	// authFailuresSustained rejections in a row, with no success between,
+	// mean the credentials are bad rather than one resource being off-limits.
+	authFailuresSustained = 2
+)
+
+type statusCondition struct {
+	Kind   string    `json:"kind"` // rate_limited, auth_failing, read_only, maintenance
+	Since  time.Time `json:"since"`
+	Detail string    `json:"detail"`
+	Advice string    `json:"advice"`
+}
+
+type statusReport struct {
+	State      string            `json:"state"` // ok or degraded
+	Instance   string            `json:"instance"`
+	Writes     string            `json:"writes"`
+	Conditions []statusCondition `json:"conditions"`
+}
+
+type statusMonitor struct {
+	mu            sync.Mutex
+	rateLimited   []time.Time
+	authFails     int
+	authFailSince time.Time
+	authDetail    string
+	readOnlySince time.Time
+	readOnlyInfo  string
+	published     string // kinds in the last published report
+
+	notify func(statusReport) // set once the server exists
+}
+
+func (s *statusMonitor) noteRateLimited() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	keep := s.rateLimited[:0]
+	for _, t := range s.rateLimited {
+		if now.Sub(t) < rateLimitWindow {
+			keep = append(keep, t)
+		}
+	}
+	s.rateLimited = append(keep, now)
+}
+
+func (s *statusMonitor) noteAuthRejected(detail string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.authFails == 0 {
+		s.authFailSince = time.Now()
+	}
+	s.authFails++
+	s.authDetail = detail
+}
+
+func (s *statusMonitor) noteReadOnly(detail string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.readOnlySince.IsZero() {
+		s.readOnlySince = time.Now()
+	}
+	s.readOnlyInfo = detail
+}
+
+// noteOK records a successful response. A write getting through ends
+// read-only mode.
+func (s *statusMonitor) noteOK(write bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.authFails, s.authDetail = 0, ""
+	if write {
+		s.readOnlySince, s.readOnlyInfo = time.Time{}, ""
+	}
+}
+
+// degraded reports whether the last published report had conditions.
+func (s *statusMonitor) degraded() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.published != ""
+}
+
+// readOnlyFrom reports whether a failed write was refused because the site
+// is in read-only mode, returning Jira's message. The body stays readable.
+func readOnlyFrom(resp *http.Response) (string, bool) {
+	if resp.StatusCode < 400 {
+		return "", false
+	}
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+	lower := bytes.ToLower(b)
+	for _, marker := range [][]byte{[]byte("read-only"), []byte("read only mode"), []byte("read_only")} {
+		if bytes.Contains(lower, marker) {
+			msg := string(b)
+			if len(msg) > 200 {
+				msg = msg[:200] + "..."
+			}
+			return resp.Status + ": " + msg, true
+		}
+	}
+	return "", false
+}
+
+func (c *JiraClient) statusReport() statusReport {
+	r := statusReport{State: "ok", Instance: c.Instance.Label, Writes: c.Instance.Writes, Conditions: []statusCondition{}}
+	if c.Instance.Writes == "deny" {
+		r.Conditions = append(r.Conditions, statusCondition{
+			Kind: "read_only", Detail: "writes to this instance are disabled by configuration",
+			Advice: "do not attempt writes; report intended changes to the user instead",
+		})
+	}
+	s := &c.status
+	s.mu.Lock()
+	now := time.Now()
+	recent := 0
+	for _, t := range s.rateLimited {
+		if now.Sub(t) < rateLimitWindow {
+			recent++
+		}
+	}
+	if recent >= rateLimitSustained {
+		r.Conditions = append(r.Conditions, statusCondition{
+			Kind: "rate_limited", Since: s.rateLimited[len(s.rateLimited)-recent],
+			Detail: fmt.Sprintf("%d rate-limited responses in the last %s", recent, rateLimitWindow),
+			Advice: "batch work, avoid bulk reads, and defer non-urgent writes",
+		})
+	}
+	if s.authFails >= authFailuresSustained {
+		r.Conditions = append(r.Conditions, statusCondition{
+			Kind: "auth_failing", Since: s.authFailSince,
+			Detail: fmt.Sprintf("%d requests rejected in a row: %s", s.authFails, s.authDetail),
+			Advice: "stop retrying; ask the user to check the Jira credentials",
+		})
+	}
+	if !s.readOnlySince.IsZero() {
+		r.Conditions = append(r.Conditions, statusCondition{
+			Kind: "read_only", Since: s.readOnlySince, Detail: s.readOnlyInfo,
+			Advice: "stop retrying writes until the site leaves read-only mode",
+		})
+	}
+	s.mu.Unlock()
+	if h := c.healthReport(); h.Maintenance {
+		r.Conditions = append(r.Conditions, statusCondition{
+			Kind: "maintenance", Since: *h.MaintenanceSince,
+			Detail: fmt.Sprintf("Jira is under maintenance; retry after about %.0fs", h.RetryAfterSecs),
+			Advice: "pause Jira work and tell the user; retry later",
+		})
+	}
+	if len(r.Conditions) > 0 {
+		r.State = "degraded"
+	}
+	return r
+}
+
+// noteStatus updates the status conditions from a response. rejected is
+// set when every configured credential was refused.
+func (c *JiraClient) noteStatus(method, path string, resp *http.Response, rejected bool) {
+	s := &c.status
+	noted := true
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		s.noteRateLimited()
+	case rejected && (resp.StatusCode == http.StatusUnauthorized || path == "/rest/api/3/myself"):
+		// A 403 elsewhere is usually one issue or project being off-limits.
+		s.noteAuthRejected(fmt.Sprintf("%s %s: %s", method, path, resp.Status))
+	case isWrite(method, path) && resp.StatusCode >= 400:
+		detail, ok := readOnlyFrom(resp)
+		if !ok {
+			return
+		}
+		s.noteReadOnly(detail)
+	case resp.StatusCode < 400:
+		s.noteOK(isWrite(method, path))
+		noted = s.degraded()
+	default:
+		return
+	}
+	if noted {
+		c.publishStatus()
+	}
+}
+
+// publishStatus notifies clients if the set of conditions changed since
+// the last report.
+func (c *JiraClient) publishStatus() {
+	r := c.statusReport()
+	kinds := ""
+	for _, cond := range r.Conditions {
+		kinds += cond.Kind + ","
+	}
+	s := &c.status
+	s.mu.Lock()
+	changed := kinds != s.published
+	s.published = kinds
+	notify := s.notify
+	s.mu.Unlock()
+	if changed && notify != nil {
+		notify(r)
+	}
+}
+
+// subscriptionHandler accepts resource subscriptions; the SDK keeps track
+// of who subscribed to what.
+func subscriptionHandler[R any](context.Context, R) error { return nil }
+
+func registerStatusResources(server *mcp.Server, jc *JiraClient) {
+	jc.status.mu.Lock()
+	jc.status.notify = func(r statusReport) {
+		ctx := context.Background()
+		_ = server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: "jira://status"})
+		level := mcp.LoggingLevel("info")
+		if r.State != "ok" {
+			level = "warning"
+		}
+		for ss := range server.Sessions() {
+			_ = ss.Log(ctx, &mcp.LoggingMessageParams{Level: level, Logger: "jira.status", Data: r})
+		}
+		debugf("status: %s %v", r.State, r.Conditions)
+	}
+	jc.status.mu.Unlock()
+
+	server.AddResource(&mcp.Resource{
+		URI:         "jira://status",
+		Name:        "status",
+		Title:       "Jira Service Status",
+		Description: "Whether Jira is degraded (rate limited, rejecting credentials, read-only, or in maintenance), with advice for each condition. Subscribe for changes",
+		MIMEType:    "application/json",
+	}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		debugf("resource=jira://status")
+		b, err := json.MarshalIndent(jc.statusReport(), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{
+			URI: req.Params.URI, MIMEType: "application/json", Text: string(b),
+		}}}, nil
+	})
+}
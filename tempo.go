@@ -0,0 +1,425 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Tempo Timesheets ----
+//
+// Sites that track time in Tempo keep worklogs there rather than in Jira.
+// Setting TEMPO_API_TOKEN (or TEMPO_API_TOKEN_FILE) enables the tempo_*
+// tools, which talk to the Tempo REST API with that token. TEMPO_API_URL
+// overrides the API base, e.g. https://api.eu.tempo.io/4 for EU-hosted
+// data.
+
+const defaultTempoURL = "https://api.tempo.io/4"
+
+type TempoClient struct {
+	BaseURL string
+	Token   string
+}
+
+func tempoFromEnv() (*TempoClient, error) {
+	token, ok, err := readSetting("TEMPO_API_TOKEN")
+	if err != nil || !ok {
+		return nil, err
+	}
+	base := strings.TrimRight(os.Getenv("TEMPO_API_URL"), "/")
+	if base == "" {
+		base = defaultTempoURL
+	}
+	return &TempoClient{BaseURL: base, Token: strings.TrimSpace(token)}, nil
+}
+
+// tempoJSON is doJSON for the Tempo API. Writes are checked against the
+// instance's write policy like Jira writes.
+func (c *JiraClient) tempoJSON(ctx context.Context, method, path string, body any, out any) error {
+	if c.Tempo == nil {
+		return errors.New("Tempo is not configured (set TEMPO_API_TOKEN)")
+	}
+	if err := c.checkWrite(ctx, method, "/tempo"+path); err != nil {
+		return err
+	}
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		debugf("Tempo request body: %s", string(b))
+		r = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.Tempo.BaseURL+path, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Tempo.Token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("tempo %s %s failed: %s - %s", method, path, resp.Status, b)
+	}
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
+
+// tempoPage returns every result of a Tempo collection endpoint.
+func tempoPage[T any](ctx context.Context, c *JiraClient, path string, q url.Values) ([]T, error) {
+	var out []T
+	for {
+		q.Set("offset", strconv.Itoa(len(out)))
+		q.Set("limit", "1000")
+		var page struct {
+			Metadata struct {
+				Next string `json:"next"`
+			} `json:"metadata"`
+			Results []T `json:"results"`
+		}
+		if err := c.tempoJSON(ctx, http.MethodGet, path+"?"+q.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		out = append(out, page.Results...)
+		if page.Metadata.Next == "" || len(page.Results) == 0 {
+			return out, nil
+		}
+	}
+}
+
+type tempoWorklog struct {
+	ID    int64 `json:"tempoWorklogId"`
+	Issue struct {
+		ID int64 `json:"id"`
+	} `json:"issue"`
+	TimeSpentSeconds int    `json:"timeSpentSeconds"`
+	BillableSeconds  int    `json:"billableSeconds"`
+	StartDate        string `json:"startDate"`
+	StartTime        string `json:"startTime"`
+	Description      string `json:"description"`
+	Author           struct {
+		AccountID string `json:"accountId"`
+	} `json:"author"`
+	Attributes struct {
+		Values []struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		} `json:"values"`
+	} `json:"attributes"`
+}
+
+type tempoWorklogView struct {
+	ID            int64             `json:"id"`
+	Key           string            `json:"key,omitempty"`
+	IssueID       int64             `json:"issue_id"`
+	Date          string            `json:"date"`
+	StartTime     string            `json:"start_time,omitempty"`
+	Hours         float64           `json:"hours"`
+	BillableHours float64           `json:"billable_hours"`
+	Description   string            `json:"description,omitempty"`
+	Author        string            `json:"author_account_id"`
+	Attributes    map[string]string `json:"attributes,omitempty"`
+}
+
+func viewTempoWorklog(w *tempoWorklog, key string) tempoWorklogView {
+	v := tempoWorklogView{
+		ID: w.ID, Key: key, IssueID: w.Issue.ID, Date: w.StartDate, StartTime: w.StartTime,
+		Hours: math.Round(float64(w.TimeSpentSeconds)/36) / 100, BillableHours: math.Round(float64(w.BillableSeconds)/36) / 100,
+		Description: w.Description, Author: w.Author.AccountID,
+	}
+	for _, a := range w.Attributes.Values {
+		if v.Attributes == nil {
+			v.Attributes = map[string]string{}
+		}
+		v.Attributes[a.Key] = a.Value
+	}
+	return v
+}
+
+// tempoSeconds converts a duration in hours and minutes to seconds. Days
+// and weeks are refused: their length is a Tempo setting.
+func tempoSeconds(s string) (int, error) {
+	d, err := normalizeDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	var secs int
+	for _, p := range strings.Fields(d) {
+		n, _ := strconv.Atoi(p[:len(p)-1])
+		switch p[len(p)-1] {
+		case 'h':
+			secs += n * 3600
+		case 'm':
+			secs += n * 60
+		default:
+			return 0, fmt.Errorf("duration %q: give Tempo time in hours and minutes", s)
+		}
+	}
+	return secs, nil
+}
+
+var tempoStartTimeRe = regexp.MustCompile(`^\d{2}:\d{2}(:\d{2})?$`)
+
+// TempoLogTime logs time on an issue as author (an accountId).
+func (c *JiraClient) TempoLogTime(ctx context.Context, key, author string, seconds, billable int, date, startTime, description string, attrs map[string]string) (*tempoWorklogView, error) {
+	iss, err := c.GetIssueFields(ctx, key, []string{"summary"})
+	if err != nil {
+		return nil, err
+	}
+	issueID, err := strconv.ParseInt(iss.ID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("issue %s has no numeric id", key)
+	}
+	body := map[string]any{
+		"issueId": issueID, "authorAccountId": author, "timeSpentSeconds": seconds,
+		"startDate": date, "description": description,
+	}
+	if billable >= 0 {
+		body["billableSeconds"] = billable
+	}
+	if startTime != "" {
+		if len(startTime) == len("15:04") {
+			startTime += ":00"
+		}
+		body["startTime"] = startTime
+	}
+	if len(attrs) > 0 {
+		keys := make([]string, 0, len(attrs))
+		for k := range attrs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		list := make([]map[string]string, len(keys))
+		for i, k := range keys {
+			list[i] = map[string]string{"key": k, "value": attrs[k]}
+		}
+		body["attributes"] = list
+	}
+	var w tempoWorklog
+	if err := c.tempoJSON(ctx, http.MethodPost, "/worklogs", body, &w); err != nil {
+		return nil, err
+	}
+	v := viewTempoWorklog(&w, iss.Key)
+	return &v, nil
+}
+
+// TempoUserWorklogs returns the Tempo worklogs of accountID between from
+// and to (YYYY-MM-DD, inclusive).
+func (c *JiraClient) TempoUserWorklogs(ctx context.Context, accountID, from, to string) ([]tempoWorklog, error) {
+	q := url.Values{}
+	q.Set("from", from)
+	q.Set("to", to)
+	return tempoPage[tempoWorklog](ctx, c, "/worklogs/user/"+url.PathEscape(accountID), q)
+}
+
+// issueKeysByID maps Jira issue ids to keys.
+func (c *JiraClient) issueKeysByID(ctx context.Context, ids []int64) (map[int64]string, error) {
+	out := map[int64]string{}
+	for start := 0; start < len(ids); start += 100 {
+		batch := ids[start:min(start+100, len(ids))]
+		parts := make([]string, len(batch))
+		for i, id := range batch {
+			parts[i] = strconv.FormatInt(id, 10)
+		}
+		issues, err := c.SearchAll(ctx, "id in ("+strings.Join(parts, ",")+")", []string{"summary"}, len(batch))
+		if err != nil {
+			return nil, err
+		}
+		for _, iss := range issues {
+			if id, err := strconv.ParseInt(iss.ID, 10, 64); err == nil {
+				out[id] = iss.Key
+			}
+		}
+	}
+	return out, nil
+}
+
+func registerTempoTools(server *mcp.Server, jc *JiraClient) {
+	if jc.Tempo == nil {
+		return
+	}
+
+	// tempo_log_time(key, time_spent, date?, start_time?, description?, billable?, attributes?)
+	type logTimeArgs struct {
+		Key         string            `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+		TimeSpent   string            `json:"time_spent" jsonschema:"Time worked in hours and minutes, e.g. 1h 30m, 1.5h, 45m"`
+		Date        string            `json:"date,omitempty" jsonschema:"Day the work was done (YYYY-MM-DD, default today)"`
+		StartTime   string            `json:"start_time,omitempty" jsonschema:"Time of day the work started (HH:MM)"`
+		Description string            `json:"description,omitempty" jsonschema:"What was done"`
+		Billable    string            `json:"billable,omitempty" jsonschema:"Billable time if it differs from time_spent, e.g. 0m for non-billable work"`
+		Attributes  map[string]string `json:"attributes,omitempty" jsonschema:"Work attribute values by attribute key, e.g. {\"_Account_\": \"ACME\"}; see tempo_attributes"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "tempo_log_time",
+		Title:       "Tempo: Log Time",
+		Description: "Log time on an issue in Tempo Timesheets as the authenticated user, with billable time and work attributes such as the Tempo account",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args logTimeArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=tempo_log_time args={key:%q,spent:%q,date:%q}", args.Key, args.TimeSpent, args.Date)
+		secs, err := tempoSeconds(args.TimeSpent)
+		if err != nil {
+			return nil, nil, err
+		}
+		billable := -1
+		if args.Billable != "" {
+			if strings.Trim(args.Billable, "0hm ") == "" {
+				billable = 0
+			} else if billable, err = tempoSeconds(args.Billable); err != nil {
+				return nil, nil, err
+			}
+		}
+		date := args.Date
+		if date == "" {
+			date = time.Now().Format("2006-01-02")
+		} else if _, err := time.Parse("2006-01-02", date); err != nil {
+			return nil, nil, fmt.Errorf("date must be YYYY-MM-DD: %w", err)
+		}
+		if args.StartTime != "" && !tempoStartTimeRe.MatchString(args.StartTime) {
+			return nil, nil, fmt.Errorf("start_time %q must be HH:MM", args.StartTime)
+		}
+		me, err := jc.Myself(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		w, err := jc.TempoLogTime(ctx, args.Key, me.AccountID, secs, billable, date, args.StartTime, args.Description, args.Attributes)
+		if err != nil {
+			debugf("tool=tempo_log_time error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: w}, nil, nil
+	})
+
+	// tempo_list_worklogs(user?, from, to)
+	type listArgs struct {
+		User string `json:"user,omitempty" jsonschema:"accountId, email, or display name (default the authenticated user)"`
+		From string `json:"from" jsonschema:"First day (YYYY-MM-DD)"`
+		To   string `json:"to" jsonschema:"Last day (YYYY-MM-DD), inclusive"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "tempo_list_worklogs",
+		Title:       "Tempo: List Worklogs",
+		Description: "List a user's Tempo worklogs for a period with totals per day and per issue",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=tempo_list_worklogs args={user:%q,from:%q,to:%q}", args.User, args.From, args.To)
+		from, err := time.Parse("2006-01-02", args.From)
+		if err != nil {
+			return nil, nil, fmt.Errorf("from must be YYYY-MM-DD: %w", err)
+		}
+		to, err := time.Parse("2006-01-02", args.To)
+		if err != nil {
+			return nil, nil, fmt.Errorf("to must be YYYY-MM-DD: %w", err)
+		}
+		if to.Before(from) {
+			return nil, nil, errors.New("to is before from")
+		}
+		var user *JiraUser
+		if args.User == "" {
+			user, err = jc.Myself(ctx)
+		} else {
+			user, err = jc.ResolveUser(ctx, args.User)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		logs, err := jc.TempoUserWorklogs(ctx, user.AccountID, args.From, args.To)
+		if err != nil {
+			debugf("tool=tempo_list_worklogs error=%v", err)
+			return nil, nil, err
+		}
+		ids := make([]int64, 0, len(logs))
+		seen := map[int64]bool{}
+		for _, w := range logs {
+			if !seen[w.Issue.ID] {
+				seen[w.Issue.ID] = true
+				ids = append(ids, w.Issue.ID)
+			}
+		}
+		keys, err := jc.issueKeysByID(ctx, ids)
+		if err != nil {
+			// Keys are a convenience; the worklogs carry issue ids.
+			debugf("tool=tempo_list_worklogs keys: %v", err)
+		}
+		views := make([]tempoWorklogView, len(logs))
+		byDay := map[string]float64{}
+		byIssue := map[string]float64{}
+		var total, billable float64
+		for i := range logs {
+			v := viewTempoWorklog(&logs[i], keys[logs[i].Issue.ID])
+			views[i] = v
+			issue := v.Key
+			if issue == "" {
+				issue = strconv.FormatInt(v.IssueID, 10)
+			}
+			byDay[v.Date] = math.Round((byDay[v.Date]+v.Hours)*100) / 100
+			byIssue[issue] = math.Round((byIssue[issue]+v.Hours)*100) / 100
+			total += v.Hours
+			billable += v.BillableHours
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{
+			"user": orNone(user.DisplayName), "account_id": user.AccountID, "from": args.From, "to": args.To,
+			"worklogs": views, "total_hours": math.Round(total*100) / 100, "billable_hours": math.Round(billable*100) / 100,
+			"hours_by_day": byDay, "hours_by_issue": byIssue,
+		}}, nil, nil
+	})
+
+	// tempo_attributes(kind?, team_id?)
+	type attributesArgs struct {
+		Kind   string `json:"kind,omitempty" jsonschema:"What to list: work_attributes (default), accounts, or teams"`
+		TeamID int64  `json:"team_id,omitempty" jsonschema:"With kind teams, list this team's members instead"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "tempo_attributes",
+		Title:       "Tempo: Attributes",
+		Description: "List Tempo work attributes (keys and allowed values for tempo_log_time), accounts, or teams and their members",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args attributesArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=tempo_attributes args={kind:%q,team:%d}", args.Kind, args.TeamID)
+		var path string
+		switch args.Kind {
+		case "", "work_attributes":
+			args.Kind, path = "work_attributes", "/work-attributes"
+		case "accounts":
+			path = "/accounts"
+		case "teams":
+			path = "/teams"
+			if args.TeamID != 0 {
+				args.Kind, path = "team_members", fmt.Sprintf("/team-memberships/team/%d", args.TeamID)
+			}
+		default:
+			return nil, nil, fmt.Errorf("unknown kind %q (valid: work_attributes, accounts, teams)", args.Kind)
+		}
+		results, err := tempoPage[map[string]any](ctx, jc, path, url.Values{})
+		if err != nil {
+			debugf("tool=tempo_attributes error=%v", err)
+			return nil, nil, err
+		}
+		for _, r := range results {
+			delete(r, "self")
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"kind": args.Kind, "results": results, "total": len(results)}}, nil, nil
+	})
+}
@@ -0,0 +1,149 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Workflow transitions ----
+
+type JiraTransition struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	To   struct {
+		ID             string `json:"id,omitempty"`
+		Name           string `json:"name"`
+		StatusCategory struct {
+			Key  string `json:"key,omitempty"`
+			Name string `json:"name,omitempty"`
+		} `json:"statusCategory"`
+	} `json:"to"`
+	HasScreen bool                     `json:"hasScreen,omitempty"`
+	Fields    map[string]EditMetaField `json:"fields,omitempty"`
+}
+
+func (c *JiraClient) ListTransitions(ctx context.Context, key string) ([]JiraTransition, error) {
+	var out struct {
+		Transitions []JiraTransition `json:"transitions"`
+	}
+	path := "/rest/api/3/issue/" + url.PathEscape(key) + "/transitions?expand=transitions.fields"
+	if err := c.doJSON(ctx, http.MethodGet, path, nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Transitions, nil
+}
+
+// findTransition matches a transition by id, name, or target status name
+// (case-insensitive).
+func findTransition(ts []JiraTransition, want string) (*JiraTransition, error) {
+	for _, match := range []func(JiraTransition) bool{
+		func(t JiraTransition) bool { return t.ID == want },
+		func(t JiraTransition) bool { return strings.EqualFold(t.Name, want) },
+		func(t JiraTransition) bool { return strings.EqualFold(t.To.Name, want) },
+	} {
+		for i := range ts {
+			if match(ts[i]) {
+				return &ts[i], nil
+			}
+		}
+	}
+	avail := make([]string, len(ts))
+	for i, t := range ts {
+		avail[i] = fmt.Sprintf("%s (id %s, to %s)", t.Name, t.ID, t.To.Name)
+	}
+	return nil, fmt.Errorf("no transition %q available; available: %s", want, strings.Join(avail, ", "))
+}
+
+// TransitionIssue performs transition id, optionally setting fields and
+// adding a comment in the same request.
+func (c *JiraClient) TransitionIssue(ctx context.Context, key, id string, fields map[string]any, comment string) error {
+	body := map[string]any{"transition": map[string]any{"id": id}}
+	if len(fields) > 0 {
+		body["fields"] = fields
+	}
+	if comment != "" {
+		body["update"] = map[string]any{
+			"comment": []any{map[string]any{"add": map[string]any{"body": c.richText(ctx, comment)}}},
+		}
+	}
+	if err := c.doJSON(ctx, http.MethodPost, "/rest/api/3/issue/"+url.PathEscape(key)+"/transitions", body, nil); err != nil {
+		return err
+	}
+	c.recordAction(ctx, actionTransition, key, "")
+	return nil
+}
+
+func registerTransitionTools(server *mcp.Server, jc *JiraClient) {
+	// list_transitions(key)
+	type listTransitionsArgs struct {
+		Key string `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_transitions",
+		Title:       "List Transitions",
+		Description: "List the workflow transitions currently available for an issue, with their target status and screen fields",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listTransitionsArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_transitions args={key:%q}", args.Key)
+		ts, err := jc.ListTransitions(ctx, args.Key)
+		if err != nil {
+			debugf("tool=list_transitions error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"key": args.Key, "transitions": ts}}, nil, nil
+	})
+
+	// transition_issue(key, transition, resolution?, comment?, fields?)
+	type transitionArgs struct {
+		Key        string         `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+		Transition string         `json:"transition" jsonschema:"Transition name or id, or the target status name"`
+		Resolution string         `json:"resolution,omitempty" jsonschema:"Resolution name, e.g. Done or Won't Do"`
+		Comment    string         `json:"comment,omitempty" jsonschema:"Comment to add with the transition (Markdown)"`
+		Fields     map[string]any `json:"fields,omitempty" jsonschema:"Fields to set on the transition screen, keyed by id, name, or alias"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "transition_issue",
+		Title:       "Transition Issue",
+		Description: "Move an issue through its workflow (e.g. In Progress -> Done), optionally setting a resolution, fields, and a comment in one call",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args transitionArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=transition_issue args={key:%q,transition:%q}", args.Key, args.Transition)
+		ts, err := jc.ListTransitions(ctx, args.Key)
+		if err != nil {
+			debugf("tool=transition_issue error=%v", err)
+			return nil, nil, err
+		}
+		t, err := findTransition(ts, args.Transition)
+		if err != nil {
+			return nil, nil, err
+		}
+		fields, err := jc.resolveFieldKeys(ctx, args.Fields)
+		if err != nil {
+			return nil, nil, err
+		}
+		if args.Resolution != "" {
+			if fields == nil {
+				fields = map[string]any{}
+			}
+			fields["resolution"] = args.Resolution
+		}
+		if len(fields) > 0 {
+			if fields, err = validateEdit(args.Key+" transition "+t.Name, t.Fields, fields); err != nil {
+				return nil, nil, err
+			}
+		}
+		if err := jc.TransitionIssue(ctx, args.Key, t.ID, fields, args.Comment); err != nil {
+			debugf("tool=transition_issue error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{
+			"key":        args.Key,
+			"transition": t.Name,
+			"status":     t.To.Name,
+		}}, nil, nil
+	})
+}
@@ -0,0 +1,425 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Declarative triage rules ----
+//
+// JIRA_TRIAGE_RULES encodes team triage policy, e.g.
+//
+//	{"rules": [{"name": "customer bugs",
+//	            "when": {"type": "Bug", "component": "empty", "label": "customer"},
+//	            "then": {"priority": "High", "assignee": "triage@example.com",
+//	                     "add_labels": ["needs-triage"], "comment_template": "ack"}}],
+//	 "comment_templates": {"ack": "Thanks! {{.Key}} is queued for triage ({{.Rule}})."}}
+//
+// A condition names a field (id, name, alias, or one of the shorthands type,
+// component, label, status_category) and a value or list of values, any of
+// which may match. "empty" and "!empty" test for presence; a leading "!"
+// negates. Rules run in order; a rule with "stop": true ends evaluation for
+// that issue.
+//
+// Each rule comments on an issue at most once: the rules that have
+// commented are recorded in the issue's mcp.triage property.
+
+const triageProperty = "mcp.triage"
+
+type triageConfig struct {
+	Rules            []triageRule      `json:"rules"`
+	CommentTemplates map[string]string `json:"comment_templates,omitempty"`
+}
+
+type triageRule struct {
+	Name string         `json:"name"`
+	When map[string]any `json:"when"`
+	Then triageAction   `json:"then"`
+	Stop bool           `json:"stop,omitempty"`
+}
+
+type triageAction struct {
+	Priority        string         `json:"priority,omitempty"`
+	Assignee        string         `json:"assignee,omitempty"`
+	AddLabels       []string       `json:"add_labels,omitempty"`
+	RemoveLabels    []string       `json:"remove_labels,omitempty"`
+	Fields          map[string]any `json:"fields,omitempty"`
+	Comment         string         `json:"comment,omitempty"`
+	CommentTemplate string         `json:"comment_template,omitempty"`
+}
+
+// loadTriageConfig reads JIRA_TRIAGE_RULES, checking that every rule is
+// named and that its comment template exists and parses.
+func loadTriageConfig() (triageConfig, error) {
+	var cfg triageConfig
+	if _, err := loadJSONSetting("JIRA_TRIAGE_RULES", &cfg); err != nil {
+		return cfg, err
+	}
+	for i, r := range cfg.Rules {
+		if r.Name == "" {
+			return cfg, fmt.Errorf("JIRA_TRIAGE_RULES: rule %d has no name", i+1)
+		}
+		body := r.Then.Comment
+		if t := r.Then.CommentTemplate; t != "" {
+			var ok bool
+			if body, ok = cfg.CommentTemplates[t]; !ok {
+				return cfg, fmt.Errorf("JIRA_TRIAGE_RULES: rule %q: unknown comment template %q", r.Name, t)
+			}
+		}
+		if _, err := template.New("comment").Parse(body); err != nil {
+			return cfg, fmt.Errorf("JIRA_TRIAGE_RULES: rule %q: %w", r.Name, err)
+		}
+	}
+	return cfg, nil
+}
+
+var triageShorthands = map[string]string{
+	"type":      "issuetype",
+	"component": "components",
+	"label":     "labels",
+	"project":   "project",
+	"status":    "status",
+	"priority":  "priority",
+	"assignee":  "assignee",
+	"reporter":  "reporter",
+}
+
+// valueStrings flattens a field value into the strings a condition can
+// match: names, keys, values, and display names of objects and lists.
+func valueStrings(v any) []string {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{t}
+	case float64:
+		return []string{strconv.FormatFloat(t, 'f', -1, 64)}
+	case bool:
+		return []string{strconv.FormatBool(t)}
+	case []string:
+		return t
+	case []any:
+		var out []string
+		for _, e := range t {
+			out = append(out, valueStrings(e)...)
+		}
+		return out
+	case map[string]any:
+		var out []string
+		for _, k := range []string{"name", "key", "value", "displayName", "accountId", "emailAddress"} {
+			if s, ok := t[k].(string); ok && s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return []string{fmt.Sprint(v)}
+}
+
+func conditionMatches(value any, want string) bool {
+	if neg, ok := strings.CutPrefix(want, "!"); ok {
+		return !conditionMatches(value, neg)
+	}
+	have := valueStrings(value)
+	if strings.EqualFold(want, "empty") {
+		return len(have) == 0
+	}
+	return containsFold(have, want)
+}
+
+// conditionField resolves a condition name to the id of the field it reads.
+func (c *JiraClient) conditionField(ctx context.Context, name string) (string, error) {
+	if strings.EqualFold(name, "status_category") {
+		return "status", nil
+	}
+	if id, ok := triageShorthands[strings.ToLower(name)]; ok {
+		return id, nil
+	}
+	return c.ResolveFieldID(ctx, name)
+}
+
+// conditionsMatch reports whether every condition of a rule holds for iss;
+// any of a list of values may match.
+func (c *JiraClient) conditionsMatch(ctx context.Context, rule string, when map[string]any, iss *JiraIssue) (bool, error) {
+	for name, want := range when {
+		id, err := c.conditionField(ctx, name)
+		if err != nil {
+			return false, fmt.Errorf("rule %q: %w", rule, err)
+		}
+		value := iss.Fields[id]
+		if strings.EqualFold(name, "status_category") {
+			value = fieldPath(iss.Fields, "status", "statusCategory")
+		}
+		wants := valueStrings(want)
+		if len(wants) == 0 {
+			return false, fmt.Errorf("rule %q: condition %q has no value", rule, name)
+		}
+		matched := false
+		for _, w := range wants {
+			if conditionMatches(value, w) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// triagePlan is what the rules would do to one issue.
+type triagePlan struct {
+	Key      string         `json:"key"`
+	Summary  string         `json:"summary,omitempty"`
+	Rules    []string       `json:"matched_rules"`
+	Fields   map[string]any `json:"set_fields,omitempty"`
+	Add      []string       `json:"add_labels,omitempty"`
+	Remove   []string       `json:"remove_labels,omitempty"`
+	Assignee string         `json:"assignee,omitempty"`
+	Comments []string       `json:"comments,omitempty"`
+	Applied  bool           `json:"applied,omitempty"`
+	Error    string         `json:"error,omitempty"`
+
+	assigneeID   string
+	commentRules []string // the rule behind each comment
+	triaged      triageRecord
+}
+
+// triageRecord is the value of an issue's mcp.triage property.
+type triageRecord struct {
+	Commented []string `json:"commented"` // rules that have commented
+}
+
+func (c *JiraClient) triageRecord(ctx context.Context, key string) (triageRecord, error) {
+	var prop struct {
+		Value triageRecord `json:"value"`
+	}
+	err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/issue/"+url.PathEscape(key)+"/properties/"+triageProperty, nil, &prop)
+	var je *JiraError
+	if errors.As(err, &je) && je.StatusCode == http.StatusNotFound {
+		return triageRecord{}, nil
+	}
+	return prop.Value, err
+}
+
+func (p *triagePlan) empty() bool {
+	return len(p.Fields) == 0 && len(p.Add) == 0 && len(p.Remove) == 0 && p.Assignee == "" && len(p.Comments) == 0
+}
+
+// planTriage evaluates the rules against iss. Changes the issue already
+// reflects (same priority or assignee, label present, rule has commented)
+// are left out. users caches assignees resolved for earlier issues.
+func (c *JiraClient) planTriage(ctx context.Context, cfg *triageConfig, iss *JiraIssue, users map[string]*JiraUser) (*triagePlan, error) {
+	plan := &triagePlan{Key: iss.Key, Summary: fieldString(iss.Fields, "summary"), Rules: []string{}}
+	labels := fieldStrings(iss.Fields, "labels")
+	for _, r := range cfg.Rules {
+		ok, err := c.conditionsMatch(ctx, r.Name, r.When, iss)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		plan.Rules = append(plan.Rules, r.Name)
+		a := r.Then
+		fields, err := c.resolveFieldKeys(ctx, a.Fields)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+		if a.Priority != "" && !strings.EqualFold(fieldString(iss.Fields, "priority", "name"), a.Priority) {
+			if fields == nil {
+				fields = map[string]any{}
+			}
+			fields["priority"] = map[string]any{"name": a.Priority}
+		}
+		for k, v := range fields {
+			if plan.Fields == nil {
+				plan.Fields = map[string]any{}
+			}
+			plan.Fields[k] = v
+		}
+		for _, l := range a.AddLabels {
+			if !containsFold(labels, l) && !containsFold(plan.Add, l) {
+				plan.Add = append(plan.Add, l)
+			}
+		}
+		for _, l := range a.RemoveLabels {
+			if containsFold(labels, l) && !containsFold(plan.Remove, l) {
+				plan.Remove = append(plan.Remove, l)
+			}
+		}
+		if a.Assignee != "" {
+			u, ok := users[a.Assignee]
+			if !ok {
+				if u, err = c.ResolveUser(ctx, a.Assignee); err != nil {
+					return nil, fmt.Errorf("rule %q: assignee: %w", r.Name, err)
+				}
+				users[a.Assignee] = u
+			}
+			plan.Assignee, plan.assigneeID = "", ""
+			if id := u.id(); id != fieldString(iss.Fields, "assignee", "accountId") && id != fieldString(iss.Fields, "assignee", "name") {
+				plan.Assignee, plan.assigneeID = a.Assignee, id
+			}
+		}
+		body := a.Comment
+		if a.CommentTemplate != "" {
+			tpl, ok := cfg.CommentTemplates[a.CommentTemplate]
+			if !ok {
+				return nil, fmt.Errorf("rule %q: unknown comment template %q", r.Name, a.CommentTemplate)
+			}
+			body = tpl
+		}
+		if body != "" {
+			text, err := renderTriageComment(body, iss, r.Name)
+			if err != nil {
+				return nil, fmt.Errorf("rule %q: %w", r.Name, err)
+			}
+			plan.Comments = append(plan.Comments, text)
+			plan.commentRules = append(plan.commentRules, r.Name)
+		}
+		if r.Stop {
+			break
+		}
+	}
+	if len(plan.Comments) > 0 {
+		rec, err := c.triageRecord(ctx, iss.Key)
+		if err != nil {
+			return nil, err
+		}
+		plan.triaged = rec
+		var comments, rules []string
+		for i, rule := range plan.commentRules {
+			if !slices.Contains(rec.Commented, rule) {
+				comments, rules = append(comments, plan.Comments[i]), append(rules, rule)
+			}
+		}
+		plan.Comments, plan.commentRules = comments, rules
+	}
+	return plan, nil
+}
+
+func renderTriageComment(body string, iss *JiraIssue, rule string) (string, error) {
+	t, err := template.New("comment").Option("missingkey=zero").Parse(body)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	err = t.Execute(&buf, map[string]any{
+		"Key":     iss.Key,
+		"Summary": fieldString(iss.Fields, "summary"),
+		"Rule":    rule,
+		"Fields":  iss.Fields,
+	})
+	return buf.String(), err
+}
+
+// applyTriage carries out a plan.
+func (c *JiraClient) applyTriage(ctx context.Context, plan *triagePlan) error {
+	if len(plan.Fields) > 0 || len(plan.Add) > 0 || len(plan.Remove) > 0 {
+		var update map[string]any
+		if len(plan.Add) > 0 || len(plan.Remove) > 0 {
+			var ops []any
+			for _, l := range plan.Add {
+				ops = append(ops, map[string]any{"add": l})
+			}
+			for _, l := range plan.Remove {
+				ops = append(ops, map[string]any{"remove": l})
+			}
+			update = map[string]any{"labels": ops}
+		}
+		if err := c.UpdateIssue(ctx, plan.Key, plan.Fields, update); err != nil {
+			return err
+		}
+	}
+	if plan.assigneeID != "" {
+		if err := c.AssignIssue(ctx, plan.Key, ptr(plan.assigneeID)); err != nil {
+			return err
+		}
+	}
+	for i, body := range plan.Comments {
+		if _, err := c.AddComment(ctx, plan.Key, body); err != nil {
+			return err
+		}
+		plan.triaged.Commented = append(plan.triaged.Commented, plan.commentRules[i])
+		if err := c.SetIssueProperty(ctx, plan.Key, triageProperty, plan.triaged); err != nil {
+			return fmt.Errorf("comment added, but recording it failed: %w", err)
+		}
+	}
+	return nil
+}
+
+func registerTriageTools(server *mcp.Server, jc *JiraClient) {
+	cfg := jc.triage
+
+	// apply_triage_rules(jql, max_issues?, apply?, confirm?)
+	type triageArgs struct {
+		JQL       string `json:"jql" jsonschema:"Issues to triage, e.g. project = PROJ AND created >= -1d"`
+		MaxIssues int    `json:"max_issues,omitempty" jsonschema:"Maximum issues to evaluate (default 50, max 500)"`
+		Apply     bool   `json:"apply,omitempty" jsonschema:"Carry out the planned changes after user confirmation; otherwise only preview"`
+		Confirm   bool   `json:"confirm,omitempty" jsonschema:"Only for clients without elicitation: set once the user has approved applying the changes"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "apply_triage_rules",
+		Title:       "Apply Triage Rules",
+		Description: fmt.Sprintf("Evaluate the %d configured triage rules against issues matching JQL and preview (or apply) the resulting priority, assignee, label, field, and comment changes", len(cfg.Rules)),
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args triageArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=apply_triage_rules args={jql:%q,max:%d,apply:%t}", args.JQL, args.MaxIssues, args.Apply)
+		if len(cfg.Rules) == 0 {
+			return nil, nil, errors.New("no triage rules configured (set JIRA_TRIAGE_RULES)")
+		}
+		limit := args.MaxIssues
+		if limit <= 0 {
+			limit = 50
+		}
+		issues, err := jc.SearchAll(ctx, args.JQL, nil, min(limit, 500))
+		if err != nil {
+			debugf("tool=apply_triage_rules error=%v", err)
+			return nil, nil, err
+		}
+		plans := []*triagePlan{}
+		users := map[string]*JiraUser{}
+		for i := range issues {
+			plan, err := jc.planTriage(ctx, &cfg, &issues[i], users)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !plan.empty() {
+				plans = append(plans, plan)
+			}
+		}
+		res := map[string]any{"jql": args.JQL, "evaluated": len(issues), "changes": plans, "dry_run": true}
+		if args.Apply && len(plans) > 0 {
+			approved, err := approveAction(ctx, req.Session, args.Confirm, fmt.Sprintf("Apply triage changes to %d issues?", len(plans)))
+			if errors.Is(err, errConfirmationUnavailable) {
+				res["note"] = err.Error()
+			} else if err != nil {
+				return nil, nil, err
+			} else if !approved {
+				res["note"] = "changes not applied: the user declined"
+			}
+			if approved {
+				res["dry_run"] = false
+				for _, plan := range plans {
+					if err := jc.applyTriage(ctx, plan); err != nil {
+						debugf("tool=apply_triage_rules %s error=%v", plan.Key, err)
+						plan.Error = err.Error()
+						continue
+					}
+					plan.Applied = true
+				}
+			}
+		}
+		return &mcp.CallToolResult{StructuredContent: res}, nil, nil
+	})
+}
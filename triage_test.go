@@ -0,0 +1,72 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestLoadTriageConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   string
+		wantErr bool
+	}{
+		{"unset", "", false},
+		{"valid", `{"rules": [{"name": "ack", "when": {"type": "Bug"}, "then": {"comment_template": "ack"}}], "comment_templates": {"ack": "Thanks, {{.Key}}"}}`, false},
+		{"malformed", `{"rules": [`, true},
+		{"unnamed rule", `{"rules": [{"when": {"type": "Bug"}, "then": {"priority": "High"}}]}`, true},
+		{"unknown template", `{"rules": [{"name": "ack", "then": {"comment_template": "nope"}}]}`, true},
+		{"bad template", `{"rules": [{"name": "ack", "then": {"comment": "{{.Key"}}]}`, true},
+	}
+	for _, tt := range tests {
+		t.Setenv("JIRA_TRIAGE_RULES", tt.rules)
+		if _, err := loadTriageConfig(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: loadTriageConfig error = %v, want error %t", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestPlanTriageSkipsDoneChanges(t *testing.T) {
+	const owner = "5b10ac8d82e05b22cc7d4ef5"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/3/issue/PROJ-1/properties/" + triageProperty:
+			w.Write([]byte(`{"key": "mcp.triage", "value": {"commented": ["ack"]}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	jc := &JiraClient{BaseURL: srv.URL, Client: srv.Client(), site: "default"}
+
+	cfg := triageConfig{Rules: []triageRule{
+		{Name: "ack", When: map[string]any{"label": "customer"}, Then: triageAction{Assignee: owner, Comment: "Thanks!"}},
+		{Name: "escalate", When: map[string]any{"label": "customer"}, Then: triageAction{Comment: "Escalated {{.Key}}"}},
+	}}
+	tests := []struct {
+		name         string
+		key          string
+		assignee     string
+		wantAssignee string
+		wantComments []string
+	}{
+		{"assigned and acked", "PROJ-1", owner, "", []string{"Escalated PROJ-1"}},
+		{"untriaged", "PROJ-2", "", owner, []string{"Thanks!", "Escalated PROJ-2"}},
+	}
+	for _, tt := range tests {
+		fields := map[string]any{"labels": []any{"customer"}}
+		if tt.assignee != "" {
+			fields["assignee"] = map[string]any{"accountId": tt.assignee}
+		}
+		plan, err := jc.planTriage(context.Background(), &cfg, &JiraIssue{Key: tt.key, Fields: fields}, map[string]*JiraUser{})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if plan.Assignee != tt.wantAssignee || !reflect.DeepEqual(plan.Comments, tt.wantComments) {
+			t.Errorf("%s: plan assignee %q comments %q, want %q %q", tt.name, plan.Assignee, plan.Comments, tt.wantAssignee, tt.wantComments)
+		}
+	}
+}
@@ -0,0 +1,379 @@
+package jira
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Chunked attachment uploads ----
+//
+// A file too large for one MCP message is uploaded in pieces: begin_upload
+// opens a temp file, append_chunk adds base64 chunks at the expected offset,
+// and commit_upload checks the size and SHA-256 before sending the whole
+// file to Jira as one multipart request. Uploads belong to the session that
+// began them and are dropped after an hour without a chunk.
+// JIRA_UPLOAD_MAX_BYTES caps the file size (default 100 MiB).
+
+const (
+	defaultUploadMaxBytes = 100 << 20
+	uploadIdleTimeout     = time.Hour
+)
+
+func uploadMaxBytes() int64 {
+	if v, err := strconv.ParseInt(os.Getenv("JIRA_UPLOAD_MAX_BYTES"), 10, 64); err == nil && v > 0 {
+		return v
+	}
+	return defaultUploadMaxBytes
+}
+
+type pendingUpload struct {
+	ID       string
+	Session  string
+	Key      string
+	Filename string
+	Size     int64  // declared size, or 0
+	SHA256   string // declared checksum, or ""
+	file     *os.File
+	hash     hash.Hash
+	received int64
+	touched  time.Time
+	closed   bool // taken for commit; appends are refused
+}
+
+func (u *pendingUpload) discard() {
+	u.file.Close()
+	os.Remove(u.file.Name())
+}
+
+type uploadStore struct {
+	mu      sync.Mutex
+	uploads map[string]*pendingUpload
+}
+
+// expire drops uploads idle for longer than uploadIdleTimeout. Callers hold
+// s.mu.
+func (s *uploadStore) expire() {
+	for id, u := range s.uploads {
+		if time.Since(u.touched) > uploadIdleTimeout {
+			debugf("upload %s for %s expired after %d bytes", id, u.Key, u.received)
+			u.discard()
+			delete(s.uploads, id)
+		}
+	}
+}
+
+func (s *uploadStore) begin(session, key, filename string, size int64, sum string) (*pendingUpload, error) {
+	f, err := os.CreateTemp("", "jira-upload-*")
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	u := &pendingUpload{
+		ID: hex.EncodeToString(b), Session: session, Key: key, Filename: filename,
+		Size: size, SHA256: sum, file: f, hash: sha256.New(), touched: time.Now(),
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	if s.uploads == nil {
+		s.uploads = map[string]*pendingUpload{}
+	}
+	s.uploads[u.ID] = u
+	return u, nil
+}
+
+// get returns session's upload id; an upload is invisible to other sessions.
+func (s *uploadStore) get(session, id string) (*pendingUpload, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lookup(session, id)
+}
+
+// lookup is get for callers that hold s.mu.
+func (s *uploadStore) lookup(session, id string) (*pendingUpload, error) {
+	s.expire()
+	u := s.uploads[id]
+	if u == nil || u.Session != session {
+		return nil, fmt.Errorf("no upload %q in this session (uploads expire after %s idle)", id, uploadIdleTimeout)
+	}
+	return u, nil
+}
+
+// take removes session's upload id from the store and hands it to the
+// caller, which must discard it. Chunks still arriving for it are refused,
+// so the file cannot change while it is checked and sent.
+func (s *uploadStore) take(session, id string) (*pendingUpload, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u, err := s.lookup(session, id)
+	if err != nil {
+		return nil, err
+	}
+	u.closed = true
+	delete(s.uploads, id)
+	return u, nil
+}
+
+// appendChunk writes data at offset, which must be where the previous chunk
+// ended.
+func (s *uploadStore) appendChunk(u *pendingUpload, offset int64, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if u.closed {
+		return fmt.Errorf("upload %q is already being committed", u.ID)
+	}
+	if offset != u.received {
+		return fmt.Errorf("chunk at offset %d, expected %d", offset, u.received)
+	}
+	if limit := uploadMaxBytes(); u.received+int64(len(data)) > limit {
+		return fmt.Errorf("upload would exceed the %d byte limit", limit)
+	}
+	if u.Size > 0 && u.received+int64(len(data)) > u.Size {
+		return fmt.Errorf("upload would exceed its declared size of %d bytes", u.Size)
+	}
+	if _, err := u.file.Write(data); err != nil {
+		return err
+	}
+	u.hash.Write(data)
+	u.received += int64(len(data))
+	u.touched = time.Now()
+	return nil
+}
+
+// AddAttachment attaches a file to an issue. The content is streamed, not
+// buffered; if it is an io.Seeker its length is sent up front and it can be
+// resent when a request has to be retried.
+func (c *JiraClient) AddAttachment(ctx context.Context, key, filename string, content io.Reader) ([]JiraAttachment, error) {
+	// form fixes the boundary for every attempt; writing the envelope
+	// without the content measures it.
+	var envelope countingWriter
+	form := multipart.NewWriter(&envelope)
+	if _, err := form.CreateFormFile("file", filename); err != nil {
+		return nil, err
+	}
+	form.Close()
+	start, length := int64(-1), int64(-1)
+	if sk, ok := content.(io.Seeker); ok {
+		pos, err := sk.Seek(0, io.SeekCurrent)
+		if err == nil {
+			var end int64
+			if end, err = sk.Seek(0, io.SeekEnd); err == nil {
+				_, err = sk.Seek(pos, io.SeekStart)
+			}
+			if err != nil {
+				return nil, err
+			}
+			start, length = pos, int64(envelope)+end-pos
+		}
+	}
+	var (
+		prev *io.PipeReader
+		done chan struct{} // closed when prev's writer has stopped
+	)
+	defer func() {
+		// Content must not be read once we return.
+		if prev != nil {
+			prev.Close()
+			<-done
+		}
+	}()
+	open := func() (io.Reader, error) {
+		if prev != nil {
+			// Stop the last attempt's writer before rewinding what it reads.
+			prev.Close()
+			<-done
+			if start < 0 {
+				return nil, errors.New("cannot resend the attachment: its content cannot be rewound")
+			}
+			if _, err := content.(io.Seeker).Seek(start, io.SeekStart); err != nil {
+				return nil, err
+			}
+		}
+		pr, pw := io.Pipe()
+		prev, done = pr, make(chan struct{})
+		go func(done chan struct{}) {
+			defer close(done)
+			mw := multipart.NewWriter(pw)
+			mw.SetBoundary(form.Boundary())
+			part, err := mw.CreateFormFile("file", filename)
+			if err == nil {
+				_, err = io.Copy(part, content)
+			}
+			if err == nil {
+				err = mw.Close()
+			}
+			pw.CloseWithError(err)
+		}(done)
+		return pr, nil
+	}
+	path := "/rest/api/3/issue/" + url.PathEscape(key) + "/attachments"
+	resp, err := c.sendBody(ctx, http.MethodPost, path, &requestBody{open: open, length: length}, form.FormDataContentType())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, &JiraError{Method: http.MethodPost, Path: path, Status: resp.Status, StatusCode: resp.StatusCode, Body: string(b)}
+	}
+	var out []JiraAttachment
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// countingWriter counts the bytes written to it.
+type countingWriter int64
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	*w += countingWriter(len(p))
+	return len(p), nil
+}
+
+type uploadStatus struct {
+	UploadID   string `json:"upload_id"`
+	Key        string `json:"key"`
+	Filename   string `json:"filename"`
+	Received   int64  `json:"received"`
+	Size       int64  `json:"size,omitempty"`
+	NextOffset int64  `json:"next_offset"`
+	MaxBytes   int64  `json:"max_bytes"`
+}
+
+func (u *pendingUpload) status() uploadStatus {
+	return uploadStatus{
+		UploadID: u.ID, Key: u.Key, Filename: u.Filename, Received: u.received,
+		Size: u.Size, NextOffset: u.received, MaxBytes: uploadMaxBytes(),
+	}
+}
+
+func registerUploadTools(server *mcp.Server, jc *JiraClient) {
+	// begin_upload(key, filename, size?, sha256?)
+	type beginUploadArgs struct {
+		Key      string `json:"key" jsonschema:"Issue to attach the file to"`
+		Filename string `json:"filename" jsonschema:"File name for the attachment"`
+		Size     int64  `json:"size,omitempty" jsonschema:"Total size in bytes, if known; checked at commit"`
+		SHA256   string `json:"sha256,omitempty" jsonschema:"Hex SHA-256 of the whole file, if known now; otherwise pass it to commit_upload"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "begin_upload",
+		Title:       "Begin Upload",
+		Description: "Start a chunked attachment upload for a file too large for one message. Send the content with append_chunk, then attach it with commit_upload",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args beginUploadArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=begin_upload args={key:%q,filename:%q,size:%d}", args.Key, args.Filename, args.Size)
+		if args.Key == "" || strings.TrimSpace(args.Filename) == "" {
+			return nil, nil, errors.New("key and filename are required")
+		}
+		if limit := uploadMaxBytes(); args.Size > limit {
+			return nil, nil, fmt.Errorf("file is %d bytes, over the %d byte upload limit", args.Size, limit)
+		}
+		sum := strings.ToLower(strings.TrimSpace(args.SHA256))
+		if _, err := hex.DecodeString(sum); err != nil || (sum != "" && len(sum) != sha256.Size*2) {
+			return nil, nil, errors.New("sha256 must be 64 hex digits")
+		}
+		u, err := jc.uploads.begin(sessionKey(req.Session), args.Key, args.Filename, args.Size, sum)
+		if err != nil {
+			debugf("tool=begin_upload error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: u.status()}, nil, nil
+	})
+
+	// append_chunk(upload_id, offset, data)
+	type appendChunkArgs struct {
+		UploadID string `json:"upload_id" jsonschema:"Upload id from begin_upload"`
+		Offset   int64  `json:"offset" jsonschema:"Byte offset of this chunk in the file; must equal next_offset from the previous call"`
+		Data     string `json:"data" jsonschema:"Chunk content, base64-encoded"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "append_chunk",
+		Title:       "Append Chunk",
+		Description: "Append the next base64-encoded chunk to an upload. Chunks must arrive in order; a chunk at the wrong offset is rejected with the expected one",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args appendChunkArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=append_chunk args={upload:%q,offset:%d,len:%d}", args.UploadID, args.Offset, len(args.Data))
+		u, err := jc.uploads.get(sessionKey(req.Session), args.UploadID)
+		if err != nil {
+			return nil, nil, err
+		}
+		data, err := base64.StdEncoding.DecodeString(args.Data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("data is not valid base64: %w", err)
+		}
+		if err := jc.uploads.appendChunk(u, args.Offset, data); err != nil {
+			debugf("tool=append_chunk error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: u.status()}, nil, nil
+	})
+
+	// commit_upload(upload_id, sha256?)
+	type commitUploadArgs struct {
+		UploadID string `json:"upload_id" jsonschema:"Upload id from begin_upload"`
+		SHA256   string `json:"sha256,omitempty" jsonschema:"Hex SHA-256 of the whole file; required unless given to begin_upload"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "commit_upload",
+		Title:       "Commit Upload",
+		Description: "Finish a chunked upload: verify its size and SHA-256, then attach the file to the issue. The upload is discarded either way",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args commitUploadArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=commit_upload args={upload:%q}", args.UploadID)
+		u, err := jc.uploads.take(sessionKey(req.Session), args.UploadID)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer u.discard()
+		want := strings.ToLower(strings.TrimSpace(args.SHA256))
+		if want == "" {
+			want = u.SHA256
+		}
+		if want == "" {
+			return nil, nil, errors.New("sha256 is required (none was given to begin_upload); the upload was discarded")
+		}
+		if u.SHA256 != "" && want != u.SHA256 {
+			return nil, nil, errors.New("sha256 differs from the one given to begin_upload; the upload was discarded")
+		}
+		if u.Size > 0 && u.received != u.Size {
+			return nil, nil, fmt.Errorf("received %d of %d declared bytes; the upload was discarded", u.received, u.Size)
+		}
+		if got := hex.EncodeToString(u.hash.Sum(nil)); got != want {
+			return nil, nil, fmt.Errorf("checksum mismatch: received %d bytes with sha256 %s, expected %s; the upload was discarded", u.received, got, want)
+		}
+		if _, err := u.file.Seek(0, io.SeekStart); err != nil {
+			return nil, nil, err
+		}
+		atts, err := jc.AddAttachment(ctx, u.Key, u.Filename, u.file)
+		if err != nil {
+			debugf("tool=commit_upload error=%v", err)
+			return nil, nil, err
+		}
+		site := jc.siteQuery(ctx)
+		views := make([]attachmentView, len(atts))
+		for i, a := range atts {
+			views[i] = attachmentView{
+				ID: a.ID, Filename: a.Filename, MimeType: a.MimeType, Size: a.Size,
+				Created: a.Created, Author: fieldString(a.Author, "displayName"), URI: "jira://attachment/" + a.ID + site,
+			}
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"key": u.Key, "sha256": want, "attachments": views}}, nil, nil
+	})
+}
@@ -0,0 +1,362 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Users and assignment ----
+
+type JiraUser struct {
+	AccountID    string `json:"accountId"`
+	Name         string `json:"name,omitempty"` // username; Server/Data Center only
+	AccountType  string `json:"accountType,omitempty"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress,omitempty"`
+	Active       bool   `json:"active"`
+}
+
+func (c *JiraClient) SearchUsers(ctx context.Context, query string, max int) ([]JiraUser, error) {
+	if max <= 0 || max > 1000 {
+		max = 50
+	}
+	q := url.Values{}
+	q.Set("query", query)
+	if c.useAPIV2(ctx) {
+		q.Set("username", query) // Server/DC searches by username, name, or email
+	}
+	q.Set("maxResults", fmt.Sprintf("%d", max))
+	var out []JiraUser
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/user/search?"+q.Encode(), nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// activeUsers drops deactivated accounts unless inactive is set. The
+// result is never nil, so it encodes as a list.
+func activeUsers(users []JiraUser, inactive bool) []JiraUser {
+	out := []JiraUser{}
+	for _, u := range users {
+		if u.Active || inactive {
+			out = append(out, u)
+		}
+	}
+	return out
+}
+
+// accountIDRe matches Atlassian account ids: legacy 24-char hex ids and the
+// newer "<number>:<uuid>" form.
+var accountIDRe = regexp.MustCompile(`^([0-9a-f]{24}|\d+:[0-9a-f-]{36})$`)
+
+// pickUser chooses the one user matching who among candidates, preferring
+// exact email or display name matches.
+func pickUser(who string, candidates []JiraUser) (*JiraUser, error) {
+	var active []JiraUser
+	for _, u := range candidates {
+		if u.Active && (u.AccountType == "" || u.AccountType == "atlassian") {
+			active = append(active, u)
+		}
+	}
+	for i, u := range active {
+		if strings.EqualFold(u.EmailAddress, who) || strings.EqualFold(u.DisplayName, who) {
+			return &active[i], nil
+		}
+	}
+	switch len(active) {
+	case 0:
+		return nil, fmt.Errorf("no active user matches %q", who)
+	case 1:
+		return &active[0], nil
+	}
+	names := make([]string, 0, len(active))
+	for _, u := range active {
+		names = append(names, fmt.Sprintf("%s (%s)", u.DisplayName, u.AccountID))
+	}
+	return nil, fmt.Errorf("%q matches several users: %s; pass an accountId", who, strings.Join(names, ", "))
+}
+
+// ResolveUser turns an accountId, email address, or display name into a
+// user. Ambiguous names are an error listing the candidates.
+func (c *JiraClient) ResolveUser(ctx context.Context, who string) (*JiraUser, error) {
+	who = strings.TrimSpace(who)
+	if who == "" {
+		return nil, errors.New("empty user")
+	}
+	if accountIDRe.MatchString(who) {
+		return &JiraUser{AccountID: who, Active: true}, nil
+	}
+	users, err := c.SearchUsers(ctx, who, 20)
+	if err != nil {
+		return nil, err
+	}
+	return pickUser(who, users)
+}
+
+// Myself returns the user the server authenticates as.
+func (c *JiraClient) Myself(ctx context.Context) (*JiraUser, error) {
+	var out JiraUser
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/myself", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// jiraSelf is the authenticated user as /myself describes them.
+type jiraSelf struct {
+	JiraUser
+	TimeZone string `json:"timeZone,omitempty"`
+	Locale   string `json:"locale,omitempty"`
+	Groups   struct {
+		Items []struct {
+			Name string `json:"name"`
+		} `json:"items"`
+	} `json:"groups"`
+}
+
+// CurrentUser returns the authenticated user with their time zone, locale,
+// and groups.
+func (c *JiraClient) CurrentUser(ctx context.Context) (*jiraSelf, error) {
+	var out jiraSelf
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/myself?expand=groups", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AssignableUsers returns users who can be assigned key, matching query (an
+// accountId, or a prefix of a name or email; empty lists everyone).
+func (c *JiraClient) AssignableUsers(ctx context.Context, key, query string, max int) ([]JiraUser, error) {
+	return c.assignableSearch(ctx, "issueKey", key, query, max)
+}
+
+// ProjectAssignableUsers is AssignableUsers for new issues in project.
+func (c *JiraClient) ProjectAssignableUsers(ctx context.Context, project, query string, max int) ([]JiraUser, error) {
+	return c.assignableSearch(ctx, "project", project, query, max)
+}
+
+func (c *JiraClient) assignableSearch(ctx context.Context, scope, id, query string, max int) ([]JiraUser, error) {
+	q := url.Values{}
+	q.Set(scope, id)
+	switch {
+	case accountIDRe.MatchString(query):
+		q.Set("accountId", query)
+	case query != "" && c.useAPIV2(ctx):
+		q.Set("username", query)
+	case query != "":
+		q.Set("query", query)
+	}
+	q.Set("maxResults", fmt.Sprintf("%d", max))
+	var out []JiraUser
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/user/assignable/search?"+q.Encode(), nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// assignmentCandidates suggests assignable users for key when who cannot be
+// assigned: those matching who's name or email first, else anyone.
+func (c *JiraClient) assignmentCandidates(ctx context.Context, key, who string, u *JiraUser) []JiraUser {
+	var queries []string
+	for _, q := range []string{u.DisplayName, who} {
+		if q != "" && !accountIDRe.MatchString(q) {
+			q, _, _ = strings.Cut(q, "@")
+			queries = append(queries, q)
+		}
+	}
+	queries = append(queries, "")
+	for _, q := range queries {
+		users, err := c.AssignableUsers(ctx, key, q, 10)
+		if err != nil {
+			debugf("assignable search %q: %v", q, err)
+			return nil
+		}
+		if len(users) > 0 {
+			return users
+		}
+	}
+	return nil
+}
+
+// AssignIssue sets the assignee. A nil accountID unassigns; "-1" selects
+// the project's default assignee.
+func (c *JiraClient) AssignIssue(ctx context.Context, key string, accountID *string) error {
+	field := "accountId"
+	if c.useAPIV2(ctx) {
+		field = "name"
+	}
+	body := map[string]any{field: nil}
+	if accountID != nil {
+		body[field] = *accountID
+	}
+	return c.doJSON(ctx, http.MethodPut, "/rest/api/3/issue/"+url.PathEscape(key)+"/assignee", body, nil)
+}
+
+func registerUserTools(server *mcp.Server, jc *JiraClient) {
+	// assign_issue(key, assignee?, mode?)
+	type assignArgs struct {
+		Key      string `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+		Assignee string `json:"assignee,omitempty" jsonschema:"accountId, email address, or display name (mode user)"`
+		Mode     string `json:"mode,omitempty" jsonschema:"user (default), unassign, or default (the project's default assignee)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "assign_issue",
+		Title:       "Assign Issue",
+		Description: "Assign an issue to a user (by accountId, email, or display name), unassign it, or hand it to the project's default assignee. Users who cannot be assigned the issue are refused up front with assignable candidates",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args assignArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=assign_issue args={key:%q,assignee:%q,mode:%q}", args.Key, args.Assignee, args.Mode)
+		result := map[string]any{"key": args.Key}
+		var accountID *string
+		switch args.Mode {
+		case "", "user":
+			if args.Assignee == "" {
+				return nil, nil, errors.New("assignee is required (or use mode unassign/default)")
+			}
+			u, err := jc.ResolveUser(ctx, args.Assignee)
+			if err != nil {
+				return nil, nil, err
+			}
+			ok, err := jc.AssignableUsers(ctx, args.Key, u.id(), 1)
+			if err != nil {
+				debugf("tool=assign_issue assignable error=%v", err)
+				return nil, nil, err
+			}
+			if len(ok) == 0 {
+				// Assigning would fail with an opaque 400; offer who can
+				// take the issue instead.
+				name := args.Assignee
+				if u.DisplayName != "" {
+					name = u.DisplayName
+				}
+				return &mcp.CallToolResult{IsError: true, StructuredContent: map[string]any{
+					"error":      "not_assignable",
+					"message":    fmt.Sprintf("%s cannot be assigned %s (not in a project role that may be assigned); pick one of the candidates", name, args.Key),
+					"key":        args.Key,
+					"assignee":   u,
+					"candidates": jc.assignmentCandidates(ctx, args.Key, args.Assignee, u),
+				}}, nil, nil
+			}
+			u = &ok[0]
+			accountID = ptr(u.id())
+			result["assignee"] = u
+		case "unassign":
+			result["assignee"] = nil
+		case "default":
+			def := "-1"
+			accountID = &def
+			result["assignee"] = "default"
+		default:
+			return nil, nil, fmt.Errorf("unknown mode %q (valid: user, unassign, default)", args.Mode)
+		}
+		if err := jc.AssignIssue(ctx, args.Key, accountID); err != nil {
+			debugf("tool=assign_issue error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: result}, nil, nil
+	})
+
+	// search_users(query, max_results?, include_inactive?)
+	type searchUsersArgs struct {
+		Query           string `json:"query" jsonschema:"Prefix of a display name or email address"`
+		MaxResults      int    `json:"max_results,omitempty" jsonschema:"Default 20"`
+		IncludeInactive bool   `json:"include_inactive,omitempty" jsonschema:"Include deactivated accounts"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "search_users",
+		Title:       "Search Users",
+		Description: "Find users by name or email prefix, to turn a person's name into an accountId",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args searchUsersArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=search_users args={query:%q,max:%d}", args.Query, args.MaxResults)
+		if strings.TrimSpace(args.Query) == "" {
+			return nil, nil, errors.New("query is required")
+		}
+		limit := args.MaxResults
+		if limit <= 0 {
+			limit = 20
+		}
+		users, err := jc.SearchUsers(ctx, args.Query, limit)
+		if err != nil {
+			debugf("tool=search_users error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"query": args.Query, "users": activeUsers(users, args.IncludeInactive)}}, nil, nil
+	})
+
+	// find_assignable_users(key? | project?, query?, max_results?)
+	type findAssignableArgs struct {
+		Key        string `json:"key,omitempty" jsonschema:"Issue the users must be assignable to"`
+		Project    string `json:"project,omitempty" jsonschema:"Project whose new issues the users must be assignable to (default the focus project, when no key is given)"`
+		Query      string `json:"query,omitempty" jsonschema:"Prefix of a display name or email address, or an accountId; empty lists everyone"`
+		MaxResults int    `json:"max_results,omitempty" jsonschema:"Default 20"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "find_assignable_users",
+		Title:       "Find Assignable Users",
+		Description: "Find users who can be assigned an issue, or new issues in a project, optionally matching a name or email prefix",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args findAssignableArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=find_assignable_users args={key:%q,project:%q,query:%q}", args.Key, args.Project, args.Query)
+		limit := args.MaxResults
+		if limit <= 0 {
+			limit = 20
+		}
+		res := map[string]any{"query": args.Query}
+		var users []JiraUser
+		var err error
+		switch project := args.Project; {
+		case args.Key != "" && project != "":
+			return nil, nil, errors.New("give key or project, not both")
+		case args.Key != "":
+			res["key"] = args.Key
+			users, err = jc.AssignableUsers(ctx, args.Key, args.Query, limit)
+		default:
+			if f := jc.focusFor(req.Session); project == "" && f != nil {
+				project = f.Project
+			}
+			if project == "" {
+				return nil, nil, errors.New("key or project is required (no focus project set)")
+			}
+			res["project"] = project
+			users, err = jc.ProjectAssignableUsers(ctx, project, args.Query, limit)
+		}
+		if err != nil {
+			debugf("tool=find_assignable_users error=%v", err)
+			return nil, nil, err
+		}
+		res["users"] = activeUsers(users, false)
+		return &mcp.CallToolResult{StructuredContent: res}, nil, nil
+	})
+
+	// whoami()
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "whoami",
+		Title:       "Who Am I",
+		Description: "Return the Jira user the server is authenticated as (accountId, name, email, time zone, groups). Also a quick credential check",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args struct{}) (*mcp.CallToolResult, any, error) {
+		debugf("tool=whoami")
+		me, err := jc.CurrentUser(ctx)
+		if err != nil {
+			debugf("tool=whoami error=%v", err)
+			return nil, nil, err
+		}
+		groups := []string{}
+		for _, g := range me.Groups.Items {
+			groups = append(groups, g.Name)
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{
+			"account_id": me.AccountID, "display_name": me.DisplayName, "email": me.EmailAddress,
+			"account_type": me.AccountType, "active": me.Active, "time_zone": me.TimeZone,
+			"locale": me.Locale, "groups": groups, "site": jc.forSite(ctx).BaseURL,
+		}}, nil, nil
+	})
+}
@@ -0,0 +1,307 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Versions and releases ----
+
+type JiraVersion struct {
+	ID          string `json:"id"`
+	Self        string `json:"self,omitempty"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	StartDate   string `json:"startDate,omitempty"`
+	ReleaseDate string `json:"releaseDate,omitempty"`
+	Released    bool   `json:"released"`
+	Archived    bool   `json:"archived"`
+	Overdue     bool   `json:"overdue,omitempty"`
+	ProjectID   int    `json:"projectId,omitempty"`
+}
+
+// status is released, archived, or unreleased.
+func (v *JiraVersion) status() string {
+	switch {
+	case v.Archived:
+		return "archived"
+	case v.Released:
+		return "released"
+	}
+	return "unreleased"
+}
+
+func (c *JiraClient) ListVersions(ctx context.Context, project string) ([]JiraVersion, error) {
+	var out []JiraVersion
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/project/"+url.PathEscape(project)+"/versions", nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *JiraClient) GetVersion(ctx context.Context, id string) (*JiraVersion, error) {
+	var out JiraVersion
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/version/"+url.PathEscape(id), nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *JiraClient) CreateVersion(ctx context.Context, body map[string]any) (*JiraVersion, error) {
+	var out JiraVersion
+	if err := c.doJSON(ctx, http.MethodPost, "/rest/api/3/version", body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *JiraClient) UpdateVersion(ctx context.Context, id string, changes map[string]any) (*JiraVersion, error) {
+	var out JiraVersion
+	if err := c.doJSON(ctx, http.MethodPut, "/rest/api/3/version/"+url.PathEscape(id), changes, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// versionRefs resolves version names (case-insensitive) or ids in a project
+// to the references fixVersions and versions take.
+func (c *JiraClient) versionRefs(ctx context.Context, project string, names []string) ([]any, error) {
+	versions, err := c.ListVersions(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	objs := make([]jiraNamedObject, len(versions))
+	for i, v := range versions {
+		objs[i] = jiraNamedObject{ID: v.ID, Name: v.Name}
+	}
+	return namedRefs("version", project, names, objs)
+}
+
+// findVersion resolves a version name (case-insensitive) or id in a
+// project.
+func (c *JiraClient) findVersion(ctx context.Context, project, ref string) (*JiraVersion, error) {
+	versions, err := c.ListVersions(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(versions))
+	for i := range versions {
+		if strings.EqualFold(versions[i].Name, ref) || versions[i].ID == ref {
+			return &versions[i], nil
+		}
+		names[i] = versions[i].Name
+	}
+	return nil, fmt.Errorf("project %s has no version %q (versions: %s)", project, ref, strings.Join(names, ", "))
+}
+
+// versionDates validates optional YYYY-MM-DD start and release dates into
+// changes.
+func versionDates(changes map[string]any, start, release string) error {
+	for k, d := range map[string]string{"startDate": start, "releaseDate": release} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return fmt.Errorf("%s must be YYYY-MM-DD: %w", k, err)
+		}
+		changes[k] = d
+	}
+	return nil
+}
+
+type versionView struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Status      string `json:"status"`
+	StartDate   string `json:"start_date,omitempty"`
+	ReleaseDate string `json:"release_date,omitempty"`
+	Overdue     bool   `json:"overdue,omitempty"`
+}
+
+func viewVersion(v *JiraVersion) versionView {
+	return versionView{
+		ID: v.ID, Name: v.Name, Description: v.Description, Status: v.status(),
+		StartDate: v.StartDate, ReleaseDate: v.ReleaseDate, Overdue: v.Overdue,
+	}
+}
+
+func registerVersionTools(server *mcp.Server, jc *JiraClient) {
+	// list_versions(project?, status?)
+	type listVersionsArgs struct {
+		Project string `json:"project,omitempty" jsonschema:"Project key (default the focus project)"`
+		Status  string `json:"status,omitempty" jsonschema:"Only versions in this state: unreleased, released, or archived"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_versions",
+		Title:       "List Versions",
+		Description: "List a project's versions with release status and dates",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listVersionsArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_versions args={project:%q,status:%q}", args.Project, args.Status)
+		switch strings.ToLower(args.Status) {
+		case "", "unreleased", "released", "archived":
+		default:
+			return nil, nil, fmt.Errorf("unknown status %q (valid: unreleased, released, archived)", args.Status)
+		}
+		project := args.Project
+		if f := jc.focusFor(req.Session); project == "" && f != nil {
+			project = f.Project
+		}
+		if project == "" {
+			return nil, nil, errors.New("project is required (no focus project set)")
+		}
+		versions, err := jc.ListVersions(ctx, project)
+		if err != nil {
+			debugf("tool=list_versions error=%v", err)
+			return nil, nil, err
+		}
+		views := []versionView{}
+		for i := range versions {
+			if args.Status == "" || strings.EqualFold(versions[i].status(), args.Status) {
+				views = append(views, viewVersion(&versions[i]))
+			}
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"project": project, "versions": views}}, nil, nil
+	})
+
+	// create_version(project?, name, description?, start_date?, release_date?)
+	type createVersionArgs struct {
+		Project     string `json:"project,omitempty" jsonschema:"Project key (default the focus project)"`
+		Name        string `json:"name" jsonschema:"Version name, e.g. 2.4.0"`
+		Description string `json:"description,omitempty"`
+		StartDate   string `json:"start_date,omitempty" jsonschema:"YYYY-MM-DD"`
+		ReleaseDate string `json:"release_date,omitempty" jsonschema:"Planned release date, YYYY-MM-DD"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "create_version",
+		Title:       "Create Version",
+		Description: "Create an unreleased version in a project",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args createVersionArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=create_version args={project:%q,name:%q}", args.Project, args.Name)
+		project := args.Project
+		if f := jc.focusFor(req.Session); project == "" && f != nil {
+			project = f.Project
+		}
+		if project == "" || strings.TrimSpace(args.Name) == "" {
+			return nil, nil, errors.New("project and name are required")
+		}
+		body := map[string]any{"project": project, "name": args.Name}
+		if args.Description != "" {
+			body["description"] = args.Description
+		}
+		if err := versionDates(body, args.StartDate, args.ReleaseDate); err != nil {
+			return nil, nil, err
+		}
+		v, err := jc.CreateVersion(ctx, body)
+		if err != nil {
+			debugf("tool=create_version error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: viewVersion(v)}, nil, nil
+	})
+
+	// update_version(id, name?, description?, start_date?, release_date?)
+	type updateVersionArgs struct {
+		ID          string `json:"id" jsonschema:"Version id (see list_versions)"`
+		Name        string `json:"name,omitempty" jsonschema:"New name"`
+		Description string `json:"description,omitempty" jsonschema:"New description"`
+		StartDate   string `json:"start_date,omitempty" jsonschema:"YYYY-MM-DD"`
+		ReleaseDate string `json:"release_date,omitempty" jsonschema:"YYYY-MM-DD"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "update_version",
+		Title:       "Update Version",
+		Description: "Rename a version or change its description, start date, or release date",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args updateVersionArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=update_version args={id:%q,name:%q}", args.ID, args.Name)
+		changes := map[string]any{}
+		if args.Name != "" {
+			changes["name"] = args.Name
+		}
+		if args.Description != "" {
+			changes["description"] = args.Description
+		}
+		if err := versionDates(changes, args.StartDate, args.ReleaseDate); err != nil {
+			return nil, nil, err
+		}
+		if len(changes) == 0 {
+			return nil, nil, errors.New("nothing to update")
+		}
+		v, err := jc.UpdateVersion(ctx, args.ID, changes)
+		if err != nil {
+			debugf("tool=update_version error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: viewVersion(v)}, nil, nil
+	})
+
+	// release_version(id, release_date?, move_unfixed_to?)
+	type releaseVersionArgs struct {
+		ID            string `json:"id" jsonschema:"Version id (see list_versions)"`
+		ReleaseDate   string `json:"release_date,omitempty" jsonschema:"YYYY-MM-DD (default today, unless the version has a date)"`
+		MoveUnfixedTo string `json:"move_unfixed_to,omitempty" jsonschema:"Id of a version to move the unresolved issues to"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "release_version",
+		Title:       "Release Version",
+		Description: "Mark a version released, optionally moving its unresolved issues to another version",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args releaseVersionArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=release_version args={id:%q,date:%q,move_to:%q}", args.ID, args.ReleaseDate, args.MoveUnfixedTo)
+		v, err := jc.GetVersion(ctx, args.ID)
+		if err != nil {
+			debugf("tool=release_version error=%v", err)
+			return nil, nil, err
+		}
+		changes := map[string]any{"released": true}
+		date := args.ReleaseDate
+		if date == "" && v.ReleaseDate == "" {
+			date = time.Now().Format("2006-01-02")
+		}
+		if err := versionDates(changes, "", date); err != nil {
+			return nil, nil, err
+		}
+		if args.MoveUnfixedTo != "" {
+			target, err := jc.GetVersion(ctx, args.MoveUnfixedTo)
+			if err != nil {
+				return nil, nil, fmt.Errorf("move_unfixed_to: %w", err)
+			}
+			changes["moveUnfixedIssuesTo"] = target.Self
+		}
+		v, err = jc.UpdateVersion(ctx, args.ID, changes)
+		if err != nil {
+			debugf("tool=release_version error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: viewVersion(v)}, nil, nil
+	})
+
+	// archive_version(id, unarchive?)
+	type archiveVersionArgs struct {
+		ID        string `json:"id" jsonschema:"Version id (see list_versions)"`
+		Unarchive bool   `json:"unarchive,omitempty" jsonschema:"Restore an archived version instead"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "archive_version",
+		Title:       "Archive Version",
+		Description: "Archive a version, hiding it from version pickers, or restore it with unarchive=true",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args archiveVersionArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=archive_version args={id:%q,unarchive:%t}", args.ID, args.Unarchive)
+		v, err := jc.UpdateVersion(ctx, args.ID, map[string]any{"archived": !args.Unarchive})
+		if err != nil {
+			debugf("tool=archive_version error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: viewVersion(v)}, nil, nil
+	})
+}
@@ -0,0 +1,190 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Watchers and votes ----
+
+type JiraWatchers struct {
+	IsWatching bool       `json:"isWatching"`
+	WatchCount int        `json:"watchCount"`
+	Watchers   []JiraUser `json:"watchers"`
+}
+
+func (c *JiraClient) GetWatchers(ctx context.Context, key string) (*JiraWatchers, error) {
+	var out JiraWatchers
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/issue/"+url.PathEscape(key)+"/watchers", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AddWatcher adds a watcher. The body is the bare accountId as a JSON string.
+func (c *JiraClient) AddWatcher(ctx context.Context, key, accountID string) error {
+	return c.doJSON(ctx, http.MethodPost, "/rest/api/3/issue/"+url.PathEscape(key)+"/watchers", accountID, nil)
+}
+
+func (c *JiraClient) RemoveWatcher(ctx context.Context, key, accountID string) error {
+	param := "accountId"
+	if c.useAPIV2(ctx) {
+		param = "username"
+	}
+	return c.doJSON(ctx, http.MethodDelete, "/rest/api/3/issue/"+url.PathEscape(key)+"/watchers?"+param+"="+url.QueryEscape(accountID), nil, nil)
+}
+
+type JiraVotes struct {
+	Votes    int        `json:"votes"`
+	HasVoted bool       `json:"hasVoted"`
+	Voters   []JiraUser `json:"voters"`
+}
+
+// GetVotes returns the vote count; voters are only listed when the user
+// may view them.
+func (c *JiraClient) GetVotes(ctx context.Context, key string) (*JiraVotes, error) {
+	var out JiraVotes
+	if err := c.doJSON(ctx, http.MethodGet, "/rest/api/3/issue/"+url.PathEscape(key)+"/votes", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Vote adds (or with remove, withdraws) the current user's vote.
+func (c *JiraClient) Vote(ctx context.Context, key string, remove bool) error {
+	method := http.MethodPost
+	if remove {
+		method = http.MethodDelete
+	}
+	return c.doJSON(ctx, method, "/rest/api/3/issue/"+url.PathEscape(key)+"/votes", nil, nil)
+}
+
+func registerWatcherTools(server *mcp.Server, jc *JiraClient) {
+	// list_watchers(key)
+	type listWatchersArgs struct {
+		Key string `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_watchers",
+		Title:       "List Watchers",
+		Description: "List who is watching an issue, and whether the current user is",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listWatchersArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_watchers args={key:%q}", args.Key)
+		w, err := jc.GetWatchers(ctx, args.Key)
+		if err != nil {
+			debugf("tool=list_watchers error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{
+			"key": args.Key, "watch_count": w.WatchCount, "is_watching": w.IsWatching, "watchers": w.Watchers,
+		}}, nil, nil
+	})
+
+	// add_watcher(key, users)
+	type addWatcherArgs struct {
+		Key   string   `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+		Users []string `json:"users" jsonschema:"accountIds, email addresses, or display names to subscribe"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "add_watcher",
+		Title:       "Add Watcher",
+		Description: "Subscribe users (by accountId, email, or display name) to an issue's notifications",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args addWatcherArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=add_watcher args={key:%q,users:%d}", args.Key, len(args.Users))
+		if len(args.Users) == 0 {
+			return nil, nil, errors.New("users is required")
+		}
+		// Resolve everyone first so an ambiguous name adds nobody.
+		users := make([]*JiraUser, 0, len(args.Users))
+		for _, who := range args.Users {
+			u, err := jc.ResolveUser(ctx, who)
+			if err != nil {
+				return nil, nil, err
+			}
+			users = append(users, u)
+		}
+		for _, u := range users {
+			if err := jc.AddWatcher(ctx, args.Key, u.id()); err != nil {
+				debugf("tool=add_watcher error=%v", err)
+				return nil, nil, err
+			}
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"key": args.Key, "added": users}}, nil, nil
+	})
+
+	// remove_watcher(key, user)
+	type removeWatcherArgs struct {
+		Key  string `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+		User string `json:"user" jsonschema:"accountId, email address, or display name"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "remove_watcher",
+		Title:       "Remove Watcher",
+		Description: "Unsubscribe a user from an issue's notifications",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args removeWatcherArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=remove_watcher args={key:%q,user:%q}", args.Key, args.User)
+		u, err := jc.ResolveUser(ctx, args.User)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := jc.RemoveWatcher(ctx, args.Key, u.id()); err != nil {
+			debugf("tool=remove_watcher error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"key": args.Key, "removed": u}}, nil, nil
+	})
+
+	// get_votes(key)
+	type votesArgs struct {
+		Key string `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "get_votes",
+		Title:       "Get Votes",
+		Description: "Get an issue's vote count, whether the current user has voted, and the voters (when visible)",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args votesArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=get_votes args={key:%q}", args.Key)
+		v, err := jc.GetVotes(ctx, args.Key)
+		if err != nil {
+			debugf("tool=get_votes error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{
+			"key": args.Key, "votes": v.Votes, "has_voted": v.HasVoted, "voters": v.Voters,
+		}}, nil, nil
+	})
+
+	// add_vote(key) and remove_vote(key)
+	for _, remove := range []bool{false, true} {
+		name, title, desc := "add_vote", "Add Vote", "Vote for an issue as the current user to register interest"
+		if remove {
+			name, title, desc = "remove_vote", "Remove Vote", "Withdraw the current user's vote from an issue"
+		}
+		mcp.AddTool(server, &mcp.Tool{
+			Name:        name,
+			Title:       title,
+			Description: desc,
+			Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+		}, func(ctx context.Context, req *mcp.CallToolRequest, args votesArgs) (*mcp.CallToolResult, any, error) {
+			debugf("tool=%s args={key:%q}", name, args.Key)
+			if err := jc.Vote(ctx, args.Key, remove); err != nil {
+				debugf("tool=%s error=%v", name, err)
+				return nil, nil, err
+			}
+			v, err := jc.GetVotes(ctx, args.Key)
+			if err != nil {
+				debugf("tool=%s error=%v", name, err)
+				return nil, nil, err
+			}
+			return &mcp.CallToolResult{StructuredContent: map[string]any{"key": args.Key, "votes": v.Votes, "has_voted": v.HasVoted}}, nil, nil
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package jira
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ---- ADF -> Jira wiki markup ----
+//
+// Server and Data Center take rich text as wiki markup rather than ADF. The
+// write path still parses Markdown to ADF and then renders that as wiki
+// markup, so both API versions accept the same Markdown.
+
+// adfToWiki renders an ADF document as wiki markup.
+func adfToWiki(doc *adfNode) string {
+	return strings.TrimRight(wikiBlocks(doc.Content, ""), "\n")
+}
+
+func wikiBlocks(nodes []*adfNode, listPrefix string) string {
+	var parts []string
+	for _, n := range nodes {
+		if s := wikiBlock(n, listPrefix); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+var wikiPanelMacros = map[string]string{"info": "info", "note": "note", "warning": "warning", "error": "warning", "success": "tip"}
+
+func wikiBlock(n *adfNode, listPrefix string) string {
+	switch n.Type {
+	case "paragraph":
+		return wikiInline(n.Content)
+	case "heading":
+		level, _ := strconv.Atoi(attrString(n, "level"))
+		level = max(1, min(level, 6))
+		return "h" + strconv.Itoa(level) + ". " + wikiInline(n.Content)
+	case "bulletList", "orderedList", "taskList", "decisionList":
+		marker := "*"
+		if n.Type == "orderedList" {
+			marker = "#"
+		}
+		prefix := listPrefix + marker
+		var items []string
+		for _, item := range n.Content {
+			if item.Type == "taskItem" || item.Type == "decisionItem" {
+				box := "(i) "
+				if s := attrString(item, "state"); s == "DONE" || s == "DECIDED" {
+					box = "(/) "
+				}
+				items = append(items, prefix+" "+box+wikiInline(item.Content))
+				continue
+			}
+			var lines []string
+			for i, c := range item.Content {
+				switch {
+				case c.Type == "bulletList" || c.Type == "orderedList":
+					lines = append(lines, wikiBlock(c, prefix))
+				case i == 0:
+					lines = append(lines, prefix+" "+wikiBlock(c, prefix))
+				default:
+					lines = append(lines, wikiBlock(c, prefix))
+				}
+			}
+			items = append(items, strings.Join(lines, "\n"))
+		}
+		return strings.Join(items, "\n")
+	case "codeBlock":
+		var text strings.Builder
+		for _, c := range n.Content {
+			text.WriteString(c.Text)
+		}
+		open := "{code}"
+		if lang := attrString(n, "language"); lang != "" {
+			open = "{code:" + lang + "}"
+		}
+		return open + "\n" + text.String() + "\n{code}"
+	case "blockquote":
+		return "{quote}\n" + wikiBlocks(n.Content, "") + "\n{quote}"
+	case "panel":
+		macro := wikiPanelMacros[attrString(n, "panelType")]
+		if macro == "" {
+			macro = "info"
+		}
+		return "{" + macro + "}\n" + wikiBlocks(n.Content, "") + "\n{" + macro + "}"
+	case "expand", "nestedExpand":
+		body := wikiBlocks(n.Content, "")
+		if title := attrString(n, "title"); title != "" {
+			return "*" + wikiEscaper.Replace(title) + "*\n\n" + body
+		}
+		return body
+	case "rule":
+		return "----"
+	case "table":
+		var rows []string
+		for _, row := range n.Content {
+			var b strings.Builder
+			for i, cell := range row.Content {
+				sep := "|"
+				if cell.Type == "tableHeader" {
+					sep = "||"
+				}
+				text := strings.ReplaceAll(wikiBlocks(cell.Content, ""), "\n", " ")
+				b.WriteString(sep + " " + text + " ")
+				if i == len(row.Content)-1 {
+					b.WriteString(sep)
+				}
+			}
+			rows = append(rows, b.String())
+		}
+		return strings.Join(rows, "\n")
+	case "mediaSingle", "mediaGroup":
+		var parts []string
+		for _, m := range n.Content {
+			parts = append(parts, wikiBlock(m, listPrefix))
+		}
+		return strings.Join(parts, "\n")
+	case "media":
+		name := attrString(n, "alt")
+		if name == "" {
+			name = attrString(n, "id")
+		}
+		return "[^" + name + "]"
+	case "blockCard", "embedCard":
+		return "[" + attrString(n, "url") + "]"
+	}
+	if len(n.Content) > 0 {
+		return wikiBlocks(n.Content, listPrefix)
+	}
+	return wikiInline([]*adfNode{n})
+}
+
+// wikiEscaper escapes the characters that would otherwise start markup.
+var wikiEscaper = strings.NewReplacer(`\`, `\\`, "{", `\{`, "[", `\[`, "*", `\*`, "|", `\|`)
+
+func wikiInline(nodes []*adfNode) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		switch n.Type {
+		case "text":
+			b.WriteString(wikiMarks(n))
+		case "hardBreak":
+			b.WriteString("\n")
+		case "mention":
+			// On Server/DC the mention id is the username.
+			b.WriteString("[~" + attrString(n, "id") + "]")
+		case "emoji":
+			if t := attrString(n, "text"); t != "" {
+				b.WriteString(t)
+			} else {
+				b.WriteString(attrString(n, "shortName"))
+			}
+		case "inlineCard":
+			b.WriteString("[" + attrString(n, "url") + "]")
+		case "date":
+			if ms, err := strconv.ParseInt(attrString(n, "timestamp"), 10, 64); err == nil {
+				b.WriteString(time.UnixMilli(ms).UTC().Format("2006-01-02"))
+			}
+		case "status":
+			b.WriteString(strings.ToUpper(attrString(n, "text")))
+		default:
+			b.WriteString(wikiInline(n.Content))
+		}
+	}
+	return b.String()
+}
+
+func wikiMarks(n *adfNode) string {
+	if hasMark(n.Marks, "code") {
+		return "{{" + n.Text + "}}"
+	}
+	text := wikiEscaper.Replace(n.Text)
+	var href string
+	for _, m := range n.Marks {
+		switch m.Type {
+		case "strong":
+			text = "*" + text + "*"
+		case "em":
+			text = "_" + text + "_"
+		case "strike":
+			text = "-" + text + "-"
+		case "underline":
+			text = "+" + text + "+"
+		case "link":
+			href, _ = m.Attrs["href"].(string)
+		}
+	}
+	if href != "" && href != n.Text {
+		return "[" + text + "|" + href + "]"
+	} else if href != "" {
+		return "[" + href + "]"
+	}
+	return text
+}
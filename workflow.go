@@ -0,0 +1,332 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Multi-step workflows with best-effort rollback ----
+//
+// A workflow is an ordered list of steps. String parameters may reference
+// the results of earlier steps as ${step_id.field}, e.g. ${epic.key}.
+
+type workflowStep struct {
+	ID     string         `json:"id,omitempty" jsonschema:"Optional step id, referenced by later steps as ${id.key}"`
+	Action string         `json:"action" jsonschema:"One of: create_issue, add_comment, link_issues, move_to_sprint"`
+	Params map[string]any `json:"params"`
+}
+
+type workflowStepResult struct {
+	ID            string         `json:"id"`
+	Action        string         `json:"action"`
+	Status        string         `json:"status"` // ok, failed, skipped, rolled_back, rollback_failed
+	Result        map[string]any `json:"result,omitempty"`
+	Error         string         `json:"error,omitempty"`
+	RollbackError string         `json:"rollback_error,omitempty"`
+}
+
+type workflowResult struct {
+	Completed  bool                 `json:"completed"`
+	RolledBack bool                 `json:"rolled_back"`
+	Steps      []workflowStepResult `json:"steps"`
+	Provenance string               `json:"provenance_run,omitempty"`
+}
+
+// stepOutcome is what an executed step produced, plus how to undo it.
+type stepOutcome struct {
+	result map[string]any
+	undo   func(ctx context.Context) error
+}
+
+var workflowRefRe = regexp.MustCompile(`\$\{([A-Za-z0-9_-]+)\.([A-Za-z0-9_]+)\}`)
+
+// resolveRefs substitutes ${step.field} references in strings, recursively.
+func resolveRefs(v any, results map[string]map[string]any) (any, error) {
+	switch t := v.(type) {
+	case string:
+		var missing error
+		out := workflowRefRe.ReplaceAllStringFunc(t, func(m string) string {
+			sub := workflowRefRe.FindStringSubmatch(m)
+			val, ok := results[sub[1]][sub[2]]
+			if !ok {
+				missing = fmt.Errorf("unresolved reference %s", m)
+				return m
+			}
+			return fmt.Sprint(val)
+		})
+		return out, missing
+	case []any:
+		out := make([]any, len(t))
+		for i, e := range t {
+			r, err := resolveRefs(e, results)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = r
+		}
+		return out, nil
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, e := range t {
+			r, err := resolveRefs(e, results)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = r
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+func paramString(p map[string]any, name string, required bool) (string, error) {
+	s, _ := p[name].(string)
+	if s == "" && required {
+		return "", fmt.Errorf("missing string param %q", name)
+	}
+	return s, nil
+}
+
+func paramStrings(p map[string]any, name string) ([]string, error) {
+	switch t := p[name].(type) {
+	case string:
+		return []string{t}, nil
+	case []any:
+		out := make([]string, 0, len(t))
+		for _, e := range t {
+			s, ok := e.(string)
+			if !ok {
+				return nil, fmt.Errorf("param %q must be a list of strings", name)
+			}
+			out = append(out, s)
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("missing list param %q", name)
+}
+
+func paramInt(p map[string]any, name string) (int, error) {
+	switch t := p[name].(type) {
+	case float64:
+		return int(t), nil
+	case string:
+		if n, err := strconv.Atoi(t); err == nil {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("missing integer param %q", name)
+}
+
+func (c *JiraClient) runWorkflowStep(ctx context.Context, action string, p map[string]any) (*stepOutcome, error) {
+	switch action {
+	case "create_issue":
+		project, err := paramString(p, "project_key", true)
+		if err != nil {
+			return nil, err
+		}
+		issueType, err := paramString(p, "issue_type", true)
+		if err != nil {
+			return nil, err
+		}
+		summary, err := paramString(p, "summary", true)
+		if err != nil {
+			return nil, err
+		}
+		fields := map[string]any{
+			"project":   map[string]any{"key": project},
+			"issuetype": map[string]any{"name": issueType},
+			"summary":   summary,
+		}
+		if d, _ := paramString(p, "description", false); d != "" {
+			fields["description"] = c.richText(ctx, d)
+		}
+		if parent, _ := paramString(p, "parent_key", false); parent != "" {
+			fields["parent"] = map[string]any{"key": parent}
+		}
+		if extra, ok := p["fields"].(map[string]any); ok {
+			resolved, err := c.resolveFieldKeys(ctx, extra)
+			if err != nil {
+				return nil, err
+			}
+			for k, v := range resolved {
+				fields[k] = v
+			}
+		}
+		iss, err := c.CreateIssueFields(ctx, fields)
+		if err != nil {
+			return nil, err
+		}
+		return &stepOutcome{
+			result: map[string]any{"key": iss.Key, "id": iss.ID},
+			undo:   func(ctx context.Context) error { return c.DeleteIssue(ctx, iss.Key, true) },
+		}, nil
+
+	case "add_comment":
+		key, err := paramString(p, "key", true)
+		if err != nil {
+			return nil, err
+		}
+		body, err := paramString(p, "body", true)
+		if err != nil {
+			return nil, err
+		}
+		cm, err := c.AddComment(ctx, key, body)
+		if err != nil {
+			return nil, err
+		}
+		return &stepOutcome{
+			result: map[string]any{"key": key, "id": cm.ID},
+			undo:   func(ctx context.Context) error { return c.DeleteComment(ctx, key, cm.ID) },
+		}, nil
+
+	case "link_issues":
+		linkType, err := paramString(p, "type", true)
+		if err != nil {
+			return nil, err
+		}
+		inward, err := paramString(p, "inward_key", true)
+		if err != nil {
+			return nil, err
+		}
+		outward, err := paramString(p, "outward_key", true)
+		if err != nil {
+			return nil, err
+		}
+		id, err := c.LinkIssues(ctx, linkType, inward, outward)
+		if err != nil {
+			return nil, err
+		}
+		return &stepOutcome{
+			result: map[string]any{"id": id},
+			undo:   func(ctx context.Context) error { return c.DeleteIssueLink(ctx, id) },
+		}, nil
+
+	case "move_to_sprint":
+		sprintID, err := paramInt(p, "sprint_id")
+		if err != nil {
+			return nil, err
+		}
+		keys, err := paramStrings(p, "keys")
+		if err != nil {
+			return nil, err
+		}
+		if err := c.MoveToSprint(ctx, sprintID, keys); err != nil {
+			return nil, err
+		}
+		return &stepOutcome{
+			result: map[string]any{"sprint_id": sprintID, "keys": strings.Join(keys, ",")},
+			// The previous sprint is not known; the backlog is the best we can do.
+			undo: func(ctx context.Context) error { return c.MoveToBacklog(ctx, keys) },
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown action %q", action)
+}
+
+// stepID is the id of step i: its own, or step1, step2, ... if unnamed.
+func stepID(steps []workflowStep, i int) string {
+	if steps[i].ID != "" {
+		return steps[i].ID
+	}
+	return fmt.Sprintf("step%d", i+1)
+}
+
+// checkStepIDs rejects workflows in which two steps would share an id,
+// including a given id that clashes with an unnamed step's number.
+func checkStepIDs(steps []workflowStep) error {
+	seen := map[string]bool{}
+	for i := range steps {
+		id := stepID(steps, i)
+		if seen[id] {
+			return fmt.Errorf("duplicate step id %q", id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// RunWorkflow executes steps in order. onError is "stop" (default),
+// "continue" or "rollback"; rollback undoes completed steps in reverse order.
+func (c *JiraClient) RunWorkflow(ctx context.Context, steps []workflowStep, onError string) *workflowResult {
+	res := &workflowResult{Steps: make([]workflowStepResult, len(steps))}
+	results := map[string]map[string]any{}
+	var done []int
+	undos := make([]func(context.Context) error, len(steps))
+	failed := false
+
+	for i, st := range steps {
+		sr := &res.Steps[i]
+		sr.ID, sr.Action = stepID(steps, i), st.Action
+		if failed && onError != "continue" {
+			sr.Status = "skipped"
+			continue
+		}
+		params, err := resolveRefs(st.Params, results)
+		var out *stepOutcome
+		if err == nil {
+			out, err = c.runWorkflowStep(ctx, st.Action, params.(map[string]any))
+		}
+		if err != nil {
+			debugf("workflow step %s (%s) error=%v", sr.ID, st.Action, err)
+			sr.Status, sr.Error = "failed", err.Error()
+			failed = true
+			continue
+		}
+		sr.Status, sr.Result = "ok", out.result
+		results[sr.ID] = out.result
+		undos[i] = out.undo
+		done = append(done, i)
+	}
+
+	res.Completed = !failed
+	if failed && onError == "rollback" {
+		res.RolledBack = true
+		// Use a context that survives cancellation of the original call so a
+		// cancelled workflow still cleans up after itself.
+		rctx := context.WithoutCancel(ctx)
+		for j := len(done) - 1; j >= 0; j-- {
+			i := done[j]
+			if err := undos[i](rctx); err != nil {
+				res.Steps[i].Status, res.Steps[i].RollbackError = "rollback_failed", err.Error()
+				res.RolledBack = false
+				continue
+			}
+			res.Steps[i].Status = "rolled_back"
+		}
+	}
+	return res
+}
+
+func registerWorkflowTools(server *mcp.Server, jc *JiraClient) {
+	// run_workflow(steps, on_error?)
+	type runWorkflowArgs struct {
+		Steps   []workflowStep `json:"steps" jsonschema:"Ordered steps; params for create_issue: project_key, issue_type, summary, description, parent_key, fields; add_comment: key, body; link_issues: type, inward_key, outward_key; move_to_sprint: sprint_id, keys"`
+		OnError string         `json:"on_error,omitempty" jsonschema:"stop (default), continue, or rollback (undo completed steps)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "run_workflow",
+		Title:       "Run Workflow",
+		Description: "Run a sequence of Jira operations (e.g. create epic, create stories under it, link them, move them to a sprint) with per-step results and optional rollback on failure. Reference earlier results as ${step_id.key}",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args runWorkflowArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=run_workflow args={steps:%d,on_error:%q}", len(args.Steps), args.OnError)
+		switch args.OnError {
+		case "", "stop", "continue", "rollback":
+		default:
+			return nil, nil, errors.New("on_error must be stop, continue or rollback")
+		}
+		if err := checkStepIDs(args.Steps); err != nil {
+			return nil, nil, err
+		}
+		ctx, run := jc.beginProvenance(ctx)
+		res := jc.RunWorkflow(ctx, args.Steps, args.OnError)
+		if !res.RolledBack {
+			res.Provenance = jc.finishProvenance(ctx, run)
+		}
+		return &mcp.CallToolResult{StructuredContent: res, IsError: !res.Completed}, nil, nil
+	})
+}
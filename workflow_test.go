@@ -0,0 +1,82 @@
+package jira
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestCheckStepIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		ids     []string
+		wantErr bool
+	}{
+		{"unnamed", []string{"", "", ""}, false},
+		{"named", []string{"epic", "story"}, false},
+		{"mixed", []string{"epic", "", "story"}, false},
+		{"duplicate", []string{"epic", "epic"}, true},
+		{"clashes with numbering", []string{"step2", ""}, true},
+		{"numbered later", []string{"", "step1"}, true},
+	}
+	for _, tt := range tests {
+		steps := make([]workflowStep, len(tt.ids))
+		for i, id := range tt.ids {
+			steps[i].ID = id
+		}
+		if err := checkStepIDs(steps); (err != nil) != tt.wantErr {
+			t.Errorf("%s: checkStepIDs(%q) = %v, want error %t", tt.name, tt.ids, err, tt.wantErr)
+		}
+	}
+}
+
+func TestRunWorkflowRollback(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls = append(calls, r.Method+" "+r.URL.RequestURI())
+		mu.Unlock()
+		switch r.Method + " " + r.URL.Path {
+		case "POST /rest/api/3/issue":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "10001", "key": "PROJ-9"}`))
+		case "DELETE /rest/api/3/issue/PROJ-9":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, `{"errorMessages": ["no"]}`, http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+	jc := &JiraClient{BaseURL: srv.URL, Client: srv.Client(), site: "default", Instance: instanceConfig{Writes: "allow"}}
+	jc.api.resolved = true // Cloud, v3
+
+	steps := []workflowStep{
+		{ID: "epic", Action: "create_issue", Params: map[string]any{"project_key": "PROJ", "issue_type": "Epic", "summary": "E"}},
+		{Action: "add_comment", Params: map[string]any{"key": "${epic.key}", "body": "hi"}},
+		{Action: "add_comment", Params: map[string]any{"key": "${epic.key}", "body": "never sent"}},
+	}
+	res := jc.RunWorkflow(context.Background(), steps, "rollback")
+
+	var got []string
+	for _, st := range res.Steps {
+		got = append(got, st.ID+":"+st.Status)
+	}
+	if want := []string{"epic:rolled_back", "step2:failed", "step3:skipped"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("steps = %q, want %q", got, want)
+	}
+	if res.Completed || !res.RolledBack {
+		t.Errorf("completed = %t, rolled back = %t; want false, true", res.Completed, res.RolledBack)
+	}
+	want := []string{
+		"POST /rest/api/3/issue",
+		"POST /rest/api/3/issue/PROJ-9/comment",
+		"DELETE /rest/api/3/issue/PROJ-9?deleteSubtasks=true",
+	}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %q, want %q", calls, want)
+	}
+}
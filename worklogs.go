@@ -0,0 +1,499 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Worklogs ----
+
+type JiraWorklog struct {
+	ID               string         `json:"id"`
+	IssueID          string         `json:"issueId,omitempty"`
+	Author           map[string]any `json:"author,omitempty"`
+	Comment          any            `json:"comment,omitempty"`
+	Started          string         `json:"started"`
+	TimeSpent        string         `json:"timeSpent,omitempty"`
+	TimeSpentSeconds int            `json:"timeSpentSeconds"`
+	Updated          string         `json:"updated,omitempty"`
+}
+
+// durationPartRe matches one part of a Jira duration such as "1.5h".
+var durationPartRe = regexp.MustCompile(`^(\d+(?:\.\d+)?)([wdhm])$`)
+
+// normalizeDuration checks a Jira-style duration ("1h 30m", "2d", "1.5h",
+// "90m") and rewrites it in whole units, e.g. "1.5h" as "1h 30m". Weeks and
+// days are kept as such, since their length is set per site.
+func normalizeDuration(s string) (string, error) {
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) == 0 {
+		return "", errors.New("duration is empty")
+	}
+	units := map[string]float64{}
+	for _, f := range fields {
+		m := durationPartRe.FindStringSubmatch(f)
+		if m == nil {
+			return "", fmt.Errorf("invalid duration %q (use e.g. 1h 30m, 2d, 45m)", s)
+		}
+		v, _ := strconv.ParseFloat(m[1], 64)
+		units[m[2]] += v
+	}
+	// Carry fractions down: hours to minutes. Fractional days or weeks
+	// depend on the site's working day, so they are refused.
+	for _, u := range []string{"w", "d"} {
+		if units[u] != math.Trunc(units[u]) {
+			return "", fmt.Errorf("invalid duration %q: use whole weeks and days", s)
+		}
+	}
+	minutes := math.Round(units["h"]*60 + units["m"])
+	var parts []string
+	for _, p := range []struct {
+		unit string
+		n    float64
+	}{{"w", units["w"]}, {"d", units["d"]}, {"h", math.Floor(minutes / 60)}, {"m", math.Mod(minutes, 60)}} {
+		if p.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d%s", int(p.n), p.unit))
+		}
+	}
+	if len(parts) == 0 {
+		return "", fmt.Errorf("duration %q is zero", s)
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// worklogStarted parses a start time given as a Jira timestamp, RFC 3339, or
+// a date (taken as 09:00 UTC), into the form the worklog API takes. Empty
+// means now.
+func worklogStarted(s string) (string, error) {
+	if s == "" {
+		return time.Now().Format(jiraTimeLayout), nil
+	}
+	t, ok := parseJiraTime(s)
+	if !ok {
+		return "", fmt.Errorf("started %q must be a timestamp like 2026-01-02T15:04:05+00:00 or a date", s)
+	}
+	if len(s) == len("2006-01-02") {
+		t = t.Add(9 * time.Hour)
+	}
+	return t.Format(jiraTimeLayout), nil
+}
+
+// estimateParams are the query parameters that say how a worklog change
+// adjusts the issue's remaining estimate. mode is auto (the default), leave,
+// new (set to value), or manual (change by value); manualParam names the
+// parameter manual uses, reduceBy or increaseBy.
+func estimateParams(mode, value, manualParam string) (url.Values, error) {
+	q := url.Values{}
+	switch mode {
+	case "", "auto":
+		if value != "" {
+			return nil, errors.New("estimate_value needs adjust_estimate new or manual")
+		}
+		return q, nil
+	case "leave":
+		q.Set("adjustEstimate", "leave")
+		return q, nil
+	case "new", "manual":
+		if value == "" {
+			return nil, fmt.Errorf("adjust_estimate %s needs estimate_value", mode)
+		}
+		d, err := normalizeDuration(value)
+		if err != nil {
+			return nil, err
+		}
+		q.Set("adjustEstimate", mode)
+		if mode == "new" {
+			q.Set("newEstimate", d)
+		} else {
+			q.Set(manualParam, d)
+		}
+		return q, nil
+	}
+	return nil, fmt.Errorf("unknown adjust_estimate %q (valid: auto, leave, new, manual)", mode)
+}
+
+func worklogPath(key, id string, q url.Values) string {
+	path := "/rest/api/3/issue/" + url.PathEscape(key) + "/worklog"
+	if id != "" {
+		path += "/" + url.PathEscape(id)
+	}
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+	return path
+}
+
+func (c *JiraClient) AddWorklog(ctx context.Context, key string, body map[string]any, q url.Values) (*JiraWorklog, error) {
+	var out JiraWorklog
+	if err := c.doJSON(ctx, http.MethodPost, worklogPath(key, "", q), body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *JiraClient) UpdateWorklog(ctx context.Context, key, id string, body map[string]any, q url.Values) (*JiraWorklog, error) {
+	var out JiraWorklog
+	if err := c.doJSON(ctx, http.MethodPut, worklogPath(key, id, q), body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func (c *JiraClient) DeleteWorklog(ctx context.Context, key, id string, q url.Values) error {
+	return c.doJSON(ctx, http.MethodDelete, worklogPath(key, id, q), nil, nil)
+}
+
+// IssueWorklogs returns an issue's worklogs, oldest first, optionally only
+// those started at or after since.
+func (c *JiraClient) IssueWorklogs(ctx context.Context, key string, since time.Time) ([]JiraWorklog, error) {
+	var out []JiraWorklog
+	for {
+		q := url.Values{}
+		q.Set("startAt", strconv.Itoa(len(out)))
+		q.Set("maxResults", "1000")
+		if !since.IsZero() {
+			q.Set("startedAfter", strconv.FormatInt(since.UnixMilli(), 10))
+		}
+		var page struct {
+			Total    int           `json:"total"`
+			Worklogs []JiraWorklog `json:"worklogs"`
+		}
+		if err := c.doJSON(ctx, http.MethodGet, worklogPath(key, "", q), nil, &page); err != nil {
+			return nil, err
+		}
+		out = append(out, page.Worklogs...)
+		if len(page.Worklogs) == 0 || len(out) >= page.Total {
+			return out, nil
+		}
+	}
+}
+
+type worklogView struct {
+	ID        string  `json:"id"`
+	Author    string  `json:"author,omitempty"`
+	Started   string  `json:"started"`
+	TimeSpent string  `json:"time_spent"`
+	Hours     float64 `json:"hours"`
+	Comment   string  `json:"comment,omitempty"`
+}
+
+func viewWorklog(w *JiraWorklog) worklogView {
+	return worklogView{
+		ID: w.ID, Author: fieldString(w.Author, "displayName"), Started: w.Started, TimeSpent: w.TimeSpent,
+		Hours: math.Round(float64(w.TimeSpentSeconds)/36) / 100, Comment: adfToMarkdown(w.Comment),
+	}
+}
+
+// UpdatedWorklogs pages the worklog sync feed and returns the full worklogs
+// created or updated since the given time.
+func (c *JiraClient) UpdatedWorklogs(ctx context.Context, since time.Time) ([]JiraWorklog, error) {
+	var ids []int64
+	path := fmt.Sprintf("/rest/api/3/worklog/updated?since=%d", since.UnixMilli())
+	for {
+		var page struct {
+			Values []struct {
+				WorklogID int64 `json:"worklogId"`
+			} `json:"values"`
+			Until    int64 `json:"until"`
+			LastPage bool  `json:"lastPage"`
+		}
+		if err := c.doJSON(ctx, http.MethodGet, path, nil, &page); err != nil {
+			return nil, err
+		}
+		for _, v := range page.Values {
+			ids = append(ids, v.WorklogID)
+		}
+		if page.LastPage || len(page.Values) == 0 {
+			break
+		}
+		path = fmt.Sprintf("/rest/api/3/worklog/updated?since=%d", page.Until)
+	}
+	var out []JiraWorklog
+	for start := 0; start < len(ids); start += 1000 {
+		var batch []JiraWorklog
+		body := map[string]any{"ids": ids[start:min(start+1000, len(ids))]}
+		if err := c.doJSON(ctx, http.MethodPost, "/rest/api/3/worklog/list", body, &batch); err != nil {
+			return nil, err
+		}
+		out = append(out, batch...)
+	}
+	return out, nil
+}
+
+type heatmapCell struct {
+	Date  string  `json:"date"`
+	Hours float64 `json:"hours"`
+}
+
+type worklogHeatmap struct {
+	User       string        `json:"user,omitempty"`
+	From       string        `json:"from"`
+	To         string        `json:"to"`
+	TotalHours float64       `json:"total_hours"`
+	Worklogs   int           `json:"worklogs"`
+	Days       []heatmapCell `json:"days"`
+	Weeks      []heatmapCell `json:"weeks"` // keyed by the Monday starting each week
+}
+
+// buildHeatmap sums worklog hours per day in [from, to] (inclusive), with a
+// zero cell for each day without work, plus weekly totals.
+func buildHeatmap(logs []JiraWorklog, accountID string, from, to time.Time) *worklogHeatmap {
+	perDay := map[string]float64{}
+	hm := &worklogHeatmap{From: from.Format("2006-01-02"), To: to.Format("2006-01-02")}
+	for _, w := range logs {
+		if accountID != "" && fieldString(w.Author, "accountId") != accountID {
+			continue
+		}
+		started, ok := parseJiraTime(w.Started)
+		if !ok {
+			continue
+		}
+		day := started.Format("2006-01-02")
+		if day < hm.From || day > hm.To {
+			continue
+		}
+		perDay[day] += float64(w.TimeSpentSeconds) / 3600
+		hm.Worklogs++
+	}
+	round := func(v float64) float64 { return math.Round(v*100) / 100 }
+	var week *heatmapCell
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		h := perDay[d.Format("2006-01-02")]
+		hm.Days = append(hm.Days, heatmapCell{Date: d.Format("2006-01-02"), Hours: round(h)})
+		hm.TotalHours += h
+		monday := d.AddDate(0, 0, -((int(d.Weekday()) + 6) % 7)).Format("2006-01-02")
+		if week == nil || week.Date != monday {
+			hm.Weeks = append(hm.Weeks, heatmapCell{Date: monday})
+			week = &hm.Weeks[len(hm.Weeks)-1]
+		}
+		week.Hours = round(week.Hours + h)
+	}
+	hm.TotalHours = round(hm.TotalHours)
+	return hm
+}
+
+func registerWorklogTools(server *mcp.Server, jc *JiraClient) {
+	// worklog_heatmap(user?, from, to)
+	type heatmapArgs struct {
+		User string `json:"user,omitempty" jsonschema:"accountId, email, or display name; the session focus assignee or everyone when omitted"`
+		From string `json:"from" jsonschema:"First day (YYYY-MM-DD)"`
+		To   string `json:"to" jsonschema:"Last day (YYYY-MM-DD), inclusive"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "worklog_heatmap",
+		Title:       "Worklog Heatmap",
+		Description: "Hours logged per day and per week in a date range, ready to render as a calendar heatmap",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args heatmapArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=worklog_heatmap args={user:%q,from:%q,to:%q}", args.User, args.From, args.To)
+		from, err := time.Parse("2006-01-02", args.From)
+		if err != nil {
+			return nil, nil, fmt.Errorf("from must be YYYY-MM-DD: %w", err)
+		}
+		to, err := time.Parse("2006-01-02", args.To)
+		if err != nil {
+			return nil, nil, fmt.Errorf("to must be YYYY-MM-DD: %w", err)
+		}
+		if to.Before(from) {
+			return nil, nil, errors.New("to is before from")
+		}
+		if to.Sub(from) > 366*24*time.Hour {
+			return nil, nil, errors.New("range is limited to one year")
+		}
+		var accountID string
+		if f := jc.focusFor(req.Session); args.User == "" && f != nil && f.Assignee != "" {
+			accountID, args.User = f.accountID, f.Assignee
+			if accountID == "" {
+				me, err := jc.Myself(ctx)
+				if err != nil {
+					return nil, nil, err
+				}
+				accountID = me.AccountID
+			}
+		} else if args.User != "" {
+			u, err := jc.ResolveUser(ctx, args.User)
+			if err != nil {
+				return nil, nil, err
+			}
+			accountID = u.AccountID
+		}
+		// Work is logged at or after it starts, so the sync feed only needs
+		// to go back to the start of the range (less a day for time zones).
+		logs, err := jc.UpdatedWorklogs(ctx, from.AddDate(0, 0, -1))
+		if err != nil {
+			debugf("tool=worklog_heatmap error=%v", err)
+			return nil, nil, err
+		}
+		hm := buildHeatmap(logs, accountID, from, to)
+		hm.User = args.User
+		return &mcp.CallToolResult{StructuredContent: hm}, nil, nil
+	})
+
+	// add_worklog(key, time_spent, started?, comment?, adjust_estimate?, estimate_value?)
+	type addWorklogArgs struct {
+		Key            string `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+		TimeSpent      string `json:"time_spent" jsonschema:"Time worked, e.g. 1h 30m, 2d, 45m, 1.5h"`
+		Started        string `json:"started,omitempty" jsonschema:"When the work started: a timestamp like 2026-01-02T15:04:05+00:00, or a date (default now)"`
+		Comment        string `json:"comment,omitempty" jsonschema:"What was done, in Markdown"`
+		AdjustEstimate string `json:"adjust_estimate,omitempty" jsonschema:"How to change the remaining estimate: auto (reduce by time_spent; default), leave, new (set to estimate_value), or manual (reduce by estimate_value)"`
+		EstimateValue  string `json:"estimate_value,omitempty" jsonschema:"Duration for adjust_estimate new or manual"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "add_worklog",
+		Title:       "Add Worklog",
+		Description: "Log time worked on an issue, with a start time and comment, adjusting the remaining estimate",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args addWorklogArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=add_worklog args={key:%q,spent:%q,started:%q,adjust:%q}", args.Key, args.TimeSpent, args.Started, args.AdjustEstimate)
+		spent, err := normalizeDuration(args.TimeSpent)
+		if err != nil {
+			return nil, nil, err
+		}
+		started, err := worklogStarted(args.Started)
+		if err != nil {
+			return nil, nil, err
+		}
+		q, err := estimateParams(args.AdjustEstimate, args.EstimateValue, "reduceBy")
+		if err != nil {
+			return nil, nil, err
+		}
+		body := map[string]any{"timeSpent": spent, "started": started}
+		if args.Comment != "" {
+			body["comment"] = jc.richText(ctx, args.Comment)
+		}
+		w, err := jc.AddWorklog(ctx, args.Key, body, q)
+		if err != nil {
+			debugf("tool=add_worklog error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"key": args.Key, "worklog": viewWorklog(w)}}, nil, nil
+	})
+
+	// list_worklogs(key, since?)
+	type listWorklogsArgs struct {
+		Key   string `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+		Since string `json:"since,omitempty" jsonschema:"Only worklogs started on or after this date (YYYY-MM-DD)"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_worklogs",
+		Title:       "List Worklogs",
+		Description: "List the time logged on an issue, oldest first, with totals per author",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args listWorklogsArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=list_worklogs args={key:%q,since:%q}", args.Key, args.Since)
+		var since time.Time
+		if args.Since != "" {
+			var err error
+			if since, err = time.Parse("2006-01-02", args.Since); err != nil {
+				return nil, nil, fmt.Errorf("since must be YYYY-MM-DD: %w", err)
+			}
+		}
+		logs, err := jc.IssueWorklogs(ctx, args.Key, since)
+		if err != nil {
+			debugf("tool=list_worklogs error=%v", err)
+			return nil, nil, err
+		}
+		views := make([]worklogView, len(logs))
+		byAuthor := map[string]float64{}
+		var total float64
+		for i := range logs {
+			views[i] = viewWorklog(&logs[i])
+			byAuthor[views[i].Author] += views[i].Hours
+			total += views[i].Hours
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{
+			"key": args.Key, "worklogs": views, "total_hours": math.Round(total*100) / 100, "hours_by_author": byAuthor,
+		}}, nil, nil
+	})
+
+	// update_worklog(key, id, time_spent?, started?, comment?, adjust_estimate?, estimate_value?)
+	type updateWorklogArgs struct {
+		Key            string `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+		ID             string `json:"id" jsonschema:"Worklog id (see list_worklogs)"`
+		TimeSpent      string `json:"time_spent,omitempty" jsonschema:"New time worked, e.g. 1h 30m"`
+		Started        string `json:"started,omitempty" jsonschema:"New start time: a timestamp or a date"`
+		Comment        string `json:"comment,omitempty" jsonschema:"New comment, in Markdown"`
+		AdjustEstimate string `json:"adjust_estimate,omitempty" jsonschema:"auto (default), leave, or new (set to estimate_value)"`
+		EstimateValue  string `json:"estimate_value,omitempty" jsonschema:"Duration for adjust_estimate new"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "update_worklog",
+		Title:       "Update Worklog",
+		Description: "Change a worklog's time spent, start time, or comment",
+		Annotations: &mcp.ToolAnnotations{IdempotentHint: true},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args updateWorklogArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=update_worklog args={key:%q,id:%q,spent:%q,adjust:%q}", args.Key, args.ID, args.TimeSpent, args.AdjustEstimate)
+		if args.AdjustEstimate == "manual" {
+			return nil, nil, errors.New("adjust_estimate manual is not available when updating; use new")
+		}
+		q, err := estimateParams(args.AdjustEstimate, args.EstimateValue, "")
+		if err != nil {
+			return nil, nil, err
+		}
+		body := map[string]any{}
+		if args.TimeSpent != "" {
+			if body["timeSpent"], err = normalizeDuration(args.TimeSpent); err != nil {
+				return nil, nil, err
+			}
+		}
+		if args.Started != "" {
+			if body["started"], err = worklogStarted(args.Started); err != nil {
+				return nil, nil, err
+			}
+		}
+		if args.Comment != "" {
+			body["comment"] = jc.richText(ctx, args.Comment)
+		}
+		if len(body) == 0 {
+			return nil, nil, errors.New("nothing to update")
+		}
+		w, err := jc.UpdateWorklog(ctx, args.Key, args.ID, body, q)
+		if err != nil {
+			debugf("tool=update_worklog error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"key": args.Key, "worklog": viewWorklog(w)}}, nil, nil
+	})
+
+	// delete_worklog(key, id, adjust_estimate?, estimate_value?, confirm?)
+	type deleteWorklogArgs struct {
+		Key            string `json:"key" jsonschema:"Jira issue key, e.g. PROJ-123"`
+		ID             string `json:"id" jsonschema:"Worklog id (see list_worklogs)"`
+		AdjustEstimate string `json:"adjust_estimate,omitempty" jsonschema:"auto (add the logged time back; default), leave, new (set to estimate_value), or manual (increase by estimate_value)"`
+		EstimateValue  string `json:"estimate_value,omitempty" jsonschema:"Duration for adjust_estimate new or manual"`
+		Confirm        bool   `json:"confirm,omitempty" jsonschema:"Only for clients without elicitation: set once the user has approved the deletion"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "delete_worklog",
+		Title:       "Delete Worklog",
+		Description: "Delete a worklog, adjusting the remaining estimate. Asks the user to confirm; clients without elicitation pass confirm=true once the user has approved",
+		Annotations: &mcp.ToolAnnotations{DestructiveHint: ptr(true)},
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args deleteWorklogArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=delete_worklog args={key:%q,id:%q,adjust:%q,confirm:%t}", args.Key, args.ID, args.AdjustEstimate, args.Confirm)
+		q, err := estimateParams(args.AdjustEstimate, args.EstimateValue, "increaseBy")
+		if err != nil {
+			return nil, nil, err
+		}
+		ok, err := approveAction(ctx, req.Session, args.Confirm, fmt.Sprintf("Delete worklog %s on %s?", args.ID, args.Key))
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			return nil, nil, fmt.Errorf("deletion of worklog %s declined by the user", args.ID)
+		}
+		if err := jc.DeleteWorklog(ctx, args.Key, args.ID, q); err != nil {
+			debugf("tool=delete_worklog error=%v", err)
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{StructuredContent: map[string]any{"key": args.Key, "id": args.ID, "deleted": true}}, nil, nil
+	})
+}
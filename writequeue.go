@@ -0,0 +1,390 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ---- Deferred write queue ----
+//
+// With JIRA_WRITE_QUEUE set, non-urgent writes (comments and label changes)
+// that fail because Jira is unreachable, rate limited, or down are saved to
+// disk and replayed in the background once it answers again:
+//
+//	{"path": "/var/lib/jira-mcp/queue.json", "retry_interval_seconds": 30, "max_items": 1000}
+//
+// Callers pass urgent=true to fail instead of queueing. Only failures that
+// mean Jira never took the write are queued, so a replay cannot post it
+// twice. Replays are logged to connected clients, and write_queue_status
+// reports what is pending.
+//
+// A write to a site with writes=confirm is replayed in the background only
+// if the user confirmed it before it was queued. Otherwise it waits for
+// write_queue_status flush=true, which asks the caller.
+
+type writeQueueConfig struct {
+	Path                 string `json:"path"`
+	RetryIntervalSeconds int    `json:"retry_interval_seconds,omitempty"`
+	MaxItems             int    `json:"max_items,omitempty"`
+}
+
+const (
+	queuedComment = "comment"
+	queuedLabels  = "labels"
+)
+
+type queuedWrite struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	Key       string    `json:"key"`
+	Body      string    `json:"body,omitempty"`   // comment Markdown
+	Public    *bool     `json:"public,omitempty"` // service desk comment visibility
+	Add       []string  `json:"add,omitempty"`
+	Remove    []string  `json:"remove,omitempty"`
+	Tool      string    `json:"tool,omitempty"`
+	Site      string    `json:"site,omitempty"`      // empty for the default site
+	Confirmed bool      `json:"confirmed,omitempty"` // the user confirmed the write (writes=confirm)
+	QueuedAt  time.Time `json:"queued_at"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+type writeQueueFile struct {
+	Pending []*queuedWrite `json:"pending"`
+	Failed  []*queuedWrite `json:"failed,omitempty"` // rejected by Jira on replay
+}
+
+type writeQueue struct {
+	cfg      writeQueueConfig
+	interval time.Duration
+	replay   sync.Mutex // one replay at a time, so nothing is applied twice
+
+	mu        sync.Mutex
+	state     writeQueueFile
+	nextID    int
+	lastRun   time.Time
+	lastError string
+}
+
+func loadWriteQueue() (*writeQueue, error) {
+	var cfg writeQueueConfig
+	ok, err := loadJSONSetting("JIRA_WRITE_QUEUE", &cfg)
+	if err != nil || !ok {
+		return nil, err
+	}
+	if cfg.Path == "" {
+		return nil, errors.New("JIRA_WRITE_QUEUE: path is required")
+	}
+	if cfg.MaxItems <= 0 {
+		cfg.MaxItems = 1000
+	}
+	q := &writeQueue{cfg: cfg, interval: 30 * time.Second}
+	if cfg.RetryIntervalSeconds > 0 {
+		q.interval = time.Duration(cfg.RetryIntervalSeconds) * time.Second
+	}
+	b, err := os.ReadFile(cfg.Path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("JIRA_WRITE_QUEUE: %w", err)
+	default:
+		if err := json.Unmarshal(b, &q.state); err != nil {
+			return nil, fmt.Errorf("JIRA_WRITE_QUEUE: %s: %w", cfg.Path, err)
+		}
+	}
+	for _, w := range append(q.state.Pending, q.state.Failed...) {
+		if n, err := strconv.Atoi(w.ID); err == nil && n > q.nextID {
+			q.nextID = n
+		}
+	}
+	return q, nil
+}
+
+// save writes the queue file atomically. Callers hold q.mu.
+func (q *writeQueue) save() error {
+	b, err := json.MarshalIndent(q.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(q.cfg.Path), 0o700); err != nil {
+		return err
+	}
+	tmp := q.cfg.Path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, q.cfg.Path)
+}
+
+func (q *writeQueue) enqueue(w *queuedWrite) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.state.Pending) >= q.cfg.MaxItems {
+		return fmt.Errorf("write queue is full (%d items)", q.cfg.MaxItems)
+	}
+	q.nextID++
+	w.ID = strconv.Itoa(q.nextID)
+	w.QueuedAt = time.Now().UTC()
+	q.state.Pending = append(q.state.Pending, w)
+	return q.save()
+}
+
+// retryableWriteError reports whether err means Jira did not take the
+// write: it could not be reached, asked us to slow down, or is down for
+// maintenance. Timeouts and other 5xx responses are not retryable, since
+// Jira may have committed the write before failing.
+func retryableWriteError(err error) bool {
+	var merr *MaintenanceError
+	var je *JiraError
+	var oe *net.OpError
+	var dnse *net.DNSError
+	switch {
+	case errors.As(err, &merr):
+		return true
+	case errors.As(err, &je):
+		return je.StatusCode == http.StatusTooManyRequests || je.StatusCode == http.StatusServiceUnavailable
+	case errors.As(err, &dnse):
+		return true
+	case errors.As(err, &oe):
+		return oe.Op == "dial"
+	}
+	return false
+}
+
+// errNeedsConfirmation stops a replay at an unconfirmed write to a site with
+// writes=confirm, in the background or for a client that cannot elicit.
+var errNeedsConfirmation = errors.New("a queued write to a site with writes=confirm needs confirmation; run write_queue_status with flush=true from a client that supports elicitation")
+
+func (c *JiraClient) applyQueuedWrite(ctx context.Context, w *queuedWrite) error {
+	site := c.siteNamed(w.Site)
+	if site == nil {
+		return fmt.Errorf("site %q is no longer configured", w.Site)
+	}
+	ctx = withSite(ctx, site)
+	switch w.Kind {
+	case queuedComment:
+		_, err := c.addComment(ctx, w.Key, w.Body, w.Public)
+		return err
+	case queuedLabels:
+		ops, err := labelOps(w.Add, w.Remove)
+		if err != nil {
+			return err
+		}
+		return c.UpdateIssue(ctx, w.Key, nil, map[string]any{"labels": ops})
+	}
+	return fmt.Errorf("unknown queued write kind %q", w.Kind)
+}
+
+// deferWrite runs write now. If it fails in a way worth retrying and the
+// queue is enabled (and the caller is not urgent), w is queued instead and
+// deferWrite reports it as queued rather than failed.
+func (c *JiraClient) deferWrite(ctx context.Context, urgent bool, w *queuedWrite, write func() error) (queued bool, err error) {
+	err = write()
+	if err == nil || c.queue == nil || urgent || !retryableWriteError(err) {
+		return false, err
+	}
+	if c.forSite(ctx).passthrough {
+		// Replay would run as the server, not as the session's user.
+		return false, err
+	}
+	if tc := currentToolCall(ctx); tc != nil {
+		w.Tool = tc.Name
+		tc.mu.Lock()
+		w.Confirmed = tc.confirmed
+		tc.mu.Unlock()
+	}
+	if s := c.forSite(ctx); s != c {
+		w.Site = s.site
+	}
+	w.LastError = err.Error()
+	if qerr := c.queue.enqueue(w); qerr != nil {
+		return false, fmt.Errorf("%w (and could not queue it: %v)", err, qerr)
+	}
+	debugf("write queue: queued %s on %s after: %v", w.Kind, w.Key, err)
+	return true, nil
+}
+
+// replayQueue tries pending writes in order, stopping at the first that
+// still cannot get through. It returns how many were applied. Called from a
+// tool, it asks that tool's caller to confirm writes to writes=confirm sites
+// that were not confirmed when queued; in the background it stops at them.
+func (c *JiraClient) replayQueue(ctx context.Context) (int, error) {
+	q := c.queue
+	q.replay.Lock()
+	defer q.replay.Unlock()
+	caller := currentToolCall(ctx)
+	ctx = withSubsystem(ctx, "write_queue")
+	applied := 0
+	for {
+		q.mu.Lock()
+		q.lastRun = time.Now()
+		if len(q.state.Pending) == 0 {
+			q.lastError = ""
+			q.mu.Unlock()
+			return applied, nil
+		}
+		w := q.state.Pending[0]
+		q.mu.Unlock()
+
+		err := c.confirmReplay(ctx, caller, w)
+		if errors.Is(err, errNeedsConfirmation) {
+			// Leave it pending for a caller who can confirm it.
+			q.mu.Lock()
+			q.lastError = err.Error()
+			q.mu.Unlock()
+			return applied, err
+		}
+		if err == nil {
+			// Confirmed now, when queued, or not needed.
+			tc := &toolCall{Name: "write_queue", confirmed: true}
+			if caller != nil {
+				tc.Name, tc.Session = caller.Name, caller.Session
+			}
+			err = c.applyQueuedWrite(context.WithValue(ctx, toolCallKey{}, tc), w)
+		}
+
+		q.mu.Lock()
+		w.Attempts++
+		switch {
+		case err == nil:
+			q.state.Pending = q.state.Pending[1:]
+			applied++
+		case retryableWriteError(err):
+			w.LastError, q.lastError = err.Error(), err.Error()
+		default:
+			w.LastError = err.Error()
+			q.state.Pending = q.state.Pending[1:]
+			q.state.Failed = append(q.state.Failed, w)
+		}
+		serr := q.save()
+		q.mu.Unlock()
+		if serr != nil {
+			return applied, serr
+		}
+		if err != nil && retryableWriteError(err) {
+			return applied, err
+		}
+	}
+}
+
+// confirmReplay asks caller to approve w if it goes to a site with
+// writes=confirm and was not confirmed when queued. Without a caller,
+// or for a client that cannot elicit, it returns errNeedsConfirmation and
+// the write stays pending.
+func (c *JiraClient) confirmReplay(ctx context.Context, caller *toolCall, w *queuedWrite) error {
+	site := c.siteNamed(w.Site)
+	if w.Confirmed || site == nil || !site.Instance.needsConfirmation() {
+		return nil
+	}
+	if caller == nil {
+		return errNeedsConfirmation
+	}
+	ok, err := confirmAction(ctx, caller.Session, fmt.Sprintf("Replay a queued %s on %s to %s?", w.Kind, w.Key, site.Instance.describe()))
+	if errors.Is(err, errConfirmationUnavailable) {
+		return errNeedsConfirmation
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("replay to %s declined by the user", site.Instance.describe())
+	}
+	return nil
+}
+
+// runWriteQueue replays the queue every interval until ctx ends, telling
+// connected clients what got through.
+func (c *JiraClient) runWriteQueue(ctx context.Context, server *mcp.Server) {
+	t := time.NewTicker(c.queue.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		n, err := c.replayQueue(ctx)
+		if n == 0 {
+			continue
+		}
+		msg := map[string]any{"event": "write_queue_replayed", "applied": n, "pending": c.queue.status().Pending}
+		if err != nil {
+			msg["error"] = err.Error()
+		}
+		for ss := range server.Sessions() {
+			_ = ss.Log(ctx, &mcp.LoggingMessageParams{Level: "info", Logger: "jira.write_queue", Data: msg})
+		}
+	}
+}
+
+type writeQueueStatus struct {
+	Pending   int           `json:"pending"`
+	Items     []queuedWrite `json:"items"`
+	Failed    []queuedWrite `json:"failed,omitempty"`
+	LastRun   *time.Time    `json:"last_run,omitempty"`
+	LastError string        `json:"last_error,omitempty"`
+	Interval  float64       `json:"retry_interval_seconds"`
+}
+
+func (q *writeQueue) status() writeQueueStatus {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	s := writeQueueStatus{
+		Pending:   len(q.state.Pending),
+		Items:     []queuedWrite{},
+		LastError: q.lastError,
+		Interval:  q.interval.Seconds(),
+	}
+	// Copies, since replays update items while the result is encoded.
+	for _, w := range q.state.Pending {
+		s.Items = append(s.Items, *w)
+	}
+	for _, w := range q.state.Failed {
+		s.Failed = append(s.Failed, *w)
+	}
+	if !q.lastRun.IsZero() {
+		t := q.lastRun
+		s.LastRun = &t
+	}
+	return s
+}
+
+func registerWriteQueueTools(server *mcp.Server, jc *JiraClient) {
+	if jc.queue == nil {
+		return
+	}
+	logger.Printf("write queue enabled at %s (%d pending)", jc.queue.cfg.Path, jc.queue.status().Pending)
+
+	// write_queue_status(flush?)
+	type statusArgs struct {
+		Flush bool `json:"flush,omitempty" jsonschema:"Try to replay pending writes now"`
+	}
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "write_queue_status",
+		Title:       "Write Queue Status",
+		Description: "Show comments and label changes queued while Jira was unreachable, and writes Jira rejected on replay; flush=true retries now",
+	}, func(ctx context.Context, req *mcp.CallToolRequest, args statusArgs) (*mcp.CallToolResult, any, error) {
+		debugf("tool=write_queue_status args={flush:%t}", args.Flush)
+		out := map[string]any{}
+		if args.Flush {
+			n, err := jc.replayQueue(ctx)
+			out["applied"] = n
+			if err != nil {
+				out["flush_error"] = err.Error()
+			}
+		}
+		out["queue"] = jc.queue.status()
+		return &mcp.CallToolResult{StructuredContent: out}, nil, nil
+	})
+}
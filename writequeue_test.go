@@ -0,0 +1,63 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestRetryableWriteError(t *testing.T) {
+	refused := errors.New("connection refused")
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"maintenance", &MaintenanceError{Status: "503", RetryAfter: time.Minute}, true},
+		{"rate limited", &JiraError{StatusCode: http.StatusTooManyRequests}, true},
+		{"unavailable", &JiraError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"wrapped unavailable", fmt.Errorf("add comment: %w", &JiraError{StatusCode: http.StatusServiceUnavailable}), true},
+		{"internal error", &JiraError{StatusCode: http.StatusInternalServerError}, false},
+		{"bad gateway", &JiraError{StatusCode: http.StatusBadGateway}, false},
+		{"gateway timeout", &JiraError{StatusCode: http.StatusGatewayTimeout}, false},
+		{"bad request", &JiraError{StatusCode: http.StatusBadRequest}, false},
+		{"dial", &url.Error{Op: "Post", URL: "https://x", Err: &net.OpError{Op: "dial", Net: "tcp", Err: refused}}, true},
+		{"dns", &url.Error{Op: "Post", URL: "https://x", Err: &net.DNSError{Name: "x", Err: "no such host"}}, true},
+		{"reset mid-request", &url.Error{Op: "Post", URL: "https://x", Err: &net.OpError{Op: "read", Net: "tcp", Err: refused}}, false},
+		{"timeout", &url.Error{Op: "Post", URL: "https://x", Err: context.DeadlineExceeded}, false},
+		{"other", errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		if got := retryableWriteError(tt.err); got != tt.want {
+			t.Errorf("%s: retryableWriteError(%v) = %t, want %t", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestConfirmReplay(t *testing.T) {
+	prod := &JiraClient{site: "default", Instance: instanceConfig{Label: "prod", Production: true, Writes: "confirm"}}
+	dev := &JiraClient{site: "default", Instance: instanceConfig{Label: "dev", Writes: "allow"}}
+	tests := []struct {
+		name   string
+		jc     *JiraClient
+		w      queuedWrite
+		caller *toolCall
+		want   error
+	}{
+		{"background", prod, queuedWrite{Kind: "comment"}, nil, errNeedsConfirmation},
+		{"client cannot elicit", prod, queuedWrite{Kind: "comment"}, &toolCall{Name: "write_queue_status"}, errNeedsConfirmation},
+		{"confirmed caller cannot elicit", prod, queuedWrite{Kind: "comment"}, &toolCall{Name: "write_queue_status", confirmed: true}, errNeedsConfirmation},
+		{"confirmed when queued", prod, queuedWrite{Kind: "comment", Confirmed: true}, nil, nil},
+		{"not production", dev, queuedWrite{Kind: "comment"}, nil, nil},
+	}
+	for _, tt := range tests {
+		if err := tt.jc.confirmReplay(context.Background(), tt.caller, &tt.w); !errors.Is(err, tt.want) {
+			t.Errorf("%s: confirmReplay = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}